@@ -0,0 +1,71 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blinklabs-io/gouroboros/ledger/mary"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+var _ connector.AccountAddressesProvider = (*BlockfrostProvider)(nil)
+
+// bfAccountAddress is an entry of /accounts/{stake_address}/addresses.
+type bfAccountAddress struct {
+	Address string `json:"address"`
+}
+
+// validateStakeAddress rejects anything that is not a Bech32 stake address.
+func validateStakeAddress(stakeAddr string) error {
+	if !strings.HasPrefix(stakeAddr, "stake") {
+		return fmt.Errorf(
+			"%w: expected a stake address (stake1...)",
+			connector.ErrInvalidAddress,
+		)
+	}
+	return nil
+}
+
+// GetAccountAddresses returns every payment address associated with the
+// stake address, in Config.Order. An account that was never active has no
+// addresses. When Config.MaxPages cuts the list short, the addresses fetched
+// so far are returned with an error wrapping connector.ErrTruncated.
+func (b *BlockfrostProvider) GetAccountAddresses(ctx context.Context, stakeAddr string) ([]string, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+	if err := validateStakeAddress(stakeAddr); err != nil {
+		return nil, err
+	}
+
+	entries, err := paginate[bfAccountAddress](ctx, b, "/accounts/"+stakeAddr+"/addresses")
+	if err != nil && !errors.Is(err, connector.ErrTruncated) {
+		return nil, fmt.Errorf("failed to get addresses of %s: %w", stakeAddr, err)
+	}
+	addresses := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		addresses = append(addresses, entry.Address)
+	}
+	return addresses, err
+}
+
+// GetAccountAssets returns the native assets held across every address of
+// the stake address; lovelace is not included. Blockfrost only reports the
+// account-wide totals, not a per-address breakdown.
+func (b *BlockfrostProvider) GetAccountAssets(
+	ctx context.Context,
+	stakeAddr string,
+) (mary.MaryTransactionOutputValue, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+	if err := validateStakeAddress(stakeAddr); err != nil {
+		return mary.MaryTransactionOutputValue{}, err
+	}
+
+	amounts, err := paginate[bfAddressAmount](ctx, b, "/accounts/"+stakeAddr+"/addresses/assets")
+	if err != nil {
+		return mary.MaryTransactionOutputValue{}, fmt.Errorf("failed to get assets of %s: %w", stakeAddr, err)
+	}
+	return valueFromAmounts(amounts)
+}
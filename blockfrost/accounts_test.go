@@ -0,0 +1,57 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+func accountAddressesFixture() []string {
+	addresses := make([]string, 5)
+	for i := range addresses {
+		addresses[i] = fmt.Sprintf("addr_test1account%d", i)
+	}
+	return addresses
+}
+
+func TestGetAccountAddresses(t *testing.T) {
+	fixtures := offlineFixtures()
+	fixtures.AccountAddresses = map[string][]string{offlineStakeAddr: accountAddressesFixture()}
+	bf, srv := newOfflineBlockfrost(t, fixtures, Config{PageSize: 2})
+
+	addresses, err := bf.GetAccountAddresses(context.Background(), offlineStakeAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, accountAddressesFixture(), addresses)
+	assert.Equal(t, 3, srv.Requests("/accounts/"+offlineStakeAddr+"/addresses"))
+}
+
+func TestGetAccountAddressesUnknownAccount(t *testing.T) {
+	bf, _ := newOfflineBlockfrost(t, offlineFixtures(), Config{})
+
+	addresses, err := bf.GetAccountAddresses(context.Background(), offlineStakeAddr)
+	assert.NoError(t, err)
+	assert.Empty(t, addresses)
+}
+
+func TestGetAccountAddressesTruncated(t *testing.T) {
+	fixtures := offlineFixtures()
+	fixtures.AccountAddresses = map[string][]string{offlineStakeAddr: accountAddressesFixture()}
+	bf, _ := newOfflineBlockfrost(t, fixtures, Config{PageSize: 2, MaxPages: 2})
+
+	addresses, err := bf.GetAccountAddresses(context.Background(), offlineStakeAddr)
+	assert.True(t, errors.Is(err, connector.ErrTruncated), "got %v", err)
+	assert.Equal(t, accountAddressesFixture()[:4], addresses)
+}
+
+func TestGetAccountAddressesRejectsPaymentAddress(t *testing.T) {
+	bf, _ := newOfflineBlockfrost(t, offlineFixtures(), Config{})
+
+	_, err := bf.GetAccountAddresses(context.Background(), balanceAddr)
+	assert.True(t, errors.Is(err, connector.ErrInvalidAddress), "got %v", err)
+	_, err = bf.GetAccountAssets(context.Background(), balanceAddr)
+	assert.True(t, errors.Is(err, connector.ErrInvalidAddress), "got %v", err)
+}
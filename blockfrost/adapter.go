@@ -1,12 +1,14 @@
 package blockfrost
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"math/big"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -16,6 +18,7 @@ import (
 	"github.com/blinklabs-io/gouroboros/ledger/common"
 	"github.com/blinklabs-io/gouroboros/ledger/mary"
 	"github.com/blinklabs-io/gouroboros/ledger/shelley"
+	"github.com/blinklabs-io/plutigo/data"
 	connector "github.com/zenGate-Global/cardano-connector-go"
 )
 
@@ -23,19 +26,18 @@ import (
 // value (lovelace + native assets) and a bare datum-hash DatumOption. Inline
 // datums and reference scripts are layered on afterwards by hydrateUtxo.
 func (raw *bfAddressUTxO) toUtxo(address common.Address) (common.Utxo, error) {
-	hashBytes, err := hex.DecodeString(raw.TxHash)
-	if err != nil {
-		return common.Utxo{}, err
-	}
-	if len(hashBytes) != common.Blake2b256Size {
+	if len(raw.TxHash) != 2*common.Blake2b256Size {
 		return common.Utxo{}, fmt.Errorf(
-			"invalid tx hash length: expected %d bytes, got %d",
+			"invalid tx hash length: expected %d bytes, got %d hex characters",
 			common.Blake2b256Size,
-			len(hashBytes),
+			len(raw.TxHash),
 		)
 	}
+	// Decode straight into the id rather than through a temporary slice.
 	var txId common.Blake2b256
-	copy(txId[:], hashBytes)
+	if _, err := hex.Decode(txId[:], []byte(raw.TxHash)); err != nil {
+		return common.Utxo{}, err
+	}
 
 	if raw.OutputIndex < 0 {
 		return common.Utxo{}, fmt.Errorf("negative output index: %d", raw.OutputIndex)
@@ -49,34 +51,33 @@ func (raw *bfAddressUTxO) toUtxo(address common.Address) (common.Utxo, error) {
 	}
 
 	var lovelace uint64
-	assetData := make(map[common.Blake2b224]map[cbor.ByteString]*big.Int)
+	// Most UTxOs hold only lovelace, so the asset map is allocated on the
+	// first native asset.
+	var assetData map[common.Blake2b224]map[cbor.ByteString]*big.Int
 
 	for _, amt := range raw.Amount {
 		if amt.Unit == "lovelace" {
-			qty, err := strconv.ParseInt(amt.Quantity, 10, 64)
+			qty, err := parseQuantity(amt.Quantity)
 			if err != nil {
-				return common.Utxo{}, fmt.Errorf("invalid lovelace quantity %q: %w", amt.Quantity, err)
-			}
-			if qty < 0 {
-				return common.Utxo{}, fmt.Errorf("negative lovelace quantity: %d", qty)
+				return common.Utxo{}, fmt.Errorf("invalid lovelace quantity: %w", err)
 			}
-			lovelace = uint64(qty) //nolint:gosec // validated non-negative above
+			lovelace = qty
 		} else if len(amt.Unit) >= 56 {
-			qty, ok := new(big.Int).SetString(amt.Quantity, 10)
-			if !ok {
-				return common.Utxo{}, fmt.Errorf("invalid asset quantity %q for unit %s", amt.Quantity, amt.Unit)
-			}
-			if qty.Sign() < 0 {
-				return common.Utxo{}, fmt.Errorf("negative asset quantity %s for unit %s", qty.String(), amt.Unit)
+			qty, err := parseQuantity(amt.Quantity)
+			if err != nil {
+				return common.Utxo{}, fmt.Errorf("invalid asset quantity for unit %s: %w", amt.Unit, err)
 			}
 			policyId, assetName, err := backend.ParseAssetUnit(amt.Unit)
 			if err != nil {
 				return common.Utxo{}, fmt.Errorf("invalid asset unit %q: %w", amt.Unit, err)
 			}
+			if assetData == nil {
+				assetData = make(map[common.Blake2b224]map[cbor.ByteString]*big.Int)
+			}
 			if _, ok := assetData[policyId]; !ok {
 				assetData[policyId] = make(map[cbor.ByteString]*big.Int)
 			}
-			assetData[policyId][assetName] = qty
+			assetData[policyId][assetName] = new(big.Int).SetUint64(qty)
 		} else {
 			return common.Utxo{}, fmt.Errorf(
 				"unrecognized unit format %q: expected \"lovelace\" or hex string >= 56 chars (policy_id + asset_name)",
@@ -188,8 +189,13 @@ func scriptRefFromHash(scriptHash common.Blake2b224, scriptCbor []byte) (*common
 // bfScriptRefFromScript encodes a reference script into the Ogmios-v5 TxOut
 // "script" wire shape used by /utils/txs/evaluate/utxos:
 // {"plutus:v1"|"plutus:v2"|"plutus:v3"|"plutus:v4": "<base16 serialised script>"}.
-// Native reference scripts are not representable in that schema and are rejected.
+// The language key follows the script's own type. Native reference scripts have
+// no execution budget to evaluate, so they are skipped (nil, nil) rather than
+// failing the whole evaluation.
 func bfScriptRefFromScript(script common.Script) (*bfScriptRef, error) {
+	if _, ok := script.(common.NativeScript); ok {
+		return nil, nil
+	}
 	scriptHex := hex.EncodeToString(script.RawScriptBytes())
 	ref := &bfScriptRef{}
 	switch script.(type) {
@@ -203,23 +209,37 @@ func bfScriptRefFromScript(script common.Script) (*bfScriptRef, error) {
 		ref.PlutusV4 = &scriptHex
 	default:
 		return nil, fmt.Errorf(
-			"unsupported script type %T in additional UTxO: only Plutus v1/v2/v3/v4 reference scripts can be encoded for /utils/txs/evaluate/utxos",
+			"unsupported script type %T in additional UTxO: only native and Plutus v1/v2/v3/v4 reference scripts are supported by /utils/txs/evaluate/utxos",
 			script,
 		)
 	}
 	return ref, nil
 }
 
-// bigIntToInt64 converts a big.Int quantity to int64, rejecting values that do
-// not fit rather than silently truncating.
-func bigIntToInt64(v *big.Int) (int64, error) {
+// bigIntToUint64 converts a big.Int quantity to uint64, rejecting values that
+// do not fit rather than silently truncating.
+func bigIntToUint64(v *big.Int) (uint64, error) {
 	if v == nil {
 		return 0, nil
 	}
-	if !v.IsInt64() {
-		return 0, fmt.Errorf("quantity %s does not fit in int64", v.String())
+	if !v.IsUint64() {
+		return 0, fmt.Errorf("quantity %s does not fit in uint64", v.String())
+	}
+	return v.Uint64(), nil
+}
+
+// parseQuantity parses a Blockfrost quantity string. Output values are
+// unsigned 64-bit on the ledger, so anything negative, malformed or above
+// 2^64-1 is an error rather than being dropped or wrapped.
+func parseQuantity(s string) (uint64, error) {
+	qty, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, fmt.Errorf("quantity %q overflows uint64", s)
+		}
+		return 0, fmt.Errorf("malformed quantity %q: %w", s, err)
 	}
-	return v.Int64(), nil
+	return qty, nil
 }
 
 // bfAdditionalUtxoItemFromUtxo builds a single [txIn, txOut] additional-UTxO
@@ -232,7 +252,7 @@ func bfAdditionalUtxoItemFromUtxo(utxo common.Utxo) (bfAdditionalUtxoItem, error
 		Index: int(utxo.Id.Index()),
 	}
 
-	coins, err := bigIntToInt64(out.Amount())
+	coins, err := bigIntToUint64(out.Amount())
 	if err != nil {
 		return bfAdditionalUtxoItem{}, fmt.Errorf("invalid lovelace amount: %w", err)
 	}
@@ -243,7 +263,7 @@ func bfAdditionalUtxoItemFromUtxo(utxo common.Utxo) (bfAdditionalUtxoItem, error
 		for _, policyId := range assets.Policies() {
 			policyHex := hex.EncodeToString(policyId.Bytes())
 			for _, assetName := range assets.Assets(policyId) {
-				qty, err := bigIntToInt64(assets.Asset(policyId, assetName))
+				qty, err := bigIntToUint64(assets.Asset(policyId, assetName))
 				if err != nil {
 					return bfAdditionalUtxoItem{}, fmt.Errorf(
 						"invalid asset quantity for %s.%s: %w",
@@ -251,7 +271,7 @@ func bfAdditionalUtxoItemFromUtxo(utxo common.Utxo) (bfAdditionalUtxoItem, error
 					)
 				}
 				if val[policyHex] == nil {
-					val[policyHex] = make(map[string]int64)
+					val[policyHex] = make(map[string]uint64)
 				}
 				val[policyHex][hex.EncodeToString(assetName)] = qty
 			}
@@ -265,7 +285,7 @@ func bfAdditionalUtxoItemFromUtxo(utxo common.Utxo) (bfAdditionalUtxoItem, error
 
 	// Inline datum CBOR hex goes in Datum; a bare datum hash goes in DatumHash.
 	if datum := out.Datum(); datum != nil {
-		datumCbor, err := datum.MarshalCBOR()
+		datumCbor, err := connector.InlineDatumCbor(datum)
 		if err != nil {
 			return bfAdditionalUtxoItem{}, fmt.Errorf("failed to encode inline datum: %w", err)
 		}
@@ -287,13 +307,103 @@ func bfAdditionalUtxoItemFromUtxo(utxo common.Utxo) (bfAdditionalUtxoItem, error
 	return bfAdditionalUtxoItem{txIn, txOut}, nil
 }
 
-// adaptBlockfrostAccountToDelegation converts Blockfrost account details to a connector delegation.
-func adaptBlockfrostAccountToDelegation(bfAcc BlockfrostAccountDetails) connector.Delegation {
+// datumCborFromJSON converts a datum in the detailed JSON schema
+// (constructor/fields, int, bytes, list, map) into its CBOR encoding, using the
+// same definite/indefinite-length conventions as the Haskell node.
+func datumCborFromJSON(raw json.RawMessage) ([]byte, error) {
+	pd, err := data.DecodeJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	return data.Encode(pd)
+}
+
+// bfNativeScript is the cardano-cli JSON form of a native script served by
+// /scripts/{hash}/json.
+type bfNativeScript struct {
+	Type     string           `json:"type"`
+	KeyHash  string           `json:"keyHash"`
+	Required uint64           `json:"required"`
+	Slot     uint64           `json:"slot"`
+	Scripts  []bfNativeScript `json:"scripts"`
+}
+
+// nativeScriptCborFromJSON serializes a native script from its JSON form into
+// the ledger CBOR encoding:
+//
+//	sig     → [0, keyHash]
+//	all     → [1, [scripts]]
+//	any     → [2, [scripts]]
+//	atLeast → [3, required, [scripts]]
+//	after   → [4, slot] (invalid before)
+//	before  → [5, slot] (invalid hereafter)
+func nativeScriptCborFromJSON(raw json.RawMessage) ([]byte, error) {
+	var script bfNativeScript
+	if err := json.Unmarshal(raw, &script); err != nil {
+		return nil, fmt.Errorf("failed to decode native script json: %w", err)
+	}
+	item, err := script.cborItem()
+	if err != nil {
+		return nil, err
+	}
+	return cbor.Encode(item)
+}
+
+func (s bfNativeScript) cborItem() (any, error) {
+	scripts := func() ([]any, error) {
+		items := make([]any, 0, len(s.Scripts))
+		for _, sub := range s.Scripts {
+			item, err := sub.cborItem()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+
+	switch s.Type {
+	case "sig":
+		keyHash, err := hex.DecodeString(s.KeyHash)
+		if err != nil || len(keyHash) != common.Blake2b224Size {
+			return nil, fmt.Errorf("invalid native script key hash %q", s.KeyHash)
+		}
+		return []any{0, keyHash}, nil
+	case "all", "any":
+		items, err := scripts()
+		if err != nil {
+			return nil, err
+		}
+		if s.Type == "all" {
+			return []any{1, items}, nil
+		}
+		return []any{2, items}, nil
+	case "atLeast":
+		items, err := scripts()
+		if err != nil {
+			return nil, err
+		}
+		return []any{3, s.Required, items}, nil
+	case "after":
+		return []any{4, s.Slot}, nil
+	case "before":
+		return []any{5, s.Slot}, nil
+	default:
+		return nil, fmt.Errorf("unsupported native script type %q", s.Type)
+	}
+}
+
+// adaptBlockfrostAccountToDelegation converts Blockfrost account details to a
+// connector delegation. Epoch is the epoch the delegation took effect in, or
+// zero when unknown.
+func adaptBlockfrostAccountToDelegation(bfAcc BlockfrostAccountDetails) (connector.Delegation, error) {
 	rewards := uint64(0)
 	if bfAcc.WithdrawableAmount != "" {
-		if parsed, err := strconv.ParseUint(bfAcc.WithdrawableAmount, 10, 64); err == nil {
-			rewards = parsed
+		parsed, err := parseQuantity(bfAcc.WithdrawableAmount)
+		if err != nil {
+			return connector.Delegation{}, fmt.Errorf("invalid withdrawable amount: %w", err)
 		}
+		rewards = parsed
 	}
 
 	poolID := ""
@@ -303,11 +413,51 @@ func adaptBlockfrostAccountToDelegation(bfAcc BlockfrostAccountDetails) connecto
 
 	delegationActive := poolID != "" && bfAcc.Active
 
+	epoch := 0
+	if bfAcc.ActiveEpoch != nil {
+		epoch = *bfAcc.ActiveEpoch
+	}
+
+	drepID := ""
+	if bfAcc.DeRepId != nil {
+		drepID = *bfAcc.DeRepId
+	}
+
 	return connector.Delegation{
 		PoolId:  poolID,
 		Rewards: rewards,
 		Active:  delegationActive,
+		Epoch:   epoch,
+		DRepId:  drepID,
+	}, nil
+}
+
+// toNetworkInfo converts the /network response, failing on any amount that
+// does not parse rather than leaving it zero.
+func (n *bfNetwork) toNetworkInfo() (connector.NetworkInfo, error) {
+	var info connector.NetworkInfo
+	fields := []struct {
+		name  string
+		value string
+		dst   *uint64
+	}{
+		{"supply.max", n.Supply.Max, &info.Supply.Max},
+		{"supply.total", n.Supply.Total, &info.Supply.Total},
+		{"supply.circulating", n.Supply.Circulating, &info.Supply.Circulating},
+		{"supply.locked", n.Supply.Locked, &info.Supply.Locked},
+		{"supply.treasury", n.Supply.Treasury, &info.Supply.Treasury},
+		{"supply.reserves", n.Supply.Reserves, &info.Supply.Reserves},
+		{"stake.live", n.Stake.Live, &info.Stake.Live},
+		{"stake.active", n.Stake.Active, &info.Stake.Active},
+	}
+	for _, f := range fields {
+		qty, err := parseQuantity(f.value)
+		if err != nil {
+			return connector.NetworkInfo{}, fmt.Errorf("invalid %s: %w", f.name, err)
+		}
+		*f.dst = qty
 	}
+	return info, nil
 }
 
 // toProtocolParams converts the BlockFrost protocol-params response into apollo
@@ -382,7 +532,7 @@ func (p *bfProtocolParams) toProtocolParams() (backend.ProtocolParameters, error
 	if err != nil {
 		return pp, err
 	}
-	pp.CostModels = costModels
+	pp.CostModels = connector.NormalizeCostModels(costModels)
 
 	return pp, nil
 }
@@ -444,18 +594,71 @@ func parseCostModels(raw, named json.RawMessage) (map[string][]int64, error) {
 // RedeemerTag. backend.ParseRedeemerTag accepts spend/mint/cert/publish/reward/
 // withdraw; Ogmios v5 additionally emits the long spellings "certificate" and
 // "withdrawal", so those are normalized to the accepted forms first
-// (case-insensitively) before delegating.
+// (case-insensitively) before delegating. The Conway purposes "vote" and
+// "propose", which backend.ParseRedeemerTag predates, are mapped directly.
 func parseRedeemerPurpose(purpose string) (common.RedeemerTag, error) {
 	switch strings.ToLower(strings.TrimSpace(purpose)) {
 	case "certificate":
 		return backend.ParseRedeemerTag("cert")
 	case "withdrawal":
 		return backend.ParseRedeemerTag("withdraw")
+	case "vote":
+		return common.RedeemerTagVoting, nil
+	case "propose":
+		return common.RedeemerTagProposing, nil
 	default:
 		return backend.ParseRedeemerTag(purpose)
 	}
 }
 
+// submitRejectionRules maps the ledger rule failures embedded in Blockfrost's
+// /tx/submit 400 messages to connector sentinels.
+var submitRejectionRules = []struct {
+	rule     string
+	sentinel error
+}{
+	{"BadInputsUTxO", connector.ErrBadInputs},
+	{"ValueNotConservedUTxO", connector.ErrValueNotConserved},
+	{"MaxTxSizeUTxO", connector.ErrTxTooLarge},
+	{"OutsideValidityIntervalUTxO", connector.ErrOutsideValidityInterval},
+	{"FeeTooSmallUTxO", connector.ErrFeeTooSmall},
+	{"ScriptWitnessNotValidatingUTXOW", connector.ErrEvaluationFailed},
+	{"ValidationTagMismatch", connector.ErrEvaluationFailed},
+	{"ExUnitsTooBigUTxO", connector.ErrEvaluationFailed},
+	{"CollectErrors", connector.ErrEvaluationFailed},
+}
+
+// parseSubmitError builds a SubmissionError from a rejected /tx/submit
+// response. The node's ApplyTxError is embedded verbatim in the message, so the
+// ledger rules are found by name; the first one (by position) that maps to a
+// sentinel determines the error kind.
+func parseSubmitError(statusCode int, message string) *connector.SubmissionError {
+	type match struct {
+		pos      int
+		rule     string
+		sentinel error
+	}
+	var matches []match
+	for _, r := range submitRejectionRules {
+		if pos := strings.Index(message, r.rule); pos >= 0 {
+			matches = append(matches, match{pos: pos, rule: r.rule, sentinel: r.sentinel})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].pos < matches[j].pos })
+
+	subErr := &connector.SubmissionError{
+		StatusCode: statusCode,
+		Message:    message,
+	}
+	for _, m := range matches {
+		subErr.Reasons = append(subErr.Reasons, m.rule)
+		if subErr.Kind == nil {
+			subErr.Kind = m.sentinel
+		}
+	}
+	return subErr
+}
+
 // jsonValuePresent reports whether a raw JSON field was present and not null.
 func jsonValuePresent(raw json.RawMessage) bool {
 	trimmed := strings.TrimSpace(string(raw))
@@ -467,41 +670,41 @@ func jsonValuePresent(raw json.RawMessage) bool {
 // jsonwsp shape ({"result":{"EvaluationResult":{...}}}) or the Ogmios v6 shape
 // ({"result":[{"validator":...,"budget":...}, ...]}, with failures reported as
 // a top-level {"error":{...}} object).
-func parseEvaluateTxResponse(data []byte) (map[common.RedeemerKey]common.ExUnits, error) {
+//
+// A redeemer whose key or purpose cannot be parsed is reported through
+// onKeyErr; the redeemer is skipped when it returns nil and parsing fails with
+// its error otherwise.
+func parseEvaluateTxResponse(
+	data []byte,
+	onKeyErr func(key string, err error) error,
+) (map[common.RedeemerKey]common.ExUnits, error) {
 	var envelope struct {
 		Result json.RawMessage `json:"result"`
 		Error  json.RawMessage `json:"error"`
+		Fault  json.RawMessage `json:"fault"`
 	}
 	if err := json.Unmarshal(data, &envelope); err != nil {
-		return nil, fmt.Errorf("failed to parse evaluate response: %w", err)
+		return nil, fmt.Errorf("%w: failed to parse evaluate response: %w", connector.ErrEvaluationFailed, err)
 	}
-	if jsonValuePresent(envelope.Error) {
-		var ogmiosErr struct {
-			Code    int             `json:"code"`
-			Message string          `json:"message"`
-			Data    json.RawMessage `json:"data"`
-		}
-		if err := json.Unmarshal(envelope.Error, &ogmiosErr); err == nil && ogmiosErr.Message != "" {
-			if jsonValuePresent(ogmiosErr.Data) {
-				return nil, fmt.Errorf("%w (code %d): %s: %s",
-					connector.ErrEvaluationFailed, ogmiosErr.Code, ogmiosErr.Message, string(ogmiosErr.Data))
-			}
-			return nil, fmt.Errorf("%w (code %d): %s", connector.ErrEvaluationFailed, ogmiosErr.Code, ogmiosErr.Message)
-		}
-		return nil, fmt.Errorf("%w: %s", connector.ErrEvaluationFailed, string(envelope.Error))
+	if jsonValuePresent(envelope.Error) || jsonValuePresent(envelope.Fault) {
+		return nil, evaluationError(data, nil)
 	}
 	if !jsonValuePresent(envelope.Result) {
-		return nil, fmt.Errorf("unrecognized evaluate response (no result or error): %s", evalErrorSnippet(data))
+		return nil, fmt.Errorf("%w: unrecognized evaluate response (no result or error): %s",
+			connector.ErrEvaluationFailed, evalErrorSnippet(data))
 	}
 	if strings.HasPrefix(strings.TrimSpace(string(envelope.Result)), "[") {
-		return parseOgmiosV6EvaluationResult(envelope.Result)
+		return parseOgmiosV6EvaluationResult(envelope.Result, onKeyErr)
 	}
-	return parseOgmiosV5EvaluationResult(envelope.Result)
+	return parseOgmiosV5EvaluationResult(envelope.Result, onKeyErr)
 }
 
 // parseOgmiosV6EvaluationResult parses the Ogmios v6 evaluateTransaction result
 // array: [{"validator":{"purpose":...,"index":...},"budget":{"memory":...,"cpu":...}}].
-func parseOgmiosV6EvaluationResult(raw json.RawMessage) (map[common.RedeemerKey]common.ExUnits, error) {
+func parseOgmiosV6EvaluationResult(
+	raw json.RawMessage,
+	onKeyErr func(key string, err error) error,
+) (map[common.RedeemerKey]common.ExUnits, error) {
 	var items []struct {
 		Validator struct {
 			Purpose string `json:"purpose"`
@@ -519,28 +722,58 @@ func parseOgmiosV6EvaluationResult(raw json.RawMessage) (map[common.RedeemerKey]
 	if len(items) == 0 {
 		return nil, fmt.Errorf("%w: script evaluation returned no results", connector.ErrEvaluationFailed)
 	}
-	result := make(map[common.RedeemerKey]common.ExUnits, len(items))
+	evalErr := &connector.EvaluationError{}
 	for _, item := range items {
-		if jsonValuePresent(item.Error) {
-			return nil, fmt.Errorf("%w for validator %s:%d: %s",
-				connector.ErrEvaluationFailed, item.Validator.Purpose, item.Validator.Index, string(item.Error))
+		if !jsonValuePresent(item.Error) {
+			continue
+		}
+		if item.Validator.Index > math.MaxUint32 {
+			// A RedeemerFailure cannot hold the index; keep the failure in
+			// the message, as evaluationFailureV5 does for unparseable keys.
+			if evalErr.Message != "" {
+				evalErr.Message += "; "
+			}
+			evalErr.Message += fmt.Sprintf("%s:%d: %s",
+				item.Validator.Purpose, item.Validator.Index, failureReason(item.Error))
+			continue
 		}
+		evalErr.Failures = append(evalErr.Failures, connector.RedeemerFailure{
+			Purpose: item.Validator.Purpose,
+			Index:   uint32(item.Validator.Index),
+			Reason:  failureReason(item.Error),
+		})
+	}
+	if len(evalErr.Failures) > 0 || evalErr.Message != "" {
+		return nil, evalErr
+	}
+	result := make(map[common.RedeemerKey]common.ExUnits, len(items))
+	for _, item := range items {
+		key := fmt.Sprintf("%s:%d", item.Validator.Purpose, item.Validator.Index)
 		if item.Validator.Purpose == "" {
-			return nil, fmt.Errorf("malformed evaluation result entry: %s", evalErrorSnippet(raw))
+			if err := onKeyErr(key, fmt.Errorf("malformed evaluation result entry: %s", evalErrorSnippet(raw))); err != nil {
+				return nil, err
+			}
+			continue
 		}
 		tag, err := parseRedeemerPurpose(item.Validator.Purpose)
 		if err != nil {
-			return nil, fmt.Errorf("invalid redeemer purpose %q: %w", item.Validator.Purpose, err)
+			if err := onKeyErr(key, fmt.Errorf("invalid redeemer purpose %q: %w", item.Validator.Purpose, err)); err != nil {
+				return nil, err
+			}
+			continue
 		}
 		if item.Validator.Index > math.MaxUint32 {
-			return nil, fmt.Errorf("redeemer index %d exceeds uint32 range", item.Validator.Index)
+			if err := onKeyErr(key, fmt.Errorf("redeemer index %d exceeds uint32 range", item.Validator.Index)); err != nil {
+				return nil, err
+			}
+			continue
 		}
 		if item.Budget.Memory > math.MaxInt64 || item.Budget.Cpu > math.MaxInt64 {
 			return nil, fmt.Errorf("ExUnits overflow for validator %s:%d: memory=%d cpu=%d",
 				item.Validator.Purpose, item.Validator.Index, item.Budget.Memory, item.Budget.Cpu)
 		}
-		key := common.RedeemerKey{Tag: tag, Index: uint32(item.Validator.Index)}
-		result[key] = common.ExUnits{Memory: int64(item.Budget.Memory), Steps: int64(item.Budget.Cpu)}
+		rKey := common.RedeemerKey{Tag: tag, Index: uint32(item.Validator.Index)}
+		result[rKey] = common.ExUnits{Memory: int64(item.Budget.Memory), Steps: int64(item.Budget.Cpu)}
 	}
 	return result, nil
 }
@@ -548,7 +781,10 @@ func parseOgmiosV6EvaluationResult(raw json.RawMessage) (map[common.RedeemerKey]
 // parseOgmiosV5EvaluationResult parses the legacy Ogmios v5 jsonwsp result
 // object: {"EvaluationResult":{"tag:index":{"memory":...,"steps":...}}} or
 // {"EvaluationFailure":{...}}.
-func parseOgmiosV5EvaluationResult(raw json.RawMessage) (map[common.RedeemerKey]common.ExUnits, error) {
+func parseOgmiosV5EvaluationResult(
+	raw json.RawMessage,
+	onKeyErr func(key string, err error) error,
+) (map[common.RedeemerKey]common.ExUnits, error) {
 	var v5Result struct {
 		EvaluationResult map[string]struct {
 			Memory uint64 `json:"memory"`
@@ -560,10 +796,10 @@ func parseOgmiosV5EvaluationResult(raw json.RawMessage) (map[common.RedeemerKey]
 		return nil, fmt.Errorf("failed to parse evaluation result: %w", err)
 	}
 	if jsonValuePresent(v5Result.EvaluationFailure) {
-		return nil, fmt.Errorf("%w: %s", connector.ErrEvaluationFailed, string(v5Result.EvaluationFailure))
+		return nil, evaluationFailureV5(v5Result.EvaluationFailure, nil)
 	}
 	if v5Result.EvaluationResult == nil {
-		return nil, fmt.Errorf("unrecognized evaluate response: %s", evalErrorSnippet(raw))
+		return nil, fmt.Errorf("%w: unrecognized evaluate response: %s", connector.ErrEvaluationFailed, evalErrorSnippet(raw))
 	}
 	if len(v5Result.EvaluationResult) == 0 {
 		return nil, fmt.Errorf("%w: script evaluation returned no results", connector.ErrEvaluationFailed)
@@ -572,15 +808,24 @@ func parseOgmiosV5EvaluationResult(raw json.RawMessage) (map[common.RedeemerKey]
 	for key, budget := range v5Result.EvaluationResult {
 		parts := strings.Split(key, ":")
 		if len(parts) != 2 {
-			return nil, fmt.Errorf("malformed redeemer key %q: expected format 'tag:index'", key)
+			if err := onKeyErr(key, fmt.Errorf("malformed redeemer key %q: expected format 'tag:index'", key)); err != nil {
+				return nil, err
+			}
+			continue
 		}
 		tag, err := parseRedeemerPurpose(parts[0])
 		if err != nil {
-			return nil, fmt.Errorf("invalid redeemer tag in key %q: %w", key, err)
+			if err := onKeyErr(key, fmt.Errorf("invalid redeemer tag in key %q: %w", key, err)); err != nil {
+				return nil, err
+			}
+			continue
 		}
 		idx, err := strconv.ParseUint(parts[1], 10, 32)
 		if err != nil {
-			return nil, fmt.Errorf("invalid redeemer index %q in key %q: %w", parts[1], key, err)
+			if err := onKeyErr(key, fmt.Errorf("invalid redeemer index %q in key %q: %w", parts[1], key, err)); err != nil {
+				return nil, err
+			}
+			continue
 		}
 		rKey := common.RedeemerKey{Tag: tag, Index: uint32(idx)}
 		if budget.Memory > math.MaxInt64 || budget.Steps > math.MaxInt64 {
@@ -591,6 +836,127 @@ func parseOgmiosV5EvaluationResult(raw json.RawMessage) (map[common.RedeemerKey]
 	return result, nil
 }
 
+// evaluationError builds a *connector.EvaluationError from an evaluator
+// failure body: an Ogmios v6 JSON-RPC error, whose data may list the failing
+// validators, a legacy jsonwsp fault, or an Ogmios v5 EvaluationFailure.
+// Bodies in none of these shapes become the message as-is.
+func evaluationError(body []byte, apiErr *connector.APIError) *connector.EvaluationError {
+	var msg struct {
+		Error *struct {
+			Code    int             `json:"code"`
+			Message string          `json:"message"`
+			Data    json.RawMessage `json:"data"`
+		} `json:"error"`
+		Fault *struct {
+			Code   string `json:"code"`
+			String string `json:"string"`
+		} `json:"fault"`
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return &connector.EvaluationError{Message: evalErrorSnippet(body), API: apiErr}
+	}
+
+	switch {
+	case msg.Error != nil:
+		evalErr := &connector.EvaluationError{
+			Message: fmt.Sprintf("code %d: %s", msg.Error.Code, msg.Error.Message),
+			API:     apiErr,
+		}
+		var items []struct {
+			Validator struct {
+				Purpose string `json:"purpose"`
+				Index   uint32 `json:"index"`
+			} `json:"validator"`
+			Error json.RawMessage `json:"error"`
+		}
+		if err := json.Unmarshal(msg.Error.Data, &items); err == nil && len(items) > 0 {
+			for _, item := range items {
+				evalErr.Failures = append(evalErr.Failures, connector.RedeemerFailure{
+					Purpose: item.Validator.Purpose,
+					Index:   item.Validator.Index,
+					Reason:  failureReason(item.Error),
+				})
+			}
+		} else if jsonValuePresent(msg.Error.Data) {
+			evalErr.Message += ": " + compactJSON(msg.Error.Data)
+		}
+		return evalErr
+	case msg.Fault != nil:
+		return &connector.EvaluationError{
+			Message: fmt.Sprintf("%s fault: %s", msg.Fault.Code, msg.Fault.String),
+			API:     apiErr,
+		}
+	}
+
+	var v5 struct {
+		EvaluationFailure json.RawMessage `json:"EvaluationFailure"`
+	}
+	if err := json.Unmarshal(msg.Result, &v5); err == nil && jsonValuePresent(v5.EvaluationFailure) {
+		return evaluationFailureV5(v5.EvaluationFailure, apiErr)
+	}
+	return &connector.EvaluationError{Message: evalErrorSnippet(body), API: apiErr}
+}
+
+// evaluationFailureV5 builds a *connector.EvaluationError from an Ogmios v5
+// EvaluationFailure, e.g. {"ScriptFailures":{"spend:0":[...]}} or
+// {"UnknownInputs":[...]}.
+func evaluationFailureV5(raw json.RawMessage, apiErr *connector.APIError) *connector.EvaluationError {
+	var failure struct {
+		ScriptFailures map[string]json.RawMessage `json:"ScriptFailures"`
+	}
+	if err := json.Unmarshal(raw, &failure); err != nil || len(failure.ScriptFailures) == 0 {
+		return &connector.EvaluationError{Message: compactJSON(raw), API: apiErr}
+	}
+
+	evalErr := &connector.EvaluationError{Message: "ScriptFailures", API: apiErr}
+	keys := make([]string, 0, len(failure.ScriptFailures))
+	for key := range failure.ScriptFailures {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		purpose, index, _ := strings.Cut(key, ":")
+		idx, err := strconv.ParseUint(index, 10, 32)
+		if err != nil {
+			evalErr.Message += "; " + key + ": " + compactJSON(failure.ScriptFailures[key])
+			continue
+		}
+		evalErr.Failures = append(evalErr.Failures, connector.RedeemerFailure{
+			Purpose: purpose,
+			Index:   uint32(idx),
+			Reason:  compactJSON(failure.ScriptFailures[key]),
+		})
+	}
+	return evalErr
+}
+
+// failureReason renders a validator error: its message and data when it is an
+// Ogmios error object, or the compacted JSON otherwise.
+func failureReason(raw json.RawMessage) string {
+	var obj struct {
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil || obj.Message == "" {
+		return compactJSON(raw)
+	}
+	if jsonValuePresent(obj.Data) {
+		return obj.Message + ": " + compactJSON(obj.Data)
+	}
+	return obj.Message
+}
+
+// compactJSON strips insignificant whitespace from raw, bounded like
+// evalErrorSnippet.
+func compactJSON(raw json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return evalErrorSnippet(raw)
+	}
+	return evalErrorSnippet(buf.Bytes())
+}
+
 // evalErrorSnippet bounds a response payload for inclusion in error messages.
 func evalErrorSnippet(data []byte) string {
 	const maxSnippet = 512
@@ -0,0 +1,80 @@
+package blockfrost
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/tj/assert"
+)
+
+// syntheticUtxos returns n UTxOs at testAddr; every tenth also holds a native
+// asset, roughly the mix of a busy wallet address.
+func syntheticUtxos(n int) []bfAddressUTxO {
+	utxos := make([]bfAddressUTxO, n)
+	for i := range utxos {
+		utxos[i] = bfAddressUTxO{
+			Address:     testAddr,
+			TxHash:      fmt.Sprintf("%064x", i+1),
+			OutputIndex: i % 4,
+			Amount:      []bfAddressAmount{{Unit: "lovelace", Quantity: "1500000"}},
+		}
+		if i%10 == 0 {
+			utxos[i].Amount = append(utxos[i].Amount, bfAddressAmount{Unit: quantityPolicy + quantityName, Quantity: "7"})
+		}
+	}
+	return utxos
+}
+
+func TestToUtxoRejectsMalformedTxHash(t *testing.T) {
+	address, err := common.NewAddress(testAddr)
+	assert.NoError(t, err)
+	for _, hash := range []string{"", strings.Repeat("ab", 31), strings.Repeat("ab", 33), strings.Repeat("zz", 32)} {
+		raw := syntheticUtxos(1)[0]
+		raw.TxHash = hash
+		_, err := raw.toUtxo(address)
+		assert.Error(t, err, "hash %q", hash)
+	}
+}
+
+func TestToUtxoLovelaceOnlySkipsAssets(t *testing.T) {
+	address, err := common.NewAddress(testAddr)
+	assert.NoError(t, err)
+	utxos := syntheticUtxos(2)
+
+	withAssets, err := utxos[0].toUtxo(address)
+	assert.NoError(t, err)
+	assert.NotNil(t, withAssets.Output.Assets())
+	lovelaceOnly, err := utxos[1].toUtxo(address)
+	assert.NoError(t, err)
+	assert.Nil(t, lovelaceOnly.Output.Assets())
+
+	allocs := func(raw bfAddressUTxO) float64 {
+		return testing.AllocsPerRun(100, func() { _, _ = raw.toUtxo(address) })
+	}
+	assert.Less(t, allocs(utxos[1]), allocs(utxos[0]))
+}
+
+// BenchmarkToUtxo adapts a 5000-UTxO listing; compare allocs/op across
+// changes with benchstat.
+func BenchmarkToUtxo(b *testing.B) {
+	address, err := common.NewAddress(testAddr)
+	if err != nil {
+		b.Fatal(err)
+	}
+	raws := syntheticUtxos(5000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		utxos := make([]common.Utxo, 0, len(raws))
+		for j := range raws {
+			utxo, err := raws[j].toUtxo(address)
+			if err != nil {
+				b.Fatal(err)
+			}
+			utxos = append(utxos, utxo)
+		}
+	}
+}
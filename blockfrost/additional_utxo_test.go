@@ -1,9 +1,13 @@
 package blockfrost
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
+	"io"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -13,6 +17,7 @@ import (
 	"github.com/blinklabs-io/gouroboros/ledger/mary"
 	"github.com/blinklabs-io/gouroboros/ledger/shelley"
 	"github.com/tj/assert"
+	"github.com/zenGate-Global/cardano-connector-go/tests"
 )
 
 const testAddr = "addr_test1wpgexmeunzsykesf42d4eqet5yvzeap6trjnflxqtkcf66g0kpnxt"
@@ -118,19 +123,59 @@ func TestBuildAdditionalUtxoItemEmitsRawScriptBytes(t *testing.T) {
 	assert.Equal(t, hex.EncodeToString(rawScriptBytes), *sr.PlutusV3)
 }
 
-func TestBuildAdditionalUtxoItemNativeScriptUnsupported(t *testing.T) {
-	// A native reference script cannot be encoded for the Ogmios v5 evaluation
-	// endpoint and must be rejected.
+func TestBuildAdditionalUtxoItemNativeScriptSkipped(t *testing.T) {
+	// A native reference script has nothing to evaluate and is not representable
+	// in the Ogmios v5 script schema, so it is omitted instead of failing the
+	// whole evaluation.
 	native := common.NativeScript{}
 	ref := &common.ScriptRef{Type: common.ScriptRefTypeNativeScript, Script: native}
 
 	utxo := buildUtxoWithScriptRef(t, ref)
-	_, err := bfAdditionalUtxoItemFromUtxo(utxo)
-	assert.Error(t, err)
-	assert.True(t,
-		strings.Contains(err.Error(), "unsupported script type"),
-		"error should mention unsupported script type, got: %v", err,
-	)
+	item, err := bfAdditionalUtxoItemFromUtxo(utxo)
+	assert.NoError(t, err)
+	assert.Nil(t, extractScriptRef(t, item))
+
+	js, err := json.Marshal(item)
+	assert.NoError(t, err)
+	assert.False(t, strings.Contains(string(js), `"script"`),
+		"native script ref must be omitted, got: %s", string(js))
+}
+
+// TestEvaluateTxSendsPlutusV3ReferenceScript drives EvaluateTx against a mock
+// server and asserts the additionalUtxoSet carries the V3 reference script
+// under "plutus:v3" with its raw bytes.
+func TestEvaluateTxSendsPlutusV3ReferenceScript(t *testing.T) {
+	scriptCbor, err := hex.DecodeString(tests.ExpectedScriptCbor)
+	assert.NoError(t, err)
+	ref := &common.ScriptRef{Type: common.ScriptRefTypePlutusV3, Script: common.PlutusV3Script(scriptCbor)}
+
+	var gotPath string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotBody, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{"result":[{"validator":{"purpose":"spend","index":0},"budget":{"memory":1700,"cpu":476468}}]}`))
+	}))
+	defer srv.Close()
+
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	units, err := provider.EvaluateTx(context.Background(), []byte{0x84}, []common.Utxo{buildUtxoWithScriptRef(t, ref)})
+	assert.NoError(t, err)
+	assert.Equal(t, "/utils/txs/evaluate/utxos", gotPath)
+	assert.Equal(t, common.ExUnits{Memory: 1700, Steps: 476468}, units[common.RedeemerKey{Tag: common.RedeemerTagSpend, Index: 0}])
+
+	var req struct {
+		AdditionalUtxoSet [][2]json.RawMessage `json:"additionalUtxoSet"`
+	}
+	assert.NoError(t, json.Unmarshal(gotBody, &req))
+	assert.Len(t, req.AdditionalUtxoSet, 1)
+	var txOut struct {
+		Script map[string]string `json:"script"`
+	}
+	assert.NoError(t, json.Unmarshal(req.AdditionalUtxoSet[0][1], &txOut))
+	assert.Equal(t, map[string]string{"plutus:v3": tests.ExpectedScriptCbor}, txOut.Script)
 }
 
 func TestBuildAdditionalUtxoItemDatumHash(t *testing.T) {
@@ -213,8 +258,8 @@ func TestBuildAdditionalUtxoItemValueShape(t *testing.T) {
 	out2, ok := item[1].(bfTxOut)
 	assert.True(t, ok)
 	// Ogmios-v6 value: lovelace under "ada", assets nested under the policy id hex.
-	assert.Equal(t, int64(2_000_000), out2.Value["ada"]["lovelace"])
-	assert.Equal(t, int64(42), out2.Value[policyHex][assetNameHex])
+	assert.Equal(t, uint64(2_000_000), out2.Value["ada"]["lovelace"])
+	assert.Equal(t, uint64(42), out2.Value[policyHex][assetNameHex])
 
 	js, err := json.Marshal(out2)
 	assert.NoError(t, err)
@@ -232,3 +277,42 @@ func mustDecodeHexT(t *testing.T, s string) []byte {
 	assert.NoError(t, err)
 	return b
 }
+
+// TestEvaluateTxSendsExactInlineDatumCbor asserts that an additional UTxO's
+// inline datum is forwarded as the raw PlutusData CBOR it was decoded from
+// (here a definite-length constructor), not the datum option wrapper or a
+// re-encoding.
+func TestEvaluateTxSendsExactInlineDatumCbor(t *testing.T) {
+	const datumHex = "d87982410001" // Constr 0 [h'00', 1], definite-length
+
+	opt, err := inlineDatumOptionFromBlockfrost(json.RawMessage(`"` + datumHex + `"`))
+	assert.NoError(t, err)
+	utxo := buildUtxoWithScriptRef(t, nil)
+	utxo.Output.(*babbage.BabbageTransactionOutput).DatumOption = opt
+
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{"result":[{"validator":{"purpose":"spend","index":0},"budget":{"memory":1,"cpu":1}}]}`))
+	}))
+	defer srv.Close()
+
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+	_, err = provider.EvaluateTx(context.Background(), []byte{0x84}, []common.Utxo{utxo})
+	assert.NoError(t, err)
+
+	var req struct {
+		AdditionalUtxoSet [][2]json.RawMessage `json:"additionalUtxoSet"`
+	}
+	assert.NoError(t, json.Unmarshal(gotBody, &req))
+	assert.Len(t, req.AdditionalUtxoSet, 1)
+	var txOut struct {
+		Datum     *string `json:"datum"`
+		DatumHash *string `json:"datumHash"`
+	}
+	assert.NoError(t, json.Unmarshal(req.AdditionalUtxoSet[0][1], &txOut))
+	assert.NotNil(t, txOut.Datum)
+	assert.Equal(t, datumHex, *txOut.Datum)
+	assert.Nil(t, txOut.DatumHash, "datum and datumHash are mutually exclusive")
+}
@@ -0,0 +1,190 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Salvionied/apollo/v2/backend"
+	"github.com/blinklabs-io/gouroboros/cbor"
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/blinklabs-io/gouroboros/ledger/mary"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+var (
+	_ connector.BalanceProvider             = (*BlockfrostProvider)(nil)
+	_ connector.StakeAddressResolver        = (*BlockfrostProvider)(nil)
+	_ connector.AddressTransactionsProvider = (*BlockfrostProvider)(nil)
+)
+
+// GetStakeAddressOfAddress returns the stake address owning addr as reported
+// by /addresses/{address}, which also resolves pointer addresses. It is empty
+// for enterprise addresses; addresses Blockfrost has never seen yield
+// connector.ErrNotFound.
+func (b *BlockfrostProvider) GetStakeAddressOfAddress(ctx context.Context, addr string) (string, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+	var info bfAddressInfo
+	if err := b.doRequest(ctx, "GET", "/addresses/"+addr, nil, &info); err != nil {
+		return "", fmt.Errorf("failed to get stake address of %s: %w", addr, err)
+	}
+	if info.StakeAddress == nil {
+		return "", nil
+	}
+	return *info.StakeAddress, nil
+}
+
+// GetBalance returns the balance of addr from Blockfrost's per-address totals,
+// which costs one request regardless of how many UTxOs the address holds. An
+// address Blockfrost has never seen has a zero balance. When the endpoint
+// fails for another reason, e.g. a self-hosted backend that does not serve it,
+// the balance is summed from the address's UTxOs instead.
+func (b *BlockfrostProvider) GetBalance(
+	ctx context.Context,
+	addr string,
+) (mary.MaryTransactionOutputValue, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+
+	var info bfAddressInfo
+	err := b.doRequest(ctx, "GET", "/addresses/"+addr, nil, &info)
+	switch {
+	case err == nil:
+		return valueFromAmounts(info.Amount)
+	case errors.Is(err, connector.ErrNotFound):
+		return mary.MaryTransactionOutputValue{}, nil
+	case errors.Is(err, connector.ErrRateLimited), errors.Is(err, connector.ErrTimeout), ctx.Err() != nil:
+		return mary.MaryTransactionOutputValue{}, fmt.Errorf("failed to get balance of %s: %w", addr, err)
+	}
+
+	utxos, sumErr := b.GetUtxosByAddress(ctx, addr)
+	if sumErr != nil {
+		return mary.MaryTransactionOutputValue{}, fmt.Errorf(
+			"failed to get balance of %s: %w",
+			addr,
+			errors.Join(err, sumErr),
+		)
+	}
+	return sumUtxoValues(utxos), nil
+}
+
+// valueFromAmounts converts a Blockfrost amount list into a value, splitting
+// asset units into policy id and asset name as toUtxo does. Asset totals are
+// kept as big integers since they are not bounded by a single output.
+func valueFromAmounts(amounts []bfAddressAmount) (mary.MaryTransactionOutputValue, error) {
+	var lovelace uint64
+	assetData := make(map[common.Blake2b224]map[cbor.ByteString]*big.Int)
+	for _, amt := range amounts {
+		if amt.Unit == "lovelace" {
+			qty, err := parseQuantity(amt.Quantity)
+			if err != nil {
+				return mary.MaryTransactionOutputValue{}, fmt.Errorf("invalid lovelace quantity: %w", err)
+			}
+			lovelace = qty
+			continue
+		}
+		if len(amt.Unit) < 56 {
+			return mary.MaryTransactionOutputValue{}, fmt.Errorf(
+				"unrecognized unit format %q: expected \"lovelace\" or hex string >= 56 chars (policy_id + asset_name)",
+				amt.Unit,
+			)
+		}
+		qty, ok := new(big.Int).SetString(amt.Quantity, 10)
+		if !ok || qty.Sign() < 0 {
+			return mary.MaryTransactionOutputValue{}, fmt.Errorf(
+				"invalid asset quantity %q for unit %s",
+				amt.Quantity,
+				amt.Unit,
+			)
+		}
+		policyId, assetName, err := backend.ParseAssetUnit(amt.Unit)
+		if err != nil {
+			return mary.MaryTransactionOutputValue{}, fmt.Errorf("invalid asset unit %q: %w", amt.Unit, err)
+		}
+		if _, ok := assetData[policyId]; !ok {
+			assetData[policyId] = make(map[cbor.ByteString]*big.Int)
+		}
+		assetData[policyId][assetName] = qty
+	}
+
+	value := mary.MaryTransactionOutputValue{Amount: lovelace}
+	if len(assetData) > 0 {
+		ma := common.NewMultiAsset[common.MultiAssetTypeOutput](assetData)
+		value.Assets = &ma
+	}
+	return value, nil
+}
+
+// sumUtxoValues adds up the lovelace and native assets of utxos.
+func sumUtxoValues(utxos []common.Utxo) mary.MaryTransactionOutputValue {
+	var lovelace uint64
+	assetData := make(map[common.Blake2b224]map[cbor.ByteString]*big.Int)
+	for _, utxo := range utxos {
+		lovelace += utxo.Output.Amount().Uint64()
+		assets := utxo.Output.Assets()
+		if assets == nil {
+			continue
+		}
+		for _, policyId := range assets.Policies() {
+			if _, ok := assetData[policyId]; !ok {
+				assetData[policyId] = make(map[cbor.ByteString]*big.Int)
+			}
+			for _, name := range assets.Assets(policyId) {
+				assetName := cbor.NewByteString(name)
+				total, ok := assetData[policyId][assetName]
+				if !ok {
+					total = new(big.Int)
+					assetData[policyId][assetName] = total
+				}
+				total.Add(total, assets.Asset(policyId, name))
+			}
+		}
+	}
+
+	value := mary.MaryTransactionOutputValue{Amount: lovelace}
+	if len(assetData) > 0 {
+		ma := common.NewMultiAsset[common.MultiAssetTypeOutput](assetData)
+		value.Assets = &ma
+	}
+	return value
+}
+
+// bfAddressTransaction is an entry of /addresses/{address}/transactions.
+type bfAddressTransaction struct {
+	TxHash      string `json:"tx_hash"`
+	TxIndex     int    `json:"tx_index"`
+	BlockHeight uint64 `json:"block_height"`
+	BlockTime   int64  `json:"block_time"`
+}
+
+// GetAddressTransactions returns the transactions involving addr, honouring
+// connector.WithOrder and connector.WithFromTo. When Config.MaxPages cuts the
+// list short, the transactions fetched so far are returned with an error
+// wrapping connector.ErrTruncated.
+func (b *BlockfrostProvider) GetAddressTransactions(
+	ctx context.Context,
+	addr string,
+	opts ...connector.QueryOption,
+) ([]connector.AddressTransaction, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+	if _, err := common.NewAddress(addr); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", connector.ErrInvalidAddress, addr, err)
+	}
+	po, err := queryPageOptions(opts, true)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, pageErr := paginateWith[bfAddressTransaction](ctx, b, "/addresses/"+addr+"/transactions", po)
+	if pageErr != nil && !errors.Is(pageErr, connector.ErrTruncated) {
+		return nil, fmt.Errorf("failed to get transactions of %s: %w", addr, pageErr)
+	}
+	txs := make([]connector.AddressTransaction, 0, len(entries))
+	for _, entry := range entries {
+		txs = append(txs, connector.AddressTransaction(entry))
+	}
+	return txs, pageErr
+}
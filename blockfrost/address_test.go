@@ -0,0 +1,51 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+const (
+	stakeBaseAddr       = "addr_test1qpycxt9d7gel0k4mnvaf2kkv0ggmxm57xw4v6dz2krd7anxr5hkplfmepxykzl4c30vy4k9xufmmn8s7jrukzvyclv0shr5358"
+	stakeEnterpriseAddr = "addr_test1wqq8ywv7jnzf0uql4s29f6cgzqrcpy0sjn560wjdh0av4fccp0zj6"
+)
+
+func TestGetStakeAddressOfAddress(t *testing.T) {
+	fixtures := offlineFixtures()
+	fixtures.Routes["/addresses/"+stakeBaseAddr] = `{
+		"address": "` + stakeBaseAddr + `",
+		"amount": [{"unit": "lovelace", "quantity": "1000000"}],
+		"stake_address": "` + offlineStakeAddr + `",
+		"type": "shelley",
+		"script": false
+	}`
+	fixtures.Routes["/addresses/"+stakeEnterpriseAddr] = `{
+		"address": "` + stakeEnterpriseAddr + `",
+		"amount": [{"unit": "lovelace", "quantity": "1000000"}],
+		"stake_address": null,
+		"type": "shelley",
+		"script": true
+	}`
+	bf, _ := newOfflineBlockfrost(t, fixtures, Config{})
+	ctx := context.Background()
+
+	stakeAddr, err := bf.GetStakeAddressOfAddress(ctx, stakeBaseAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, offlineStakeAddr, stakeAddr)
+
+	stakeAddr, err = bf.GetStakeAddressOfAddress(ctx, stakeEnterpriseAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, "", stakeAddr)
+
+	_, err = bf.GetStakeAddressOfAddress(ctx, balanceAddr)
+	assert.True(t, errors.Is(err, connector.ErrNotFound), "got %v", err)
+
+	// The root helper defers to the provider.
+	stakeAddr, err = connector.StakeAddressOf(ctx, bf, stakeBaseAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, offlineStakeAddr, stakeAddr)
+}
@@ -0,0 +1,73 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Salvionied/apollo/v2/backend"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+var _ connector.AssetHistoryProvider = (*BlockfrostProvider)(nil)
+
+// bfAssetHistory is an entry of /assets/{unit}/history. Amount is unsigned;
+// Action tells mints from burns.
+type bfAssetHistory struct {
+	TxHash string `json:"tx_hash"`
+	Amount string `json:"amount"`
+	Action string `json:"action"`
+}
+
+// GetAssetHistory returns every mint and burn of unit, oldest first whatever
+// Config.Order says unless connector.WithOrder is given. Blockfrost cannot
+// range asset history by block, so connector.WithFromTo is ignored. Burns
+// carry a negative quantity. When Config.MaxPages cuts the history short, the
+// actions fetched so far are returned with an error wrapping
+// connector.ErrTruncated.
+func (b *BlockfrostProvider) GetAssetHistory(
+	ctx context.Context,
+	unit string,
+	opts ...connector.QueryOption,
+) ([]connector.AssetAction, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+	if _, _, err := backend.ParseAssetUnit(unit); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", connector.ErrInvalidUnit, unit, err)
+	}
+	po, err := queryPageOptions(opts, false)
+	if err != nil {
+		return nil, err
+	}
+	if po.order == "" {
+		po.order = string(connector.OrderAsc)
+	}
+	po.notFoundErr = true
+
+	history, pageErr := paginateWith[bfAssetHistory](ctx, b, "/assets/"+unit+"/history", po)
+	if pageErr != nil && !errors.Is(pageErr, connector.ErrTruncated) {
+		return nil, fmt.Errorf("failed to get history of %s: %w", unit, pageErr)
+	}
+
+	actions := make([]connector.AssetAction, 0, len(history))
+	for _, entry := range history {
+		qty, ok := new(big.Int).SetString(entry.Amount, 10)
+		if !ok || qty.Sign() < 0 {
+			return nil, fmt.Errorf("invalid amount %q in history of %s at tx %s", entry.Amount, unit, entry.TxHash)
+		}
+		switch entry.Action {
+		case connector.AssetActionMinted:
+		case connector.AssetActionBurned:
+			qty.Neg(qty)
+		default:
+			return nil, fmt.Errorf("unknown action %q in history of %s at tx %s", entry.Action, unit, entry.TxHash)
+		}
+		actions = append(actions, connector.AssetAction{
+			TxHash:   entry.TxHash,
+			Action:   entry.Action,
+			Quantity: qty,
+		})
+	}
+	return actions, pageErr
+}
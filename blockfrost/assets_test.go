@@ -0,0 +1,93 @@
+package blockfrost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+const historyUnit = "c0ffee00c0ffee00c0ffee00c0ffee00c0ffee00c0ffee00c0ffee00746f6b656e"
+
+// historyFixture is the oldest-first history of historyUnit.
+var historyFixture = []bfAssetHistory{
+	{TxHash: strings.Repeat("01", 32), Amount: "100", Action: "minted"},
+	{TxHash: strings.Repeat("02", 32), Amount: "30", Action: "burned"},
+	{TxHash: strings.Repeat("03", 32), Amount: "18446744073709551616", Action: "minted"},
+	{TxHash: strings.Repeat("04", 32), Amount: "75", Action: "burned"},
+	{TxHash: strings.Repeat("05", 32), Amount: "1", Action: "minted"},
+}
+
+// newHistoryServer pages historyFixture for historyUnit honouring count, page
+// and order, and answers 404 for any other unit.
+func newHistoryServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/assets/"+historyUnit+"/history" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"status_code":404,"error":"Not Found","message":"The requested component has not been found."}`))
+			return
+		}
+		query := r.URL.Query()
+		count, _ := strconv.Atoi(query.Get("count"))
+		page, _ := strconv.Atoi(query.Get("page"))
+		entries := slices.Clone(historyFixture)
+		if query.Get("order") == "desc" {
+			slices.Reverse(entries)
+		}
+		start := min((page-1)*count, len(entries))
+		_ = json.NewEncoder(w).Encode(entries[start:min(start+count, len(entries))])
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGetAssetHistory(t *testing.T) {
+	srv := newHistoryServer(t)
+	for _, order := range []string{"", "asc", "desc"} {
+		provider, err := New(Config{BaseURL: srv.URL, PageSize: 2, Order: order})
+		assert.NoError(t, err)
+
+		actions, err := provider.GetAssetHistory(context.Background(), historyUnit)
+		assert.NoError(t, err)
+		assert.Len(t, actions, len(historyFixture))
+
+		want := []string{"100", "-30", "18446744073709551616", "-75", "1"}
+		for i, action := range actions {
+			assert.Equal(t, historyFixture[i].TxHash, action.TxHash, "order %q", order)
+			assert.Equal(t, historyFixture[i].Action, action.Action)
+			assert.Equal(t, want[i], action.Quantity.String())
+		}
+	}
+}
+
+func TestGetAssetHistoryUnknownUnit(t *testing.T) {
+	srv := newHistoryServer(t)
+	provider, err := New(Config{BaseURL: srv.URL})
+	assert.NoError(t, err)
+
+	unknown := strings.Replace(historyUnit, "c0ffee00", "deadbeef", 1)
+	_, err = provider.GetAssetHistory(context.Background(), unknown)
+	assert.True(t, errors.Is(err, connector.ErrNotFound), "got %v", err)
+
+	_, err = provider.GetAssetHistory(context.Background(), "lovelace")
+	assert.True(t, errors.Is(err, connector.ErrInvalidUnit), "got %v", err)
+}
+
+func TestGetAssetHistoryTruncated(t *testing.T) {
+	srv := newHistoryServer(t)
+	provider, err := New(Config{BaseURL: srv.URL, PageSize: 2, MaxPages: 1})
+	assert.NoError(t, err)
+
+	actions, err := provider.GetAssetHistory(context.Background(), historyUnit)
+	assert.True(t, errors.Is(err, connector.ErrTruncated), "got %v", err)
+	assert.Len(t, actions, 2)
+}
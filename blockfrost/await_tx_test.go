@@ -0,0 +1,84 @@
+package blockfrost
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+)
+
+// awaitPoll is what the mock server reports for one AwaitTx poll: the height
+// of the block holding the tx (0 when the tx is unknown) and the tip height.
+type awaitPoll struct {
+	txHeight  uint64
+	tipHeight uint64
+}
+
+// newAwaitTxServer replays polls in order, repeating the last one once they are
+// exhausted. The tip is served from the poll that last looked up the tx.
+func newAwaitTxServer(t *testing.T, polls []awaitPoll, txLookups *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/txs/"):
+			n := int(txLookups.Add(1))
+			poll := polls[min(n, len(polls))-1]
+			if poll.txHeight == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"status_code":404,"error":"Not Found","message":"The requested component has not been found."}`))
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"hash":"abc","block":"block%d","block_height":%d}`, poll.txHeight, poll.txHeight)
+		case r.URL.Path == "/blocks/latest":
+			n := int(txLookups.Load())
+			poll := polls[min(n, len(polls))-1]
+			_, _ = fmt.Fprintf(w, `{"height":%d,"hash":"tip%d","slot":%d}`, poll.tipHeight, poll.tipHeight, poll.tipHeight*20)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAwaitTxConfirmsOnFirstBlockByDefault(t *testing.T) {
+	var lookups atomic.Int32
+	srv := newAwaitTxServer(t, []awaitPoll{{txHeight: 10, tipHeight: 10}}, &lookups)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	start := time.Now()
+	ok, err := provider.AwaitTx(context.Background(), "abc", 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int32(1), lookups.Load())
+	assert.True(t, time.Since(start) < 500*time.Millisecond, "AwaitTx should not sleep after confirmation")
+}
+
+func TestAwaitTxWaitsThroughRollback(t *testing.T) {
+	var lookups atomic.Int32
+	srv := newAwaitTxServer(t, []awaitPoll{
+		{txHeight: 10, tipHeight: 10}, // included, but only one block deep
+		{txHeight: 0, tipHeight: 10},  // rolled back
+		{txHeight: 12, tipHeight: 12}, // re-included in a later block
+		{txHeight: 12, tipHeight: 13}, // two blocks deep
+	}, &lookups)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test", ConfirmationDepth: 2})
+	assert.NoError(t, err)
+
+	ok, err := provider.AwaitTx(context.Background(), "abc", 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int32(4), lookups.Load())
+}
+
+func TestAwaitTxRejectsNegativeConfirmationDepth(t *testing.T) {
+	_, err := New(Config{BaseURL: "http://localhost", ConfirmationDepth: -1})
+	assert.Error(t, err)
+}
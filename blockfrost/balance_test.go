@@ -0,0 +1,117 @@
+package blockfrost
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Salvionied/apollo/v2/backend"
+	"github.com/blinklabs-io/gouroboros/ledger/mary"
+	"github.com/tj/assert"
+)
+
+const (
+	balanceAddr    = "addr_test1wrzqlyffcf5yq3htqge9h9k29zv6d7ny0rqam6d4c5eqdfgg0h7yw"
+	balancePolicyA = "aaaa00000000000000000000000000000000000000000000000000aa"
+	balancePolicyB = "bbbb00000000000000000000000000000000000000000000000000bb"
+)
+
+func balanceUtxos() string {
+	return `[
+		{
+			"address": "` + balanceAddr + `",
+			"tx_hash": "` + strings.Repeat("01", 32) + `",
+			"output_index": 0,
+			"amount": [
+				{"unit": "lovelace", "quantity": "3000000"},
+				{"unit": "` + balancePolicyA + `74657374", "quantity": "5"},
+				{"unit": "` + balancePolicyA + `", "quantity": "9223372036854775807"}
+			],
+			"block": "` + strings.Repeat("0a", 32) + `",
+			"data_hash": null,
+			"inline_datum": null,
+			"reference_script_hash": null
+		},
+		{
+			"address": "` + balanceAddr + `",
+			"tx_hash": "` + strings.Repeat("02", 32) + `",
+			"output_index": 1,
+			"amount": [
+				{"unit": "lovelace", "quantity": "1500000"},
+				{"unit": "` + balancePolicyA + `", "quantity": "9223372036854775807"},
+				{"unit": "` + balancePolicyB + `6e6674", "quantity": "1"}
+			],
+			"block": "` + strings.Repeat("0b", 32) + `",
+			"data_hash": null,
+			"inline_datum": null,
+			"reference_script_hash": null
+		}
+	]`
+}
+
+func assertBalance(t *testing.T, value mary.MaryTransactionOutputValue) {
+	t.Helper()
+	assert.Equal(t, uint64(4_500_000), value.Amount)
+	if !assert.NotNil(t, value.Assets) {
+		return
+	}
+	for unit, want := range map[string]string{
+		balancePolicyA + "74657374": "5",
+		balancePolicyA:              "18446744073709551614",
+		balancePolicyB + "6e6674":   "1",
+	} {
+		policyId, assetName, err := backend.ParseAssetUnit(unit)
+		assert.NoError(t, err)
+		assert.Equal(t, want, value.Assets.Asset(policyId, assetName.Bytes()).String(), unit)
+	}
+	assert.Len(t, value.Assets.Policies(), 2)
+}
+
+func TestGetBalance(t *testing.T) {
+	fixtures := offlineFixtures()
+	fixtures.AddressUtxos[balanceAddr] = balanceUtxos()
+	bf, srv := newOfflineBlockfrost(t, fixtures, Config{})
+
+	value, err := bf.GetBalance(context.Background(), balanceAddr)
+	assert.NoError(t, err)
+	assertBalance(t, value)
+	assert.Equal(t, 0, srv.Requests("/addresses/"+balanceAddr+"/utxos"))
+}
+
+func TestGetBalanceUnknownAddress(t *testing.T) {
+	bf, _ := newOfflineBlockfrost(t, offlineFixtures(), Config{})
+
+	value, err := bf.GetBalance(context.Background(), balanceAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), value.Amount)
+	assert.Nil(t, value.Assets)
+}
+
+func TestGetBalanceFallsBackToUtxos(t *testing.T) {
+	fixtures := offlineFixtures()
+	fixtures.AddressUtxos[balanceAddr] = balanceUtxos()
+	bf, srv := newOfflineBlockfrost(t, fixtures, Config{})
+
+	// Stand in for a backend that does not serve /addresses/{address}.
+	target, err := url.Parse(srv.URL)
+	assert.NoError(t, err)
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	unsupported := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/addresses/"+balanceAddr {
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		proxy.ServeHTTP(w, r)
+	}))
+	t.Cleanup(unsupported.Close)
+	bf.baseURL = unsupported.URL
+
+	value, err := bf.GetBalance(context.Background(), balanceAddr)
+	assert.NoError(t, err)
+	assertBalance(t, value)
+	assert.Equal(t, 1, srv.Requests("/addresses/"+balanceAddr+"/utxos"))
+}
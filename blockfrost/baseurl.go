@@ -0,0 +1,56 @@
+package blockfrost
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// resolveBaseURL builds the API root from Config.BaseURL, or the hosted
+// Blockfrost URL for networkName when it is empty, and Config.APIVersionPath.
+// BaseURL is used verbatim apart from trailing slashes: the hosted API needs
+// its /api/v0 suffix spelled out, as does a self-hosted backend's prefix
+// (e.g. Yaci Store's /api/v1) or a reverse-proxy path.
+func resolveBaseURL(baseURL, apiVersionPath, networkName string) (string, error) {
+	if baseURL == "" {
+		switch networkName {
+		case "mainnet":
+			baseURL = defaultMainnetBaseURL
+		case "preprod":
+			baseURL = defaultPreprodBaseURL
+		case "preview":
+			baseURL = defaultPreviewBaseURL
+		default:
+			return "", fmt.Errorf(
+				"unsupported or missing network name: %s, and no BaseURL provided",
+				networkName,
+			)
+		}
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid base URL %q: missing host", baseURL)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("invalid base URL %q: must not contain a query or fragment", baseURL)
+	}
+
+	return joinURLPath(baseURL, apiVersionPath), nil
+}
+
+// joinURLPath joins base and path with exactly one slash between them.
+func joinURLPath(base, path string) string {
+	base = strings.TrimRight(base, "/")
+	path = strings.TrimLeft(path, "/")
+	if path == "" {
+		return base
+	}
+	return base + "/" + path
+}
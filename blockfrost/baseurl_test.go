@@ -0,0 +1,75 @@
+package blockfrost
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestResolveBaseURL(t *testing.T) {
+	cases := []struct {
+		name           string
+		baseURL        string
+		apiVersionPath string
+		networkName    string
+		want           string
+	}{
+		{"hosted default", "", "", "preprod", "https://cardano-preprod.blockfrost.io/api/v0"},
+		{"hosted with /v0", "https://cardano-mainnet.blockfrost.io/api/v0", "", "", "https://cardano-mainnet.blockfrost.io/api/v0"},
+		{"hosted without /v0", "https://cardano-mainnet.blockfrost.io/api", "v0", "", "https://cardano-mainnet.blockfrost.io/api/v0"},
+		{"trailing slashes", "https://cardano-mainnet.blockfrost.io/api/", "/v0/", "", "https://cardano-mainnet.blockfrost.io/api/v0"},
+		{"blockfrost ryo", "http://localhost:3000", "", "", "http://localhost:3000"},
+		{"yaci store", "http://localhost:8080/api/v1", "", "", "http://localhost:8080/api/v1"},
+		{"yaci store version path", "http://localhost:8080", "/api/v1", "", "http://localhost:8080/api/v1"},
+		{"reverse proxy", "https://proxy.example.com/cardano/blockfrost", "/api/v0", "", "https://proxy.example.com/cardano/blockfrost/api/v0"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := resolveBaseURL(tc.baseURL, tc.apiVersionPath, tc.networkName)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestResolveBaseURLRejectsInvalid(t *testing.T) {
+	for _, baseURL := range []string{
+		"localhost:3000",
+		"ftp://example.com",
+		"https://",
+		"https://example.com/api?project_id=x",
+		"://bad",
+	} {
+		_, err := resolveBaseURL(baseURL, "", "")
+		assert.Error(t, err, baseURL)
+	}
+
+	_, err := resolveBaseURL("", "", "sanchonet")
+	assert.Error(t, err)
+}
+
+func TestDoRequestJoinsProxyPath(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cardano/api/v1/epochs/latest" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		hits.Add(1)
+		_, _ = w.Write([]byte(`{"epoch": 7}`))
+	}))
+	defer srv.Close()
+
+	provider, err := New(Config{BaseURL: srv.URL + "/cardano/", APIVersionPath: "/api/v1/"})
+	assert.NoError(t, err)
+
+	epoch, err := provider.Epoch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 7, epoch)
+	assert.Equal(t, int32(1), hits.Load())
+}
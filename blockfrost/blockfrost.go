@@ -9,12 +9,15 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math/big"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Salvionied/apollo/v2/backend"
+	"github.com/blinklabs-io/gouroboros/cbor"
 	"github.com/blinklabs-io/gouroboros/ledger/babbage"
 	"github.com/blinklabs-io/gouroboros/ledger/common"
 	connector "github.com/zenGate-Global/cardano-connector-go"
@@ -24,53 +27,163 @@ const (
 	defaultMainnetBaseURL = "https://cardano-mainnet.blockfrost.io/api/v0"
 	defaultPreprodBaseURL = "https://cardano-preprod.blockfrost.io/api/v0"
 	defaultPreviewBaseURL = "https://cardano-preview.blockfrost.io/api/v0"
+
+	// maxPageSize is the largest count= Blockfrost accepts on paginated endpoints.
+	maxPageSize = 100
 )
 
-var _ connector.Provider = (*BlockfrostProvider)(nil)
+var (
+	_ connector.Provider          = (*BlockfrostProvider)(nil)
+	_ connector.UtxoQueryProvider = (*BlockfrostProvider)(nil)
+)
 
 func New(config Config) (*BlockfrostProvider, error) {
 	httpClient := config.HTTPClient
+	useGzip := false
 	if httpClient == nil {
-		httpClient = &http.Client{Timeout: 30 * time.Second}
-	}
-
-	baseURL := config.BaseURL
-	if baseURL == "" {
-		switch strings.ToLower(config.NetworkName) {
-		case "mainnet":
-			baseURL = defaultMainnetBaseURL
-		case "preprod":
-			baseURL = defaultPreprodBaseURL
-		case "preview":
-			baseURL = defaultPreviewBaseURL
-		default:
-			return nil, fmt.Errorf(
-				"unsupported or missing network name: %s, and no BaseURL provided",
-				config.NetworkName,
-			)
+		var err error
+		if httpClient, err = newHTTPClient(config); err != nil {
+			return nil, err
 		}
-	} else {
-		if strings.Contains(baseURL, "blockfrost.io") && !strings.HasSuffix(baseURL, "/v0") {
-			baseURL += "/v0"
+		useGzip = !config.DisableCompression
+	}
+
+	networkName, err := resolveNetworkName(config.NetworkName, config.BaseURL, config.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	networkId, err := resolveNetworkId(networkName, config.NetworkId)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL, err := resolveBaseURL(config.BaseURL, config.APIVersionPath, networkName)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := config.PageSize
+	if pageSize == 0 {
+		pageSize = maxPageSize
+	}
+	if pageSize < 0 || pageSize > maxPageSize {
+		return nil, fmt.Errorf("page size must be between 1 and %d, got %d", maxPageSize, config.PageSize)
+	}
+	if config.MaxPages < 0 {
+		return nil, fmt.Errorf("max pages must not be negative, got %d", config.MaxPages)
+	}
+	order := strings.ToLower(config.Order)
+	switch order {
+	case "", "asc", "desc":
+	default:
+		return nil, fmt.Errorf("unsupported order %q: expected \"asc\" or \"desc\"", config.Order)
+	}
+
+	confirmationDepth := config.ConfirmationDepth
+	if confirmationDepth == 0 {
+		confirmationDepth = 1
+	}
+	if confirmationDepth < 0 {
+		return nil, fmt.Errorf("confirmation depth must not be negative, got %d", config.ConfirmationDepth)
+	}
+
+	ppTTL := config.ProtocolParamsTTL
+	if ppTTL == 0 {
+		ppTTL = defaultProtocolParamsTTL
+	}
+	if ppTTL < 0 {
+		return nil, fmt.Errorf("protocol params TTL must not be negative, got %v", ppTTL)
+	}
+	if config.DisableProtocolParamsCache {
+		ppTTL = 0
+	}
+
+	if config.RequestTimeout < 0 {
+		return nil, fmt.Errorf("request timeout must not be negative, got %v", config.RequestTimeout)
+	}
+	if config.MethodTimeout < 0 {
+		return nil, fmt.Errorf("method timeout must not be negative, got %v", config.MethodTimeout)
+	}
+
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	retry, err := config.Retry.WithDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	if config.UnitAddressCacheTTL < 0 {
+		return nil, fmt.Errorf("unit address cache TTL must not be negative, got %v", config.UnitAddressCacheTTL)
+	}
+	unitCacheSize := config.UnitAddressCacheSize
+	if unitCacheSize == 0 {
+		unitCacheSize = defaultUnitAddressCacheSize
+	}
+	if unitCacheSize < 0 {
+		return nil, fmt.Errorf("unit address cache size must not be negative, got %d", unitCacheSize)
+	}
+
+	var limiter *rateLimiter
+	if !config.DisableRateLimit {
+		rateLimit := config.RateLimit
+		if rateLimit == 0 {
+			rateLimit = defaultRateLimit
 		}
+		rateBurst := config.RateBurst
+		if rateBurst == 0 {
+			rateBurst = defaultRateBurst
+		}
+		if rateLimit < 0 || rateBurst < 0 {
+			return nil, fmt.Errorf("rate limit and burst must not be negative, got %v and %d", rateLimit, rateBurst)
+		}
+		limiter = newRateLimiter(rateLimit, rateBurst)
 	}
 
 	provider := &BlockfrostProvider{
 		httpClient:                httpClient,
 		baseURL:                   baseURL,
 		projectID:                 config.ProjectID,
-		networkName:               config.NetworkName,
-		networkId:                 config.NetworkId,
+		networkName:               networkName,
+		networkId:                 networkId,
 		customSubmissionEndpoints: config.CustomSubmissionEndpoints,
+		submitStrategy:            config.SubmitStrategy,
+		pageSize:                  pageSize,
+		maxPages:                  config.MaxPages,
+		order:                     order,
+		limiter:                   limiter,
+		confirmationDepth:         confirmationDepth,
+		awaitTxCheckMempool:       config.AwaitTxCheckMempool,
+		gzip:                      useGzip,
+		ppCache:                   newProtocolParamsCache(ppTTL, ppFetchTimeout(config)),
+		strictOutRefs:             config.StrictOutRefs,
+		lenientEvaluation:         config.LenientEvaluation,
+		requestTimeout:            config.RequestTimeout,
+		methodTimeout:             config.MethodTimeout,
+		unitCache:                 newUnitAddressCache(config.UnitAddressCacheTTL, unitCacheSize),
+		retry:                     retry,
+		logger:                    logger,
+		debugHTTP:                 config.DebugHTTP,
+	}
+	if config.ValidateOnNew {
+		if err := provider.Validate(context.Background()); err != nil {
+			return nil, err
+		}
 	}
 	return provider, nil
 }
 
+// Network returns the network id, derived from the network name when it was
+// not configured explicitly.
 func (b *BlockfrostProvider) Network() int {
 	return b.networkId
 }
 
 func (b *BlockfrostProvider) Epoch(ctx context.Context) (int, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
 	var bfEpoch BlockfrostEpoch
 	path := "/epochs/latest"
 
@@ -82,9 +195,27 @@ func (b *BlockfrostProvider) Epoch(ctx context.Context) (int, error) {
 	return bfEpoch.Epoch, nil
 }
 
-// GetProtocolParameters fetches the current protocol parameters from Blockfrost.
+// GetProtocolParameters returns the current protocol parameters. Results are
+// cached for Config.ProtocolParamsTTL and concurrent callers share a single
+// upstream request.
 func (b *BlockfrostProvider) GetProtocolParameters(
 	ctx context.Context,
+) (backend.ProtocolParameters, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+	params, err := b.ppCache.get(ctx, b.fetchProtocolParameters)
+	return params, timeoutError(err)
+}
+
+// InvalidateProtocolParameters drops the cached protocol parameters so the
+// next GetProtocolParameters call fetches them again, e.g. after an epoch
+// boundary or a fee-related submission failure.
+func (b *BlockfrostProvider) InvalidateProtocolParameters() {
+	b.ppCache.invalidate()
+}
+
+func (b *BlockfrostProvider) fetchProtocolParameters(
+	ctx context.Context,
 ) (backend.ProtocolParameters, error) {
 	var raw bfProtocolParams
 	path := "/epochs/latest/parameters"
@@ -103,6 +234,8 @@ func (b *BlockfrostProvider) GetProtocolParameters(
 func (b *BlockfrostProvider) GetGenesisParams(
 	ctx context.Context,
 ) (backend.GenesisParameters, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
 	var raw bfGenesisParams
 	path := "/genesis"
 
@@ -131,6 +264,8 @@ func (b *BlockfrostProvider) GetGenesisParams(
 func (b *BlockfrostProvider) GetTip(
 	ctx context.Context,
 ) (connector.Tip, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
 	var bfTip struct {
 		Height uint64 `json:"height"`
 		Hash   string `json:"hash"`
@@ -149,16 +284,54 @@ func (b *BlockfrostProvider) GetTip(
 	}, nil
 }
 
+// doRequest sends a request, retrying it according to Config.Retry, and
+// decodes a successful response into target.
 func (b *BlockfrostProvider) doRequest(
 	ctx context.Context,
 	method, path string,
 	body io.Reader,
 	target interface{},
 ) error {
-	fullURL := b.baseURL + path // Assumes path starts with "/"
-	req, err := http.NewRequestWithContext(ctx, method, fullURL, body)
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = io.ReadAll(body); err != nil {
+			return fmt.Errorf("blockfrost: failed to read request body: %w", err)
+		}
+	}
+	for attempt := 1; ; attempt++ {
+		status, err := b.doRequestOnce(ctx, method, path, payload, target)
+		if err == nil || attempt >= b.retry.MaxAttempts || !retryable(method, path, status) {
+			return err
+		}
+		if !b.retry.Wait(ctx, attempt) {
+			return err
+		}
+	}
+}
+
+// doRequestOnce sends a single request and decodes a successful response
+// into target. It returns the response status, or 0 when none was received.
+func (b *BlockfrostProvider) doRequestOnce(
+	ctx context.Context,
+	method, path string,
+	body []byte,
+	target interface{},
+) (int, error) {
+	if err := b.limiter.Wait(ctx); err != nil {
+		return 0, fmt.Errorf("blockfrost: rate limiter wait: %w", timeoutError(err))
+	}
+	ctx, cancel := b.requestContext(ctx)
+	defer cancel()
+
+	fullURL := joinURLPath(b.baseURL, path)
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, reqBody)
 	if err != nil {
-		return fmt.Errorf("blockfrost: failed to create request: %w", err)
+		return 0, fmt.Errorf("blockfrost: failed to create request: %w", err)
 	}
 
 	if b.projectID != "" {
@@ -167,16 +340,22 @@ func (b *BlockfrostProvider) doRequest(
 	if body != nil {
 		req.Header.Set("Content-Type", "application/cbor")
 	}
+	if b.gzip {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
 
+	start := time.Now()
 	resp, err := b.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("blockfrost: request failed: %w", err)
+		b.logHTTP(ctx, req, 0, start, nil, err)
+		return 0, fmt.Errorf("blockfrost: request failed: %w", timeoutError(err))
 	}
 	defer resp.Body.Close()
 
-	respBodyBytes, err := io.ReadAll(resp.Body)
+	respBodyBytes, err := readBody(resp)
+	b.logHTTP(ctx, req, resp.StatusCode, start, respBodyBytes, err)
 	if err != nil {
-		return fmt.Errorf("blockfrost: failed to read response body: %w", err)
+		return resp.StatusCode, fmt.Errorf("blockfrost: failed to read response body: %w", timeoutError(err))
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
@@ -185,10 +364,21 @@ func (b *BlockfrostProvider) doRequest(
 			Err        string `json:"error"`
 			Message    string `json:"message"`
 		}
-		if json.Unmarshal(respBodyBytes, &bfError) == nil &&
-			bfError.Message != "" {
+		_ = json.Unmarshal(respBodyBytes, &bfError)
+		if err := b.projectError(resp.StatusCode, bfError.Message, respBodyBytes); err != nil {
+			return resp.StatusCode, err
+		}
+		if resp.StatusCode == http.StatusBadRequest && method == "POST" &&
+			strings.HasSuffix(path, "/tx/submit") {
+			message := bfError.Message
+			if message == "" {
+				message = string(respBodyBytes)
+			}
+			return resp.StatusCode, parseSubmitError(resp.StatusCode, message)
+		}
+		if bfError.Message != "" {
 			if bfError.StatusCode == http.StatusNotFound {
-				return fmt.Errorf(
+				return resp.StatusCode, fmt.Errorf(
 					"blockfrost API error (%d - %s): %s: %w",
 					resp.StatusCode,
 					http.StatusText(resp.StatusCode),
@@ -196,7 +386,7 @@ func (b *BlockfrostProvider) doRequest(
 					connector.ErrNotFound,
 				)
 			}
-			return fmt.Errorf(
+			return resp.StatusCode, fmt.Errorf(
 				"blockfrost API error (%d - %s): %s",
 				resp.StatusCode,
 				http.StatusText(resp.StatusCode),
@@ -204,7 +394,7 @@ func (b *BlockfrostProvider) doRequest(
 			)
 		}
 		if resp.StatusCode == http.StatusNotFound {
-			return fmt.Errorf(
+			return resp.StatusCode, fmt.Errorf(
 				"blockfrost API error: status %d - %s. Body: %s: %w",
 				resp.StatusCode,
 				http.StatusText(resp.StatusCode),
@@ -212,7 +402,7 @@ func (b *BlockfrostProvider) doRequest(
 				connector.ErrNotFound,
 			)
 		}
-		return fmt.Errorf(
+		return resp.StatusCode, fmt.Errorf(
 			"blockfrost API error: status %d - %s. Body: %s",
 			resp.StatusCode,
 			http.StatusText(resp.StatusCode),
@@ -225,27 +415,49 @@ func (b *BlockfrostProvider) doRequest(
 			if s, ok := target.(*string); ok &&
 				(method == "POST" && (strings.HasSuffix(path, "/tx/submit"))) {
 				*s = strings.Trim(string(respBodyBytes), "\"")
-				return nil
+				return resp.StatusCode, nil
 			}
-			return fmt.Errorf(
+			return resp.StatusCode, fmt.Errorf(
 				"blockfrost: failed to decode JSON response: %w. Body: %s",
 				err,
 				string(respBodyBytes),
 			)
 		}
 	}
-	return nil
+	return resp.StatusCode, nil
 }
 
 func (b *BlockfrostProvider) GetUtxosByAddress(
 	ctx context.Context,
 	addr string,
 ) ([]common.Utxo, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
 	address, err := common.NewAddress(addr)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %s: %w", connector.ErrInvalidAddress, addr, err)
 	}
-	return b.fetchUtxosPaged(ctx, address, fmt.Sprintf("/addresses/%s/utxos", addr))
+	return b.fetchUtxosPaged(ctx, address, fmt.Sprintf("/addresses/%s/utxos", addr), pageOptions{})
+}
+
+// GetUtxosByAddressWith is GetUtxosByAddress honouring connector.WithOrder.
+// Blockfrost cannot range UTxOs by block, so connector.WithFromTo is ignored.
+func (b *BlockfrostProvider) GetUtxosByAddressWith(
+	ctx context.Context,
+	addr string,
+	opts ...connector.QueryOption,
+) ([]common.Utxo, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+	address, err := common.NewAddress(addr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", connector.ErrInvalidAddress, addr, err)
+	}
+	po, err := queryPageOptions(opts, false)
+	if err != nil {
+		return nil, err
+	}
+	return b.fetchUtxosPaged(ctx, address, fmt.Sprintf("/addresses/%s/utxos", addr), po)
 }
 
 func (b *BlockfrostProvider) GetUtxosWithUnit(
@@ -253,66 +465,64 @@ func (b *BlockfrostProvider) GetUtxosWithUnit(
 	addr string,
 	unit string,
 ) ([]common.Utxo, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
 	address, err := common.NewAddress(addr)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %s: %w", connector.ErrInvalidAddress, addr, err)
 	}
-	return b.fetchUtxosPaged(ctx, address, fmt.Sprintf("/addresses/%s/utxos/%s", addr, unit))
+	return b.fetchUtxosPaged(ctx, address, fmt.Sprintf("/addresses/%s/utxos/%s", addr, unit), pageOptions{})
 }
 
 // fetchUtxosPaged fetches and hydrates all pages of a Blockfrost UTxO listing.
+// When the listing is truncated at Config.MaxPages, the UTxOs fetched so far
+// are returned together with an error wrapping connector.ErrTruncated.
 func (b *BlockfrostProvider) fetchUtxosPaged(
 	ctx context.Context,
 	address common.Address,
 	basePath string,
+	opts pageOptions,
 ) ([]common.Utxo, error) {
-	var allUtxos []common.Utxo
-	page := 1
+	rawUtxos, pageErr := paginateWith[bfAddressUTxO](ctx, b, basePath, opts)
+	if pageErr != nil && !errors.Is(pageErr, connector.ErrTruncated) {
+		return nil, pageErr
+	}
 
-	for {
-		var rawUtxos []bfAddressUTxO
-		sep := "?"
-		if strings.Contains(basePath, "?") {
-			sep = "&"
-		}
-		path := fmt.Sprintf("%s%spage=%d", basePath, sep, page)
-		err := b.doRequest(ctx, "GET", path, nil, &rawUtxos)
+	utxos := make([]common.Utxo, 0, len(rawUtxos))
+	for _, raw := range rawUtxos {
+		utxo, err := b.hydrateUtxo(ctx, raw, address)
 		if err != nil {
-			if page == 1 && errors.Is(err, connector.ErrNotFound) {
-				return []common.Utxo{}, nil
-			}
-			return nil, err
-		}
-
-		if len(rawUtxos) == 0 {
-			break
-		}
-
-		for _, raw := range rawUtxos {
-			utxo, err := b.hydrateUtxo(ctx, raw, address)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse UTxO %s#%d: %w", raw.TxHash, raw.OutputIndex, err)
-			}
-			allUtxos = append(allUtxos, utxo)
-		}
-
-		if len(rawUtxos) < 100 {
-			break
+			return nil, fmt.Errorf("failed to parse UTxO %s#%d: %w", raw.TxHash, raw.OutputIndex, err)
 		}
-		page++
+		utxos = append(utxos, utxo)
 	}
-
-	return allUtxos, nil
+	return utxos, pageErr
 }
 
+// GetScriptCborByScriptHash returns the CBOR hex of the script with the given
+// hash. Blockfrost serves no CBOR for native (timelock) scripts, so those are
+// rebuilt from /scripts/{hash}/json and checked against the hash.
 func (b *BlockfrostProvider) GetScriptCborByScriptHash(
 	ctx context.Context,
 	scriptHash string,
 ) (string, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+	var bfScriptInfo struct {
+		Type string `json:"type"`
+	}
+	err := b.doRequest(ctx, "GET", "/scripts/"+scriptHash, nil, &bfScriptInfo)
+	if err != nil {
+		return "", err
+	}
+	if bfScriptInfo.Type == "timelock" {
+		return b.getNativeScriptCbor(ctx, scriptHash)
+	}
+
 	var bfScript bfScriptCbor
 	path := fmt.Sprintf("/scripts/%s/cbor", scriptHash)
 
-	err := b.doRequest(ctx, "GET", path, nil, &bfScript)
+	err = b.doRequest(ctx, "GET", path, nil, &bfScript)
 	if err != nil {
 		return "", err
 	}
@@ -327,18 +537,72 @@ func (b *BlockfrostProvider) GetScriptCborByScriptHash(
 	return bfScript.ScriptCbor, nil
 }
 
-// GetUtxoByUnit queries a UTxO by a specific unit.
+// getNativeScriptCbor serializes the native script served by
+// /scripts/{hash}/json to CBOR hex.
+func (b *BlockfrostProvider) getNativeScriptCbor(
+	ctx context.Context,
+	scriptHash string,
+) (string, error) {
+	var bfScript struct {
+		JSON json.RawMessage `json:"json"`
+	}
+	path := fmt.Sprintf("/scripts/%s/json", scriptHash)
+	if err := b.doRequest(ctx, "GET", path, nil, &bfScript); err != nil {
+		return "", err
+	}
+	if !jsonValuePresent(bfScript.JSON) {
+		return "", fmt.Errorf("no native script json found for script hash: %s", scriptHash)
+	}
+
+	scriptCbor, err := nativeScriptCborFromJSON(bfScript.JSON)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert native script %s from json: %w", scriptHash, err)
+	}
+	var native common.NativeScript
+	if err := native.UnmarshalCBOR(scriptCbor); err != nil {
+		return "", fmt.Errorf("failed to decode native script %s: %w", scriptHash, err)
+	}
+	if got := native.Hash().String(); got != strings.ToLower(scriptHash) {
+		return "", fmt.Errorf("native script %s re-encodes to a script with hash %s", scriptHash, got)
+	}
+	return hex.EncodeToString(scriptCbor), nil
+}
+
+// GetUtxoByUnit queries a UTxO by a specific unit. The unit may be an NFT or
+// a fungible token whose entire circulating supply sits in a single UTxO; a
+// unit held by several addresses or UTxOs yields connector.ErrMultipleUTXOs.
+//
+// With Config.UnitAddressCacheTTL set, the holding address is remembered and
+// later calls only re-scan that address, falling back to a full lookup when
+// the unit is no longer there. A cached unit that has since also appeared at
+// other addresses is not detected until its entry expires.
 func (b *BlockfrostProvider) GetUtxoByUnit(
 	ctx context.Context,
 	unit string,
 ) (*common.Utxo, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+	policyId, assetName, err := backend.ParseAssetUnit(unit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", connector.ErrInvalidUnit, unit, err)
+	}
+
+	if address, ok := b.unitCache.address(unit); ok {
+		utxo, err := b.utxoByUnitAt(ctx, address, unit, policyId, assetName)
+		if !errors.Is(err, connector.ErrNotFound) {
+			return utxo, err
+		}
+		// The unit moved; resolve its address afresh.
+		b.unitCache.forget(unit)
+	}
+
 	var addressesHoldingAsset []struct {
 		Address  string `json:"address"`
 		Quantity string `json:"quantity"`
 	}
 
 	assetAddressesPath := fmt.Sprintf("/assets/%s/addresses?count=2", unit)
-	err := b.doRequest(ctx, "GET", assetAddressesPath, nil, &addressesHoldingAsset)
+	err = b.doRequest(ctx, "GET", assetAddressesPath, nil, &addressesHoldingAsset)
 	if err != nil {
 		if errors.Is(err, connector.ErrNotFound) {
 			return nil, fmt.Errorf("unit not found: %w", connector.ErrNotFound)
@@ -351,32 +615,82 @@ func (b *BlockfrostProvider) GetUtxoByUnit(
 	}
 
 	if len(addressesHoldingAsset) > 1 {
-		return nil, connector.ErrMultipleUTXOs
+		return nil, fmt.Errorf(
+			"%w: unit %s is held by more than one address",
+			connector.ErrMultipleUTXOs,
+			unit,
+		)
 	}
 
 	address := addressesHoldingAsset[0].Address
 
+	utxo, err := b.utxoByUnitAt(ctx, address, unit, policyId, assetName)
+	if err != nil {
+		return nil, err
+	}
+	b.unitCache.store(unit, address, connector.OutRef{
+		TxHash: hex.EncodeToString(utxo.Id.Id().Bytes()),
+		Index:  utxo.Id.Index(),
+	})
+	return utxo, nil
+}
+
+// utxoByUnitAt returns the single UTxO at address holding unit.
+func (b *BlockfrostProvider) utxoByUnitAt(
+	ctx context.Context,
+	address, unit string,
+	policyId common.Blake2b224,
+	assetName cbor.ByteString,
+) (*common.Utxo, error) {
 	utxos, err := b.GetUtxosWithUnit(ctx, address, unit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get UTxOs for address %s with unit %s: %w", address, unit, err)
 	}
 
-	if len(utxos) == 0 {
+	// Only UTxOs carrying a positive quantity of the unit count towards it.
+	holding := make([]common.Utxo, 0, 1)
+	total := new(big.Int)
+	for _, utxo := range utxos {
+		assets := utxo.Output.Assets()
+		if assets == nil {
+			continue
+		}
+		qty := assets.Asset(policyId, assetName.Bytes())
+		if qty == nil || qty.Sign() <= 0 {
+			continue
+		}
+		holding = append(holding, utxo)
+		total.Add(total, qty)
+	}
+
+	if len(holding) == 0 {
 		return nil, fmt.Errorf("unit not found in address UTxOs: %w", connector.ErrNotFound)
 	}
 
-	if len(utxos) > 1 {
-		return nil, connector.ErrMultipleUTXOs
+	if len(holding) > 1 {
+		return nil, fmt.Errorf(
+			"%w: unit %s (total quantity %s) is spread across %d UTxOs at %s",
+			connector.ErrMultipleUTXOs,
+			unit,
+			total.String(),
+			len(holding),
+			address,
+		)
 	}
 
-	return &utxos[0], nil
+	return &holding[0], nil
 }
 
-// GetUtxosByOutRef queries UTxOs by their output references.
+// GetUtxosByOutRef queries UTxOs by their output references. Results follow
+// the order of outRefs with duplicates removed; refs that cannot be resolved
+// are skipped, or reported as a *connector.MissingOutRefsError when
+// Config.StrictOutRefs is set.
 func (b *BlockfrostProvider) GetUtxosByOutRef(
 	ctx context.Context,
 	outRefs []connector.OutRef,
 ) ([]common.Utxo, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
 	if len(outRefs) == 0 {
 		return []common.Utxo{}, nil
 	}
@@ -417,13 +731,17 @@ func (b *BlockfrostProvider) GetUtxosByOutRef(
 		txOutputsMap[result.txHash] = result.outputs
 	}
 
-	var results []common.Utxo
+	results := make([]common.Utxo, 0, len(outRefs))
+	var missing []connector.OutRef
+	seen := make(map[connector.OutRef]bool, len(outRefs))
 	for _, ref := range outRefs {
-		outputs, exists := txOutputsMap[ref.TxHash]
-		if !exists {
+		if seen[ref] {
 			continue
 		}
-		for _, raw := range outputs {
+		seen[ref] = true
+
+		found := false
+		for _, raw := range txOutputsMap[ref.TxHash] {
 			if raw.OutputIndex == int(ref.Index) {
 				// The /txs/{hash}/utxos outputs carry no tx_hash field, so set
 				// it from the requested ref before hydrating.
@@ -437,11 +755,18 @@ func (b *BlockfrostProvider) GetUtxosByOutRef(
 					return nil, fmt.Errorf("failed to adapt utxo for %s#%d: %w", ref.TxHash, ref.Index, err)
 				}
 				results = append(results, utxo)
+				found = true
 				break
 			}
 		}
+		if !found {
+			missing = append(missing, ref)
+		}
 	}
 
+	if b.strictOutRefs && len(missing) > 0 {
+		return results, &connector.MissingOutRefsError{Refs: missing}
+	}
 	return results, nil
 }
 
@@ -471,11 +796,10 @@ func (b *BlockfrostProvider) hydrateUtxo(
 		scriptRef, err := b.scriptRefByHash(ctx, raw.ReferenceScriptHash)
 		if err != nil {
 			// Chain-read hydration is best-effort: a reference script that
-			// cannot be resolved (empty CBOR, native scripts served only at
-			// /scripts/{hash}/json, parse error, transient failure) must NOT
-			// abort the whole UTxO fetch. Keep the UTxO with an unresolved
-			// (nil) reference script.
-			slog.Warn("blockfrost: leaving reference script unresolved during hydration",
+			// cannot be resolved (empty CBOR, parse error, transient
+			// failure) must NOT abort the whole UTxO fetch. Keep the UTxO
+			// with an unresolved (nil) reference script.
+			b.logger.Warn("blockfrost: leaving reference script unresolved during hydration",
 				"script_hash", raw.ReferenceScriptHash,
 				"utxo", fmt.Sprintf("%s#%d", raw.TxHash, raw.OutputIndex),
 				"err", err)
@@ -515,11 +839,10 @@ func (b *BlockfrostProvider) GetDelegation(
 	ctx context.Context,
 	stakeAddrStr string,
 ) (connector.Delegation, error) {
-	if !strings.HasPrefix(stakeAddrStr, "stake") {
-		return connector.Delegation{}, fmt.Errorf(
-			"%w: expected a stake address (stake1...)",
-			connector.ErrInvalidAddress,
-		)
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+	if err := validateStakeAddress(stakeAddrStr); err != nil {
+		return connector.Delegation{}, err
 	}
 
 	var bfAccountDetails BlockfrostAccountDetails
@@ -537,14 +860,18 @@ func (b *BlockfrostProvider) GetDelegation(
 		return connector.Delegation{}, fmt.Errorf("failed to get account details for %s: %w", stakeAddrStr, err)
 	}
 
-	return adaptBlockfrostAccountToDelegation(bfAccountDetails), nil
+	return adaptBlockfrostAccountToDelegation(bfAccountDetails)
 }
 
 // GetDatum fetches a datum by its hash and returns the decoded gouroboros datum.
+// Datums Blockfrost only holds in JSON form (empty /cbor) are rebuilt from
+// /scripts/datum/{hash}, provided the re-encoded CBOR matches the hash.
 func (b *BlockfrostProvider) GetDatum(
 	ctx context.Context,
 	datumHash string,
 ) (common.Datum, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
 	var bfDatum struct {
 		Cbor  string `json:"cbor"`
 		Error string `json:"error"`
@@ -556,7 +883,7 @@ func (b *BlockfrostProvider) GetDatum(
 	}
 
 	if bfDatum.Error != "" || bfDatum.Cbor == "" {
-		return common.Datum{}, fmt.Errorf("no datum found for datum hash: %s", datumHash)
+		return b.getDatumFromJSON(ctx, datumHash)
 	}
 
 	datumBytes, err := hex.DecodeString(bfDatum.Cbor)
@@ -570,7 +897,48 @@ func (b *BlockfrostProvider) GetDatum(
 	return datum, nil
 }
 
-// AwaitTx waits for a transaction to be confirmed.
+// getDatumFromJSON rebuilds a datum from the detailed-schema JSON served by
+// /scripts/datum/{hash}.
+func (b *BlockfrostProvider) getDatumFromJSON(
+	ctx context.Context,
+	datumHash string,
+) (common.Datum, error) {
+	var bfDatum struct {
+		JSONValue json.RawMessage `json:"json_value"`
+	}
+	path := "/scripts/datum/" + datumHash
+	if err := b.doRequest(ctx, "GET", path, nil, &bfDatum); err != nil {
+		return common.Datum{}, err
+	}
+	if !jsonValuePresent(bfDatum.JSONValue) {
+		return common.Datum{}, fmt.Errorf("no datum found for datum hash: %s: %w", datumHash, connector.ErrNotFound)
+	}
+
+	datumBytes, err := datumCborFromJSON(bfDatum.JSONValue)
+	if err != nil {
+		return common.Datum{}, fmt.Errorf("failed to convert datum %s from json: %w", datumHash, err)
+	}
+	if got := common.Blake2b256Hash(datumBytes).String(); got != strings.ToLower(datumHash) {
+		return common.Datum{}, fmt.Errorf(
+			"datum %s is only available as json and its canonical re-encoding hashes to %s",
+			datumHash,
+			got,
+		)
+	}
+	var datum common.Datum
+	if err := datum.UnmarshalCBOR(datumBytes); err != nil {
+		return common.Datum{}, fmt.Errorf("failed to unmarshal datum cbor: %w", err)
+	}
+	return datum, nil
+}
+
+// AwaitTx waits for a transaction to be confirmed, i.e. for its block to be
+// Config.ConfirmationDepth blocks deep. The transaction is looked up again on
+// every poll, so one that is rolled back after appearing in a block is waited
+// for again rather than reported as confirmed. With Config.AwaitTxCheckMempool
+// set, a context error is wrapped with the last observed state: not seen,
+// pending in the mempool or included but not yet deep enough. AwaitTx is not
+// bounded by Config.MethodTimeout; use ctx to limit how long it waits.
 func (b *BlockfrostProvider) AwaitTx(
 	ctx context.Context,
 	txHash string,
@@ -582,64 +950,194 @@ func (b *BlockfrostProvider) AwaitTx(
 	ticker := time.NewTicker(checkInterval)
 	defer ticker.Stop()
 
+	status := "not yet seen"
 	for {
 		select {
 		case <-ctx.Done():
-			return false, ctx.Err()
+			if !b.awaitTxCheckMempool {
+				return false, ctx.Err()
+			}
+			return false, fmt.Errorf("transaction %s %s: %w", txHash, status, ctx.Err())
 		case <-ticker.C:
 			var txInfo struct {
-				Block string `json:"block"`
-				Error string `json:"error"`
+				Block       string `json:"block"`
+				BlockHeight uint64 `json:"block_height"`
+				Error       string `json:"error"`
 			}
 			path := "/txs/" + txHash
 			err := b.doRequest(ctx, "GET", path, nil, &txInfo)
-			if err != nil {
-				if errors.Is(err, connector.ErrNotFound) {
-					continue
-				}
+			if err != nil && !errors.Is(err, connector.ErrNotFound) {
 				return false, err
 			}
 
-			if txInfo.Error != "" {
+			if err != nil || txInfo.Error != "" || txInfo.Block == "" {
+				if b.awaitTxCheckMempool {
+					// The mempool state only feeds the timeout message, so a
+					// failed lookup keeps the previous state.
+					if inMempool, err := b.InMempool(ctx, txHash); err == nil {
+						status = "not yet seen"
+						if inMempool {
+							status = "still pending in mempool"
+						}
+					}
+				}
 				continue
 			}
+			status = fmt.Sprintf("in block %s, awaiting %d confirmations", txInfo.Block, b.confirmationDepth)
 
-			if txInfo.Block != "" {
-				select {
-				case <-ctx.Done():
-					return false, ctx.Err()
-				case <-time.After(1 * time.Second):
-					return true, nil
-				}
+			tip, err := b.GetTip(ctx)
+			if err != nil {
+				return false, err
+			}
+			if tip.Height >= txInfo.BlockHeight &&
+				tip.Height-txInfo.BlockHeight+1 >= uint64(b.confirmationDepth) {
+				return true, nil
 			}
 		}
 	}
 }
 
-// SubmitTx submits a signed transaction.
+// SubmitTx submits a signed transaction. Custom submission endpoints are tried
+// concurrently; how they combine with the Blockfrost submit endpoint is
+// controlled by Config.SubmitStrategy. When every attempt fails, the returned
+// error joins the individual failures. Once submitted, the transaction's
+// inputs are dropped from the GetUtxoByUnit address cache.
 func (b *BlockfrostProvider) SubmitTx(
 	ctx context.Context,
 	txBytes []byte,
 ) (string, error) {
-	var submittedTxHashStr string
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+	txHash, err := b.submitTx(ctx, txBytes)
+	if err == nil && b.unitCache != nil {
+		b.unitCache.forgetSpent(spentOutRefs(txBytes))
+	}
+	return txHash, err
+}
+
+func (b *BlockfrostProvider) submitTx(ctx context.Context, txBytes []byte) (string, error) {
+	attempts := make([]submitAttempt, 0, len(b.customSubmissionEndpoints)+1)
+	for _, endpoint := range b.customSubmissionEndpoints {
+		attempts = append(attempts, func(ctx context.Context) (string, error) {
+			var txHash string
+			if err := b.doCustomSubmit(ctx, endpoint, txBytes, &txHash); err != nil {
+				return "", err
+			}
+			if txHash == "" {
+				return "", fmt.Errorf("custom submit to %s returned no transaction hash", endpoint)
+			}
+			return txHash, nil
+		})
+	}
+	blockfrostAttempt := func(ctx context.Context) (string, error) {
+		return b.submitToBlockfrost(ctx, txBytes)
+	}
+
+	var errs []error
+	if b.submitStrategy == SubmitFirstSuccess {
+		txHash, raceErrs := raceSubmissions(ctx, b.submitAttemptTimeout(), append(attempts, blockfrostAttempt))
+		if raceErrs == nil {
+			return txHash, nil
+		}
+		errs = raceErrs
+	} else {
+		if len(attempts) > 0 {
+			txHash, raceErrs := raceSubmissions(ctx, b.submitAttemptTimeout(), attempts)
+			if raceErrs == nil {
+				return txHash, nil
+			}
+			errs = raceErrs
+		}
+		txHash, err := blockfrostAttempt(ctx)
+		if err == nil {
+			return txHash, nil
+		}
+		errs = append(errs, err)
+	}
+	return "", fmt.Errorf("%w: %w", connector.ErrTxSubmissionFailed, errors.Join(errs...))
+}
+
+// submitAttempt submits a transaction to a single endpoint and returns its hash.
+type submitAttempt func(ctx context.Context) (string, error)
+
+// raceSubmissions runs all attempts concurrently and returns the hash from the
+// first one that succeeds. Remaining attempts are left to finish in the
+// background so the transaction still propagates through every endpoint:
+// they run detached from ctx, each bounded by timeout instead, so that
+// neither the caller returning nor a MethodTimeout aborts them. If all
+// attempts fail, their errors are returned in attempt order; if ctx ends
+// first, the errors so far are returned followed by the context's error.
+func raceSubmissions(
+	ctx context.Context,
+	timeout time.Duration,
+	attempts []submitAttempt,
+) (string, []error) {
+	type result struct {
+		index  int
+		txHash string
+		err    error
+	}
+	results := make(chan result, len(attempts))
+	for i, attempt := range attempts {
+		go func() {
+			attemptCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+			defer cancel()
+			txHash, err := attempt(attemptCtx)
+			results <- result{index: i, txHash: txHash, err: err}
+		}()
+	}
 
-	if len(b.customSubmissionEndpoints) > 0 {
-		for _, endpoint := range b.customSubmissionEndpoints {
-			err := b.doCustomSubmit(ctx, endpoint, txBytes, &submittedTxHashStr)
-			if err == nil && submittedTxHashStr != "" {
-				return submittedTxHashStr, nil
+	errs := make([]error, len(attempts))
+	for range attempts {
+		select {
+		case res := <-results:
+			if res.err == nil {
+				return res.txHash, nil
 			}
+			errs[res.index] = res.err
+		case <-ctx.Done():
+			errs = slices.DeleteFunc(errs, func(err error) bool { return err == nil })
+			return "", append(errs, timeoutError(ctx.Err()))
 		}
 	}
+	return "", errs
+}
+
+// ppFetchTimeout bounds a shared protocol parameters fetch, which outlives
+// the callers waiting on it: MethodTimeout, else RequestTimeout, else the
+// default HTTP client timeout.
+func ppFetchTimeout(config Config) time.Duration {
+	switch {
+	case config.MethodTimeout > 0:
+		return config.MethodTimeout
+	case config.RequestTimeout > 0:
+		return config.RequestTimeout
+	default:
+		return defaultRequestTimeout
+	}
+}
 
-	err := b.doRequest(ctx, "POST", "/tx/submit", bytes.NewReader(txBytes), &submittedTxHashStr)
+// submitAttemptTimeout bounds each raced submission attempt: RequestTimeout,
+// or the default HTTP client timeout when that is unset.
+func (b *BlockfrostProvider) submitAttemptTimeout() time.Duration {
+	if b.requestTimeout > 0 {
+		return b.requestTimeout
+	}
+	return defaultRequestTimeout
+}
+
+// submitToBlockfrost submits a transaction through the Blockfrost
+// /tx/submit endpoint.
+func (b *BlockfrostProvider) submitToBlockfrost(ctx context.Context, txBytes []byte) (string, error) {
+	var txHash string
+	err := b.doRequest(ctx, "POST", "/tx/submit", bytes.NewReader(txBytes), &txHash)
 	if err != nil {
-		return "", fmt.Errorf("%w: %w", connector.ErrTxSubmissionFailed, err)
+		return "", err
 	}
-	if submittedTxHashStr == "" {
+	if txHash == "" {
 		return "", errors.New("blockfrost did not return a transaction hash on submission")
 	}
-	return submittedTxHashStr, nil
+	return txHash, nil
 }
 
 func (b *BlockfrostProvider) doCustomSubmit(
@@ -648,19 +1146,35 @@ func (b *BlockfrostProvider) doCustomSubmit(
 	txBytes []byte,
 	target *string,
 ) error {
+	if err := b.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("custom submit to %s: rate limiter wait: %w", endpoint, timeoutError(err))
+	}
+	ctx, cancel := b.requestContext(ctx)
+	defer cancel()
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(txBytes))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/cbor")
 
+	start := time.Now()
 	resp, err := b.httpClient.Do(req)
 	if err != nil {
-		return err
+		b.logHTTP(ctx, req, 0, start, nil, err)
+		return timeoutError(err)
 	}
 	defer resp.Body.Close()
 
-	bodyBytes, _ := io.ReadAll(resp.Body)
+	bodyBytes, err := readBody(resp)
+	b.logHTTP(ctx, req, resp.StatusCode, start, bodyBytes, err)
+	if err != nil {
+		return fmt.Errorf(
+			"custom submit to %s: failed to read response body: %w",
+			endpoint,
+			timeoutError(err),
+		)
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return fmt.Errorf("custom submit to %s failed: status %d, body: %s", endpoint, resp.StatusCode, string(bodyBytes))
 	}
@@ -672,12 +1186,16 @@ func (b *BlockfrostProvider) doCustomSubmit(
 
 // EvaluateTx evaluates a transaction's scripts and returns the per-redeemer
 // execution units. additionalUTxOs are forwarded to the evaluator (e.g. inputs
-// not yet confirmed on-chain) via the /utils/txs/evaluate/utxos endpoint.
+// not yet confirmed on-chain) via the /utils/txs/evaluate/utxos endpoint;
+// without any, the raw CBOR goes to the lighter /utils/txs/evaluate, which
+// proxies that reject an empty additionalUtxoSet still accept.
 func (b *BlockfrostProvider) EvaluateTx(
 	ctx context.Context,
 	txBytes []byte,
 	additionalUTxOs []common.Utxo,
 ) (map[common.RedeemerKey]common.ExUnits, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
 	if len(additionalUTxOs) > 0 {
 		items := make([]bfAdditionalUtxoItem, 0, len(additionalUTxOs))
 		for _, utxo := range additionalUTxOs {
@@ -699,7 +1217,7 @@ func (b *BlockfrostProvider) EvaluateTx(
 		if err != nil {
 			return nil, err
 		}
-		return parseEvaluateTxResponse(data)
+		return parseEvaluateTxResponse(data, b.evalKeyError)
 	}
 
 	// Bare evaluation: BlockFrost expects the transaction CBOR hex-encoded in the
@@ -709,7 +1227,18 @@ func (b *BlockfrostProvider) EvaluateTx(
 	if err != nil {
 		return nil, err
 	}
-	return parseEvaluateTxResponse(data)
+	return parseEvaluateTxResponse(data, b.evalKeyError)
+}
+
+// evalKeyError handles a redeemer of an evaluation result whose key cannot be
+// parsed: an error by default, or a logged warning that drops the redeemer
+// when Config.LenientEvaluation is set.
+func (b *BlockfrostProvider) evalKeyError(key string, err error) error {
+	if !b.lenientEvaluation {
+		return err
+	}
+	b.logger.Warn("blockfrost: dropping unparseable redeemer from evaluation result", "key", key, "err", err)
+	return nil
 }
 
 // doEvaluate performs a POST to a BlockFrost evaluation endpoint and returns the
@@ -720,7 +1249,13 @@ func (b *BlockfrostProvider) doEvaluate(
 	body []byte,
 	contentType string,
 ) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL+path, bytes.NewReader(body))
+	if err := b.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("blockfrost eval: rate limiter wait: %w", timeoutError(err))
+	}
+	ctx, cancel := b.requestContext(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, joinURLPath(b.baseURL, path), bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
@@ -728,24 +1263,52 @@ func (b *BlockfrostProvider) doEvaluate(
 		req.Header.Set("project_id", b.projectID)
 	}
 	req.Header.Set("Content-Type", contentType)
+	if b.gzip {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
 
+	start := time.Now()
 	resp, err := b.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("blockfrost eval request failed: %w", err)
+		b.logHTTP(ctx, req, 0, start, nil, err)
+		return nil, fmt.Errorf("blockfrost eval request failed: %w", timeoutError(err))
 	}
 	defer resp.Body.Close()
 
-	respBytes, _ := io.ReadAll(resp.Body)
+	respBytes, err := readBody(resp)
+	b.logHTTP(ctx, req, resp.StatusCode, start, respBytes, err)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"blockfrost eval: failed to read response body: %w",
+			timeoutError(err),
+		)
+	}
 	if resp.StatusCode != http.StatusOK {
 		var errorResp struct {
 			StatusCode int    `json:"status_code"`
 			Message    string `json:"message"`
 			Fault      bool   `json:"fault"`
 		}
-		if json.Unmarshal(respBytes, &errorResp) == nil && errorResp.Message != "" {
-			return nil, fmt.Errorf("%w: %s", connector.ErrEvaluationFailed, errorResp.Message)
+		_ = json.Unmarshal(respBytes, &errorResp)
+		if err := b.projectError(resp.StatusCode, errorResp.Message, respBytes); err != nil {
+			return nil, err
+		}
+		apiErr := &connector.APIError{
+			StatusCode: resp.StatusCode,
+			Message:    errorResp.Message,
+			Details:    string(respBytes),
+		}
+		// Blockfrost embeds the evaluator's reply in the message, e.g.
+		// "Could not evaluate the transaction: {...}.".
+		start, end := strings.IndexByte(errorResp.Message, '{'), strings.LastIndexByte(errorResp.Message, '}')
+		if start >= 0 && end > start && json.Valid([]byte(errorResp.Message[start:end+1])) {
+			return nil, evaluationError([]byte(errorResp.Message[start:end+1]), apiErr)
+		}
+		message := errorResp.Message
+		if message == "" {
+			message = "could not evaluate the transaction: " + evalErrorSnippet(respBytes)
 		}
-		return nil, fmt.Errorf("%w: could not evaluate the transaction: %s", connector.ErrEvaluationFailed, evalErrorSnippet(respBytes))
+		return nil, &connector.EvaluationError{Message: message, API: apiErr}
 	}
 	return respBytes, nil
 }
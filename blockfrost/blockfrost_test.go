@@ -3,6 +3,7 @@ package blockfrost
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"os"
 	"testing"
 	"time"
@@ -55,6 +56,9 @@ func TestGetProtocolParameters(t *testing.T) {
 	if pp.MaxTxSize == 0 {
 		t.Error("Expected non-zero MaxTxSize")
 	}
+	if diff := tests.CostModelKeysDiff(pp.CostModels); diff != "" {
+		t.Error(diff)
+	}
 }
 
 func TestGetGenesisParams(t *testing.T) {
@@ -144,6 +148,23 @@ func TestGetTip(t *testing.T) {
 	assert.True(t, len(tip.Hash) == 64, "Hash should be 64 characters long")
 }
 
+func TestGetNetworkInfo(t *testing.T) {
+	bf := setupBlockfrost(t)
+	ctx := context.Background()
+
+	info, err := bf.GetNetworkInfo(ctx)
+	if err != nil {
+		t.Fatalf("GetNetworkInfo failed: %v", err)
+	}
+
+	t.Logf("NetworkInfo: %+v", info)
+
+	assert.Equal(t, uint64(45000000000000000), info.Supply.Max)
+	assert.True(t, info.Supply.Circulating > 0, "Circulating supply should be positive")
+	assert.True(t, info.Supply.Total <= info.Supply.Max, "Total supply should not exceed max supply")
+	assert.True(t, info.Stake.Active > 0, "Active stake should be positive")
+}
+
 func TestGetUtxos(t *testing.T) {
 	bf := setupBlockfrost(t)
 	ctx := context.Background()
@@ -213,6 +234,11 @@ func TestGetUtxosByOutRef(t *testing.T) {
 			TxHash: "b50e73e74a3073bc44f555928702c0ae0f555a43f1afdce34b3294247dce022d",
 			Index:  0,
 		},
+		{
+			// Does not exist: silently skipped unless StrictOutRefs is set.
+			TxHash: "b50e73e74a3073bc44f555928702c0ae0f555a43f1afdce34b3294247dce022d",
+			Index:  99,
+		},
 	}
 
 	utxos, err := bf.GetUtxosByOutRef(ctx, outRefs)
@@ -227,6 +253,13 @@ func TestGetUtxosByOutRef(t *testing.T) {
 	if !tests.UtxosEqual(utxos[0], tests.ApolloDiscoveryUTxO) {
 		t.Errorf("UTxO mismatch: %s", tests.UtxoDiff(utxos[0], tests.ApolloDiscoveryUTxO))
 	}
+
+	bf.strictOutRefs = true
+	_, err = bf.GetUtxosByOutRef(ctx, outRefs)
+	var missingErr *connector.MissingOutRefsError
+	if !errors.As(err, &missingErr) || len(missingErr.Refs) != 1 || missingErr.Refs[0] != outRefs[1] {
+		t.Errorf("expected MissingOutRefsError for %v, got %v", outRefs[1], err)
+	}
 }
 
 func TestGetDelegation(t *testing.T) {
@@ -0,0 +1,508 @@
+// Package blockfrosttest provides an in-process Blockfrost API mock for testing
+// the blockfrost provider, and code built on it, without network access or a
+// project key.
+//
+//	srv := blockfrosttest.NewServer(blockfrosttest.Fixtures{...})
+//	defer srv.Close()
+//	provider, _ := blockfrost.New(blockfrost.Config{BaseURL: srv.URL, NetworkName: "preprod"})
+package blockfrosttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fixtures holds the canned JSON bodies a Server answers with. Bodies are
+// served verbatim; a request for anything without a fixture gets Blockfrost's
+// 404 response.
+type Fixtures struct {
+	ProtocolParameters string // GET /epochs/latest/parameters
+	Genesis            string // GET /genesis
+	LatestEpoch        string // GET /epochs/latest
+	LatestBlock        string // GET /blocks/latest
+
+	// AddressUtxos maps an address to the JSON array of its UTxOs, in the
+	// /addresses/{address}/utxos schema. The array is paged according to the
+	// count, page and order query parameters, and also backs
+	// /addresses/{address}/utxos/{unit}, /addresses/{address} and
+	// /assets/{unit}/addresses.
+	AddressUtxos map[string]string
+	// Txs maps a transaction hash to its /txs/{hash} body.
+	Txs map[string]string
+	// TxUtxos maps a transaction hash to its /txs/{hash}/utxos body.
+	TxUtxos map[string]string
+	// Accounts maps a stake address to its /accounts/{stake_address} body.
+	Accounts map[string]string
+	// AccountAddresses maps a stake address to its payment addresses, paged
+	// like AddressUtxos under /accounts/{stake_address}/addresses.
+	AccountAddresses map[string][]string
+	// Scripts maps a script hash to the script served under /scripts/{hash}.
+	Scripts map[string]Script
+	// Datums maps a datum hash to its CBOR hex, served by
+	// /scripts/datum/{hash}/cbor.
+	Datums map[string]string
+
+	// SubmitTxHash is returned by POST /tx/submit.
+	SubmitTxHash string
+	// Evaluation is returned by POST /utils/txs/evaluate and
+	// /utils/txs/evaluate/utxos.
+	Evaluation string
+
+	// Routes maps any other GET path (without query) to its body.
+	Routes map[string]string
+}
+
+// Script is a script fixture. Type is Blockfrost's script type ("timelock",
+// "plutusV1", "plutusV2" or "plutusV3"); Cbor is served by /scripts/{hash}/cbor
+// and JSON, for native scripts, by /scripts/{hash}/json.
+type Script struct {
+	Type string
+	Cbor string
+	JSON string
+}
+
+// Server is a Blockfrost API mock. Failures and latency can be injected while
+// it is running.
+type Server struct {
+	*httptest.Server
+
+	fixtures Fixtures
+
+	mu       sync.Mutex
+	failures map[string]failure
+	latency  time.Duration
+	requests map[string]int
+	bodies   map[string][]byte
+}
+
+type failure struct {
+	status  int
+	message string
+}
+
+// NewServer starts a Server answering from fixtures. Callers must Close it.
+func NewServer(fixtures Fixtures) *Server {
+	s := &Server{
+		fixtures: fixtures,
+		failures: make(map[string]failure),
+		requests: make(map[string]int),
+		bodies:   make(map[string][]byte),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Fail makes every request whose path starts with pathPrefix answer with
+// status and a Blockfrost error body carrying message. The longest matching
+// prefix wins.
+func (s *Server) Fail(pathPrefix string, status int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[pathPrefix] = failure{status: status, message: message}
+}
+
+// ClearFailures removes every failure injected with Fail.
+func (s *Server) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = make(map[string]failure)
+}
+
+// SetLatency delays every response by d, or until the request is cancelled.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// SetAddressUtxos replaces the UTxO fixture of address, or removes it when
+// utxos is empty, e.g. to simulate a token moving between addresses.
+func (s *Server) SetAddressUtxos(address, utxos string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	updated := make(map[string]string, len(s.fixtures.AddressUtxos)+1)
+	for a, body := range s.fixtures.AddressUtxos {
+		updated[a] = body
+	}
+	if utxos == "" {
+		delete(updated, address)
+	} else {
+		updated[address] = utxos
+	}
+	s.fixtures.AddressUtxos = updated
+}
+
+// Requests returns the number of requests received for path (without query).
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+// LastBody returns the body of the most recent request for path.
+func (s *Server) LastBody(path string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bodies[path]
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	path := r.URL.Path
+
+	s.mu.Lock()
+	s.requests[path]++
+	s.bodies[path] = body
+	latency := s.latency
+	fail, failing := s.failureFor(path)
+	fixtures := s.fixtures
+	s.mu.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-r.Context().Done():
+			return
+		case <-timer.C:
+		}
+	}
+	if failing {
+		writeError(w, fail.status, fail.message)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		servePost(w, fixtures, path, r.Header.Get("Content-Type"))
+		return
+	}
+
+	resp, ok, err := get(r, fixtures)
+	switch {
+	case err != nil:
+		writeError(w, http.StatusBadRequest, err.Error())
+	case !ok:
+		writeError(w, http.StatusNotFound, "The requested component has not been found.")
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, resp)
+	}
+}
+
+// failureFor returns the failure injected for the longest prefix of path.
+// The caller holds s.mu.
+func (s *Server) failureFor(path string) (failure, bool) {
+	best, found := "", false
+	for prefix := range s.failures {
+		if strings.HasPrefix(path, prefix) && (!found || len(prefix) > len(best)) {
+			best, found = prefix, true
+		}
+	}
+	return s.failures[best], found
+}
+
+// evaluateContentTypes is the request body type each evaluation endpoint
+// accepts.
+var evaluateContentTypes = map[string]string{
+	"/utils/txs/evaluate":       "application/cbor",
+	"/utils/txs/evaluate/utxos": "application/json",
+}
+
+func servePost(w http.ResponseWriter, f Fixtures, path, contentType string) {
+	switch path {
+	case "/tx/submit":
+		if f.SubmitTxHash == "" {
+			writeError(w, http.StatusBadRequest, "transaction submit error: no submit fixture")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, "%q", f.SubmitTxHash)
+	case "/utils/txs/evaluate", "/utils/txs/evaluate/utxos":
+		if want := evaluateContentTypes[path]; contentType != want {
+			writeError(w, http.StatusUnsupportedMediaType, fmt.Sprintf("expected Content-Type %s, got %q", want, contentType))
+			return
+		}
+		if f.Evaluation == "" {
+			writeError(w, http.StatusBadRequest, "no evaluation fixture")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, f.Evaluation)
+	default:
+		writeError(w, http.StatusNotFound, "The requested component has not been found.")
+	}
+}
+
+// get resolves a GET request to its fixture body.
+func get(r *http.Request, f Fixtures) (string, bool, error) {
+	path := r.URL.Path
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	case path == "/epochs/latest/parameters":
+		return f.ProtocolParameters, f.ProtocolParameters != "", nil
+	case path == "/genesis":
+		return f.Genesis, f.Genesis != "", nil
+	case path == "/epochs/latest":
+		return f.LatestEpoch, f.LatestEpoch != "", nil
+	case path == "/blocks/latest":
+		return f.LatestBlock, f.LatestBlock != "", nil
+	case len(segments) == 2 && segments[0] == "addresses" && f.AddressUtxos[segments[1]] != "":
+		return addressInfo(f, segments[1])
+	case len(segments) >= 3 && segments[0] == "addresses" && segments[2] == "utxos":
+		unit := ""
+		if len(segments) == 4 {
+			unit = segments[3]
+		}
+		return addressUtxos(r, f, segments[1], unit)
+	case len(segments) == 3 && segments[0] == "assets" && segments[2] == "addresses":
+		return assetAddresses(f, segments[1])
+	case len(segments) == 2 && segments[0] == "txs":
+		return lookup(f.Txs, segments[1])
+	case len(segments) == 3 && segments[0] == "txs" && segments[2] == "utxos":
+		return lookup(f.TxUtxos, segments[1])
+	case len(segments) == 2 && segments[0] == "accounts":
+		return lookup(f.Accounts, segments[1])
+	case len(segments) == 3 && segments[0] == "accounts" && segments[2] == "addresses":
+		return accountAddresses(r, f, segments[1])
+	case len(segments) == 4 && segments[0] == "scripts" && segments[1] == "datum" && segments[3] == "cbor":
+		cborHex, ok := f.Datums[segments[2]]
+		return fmt.Sprintf(`{"cbor":%q}`, cborHex), ok, nil
+	case len(segments) >= 2 && segments[0] == "scripts" && segments[1] != "datum":
+		return script(f, segments[1:])
+	}
+	return lookup(f.Routes, path)
+}
+
+func lookup(m map[string]string, key string) (string, bool, error) {
+	v, ok := m[key]
+	return v, ok, nil
+}
+
+func script(f Fixtures, segments []string) (string, bool, error) {
+	script, ok := f.Scripts[segments[0]]
+	if !ok {
+		return "", false, nil
+	}
+	switch {
+	case len(segments) == 1:
+		return fmt.Sprintf(`{"script_hash":%q,"type":%q,"serialised_size":%d}`,
+			segments[0], script.Type, len(script.Cbor)/2), true, nil
+	case len(segments) == 2 && segments[1] == "cbor":
+		if script.Cbor == "" {
+			return `{"cbor":null}`, true, nil
+		}
+		return fmt.Sprintf(`{"cbor":%q}`, script.Cbor), true, nil
+	case len(segments) == 2 && segments[1] == "json":
+		if script.JSON == "" {
+			return `{"json":null}`, true, nil
+		}
+		return `{"json":` + script.JSON + `}`, true, nil
+	}
+	return "", false, nil
+}
+
+// utxoAmounts is the part of a UTxO fixture the server inspects.
+type utxoAmounts struct {
+	Amount []struct {
+		Unit     string `json:"unit"`
+		Quantity string `json:"quantity"`
+	} `json:"amount"`
+}
+
+func (u utxoAmounts) quantity(unit string) *big.Int {
+	total := new(big.Int)
+	for _, a := range u.Amount {
+		if a.Unit != unit {
+			continue
+		}
+		if q, ok := new(big.Int).SetString(a.Quantity, 10); ok {
+			total.Add(total, q)
+		}
+	}
+	return total
+}
+
+// addressUtxos pages the address's UTxOs, keeping only those holding unit
+// when it is set.
+func addressUtxos(r *http.Request, f Fixtures, address, unit string) (string, bool, error) {
+	fixture, ok := f.AddressUtxos[address]
+	if !ok {
+		return "", false, nil
+	}
+	var utxos []json.RawMessage
+	if err := json.Unmarshal([]byte(fixture), &utxos); err != nil {
+		return "", false, fmt.Errorf("invalid utxo fixture for %s: %w", address, err)
+	}
+	if unit != "" {
+		filtered := utxos[:0]
+		for _, raw := range utxos {
+			var u utxoAmounts
+			if err := json.Unmarshal(raw, &u); err != nil {
+				return "", false, fmt.Errorf("invalid utxo fixture for %s: %w", address, err)
+			}
+			if u.quantity(unit).Sign() > 0 {
+				filtered = append(filtered, raw)
+			}
+		}
+		if len(filtered) == 0 {
+			return "", false, nil
+		}
+		utxos = filtered
+	}
+
+	page, err := paginate(r, utxos)
+	if err != nil {
+		return "", false, err
+	}
+	out, err := json.Marshal(page)
+	return string(out), true, err
+}
+
+// accountAddresses pages the account's addresses as {"address": ...} objects.
+func accountAddresses(r *http.Request, f Fixtures, stakeAddress string) (string, bool, error) {
+	addresses, ok := f.AccountAddresses[stakeAddress]
+	if !ok {
+		return "", false, nil
+	}
+	items := make([]json.RawMessage, 0, len(addresses))
+	for _, address := range addresses {
+		item, err := json.Marshal(struct {
+			Address string `json:"address"`
+		}{address})
+		if err != nil {
+			return "", false, err
+		}
+		items = append(items, item)
+	}
+	page, err := paginate(r, items)
+	if err != nil {
+		return "", false, err
+	}
+	out, err := json.Marshal(page)
+	return string(out), true, err
+}
+
+// paginate applies Blockfrost's count (default 100), page (1-based, default
+// 1) and order (asc or desc) query parameters.
+func paginate(r *http.Request, items []json.RawMessage) ([]json.RawMessage, error) {
+	query := r.URL.Query()
+	count, page := 100, 1
+	if v := query.Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 100 {
+			return nil, fmt.Errorf("invalid count %q", v)
+		}
+		count = n
+	}
+	if v := query.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid page %q", v)
+		}
+		page = n
+	}
+	if query.Get("order") == "desc" {
+		reversed := make([]json.RawMessage, len(items))
+		for i, item := range items {
+			reversed[len(items)-1-i] = item
+		}
+		items = reversed
+	}
+
+	start := (page - 1) * count
+	if start >= len(items) {
+		return []json.RawMessage{}, nil
+	}
+	return items[start:min(start+count, len(items))], nil
+}
+
+// addressInfo derives /addresses/{address}, whose amount is the sum of the
+// address's UTxOs per unit, from the UTxO fixtures. Its stake_address is
+// always null; addresses without a UTxO fixture are served from Routes.
+func addressInfo(f Fixtures, address string) (string, bool, error) {
+	fixture, ok := f.AddressUtxos[address]
+	if !ok {
+		return "", false, nil
+	}
+	var utxos []utxoAmounts
+	if err := json.Unmarshal([]byte(fixture), &utxos); err != nil {
+		return "", false, fmt.Errorf("invalid utxo fixture for %s: %w", address, err)
+	}
+	totals := make(map[string]*big.Int)
+	units := []string{}
+	for _, u := range utxos {
+		for _, a := range u.Amount {
+			if _, ok := totals[a.Unit]; !ok {
+				totals[a.Unit] = new(big.Int)
+				units = append(units, a.Unit)
+			}
+			if q, ok := new(big.Int).SetString(a.Quantity, 10); ok {
+				totals[a.Unit].Add(totals[a.Unit], q)
+			}
+		}
+	}
+	type amount struct {
+		Unit     string `json:"unit"`
+		Quantity string `json:"quantity"`
+	}
+	amounts := make([]amount, 0, len(units))
+	for _, unit := range units {
+		amounts = append(amounts, amount{Unit: unit, Quantity: totals[unit].String()})
+	}
+	out, err := json.Marshal(struct {
+		Address      string   `json:"address"`
+		Amount       []amount `json:"amount"`
+		StakeAddress *string  `json:"stake_address"`
+		Type         string   `json:"type"`
+		Script       bool     `json:"script"`
+	}{Address: address, Amount: amounts, Type: "shelley"})
+	return string(out), true, err
+}
+
+// assetAddresses derives /assets/{unit}/addresses from the UTxO fixtures.
+func assetAddresses(f Fixtures, unit string) (string, bool, error) {
+	type holder struct {
+		Address  string `json:"address"`
+		Quantity string `json:"quantity"`
+	}
+	var holders []holder
+	for address, fixture := range f.AddressUtxos {
+		var utxos []utxoAmounts
+		if err := json.Unmarshal([]byte(fixture), &utxos); err != nil {
+			return "", false, fmt.Errorf("invalid utxo fixture for %s: %w", address, err)
+		}
+		total := new(big.Int)
+		for _, u := range utxos {
+			total.Add(total, u.quantity(unit))
+		}
+		if total.Sign() > 0 {
+			holders = append(holders, holder{Address: address, Quantity: total.String()})
+		}
+	}
+	if len(holders) == 0 {
+		return "", false, nil
+	}
+	sort.Slice(holders, func(i, j int) bool { return holders[i].Address < holders[j].Address })
+	out, err := json.Marshal(holders)
+	return string(out), true, err
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(struct {
+		StatusCode int    `json:"status_code"`
+		Error      string `json:"error"`
+		Message    string `json:"message"`
+	}{status, http.StatusText(status), message})
+}
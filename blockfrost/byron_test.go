@@ -0,0 +1,78 @@
+package blockfrost
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+const (
+	// byronAddr is a Byron bootstrap address carrying the preprod network
+	// magic (1), as held by the outputs of preprod's Byron-era genesis.
+	byronAddr   = "FHnt4NL7yPXgeyfyCw977n7wGrpR5QqfpYe6mHZffZ1K4qYx43p67uo6koYrBAK"
+	byronTxHash = "7b2ed1e9d2c0e8d18bc1f0b54b16bd5b5a8d5bc0be71a3a27f53cd6c6a2e5c01"
+)
+
+func byronFixtures() (addressUtxos, txUtxos string) {
+	utxo := `{
+		"address": "` + byronAddr + `",
+		"tx_hash": "` + byronTxHash + `",
+		"output_index": 1,
+		"amount": [{"unit": "lovelace", "quantity": "30000000000000"}],
+		"block": "` + strings.Repeat("0c", 32) + `",
+		"data_hash": null,
+		"inline_datum": null,
+		"reference_script_hash": null
+	}`
+	return "[" + utxo + "]", `{"hash": "` + byronTxHash + `", "inputs": [], "outputs": [` + utxo + `]}`
+}
+
+func assertByronUtxo(t *testing.T, utxo common.Utxo) {
+	t.Helper()
+	assert.Equal(t, byronTxHash, utxo.Id.Id().String())
+	assert.Equal(t, uint32(1), utxo.Id.Index())
+	assert.Equal(t, uint64(30_000_000_000_000), utxo.Output.Amount())
+	address := utxo.Output.Address()
+	assert.Equal(t, uint8(common.AddressTypeByron), address.Type())
+	assert.Equal(t, byronAddr, address.String())
+}
+
+func newByronBlockfrost(t *testing.T) *BlockfrostProvider {
+	t.Helper()
+	fixtures := offlineFixtures()
+	fixtures.AddressUtxos[byronAddr], fixtures.TxUtxos[byronTxHash] = byronFixtures()
+	bf, _ := newOfflineBlockfrost(t, fixtures, Config{})
+	return bf
+}
+
+func TestGetUtxosByAddressByron(t *testing.T) {
+	bf := newByronBlockfrost(t)
+
+	utxos, err := bf.GetUtxosByAddress(context.Background(), byronAddr)
+	assert.NoError(t, err)
+	if assert.Len(t, utxos, 1) {
+		assertByronUtxo(t, utxos[0])
+	}
+}
+
+func TestGetUtxosByOutRefByron(t *testing.T) {
+	bf := newByronBlockfrost(t)
+
+	utxos, err := bf.GetUtxosByOutRef(context.Background(), []connector.OutRef{{TxHash: byronTxHash, Index: 1}})
+	assert.NoError(t, err)
+	if assert.Len(t, utxos, 1) {
+		assertByronUtxo(t, utxos[0])
+	}
+}
+
+func TestGetStakeAddressOfByronAddress(t *testing.T) {
+	bf := newByronBlockfrost(t)
+
+	stake, err := connector.StakeAddressOf(context.Background(), bf, byronAddr)
+	assert.NoError(t, err)
+	assert.Empty(t, stake)
+}
@@ -0,0 +1,77 @@
+package blockfrost
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// Datum fixture as served by Blockfrost in both forms:
+// Constr 0 [h'deadbeef', 42, [1, 2], {h'aa': 1}].
+const (
+	datumFixtureHash = "6eebe614b808ffed003e9128b0e508a45cccdd7a0222eddbeaa9784175ae2417"
+	datumFixtureCbor = "d8799f44deadbeef182a9f0102ffa141aa01ff"
+	datumFixtureJSON = `{"constructor":0,"fields":[{"bytes":"deadbeef"},{"int":42},{"list":[{"int":1},{"int":2}]},{"map":[{"k":{"bytes":"aa"},"v":{"int":1}}]}]}`
+)
+
+func TestDatumCborFromJSONRoundTrips(t *testing.T) {
+	got, err := datumCborFromJSON(json.RawMessage(datumFixtureJSON))
+	assert.NoError(t, err)
+	assert.Equal(t, datumFixtureCbor, hex.EncodeToString(got))
+}
+
+// newDatumServer serves an empty /cbor for every datum and the given
+// json_value from /scripts/datum/{hash}.
+func newDatumServer(t *testing.T, jsonValue string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/cbor"):
+			_, _ = w.Write([]byte(`{"cbor": null}`))
+		case strings.HasPrefix(r.URL.Path, "/scripts/datum/"):
+			_, _ = w.Write([]byte(`{"json_value": ` + jsonValue + `}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGetDatumFallsBackToJSON(t *testing.T) {
+	srv := newDatumServer(t, datumFixtureJSON)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	datum, err := provider.GetDatum(context.Background(), datumFixtureHash)
+	assert.NoError(t, err)
+	assert.Equal(t, datumFixtureCbor, hex.EncodeToString(datum.Cbor()))
+	assert.Equal(t, datumFixtureHash, datum.Hash().String())
+}
+
+func TestGetDatumJSONHashMismatch(t *testing.T) {
+	srv := newDatumServer(t, `{"int":7}`)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	_, err = provider.GetDatum(context.Background(), datumFixtureHash)
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "hashes to"), "got %v", err)
+}
+
+func TestGetDatumUnknownInAnyForm(t *testing.T) {
+	srv := newDatumServer(t, `null`)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	_, err = provider.GetDatum(context.Background(), datumFixtureHash)
+	assert.True(t, errors.Is(err, connector.ErrNotFound), "got %v", err)
+}
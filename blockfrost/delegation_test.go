@@ -0,0 +1,69 @@
+package blockfrost
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+func TestAdaptBlockfrostAccountToDelegation(t *testing.T) {
+	cases := []struct {
+		name    string
+		account string
+		want    connector.Delegation
+	}{
+		{
+			name: "delegated with drep",
+			account: `{
+				"stake_address": "stake_test1uqfu74w3wh4gfzu8m6e7j987h4lq9r3t7ef5gaw497uu85qsqfy27",
+				"active": true,
+				"active_epoch": 412,
+				"withdrawable_amount": "1234",
+				"pool_id": "pool1pu5jlj4q9w9jlxeu370a3c9myx47md5j5m2str0naunn2q3lkdy",
+				"drep_id": "drep1yg2h7mwm5ha4l3dd2xrqhtxhvsfsjlvfz5u6ehedpz3qsdeskvnk8"
+			}`,
+			want: connector.Delegation{
+				Active:  true,
+				Rewards: 1234,
+				PoolId:  "pool1pu5jlj4q9w9jlxeu370a3c9myx47md5j5m2str0naunn2q3lkdy",
+				Epoch:   412,
+				DRepId:  "drep1yg2h7mwm5ha4l3dd2xrqhtxhvsfsjlvfz5u6ehedpz3qsdeskvnk8",
+			},
+		},
+		{
+			name: "registered but undelegated",
+			account: `{
+				"stake_address": "stake_test1uqfu74w3wh4gfzu8m6e7j987h4lq9r3t7ef5gaw497uu85qsqfy27",
+				"active": true,
+				"active_epoch": null,
+				"withdrawable_amount": "0",
+				"pool_id": null,
+				"drep_id": null
+			}`,
+			want: connector.Delegation{},
+		},
+		{
+			name: "voting delegation only",
+			account: `{
+				"stake_address": "stake_test1uqfu74w3wh4gfzu8m6e7j987h4lq9r3t7ef5gaw497uu85qsqfy27",
+				"active": false,
+				"active_epoch": null,
+				"withdrawable_amount": "5",
+				"pool_id": null,
+				"drep_id": "drep_always_abstain"
+			}`,
+			want: connector.Delegation{Rewards: 5, DRepId: "drep_always_abstain"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var account BlockfrostAccountDetails
+			assert.NoError(t, json.Unmarshal([]byte(tc.account), &account))
+			delegation, err := adaptBlockfrostAccountToDelegation(account)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, delegation)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package blockfrost
+
+import (
+	"errors"
+	"net/http"
+
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// ErrNetworkMismatch indicates that the configured network, the configured
+// network id and/or the Blockfrost project key do not agree with each other.
+var ErrNetworkMismatch = errors.New(
+	"blockfrost: project key or network id does not match the configured network",
+)
+
+// ErrInvalidProjectKey indicates that Blockfrost rejected the project key.
+var ErrInvalidProjectKey = errors.New("blockfrost: project key rejected")
+
+// ErrUnreachable indicates that the Blockfrost backend could not be reached
+// or does not serve the Blockfrost API at the configured base URL.
+var ErrUnreachable = errors.New("blockfrost: backend unreachable")
+
+// ErrUnhealthy indicates that the Blockfrost backend reports itself unhealthy.
+var ErrUnhealthy = errors.New("blockfrost: backend unhealthy")
+
+// Provider codes set on the *connector.APIError returned for Blockfrost's
+// project-level error statuses.
+const (
+	// ProviderCodeQuotaExceeded is set for 402: the project's daily request
+	// quota is exhausted.
+	ProviderCodeQuotaExceeded = "quota_exceeded"
+	// ProviderCodeInvalidProjectToken is set for 403: the project key is
+	// missing, invalid or belongs to another network.
+	ProviderCodeInvalidProjectToken = "invalid_project_token"
+	// ProviderCodeBanned is set for 418: the client was banned after flooding
+	// the API.
+	ProviderCodeBanned = "banned"
+	// ProviderCodeRateLimited is set for 429: the request rate limit was hit.
+	ProviderCodeRateLimited = "rate_limited"
+	// ProviderCodeMempoolFull is set for 425: the mempool is full and the
+	// transaction was not accepted.
+	ProviderCodeMempoolFull = "mempool_full"
+)
+
+// projectError maps Blockfrost's project-level error statuses to a populated
+// *connector.APIError, wrapping the matching sentinel where one exists
+// (429/418/402 → ErrRateLimited, 403 → ErrInvalidProjectKey,
+// 425 → ErrTxSubmissionFailed). It returns nil for any other status. Keys
+// for another network never get this far: New rejects them.
+func (b *BlockfrostProvider) projectError(statusCode int, message string, body []byte) error {
+	apiErr := &connector.APIError{
+		StatusCode: statusCode,
+		Message:    message,
+		Details:    evalErrorSnippet(body),
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(statusCode)
+	}
+
+	switch statusCode {
+	case http.StatusPaymentRequired:
+		apiErr.ProviderCode = ProviderCodeQuotaExceeded
+		apiErr.UnderlyingErr = connector.ErrRateLimited
+	case http.StatusForbidden:
+		apiErr.ProviderCode = ProviderCodeInvalidProjectToken
+		apiErr.UnderlyingErr = ErrInvalidProjectKey
+	case http.StatusTeapot:
+		apiErr.ProviderCode = ProviderCodeBanned
+		apiErr.UnderlyingErr = connector.ErrRateLimited
+	case http.StatusTooEarly:
+		apiErr.ProviderCode = ProviderCodeMempoolFull
+		apiErr.UnderlyingErr = connector.ErrTxSubmissionFailed
+	case http.StatusTooManyRequests:
+		apiErr.ProviderCode = ProviderCodeRateLimited
+		apiErr.UnderlyingErr = connector.ErrRateLimited
+	default:
+		return nil
+	}
+	return apiErr
+}
@@ -0,0 +1,186 @@
+package blockfrost
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// conwayEvaluation is an Ogmios v5 result with a Conway vote redeemer and a
+// key that cannot be parsed.
+const conwayEvaluation = `{"type":"jsonwsp/response","version":"1.0","servicename":"ogmios","methodname":"EvaluateTx",` +
+	`"result":{"EvaluationResult":{"spend:0":{"memory":100,"steps":1000},"withdrawal:1":{"memory":200,"steps":2000},` +
+	`"vote:0":{"memory":300,"steps":3000},"bogus":{"memory":400,"steps":4000}}}}`
+
+func newEvaluateProvider(t *testing.T, body string, config Config) *BlockfrostProvider {
+	t.Helper()
+	return newEvaluateProviderStatus(t, http.StatusOK, body, config)
+}
+
+func newEvaluateProviderStatus(t *testing.T, status int, body string, config Config) *BlockfrostProvider {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	config.BaseURL = srv.URL
+	provider, err := New(config)
+	assert.NoError(t, err)
+	return provider
+}
+
+func TestEvaluateTxRejectsUnparseableRedeemer(t *testing.T) {
+	provider := newEvaluateProvider(t, conwayEvaluation, Config{})
+
+	_, err := provider.EvaluateTx(context.Background(), []byte{0x84}, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `malformed redeemer key "bogus"`)
+}
+
+func TestEvaluateTxLenientDropsUnparseableRedeemer(t *testing.T) {
+	var logs bytes.Buffer
+	provider := newEvaluateProvider(t, conwayEvaluation, Config{
+		LenientEvaluation: true,
+		Logger:            slog.New(slog.NewTextHandler(&logs, nil)),
+	})
+
+	redeemers, err := provider.EvaluateTx(context.Background(), []byte{0x84}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[common.RedeemerKey]common.ExUnits{
+		{Tag: common.RedeemerTagSpend, Index: 0}:  {Memory: 100, Steps: 1000},
+		{Tag: common.RedeemerTagReward, Index: 1}: {Memory: 200, Steps: 2000},
+		{Tag: common.RedeemerTagVoting, Index: 0}: {Memory: 300, Steps: 3000},
+	}, redeemers)
+	assert.Contains(t, logs.String(), "key=bogus")
+}
+
+func TestEvaluateTxConwayPurposesV6(t *testing.T) {
+	provider := newEvaluateProvider(t, `{"jsonrpc":"2.0","method":"evaluateTransaction","result":[`+
+		`{"validator":{"purpose":"vote","index":0},"budget":{"memory":10,"cpu":20}},`+
+		`{"validator":{"purpose":"propose","index":2},"budget":{"memory":30,"cpu":40}}]}`, Config{})
+
+	redeemers, err := provider.EvaluateTx(context.Background(), []byte{0x84}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[common.RedeemerKey]common.ExUnits{
+		{Tag: common.RedeemerTagVoting, Index: 0}:    {Memory: 10, Steps: 20},
+		{Tag: common.RedeemerTagProposing, Index: 2}: {Memory: 30, Steps: 40},
+	}, redeemers)
+}
+
+func TestEvaluateTxUnknownPurposeV6(t *testing.T) {
+	body := `{"jsonrpc":"2.0","method":"evaluateTransaction","result":[` +
+		`{"validator":{"purpose":"spend","index":0},"budget":{"memory":10,"cpu":20}},` +
+		`{"validator":{"purpose":"guard","index":0},"budget":{"memory":30,"cpu":40}}]}`
+
+	_, err := newEvaluateProvider(t, body, Config{}).EvaluateTx(context.Background(), []byte{0x84}, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid redeemer purpose "guard"`)
+
+	redeemers, err := newEvaluateProvider(t, body, Config{LenientEvaluation: true}).
+		EvaluateTx(context.Background(), []byte{0x84}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, redeemers, 1)
+}
+
+// Captured evaluator failures: the legacy jsonwsp fault and v5
+// EvaluationFailure, and the Ogmios v6 JSON-RPC error.
+const (
+	jsonwspFault = `{"type":"jsonwsp/fault","version":"1.0","servicename":"ogmios",` +
+		`"fault":{"code":"client","string":"Invalid request: failed to decode payload from base64 or base16."},` +
+		`"reflection":{"id":"17f6c075-6d70-444e-a0e5-7cbbd064508c"}}`
+	v5ScriptFailures = `{"type":"jsonwsp/response","version":"1.0","servicename":"ogmios","methodname":"EvaluateTx",` +
+		`"result":{"EvaluationFailure":{"ScriptFailures":{"spend:1":[{"validatorFailed":{"error":"An error has occurred","traces":["not signed"]}}]}}}}`
+	v6ScriptFailures = `{"jsonrpc":"2.0","method":"evaluateTransaction","error":{"code":3010,` +
+		`"message":"Some scripts of the transactions terminated with error(s).","data":[` +
+		`{"validator":{"purpose":"mint","index":0},"error":{"code":3012,"message":"Some of the scripts failed to evaluate to a positive outcome.",` +
+		`"data":{"validationError":"An error has occurred","traces":["bad mint"]}}},` +
+		`{"validator":{"purpose":"spend","index":2},"error":{"code":3161,"message":"The execution budget was exceeded."}}]}}`
+)
+
+func evaluateError(t *testing.T, provider *BlockfrostProvider) *connector.EvaluationError {
+	t.Helper()
+	_, err := provider.EvaluateTx(context.Background(), []byte{0x84}, nil)
+	assert.True(t, connector.IsEvaluationFailed(err), "got %v", err)
+	var evalErr *connector.EvaluationError
+	if !assert.True(t, errors.As(err, &evalErr), "got %v", err) {
+		t.FailNow()
+	}
+	return evalErr
+}
+
+func TestEvaluateTxJsonwspFault(t *testing.T) {
+	evalErr := evaluateError(t, newEvaluateProvider(t, jsonwspFault, Config{}))
+	assert.Equal(t, "client fault: Invalid request: failed to decode payload from base64 or base16.", evalErr.Message)
+	assert.Empty(t, evalErr.Failures)
+	assert.Nil(t, evalErr.API)
+}
+
+func TestEvaluateTxFaultInErrorResponse(t *testing.T) {
+	body := `{"status_code":400,"error":"Bad Request","message":"Could not evaluate the transaction: ` +
+		strings.ReplaceAll(jsonwspFault, `"`, `\"`) + `."}`
+	evalErr := evaluateError(t, newEvaluateProviderStatus(t, http.StatusBadRequest, body, Config{}))
+	assert.Contains(t, evalErr.Message, "client fault: Invalid request")
+
+	var apiErr *connector.APIError
+	_, err := newEvaluateProviderStatus(t, http.StatusBadRequest, body, Config{}).
+		EvaluateTx(context.Background(), []byte{0x84}, nil)
+	assert.True(t, errors.As(err, &apiErr), "got %v", err)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, body, apiErr.Details)
+}
+
+func TestEvaluateTxV5ScriptFailures(t *testing.T) {
+	evalErr := evaluateError(t, newEvaluateProvider(t, v5ScriptFailures, Config{}))
+	assert.Equal(t, []connector.RedeemerFailure{{
+		Purpose: "spend",
+		Index:   1,
+		Reason:  `[{"validatorFailed":{"error":"An error has occurred","traces":["not signed"]}}]`,
+	}}, evalErr.Failures)
+}
+
+func TestEvaluateTxV6ScriptFailures(t *testing.T) {
+	evalErr := evaluateError(t, newEvaluateProvider(t, v6ScriptFailures, Config{}))
+	assert.Equal(t, "code 3010: Some scripts of the transactions terminated with error(s).", evalErr.Message)
+	assert.Equal(t, []connector.RedeemerFailure{
+		{
+			Purpose: "mint",
+			Index:   0,
+			Reason:  `Some of the scripts failed to evaluate to a positive outcome.: {"validationError":"An error has occurred","traces":["bad mint"]}`,
+		},
+		{Purpose: "spend", Index: 2, Reason: "The execution budget was exceeded."},
+	}, evalErr.Failures)
+}
+
+func TestEvaluateTxV6ResultItemFailure(t *testing.T) {
+	body := `{"jsonrpc":"2.0","method":"evaluateTransaction","result":[` +
+		`{"validator":{"purpose":"spend","index":0},"budget":{"memory":10,"cpu":20}},` +
+		`{"validator":{"purpose":"spend","index":1},"error":{"code":3012,"message":"Validator failed."}}]}`
+	evalErr := evaluateError(t, newEvaluateProvider(t, body, Config{}))
+	assert.Equal(t, []connector.RedeemerFailure{{Purpose: "spend", Index: 1, Reason: "Validator failed."}}, evalErr.Failures)
+}
+
+func TestEvaluateTxV6FailureIndexOutOfRange(t *testing.T) {
+	body := `{"jsonrpc":"2.0","method":"evaluateTransaction","result":[` +
+		`{"validator":{"purpose":"spend","index":0},"budget":{"memory":10,"cpu":20}},` +
+		`{"validator":{"purpose":"spend","index":4294967296},"error":{"code":3012,"message":"Validator failed."}}]}`
+	evalErr := evaluateError(t, newEvaluateProvider(t, body, Config{LenientEvaluation: true}))
+	assert.Equal(t, 0, len(evalErr.Failures))
+	assert.Contains(t, evalErr.Message, "spend:4294967296: Validator failed.")
+}
+
+func TestEvaluateTxUnrecognizedResponse(t *testing.T) {
+	for _, body := range []string{`{"jsonrpc":"2.0"}`, `not json`, `{"result":{"Other":{}}}`} {
+		_, err := newEvaluateProvider(t, body, Config{}).EvaluateTx(context.Background(), []byte{0x84}, nil)
+		assert.True(t, connector.IsEvaluationFailed(err), "body %s: got %v", body, err)
+	}
+}
@@ -9,8 +9,8 @@ import (
 )
 
 // TestHydrateUtxoUnresolvableReferenceScriptIsBestEffort asserts that when a
-// UTxO's reference script CBOR cannot be resolved (e.g. a native script whose
-// /scripts/{hash}/cbor is empty / 404), chain-read hydration does NOT abort the
+// UTxO's reference script CBOR cannot be resolved (e.g. the /scripts/{hash}
+// lookups return 404), chain-read hydration does NOT abort the
 // whole GetUtxosByAddress: the UTxO is returned with a nil reference script and
 // no error.
 func TestHydrateUtxoUnresolvableReferenceScriptIsBestEffort(t *testing.T) {
@@ -0,0 +1,63 @@
+package blockfrost
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// maxLoggedBodyBytes bounds how much of a response body Config.DebugHTTP logs.
+const maxLoggedBodyBytes = 2048
+
+// redacted replaces secret header values in logs.
+const redacted = "[REDACTED]"
+
+// logHTTP records a finished request when Config.DebugHTTP is set: a summary
+// at info level and, when the logger has debug enabled, the (truncated)
+// response body. status is 0 and body nil when no response was received.
+// The project_id header and any URL password are never logged.
+func (b *BlockfrostProvider) logHTTP(
+	ctx context.Context,
+	req *http.Request,
+	status int,
+	start time.Time,
+	body []byte,
+	err error,
+) {
+	if !b.debugHTTP {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.Redacted()),
+		slog.Int("status", status),
+		slog.Duration("duration", time.Since(start)),
+		slog.Any("headers", redactHeaders(req.Header)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("err", err.Error()))
+	}
+	b.logger.LogAttrs(ctx, slog.LevelInfo, "blockfrost: http request", attrs...)
+
+	if len(body) > 0 && b.logger.Enabled(ctx, slog.LevelDebug) {
+		logged := string(body)
+		if len(body) > maxLoggedBodyBytes {
+			logged = string(body[:maxLoggedBodyBytes]) + "...(truncated)"
+		}
+		b.logger.LogAttrs(ctx, slog.LevelDebug, "blockfrost: http response body",
+			slog.String("method", req.Method),
+			slog.String("url", req.URL.Redacted()),
+			slog.String("body", logged),
+		)
+	}
+}
+
+// redactHeaders returns a copy of h with the project key masked.
+func redactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	if out.Get("project_id") != "" {
+		out.Set("project_id", redacted)
+	}
+	return out
+}
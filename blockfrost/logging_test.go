@@ -0,0 +1,77 @@
+package blockfrost
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/tj/assert"
+	"github.com/zenGate-Global/cardano-connector-go/tests"
+)
+
+const loggingProjectID = "preprodS3cr3tPr0j3ctK3y"
+
+func TestDebugHTTPRedactsProjectID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	fixtures := offlineFixtures()
+	fixtures.SubmitTxHash = offlineDiscoveryTxHash
+	bf, srv := newOfflineBlockfrost(t, fixtures, Config{
+		ProjectID: loggingProjectID,
+		Logger:    logger,
+		DebugHTTP: true,
+	})
+	bf.customSubmissionEndpoints = []string{srv.URL + "/tx/submit"}
+	ctx := context.Background()
+
+	_, err := bf.Epoch(ctx)
+	assert.NoError(t, err)
+	txBytes, err := hex.DecodeString(tests.ApolloEvalSample1Transaction)
+	assert.NoError(t, err)
+	_, err = bf.EvaluateTx(ctx, txBytes, nil)
+	assert.NoError(t, err)
+	_, err = bf.SubmitTx(ctx, txBytes)
+	assert.NoError(t, err)
+
+	out := buf.String()
+	assert.NotContains(t, out, loggingProjectID)
+	assert.Contains(t, out, redacted)
+	for _, path := range []string{"/epochs/latest", "/utils/txs/evaluate", "/tx/submit"} {
+		assert.Contains(t, out, path)
+	}
+	assert.Contains(t, out, "status=200")
+	assert.Contains(t, out, "http response body")
+	assert.Contains(t, out, `\"epoch\": 180`)
+}
+
+func TestDebugHTTPBodiesOnlyAtDebugLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	bf, _ := newOfflineBlockfrost(t, offlineFixtures(), Config{Logger: logger, DebugHTTP: true})
+
+	_, err := bf.Epoch(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "http request")
+	assert.NotContains(t, buf.String(), "http response body")
+}
+
+func TestDebugHTTPOff(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	bf, _ := newOfflineBlockfrost(t, offlineFixtures(), Config{ProjectID: loggingProjectID, Logger: logger})
+
+	_, err := bf.Epoch(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(buf.String()))
+}
+
+func TestNewDefaultLoggerDiscards(t *testing.T) {
+	provider, err := New(Config{BaseURL: "http://127.0.0.1:1", ProjectID: "test"})
+	assert.NoError(t, err)
+	assert.NotNil(t, provider.logger)
+	assert.False(t, provider.logger.Enabled(context.Background(), slog.LevelError))
+}
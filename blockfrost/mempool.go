@@ -0,0 +1,52 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+var _ connector.MempoolWatcher = (*BlockfrostProvider)(nil)
+
+// bfMempoolTx is an entry of /mempool and /mempool/addresses/{address}.
+type bfMempoolTx struct {
+	TxHash string `json:"tx_hash"`
+}
+
+// InMempool reports whether txHash is pending in the Blockfrost mempool. Only
+// transactions submitted through Blockfrost are visible there.
+func (b *BlockfrostProvider) InMempool(ctx context.Context, txHash string) (bool, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+	var tx struct {
+		Tx struct {
+			Hash string `json:"hash"`
+		} `json:"tx"`
+	}
+	err := b.doRequest(ctx, "GET", "/mempool/"+txHash, nil, &tx)
+	if err != nil {
+		if errors.Is(err, connector.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to query mempool for %s: %w", txHash, err)
+	}
+	return true, nil
+}
+
+// ListMempoolByAddress returns the hashes of transactions pending in the
+// Blockfrost mempool that involve addr.
+func (b *BlockfrostProvider) ListMempoolByAddress(ctx context.Context, addr string) ([]string, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+	txs, err := paginate[bfMempoolTx](ctx, b, "/mempool/addresses/"+addr)
+	if err != nil && !errors.Is(err, connector.ErrTruncated) {
+		return nil, err
+	}
+	txHashes := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		txHashes = append(txHashes, tx.TxHash)
+	}
+	return txHashes, err
+}
@@ -0,0 +1,132 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+const mempoolNotFound = `{"status_code":404,"error":"Not Found","message":"The requested component has not been found."}`
+
+// newMempoolServer reports txHash as pending in the mempool for the first
+// pendingPolls lookups of /txs/{hash} and as confirmed in block 10 afterwards.
+// pendingPolls < 0 keeps it pending forever.
+func newMempoolServer(t *testing.T, pendingPolls int32, txLookups *atomic.Int32) *httptest.Server {
+	t.Helper()
+	confirmed := func() bool {
+		return pendingPolls >= 0 && txLookups.Load() > pendingPolls
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/txs/"):
+			txLookups.Add(1)
+			if !confirmed() {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(mempoolNotFound))
+				return
+			}
+			_, _ = w.Write([]byte(`{"hash":"abc","block":"block10","block_height":10}`))
+		case r.URL.Path == "/mempool/abc":
+			if confirmed() {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(mempoolNotFound))
+				return
+			}
+			_, _ = w.Write([]byte(`{"tx":{"hash":"abc"},"inputs":[],"outputs":[],"redeemers":[]}`))
+		case r.URL.Path == "/blocks/latest":
+			_, _ = w.Write([]byte(`{"height":10,"hash":"block10","slot":200}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(mempoolNotFound))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestInMempool(t *testing.T) {
+	var lookups atomic.Int32
+	srv := newMempoolServer(t, -1, &lookups)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	pending, err := provider.InMempool(context.Background(), "abc")
+	assert.NoError(t, err)
+	assert.True(t, pending)
+
+	pending, err = provider.InMempool(context.Background(), "unknown")
+	assert.NoError(t, err)
+	assert.False(t, pending)
+}
+
+func TestListMempoolByAddress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/mempool/addresses/addr_test1":
+			assert.Equal(t, "1", r.URL.Query().Get("page"))
+			_, _ = w.Write([]byte(`[{"tx_hash":"aa"},{"tx_hash":"bb"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(mempoolNotFound))
+		}
+	}))
+	defer srv.Close()
+
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	txHashes, err := provider.ListMempoolByAddress(context.Background(), "addr_test1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"aa", "bb"}, txHashes)
+
+	txHashes, err = provider.ListMempoolByAddress(context.Background(), "addr_test_empty")
+	assert.NoError(t, err)
+	assert.Empty(t, txHashes)
+}
+
+func TestAwaitTxMempoolToConfirmed(t *testing.T) {
+	var lookups atomic.Int32
+	srv := newMempoolServer(t, 2, &lookups)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test", AwaitTxCheckMempool: true})
+	assert.NoError(t, err)
+
+	ok, err := provider.AwaitTx(context.Background(), "abc", 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int32(3), lookups.Load())
+}
+
+func TestAwaitTxTimeoutReportsMempoolState(t *testing.T) {
+	var lookups atomic.Int32
+	srv := newMempoolServer(t, -1, &lookups)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test", AwaitTxCheckMempool: true})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	ok, err := provider.AwaitTx(ctx, "abc", 10*time.Millisecond)
+	assert.False(t, ok)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+	assert.True(t, strings.Contains(err.Error(), "pending in mempool"), "got %v", err)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = provider.AwaitTx(ctx, "unknown", 10*time.Millisecond)
+	assert.True(t, strings.Contains(err.Error(), "not yet seen"), "got %v", err)
+}
+
+func TestMempoolWatcherCapability(t *testing.T) {
+	provider, err := New(Config{BaseURL: "http://localhost", ProjectID: "test"})
+	assert.NoError(t, err)
+	var p connector.Provider = provider
+	_, ok := p.(connector.MempoolWatcher)
+	assert.True(t, ok)
+}
@@ -0,0 +1,111 @@
+package blockfrost
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/tj/assert"
+)
+
+// Native script fixture:
+// all [sig e09d…735a, any [sig a96d…5ed9, after 1000], before 50000000].
+const (
+	nativeScriptFixtureHash = "6299ca318f3988b719cb639663fc865644e6be778cadcb318b283583"
+	nativeScriptFixtureCbor = "8201838200581ce09d36c79dec9bd1b3d9e152247701cd0bb860b5ebfd1de8abb6735a" +
+		"8202828200581ca96da581c39549aeda81f539ac3940ac0cb53657e774ca7e68f15ed9" +
+		"82041903e882051a02faf080"
+	nativeScriptFixtureJSON = `{
+		"type": "all",
+		"scripts": [
+			{"type": "sig", "keyHash": "e09d36c79dec9bd1b3d9e152247701cd0bb860b5ebfd1de8abb6735a"},
+			{"type": "any", "scripts": [
+				{"type": "sig", "keyHash": "a96da581c39549aeda81f539ac3940ac0cb53657e774ca7e68f15ed9"},
+				{"type": "after", "slot": 1000}
+			]},
+			{"type": "before", "slot": 50000000}
+		]
+	}`
+)
+
+func TestNativeScriptCborFromJSON(t *testing.T) {
+	got, err := nativeScriptCborFromJSON([]byte(nativeScriptFixtureJSON))
+	assert.NoError(t, err)
+	assert.Equal(t, nativeScriptFixtureCbor, hex.EncodeToString(got))
+
+	var native common.NativeScript
+	assert.NoError(t, native.UnmarshalCBOR(got))
+	assert.Equal(t, nativeScriptFixtureHash, native.Hash().String())
+}
+
+func TestNativeScriptCborFromJSONAtLeast(t *testing.T) {
+	got, err := nativeScriptCborFromJSON([]byte(`{"type":"atLeast","required":1,"scripts":[{"type":"after","slot":5}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "83030181820405", hex.EncodeToString(got))
+}
+
+func TestNativeScriptCborFromJSONRejectsUnknownType(t *testing.T) {
+	_, err := nativeScriptCborFromJSON([]byte(`{"type":"multisig"}`))
+	assert.Error(t, err)
+}
+
+// newNativeScriptServer serves the fixture as a timelock script whose /cbor is
+// null, plus a UTxO that references it.
+func newNativeScriptServer(t *testing.T, addr string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/scripts/" + nativeScriptFixtureHash:
+			_, _ = w.Write([]byte(`{"script_hash":"` + nativeScriptFixtureHash + `","type":"timelock","serialised_size":null}`))
+		case "/scripts/" + nativeScriptFixtureHash + "/cbor":
+			_, _ = w.Write([]byte(`{"cbor":null}`))
+		case "/scripts/" + nativeScriptFixtureHash + "/json":
+			_, _ = w.Write([]byte(`{"json":` + nativeScriptFixtureJSON + `}`))
+		case "/addresses/" + addr + "/utxos":
+			if r.URL.Query().Get("page") != "1" {
+				_, _ = w.Write([]byte(`[]`))
+				return
+			}
+			_, _ = w.Write([]byte(`[{
+				"address": "` + addr + `",
+				"tx_hash": "8ae470ef0000000000000000000000000000000000000000000000000000beef",
+				"output_index": 0,
+				"amount": [{"unit":"lovelace","quantity":"2000000"}],
+				"inline_datum": null,
+				"reference_script_hash": "` + nativeScriptFixtureHash + `"
+			}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"status_code":404,"error":"Not Found","message":"The requested component has not been found."}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGetScriptCborByScriptHashNativeScript(t *testing.T) {
+	srv := newNativeScriptServer(t, "")
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	scriptCbor, err := provider.GetScriptCborByScriptHash(context.Background(), nativeScriptFixtureHash)
+	assert.NoError(t, err)
+	assert.Equal(t, nativeScriptFixtureCbor, scriptCbor)
+}
+
+func TestHydrateUtxoNativeReferenceScript(t *testing.T) {
+	const addr = "addr_test1wpgexmeunzsykesf42d4eqet5yvzeap6trjnflxqtkcf66g0kpnxt"
+	srv := newNativeScriptServer(t, addr)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	utxos, err := provider.GetUtxosByAddress(context.Background(), addr)
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 1)
+	scriptRef := utxos[0].Output.ScriptRef()
+	assert.NotNil(t, scriptRef)
+	assert.Equal(t, nativeScriptFixtureHash, scriptRef.Hash().String())
+}
@@ -0,0 +1,143 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Salvionied/apollo/v2/constants"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+var _ connector.NetworkInfoProvider = (*BlockfrostProvider)(nil)
+
+// networkIds maps the supported Blockfrost network names to the apollo
+// constants.Network value reported by Network().
+var networkIds = map[string]int{
+	"mainnet": int(constants.MAINNET),
+	"preprod": int(constants.PREPROD),
+	"preview": int(constants.PREVIEW),
+}
+
+// networkMagics maps the supported Blockfrost network names to the network
+// magic reported by /genesis.
+var networkMagics = map[string]int{
+	"mainnet": 764824073,
+	"preprod": 1,
+	"preview": 2,
+}
+
+// resolveNetworkName picks the network from, in order, the configured name,
+// the hosted base URL and, when neither a name nor a base URL is configured,
+// the project key prefix. A hosted network that contradicts the key prefix is
+// rejected, since Blockfrost would refuse every request.
+func resolveNetworkName(networkName, baseURL, projectID string) (string, error) {
+	networkName = strings.ToLower(networkName)
+	if networkName == "" {
+		networkName = networkNameFromBaseURL(baseURL)
+	}
+	keyNetwork := networkNameFromProjectID(projectID)
+	if networkName == "" && baseURL == "" {
+		if keyNetwork == "" {
+			return "", errors.New(
+				"cannot infer the network: set NetworkName or BaseURL, or use a project ID " +
+					"prefixed with mainnet, preprod or preview",
+			)
+		}
+		return keyNetwork, nil
+	}
+	if _, known := networkIds[networkName]; known && keyNetwork != "" && keyNetwork != networkName {
+		return "", fmt.Errorf(
+			"%w: project key is for %s but the provider is configured for %s",
+			ErrNetworkMismatch,
+			keyNetwork,
+			networkName,
+		)
+	}
+	return networkName, nil
+}
+
+// resolveNetworkId derives the network id from the network name when no id
+// was configured, and rejects an explicit id that disagrees with the name.
+// Unknown names (e.g. self-hosted backends) keep the configured id as-is.
+func resolveNetworkId(networkName string, networkId int) (int, error) {
+	expected, ok := networkIds[networkName]
+	if !ok {
+		return networkId, nil
+	}
+	if networkId == 0 {
+		return expected, nil
+	}
+	if networkId != expected {
+		return 0, fmt.Errorf(
+			"%w: network id %d does not match network name %q (expected %d)",
+			ErrNetworkMismatch,
+			networkId,
+			networkName,
+			expected,
+		)
+	}
+	return networkId, nil
+}
+
+// networkNameFromBaseURL returns the network served by one of the hosted
+// Blockfrost base URLs, or "" for any other URL.
+func networkNameFromBaseURL(baseURL string) string {
+	for name := range networkIds {
+		if strings.Contains(baseURL, "cardano-"+name+".blockfrost.io") {
+			return name
+		}
+	}
+	return ""
+}
+
+// networkNameFromProjectID returns the network encoded in a hosted Blockfrost
+// project key prefix (e.g. "preprodAbC..."), or "" if it has none.
+func networkNameFromProjectID(projectID string) string {
+	for name := range networkIds {
+		if strings.HasPrefix(projectID, name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// VerifyNetwork checks that the Blockfrost backend actually serves the
+// configured network by comparing the genesis network magic, surfacing a
+// descriptive ErrNetworkMismatch otherwise. Providers configured without a
+// known network name only check that the project key is accepted.
+func (b *BlockfrostProvider) VerifyNetwork(ctx context.Context) error {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+	genesis, err := b.GetGenesisParams(ctx)
+	if err != nil {
+		return err
+	}
+	expected, ok := networkMagics[b.networkName]
+	if !ok {
+		return nil
+	}
+	if genesis.NetworkMagic != expected {
+		return fmt.Errorf(
+			"%w: configured network %q expects network magic %d, but %s reports %d",
+			ErrNetworkMismatch,
+			b.networkName,
+			expected,
+			b.baseURL,
+			genesis.NetworkMagic,
+		)
+	}
+	return nil
+}
+
+// GetNetworkInfo returns the network's supply and stake from /network.
+func (b *BlockfrostProvider) GetNetworkInfo(ctx context.Context) (connector.NetworkInfo, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+	var network bfNetwork
+	if err := b.doRequest(ctx, "GET", "/network", nil, &network); err != nil {
+		return connector.NetworkInfo{}, fmt.Errorf("failed to get network info: %w", err)
+	}
+	return network.toNetworkInfo()
+}
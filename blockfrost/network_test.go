@@ -0,0 +1,106 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Salvionied/apollo/v2/constants"
+	"github.com/tj/assert"
+)
+
+func TestNewDerivesNetworkIdFromName(t *testing.T) {
+	cases := map[string]constants.Network{
+		"mainnet": constants.MAINNET,
+		"preprod": constants.PREPROD,
+		"Preview": constants.PREVIEW,
+	}
+	for name, want := range cases {
+		provider, err := New(Config{ProjectID: "test", NetworkName: name})
+		assert.NoError(t, err)
+		assert.Equal(t, int(want), provider.Network(), name)
+	}
+}
+
+func TestNewDerivesNetworkIdFromBaseURL(t *testing.T) {
+	provider, err := New(Config{ProjectID: "test", BaseURL: defaultPreviewBaseURL})
+	assert.NoError(t, err)
+	assert.Equal(t, int(constants.PREVIEW), provider.Network())
+}
+
+func TestNewRejectsInconsistentNetworkId(t *testing.T) {
+	_, err := New(Config{
+		ProjectID:   "test",
+		NetworkName: "preprod",
+		NetworkId:   int(constants.PREVIEW),
+	})
+	assert.True(t, errors.Is(err, ErrNetworkMismatch), "got %v", err)
+}
+
+func TestNewKeepsNetworkIdForCustomBackends(t *testing.T) {
+	provider, err := New(Config{BaseURL: "http://localhost:3000", NetworkId: int(constants.PREPROD)})
+	assert.NoError(t, err)
+	assert.Equal(t, int(constants.PREPROD), provider.Network())
+}
+
+func TestVerifyNetwork(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"network_magic": 2, "max_lovelace_supply": "45000000000000000"}`))
+	}))
+	defer srv.Close()
+
+	preview, err := New(Config{BaseURL: srv.URL, NetworkName: "preview"})
+	assert.NoError(t, err)
+	assert.NoError(t, preview.VerifyNetwork(context.Background()))
+
+	preprod, err := New(Config{BaseURL: srv.URL, NetworkName: "preprod"})
+	assert.NoError(t, err)
+	err = preprod.VerifyNetwork(context.Background())
+	assert.True(t, errors.Is(err, ErrNetworkMismatch), "got %v", err)
+}
+
+func TestNewDerivesNetworkFromProjectID(t *testing.T) {
+	cases := map[string]struct {
+		network string
+		id      constants.Network
+		baseURL string
+	}{
+		"mainnetAbC123": {"mainnet", constants.MAINNET, defaultMainnetBaseURL},
+		"preprodAbC123": {"preprod", constants.PREPROD, defaultPreprodBaseURL},
+		"previewAbC123": {"preview", constants.PREVIEW, defaultPreviewBaseURL},
+	}
+	for projectID, want := range cases {
+		provider, err := New(Config{ProjectID: projectID})
+		assert.NoError(t, err, projectID)
+		assert.Equal(t, want.network, provider.networkName)
+		assert.Equal(t, int(want.id), provider.Network())
+		assert.Equal(t, want.baseURL, provider.baseURL)
+	}
+}
+
+func TestNewRejectsUnknownProjectIDPrefix(t *testing.T) {
+	_, err := New(Config{ProjectID: "sanchonetAbC123"})
+	assert.Error(t, err)
+
+	// An explicit network or base URL makes the prefix irrelevant.
+	_, err = New(Config{ProjectID: "sanchonetAbC123", NetworkName: "preprod"})
+	assert.NoError(t, err)
+	_, err = New(Config{ProjectID: "sanchonetAbC123", BaseURL: "http://localhost:3000"})
+	assert.NoError(t, err)
+}
+
+func TestNewRejectsProjectIDForAnotherNetwork(t *testing.T) {
+	for _, config := range []Config{
+		{ProjectID: "mainnetAbC123", NetworkName: "preprod"},
+		{ProjectID: "mainnetAbC123", BaseURL: defaultPreviewBaseURL},
+	} {
+		_, err := New(config)
+		assert.True(t, errors.Is(err, ErrNetworkMismatch), "got %v", err)
+	}
+
+	// Self-hosted backends may name their network freely.
+	_, err := New(Config{ProjectID: "mainnetAbC123", NetworkName: "devnet", BaseURL: "http://localhost:3000"})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,393 @@
+package blockfrost
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Salvionied/apollo/v2/constants"
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+	"github.com/zenGate-Global/cardano-connector-go/blockfrost/blockfrosttest"
+	tests "github.com/zenGate-Global/cardano-connector-go/tests"
+)
+
+// The tests in this file mirror blockfrost_test.go against a
+// blockfrosttest.Server serving preprod responses, so they run without a
+// project key or network access.
+
+const (
+	offlineDiscoveryTxHash = "b50e73e74a3073bc44f555928702c0ae0f555a43f1afdce34b3294247dce022d"
+	offlineDiscoveryAddr   = "addr_test1wpgexmeunzsykesf42d4eqet5yvzeap6trjnflxqtkcf66g0kpnxt"
+	offlineDiscoveryUnit   = "4a83e031d4c37fc7ca6177a2f3581a8eec2ce155da91f59cfdb3bb28446973636f7665727956616c696461746f72"
+	offlineDatumHash       = "9781f0bc32835479f5051e367556df615a9040714fe7df167782df8e3e5b76df"
+	offlineStakeAddr       = "stake_test17zt3vxfjx9pjnpnapa65lx375p2utwxmpc8afj053h0l3vgc8a3g3"
+	offlineAwaitTxHash     = "2a1f95a9d85bf556a3dc889831593ee963ba491ca7164d930b3af0802a9796d0"
+)
+
+func offlineFixtures() blockfrosttest.Fixtures {
+	discoveryScript := tests.ApolloDiscoveryUTxO.Output.ScriptRef()
+	discoveryScriptHash := discoveryScript.Hash().String()
+	discoveryUtxo := fmt.Sprintf(`{
+		"address": %q,
+		"tx_hash": %q,
+		"output_index": 0,
+		"amount": [
+			{"unit": "lovelace", "quantity": "11977490"},
+			{"unit": %q, "quantity": "1"}
+		],
+		"block": "d5b5b3ff4d2fd2b4a1b1a5c4c4a0e1d3b3f0a8d2c8b0a1e3f5d7c9b1a3e5f7d9",
+		"data_hash": null,
+		"inline_datum": null,
+		"reference_script_hash": %q
+	}`, offlineDiscoveryAddr, offlineDiscoveryTxHash, offlineDiscoveryUnit, discoveryScriptHash)
+
+	return blockfrosttest.Fixtures{
+		ProtocolParameters: `{
+			"epoch": 180,
+			"min_fee_a": 44,
+			"min_fee_b": 155381,
+			"max_block_size": 90112,
+			"max_tx_size": 16384,
+			"max_block_header_size": 1100,
+			"key_deposit": "2000000",
+			"pool_deposit": "500000000",
+			"e_max": 18,
+			"n_opt": 500,
+			"a0": 0.3,
+			"rho": 0.003,
+			"tau": 0.2,
+			"protocol_major_ver": 10,
+			"protocol_minor_ver": 0,
+			"min_pool_cost": "170000000",
+			"price_mem": 0.0577,
+			"price_step": 0.0000721,
+			"max_tx_ex_mem": "14000000",
+			"max_tx_ex_steps": "10000000000",
+			"max_block_ex_mem": "62000000",
+			"max_block_ex_steps": "20000000000",
+			"max_val_size": "5000",
+			"collateral_percent": 150,
+			"max_collateral_inputs": 3,
+			"coins_per_utxo_size": "4310",
+			"min_fee_ref_script_cost_per_byte": 15
+		}`,
+		Genesis: `{
+			"active_slots_coefficient": 0.05,
+			"update_quorum": 5,
+			"max_lovelace_supply": "45000000000000000",
+			"network_magic": 1,
+			"epoch_length": 432000,
+			"system_start": 1654041600,
+			"slots_per_kes_period": 129600,
+			"slot_length": 1,
+			"max_kes_evolutions": 62,
+			"security_param": 2160
+		}`,
+		LatestEpoch: `{"epoch": 180, "start_time": 1731628800, "end_time": 1732060800}`,
+		LatestBlock: `{"height": 3600000, "hash": "` +
+			"e1c2a5b2f0d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b2" +
+			`", "slot": 94000000}`,
+		AddressUtxos: map[string]string{
+			offlineDiscoveryAddr: "[" + discoveryUtxo + "]",
+		},
+		TxUtxos: map[string]string{
+			offlineDiscoveryTxHash: `{"hash": "` + offlineDiscoveryTxHash + `", "inputs": [], "outputs": [` + discoveryUtxo + `]}`,
+		},
+		Txs: map[string]string{
+			offlineAwaitTxHash: `{"hash": "` + offlineAwaitTxHash + `", "block": "abc", "block_height": 3500000}`,
+		},
+		Accounts: map[string]string{
+			offlineStakeAddr: `{
+				"stake_address": "` + offlineStakeAddr + `",
+				"active": true,
+				"active_epoch": 150,
+				"controlled_amount": "1000000",
+				"withdrawable_amount": "2500",
+				"pool_id": "pool1z22x50lqsrwent6en0llzzs9e577rx7n3mv9kfw7udwa2rf42fa"
+			}`,
+		},
+		Scripts: map[string]blockfrosttest.Script{
+			discoveryScriptHash: {
+				Type: "plutusV2",
+				Cbor: hex.EncodeToString(discoveryScript.(common.PlutusV2Script)),
+			},
+			tests.ScriptHashToQuery: {Type: "plutusV3", Cbor: tests.ExpectedScriptCbor},
+		},
+		Datums: map[string]string{
+			offlineDatumHash: tests.ExpectedDatum,
+		},
+		Evaluation: `{"type":"jsonwsp/response","version":"1.0","servicename":"ogmios","methodname":"EvaluateTx",` +
+			`"result":{"EvaluationResult":{"spend:0":{"memory":26285,"steps":7850649},"spend:1":{"memory":26285,"steps":7850649},` +
+			`"spend:2":{"memory":26285,"steps":7850649},"spend:3":{"memory":26285,"steps":7850649}}}}`,
+		Routes: map[string]string{
+			"/network": `{
+				"supply": {
+					"max": "45000000000000000",
+					"total": "37105630785934829",
+					"circulating": "36925481016283469",
+					"locked": "8540358437474",
+					"treasury": "1488212011622452",
+					"reserves": "7894369214065171"
+				},
+				"stake": {"live": "21962546434327378", "active": "21950437268587186"}
+			}`,
+		},
+	}
+}
+
+func setupOfflineBlockfrost(t *testing.T) (*BlockfrostProvider, *blockfrosttest.Server) {
+	t.Helper()
+	return newOfflineBlockfrost(t, offlineFixtures(), Config{})
+}
+
+// newOfflineBlockfrost starts a server for fixtures and points a preprod
+// provider built from config at it.
+func newOfflineBlockfrost(
+	t *testing.T,
+	fixtures blockfrosttest.Fixtures,
+	config Config,
+) (*BlockfrostProvider, *blockfrosttest.Server) {
+	t.Helper()
+	srv := blockfrosttest.NewServer(fixtures)
+	t.Cleanup(srv.Close)
+
+	config.BaseURL = srv.URL
+	config.NetworkName = "preprod"
+	config.NetworkId = int(constants.PREPROD)
+	provider, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to create Blockfrost provider: %v", err)
+	}
+	return provider, srv
+}
+
+func TestOfflineGetProtocolParameters(t *testing.T) {
+	bf, _ := setupOfflineBlockfrost(t)
+
+	pp, err := bf.GetProtocolParameters(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 155381, int(pp.MinFeeConstant))
+	assert.Equal(t, 44, int(pp.MinFeeCoefficient))
+	assert.Equal(t, 16384, int(pp.MaxTxSize))
+}
+
+func TestOfflineGetGenesisParams(t *testing.T) {
+	bf, _ := setupOfflineBlockfrost(t)
+
+	gp, err := bf.GetGenesisParams(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0.05, gp.ActiveSlotsCoefficient)
+	assert.Equal(t, 5, gp.UpdateQuorum)
+	assert.Equal(t, "45000000000000000", gp.MaxLovelaceSupply)
+	assert.Equal(t, 1, gp.NetworkMagic)
+	assert.Equal(t, 432000, gp.EpochLength)
+	assert.Equal(t, int64(1654041600), gp.SystemStart)
+	assert.Equal(t, 129600, gp.SlotsPerKesPeriod)
+	assert.Equal(t, 1, gp.SlotLength)
+	assert.Equal(t, 62, gp.MaxKesEvolutions)
+	assert.Equal(t, 2160, gp.SecurityParam)
+}
+
+func TestOfflineNetworkAndEpoch(t *testing.T) {
+	bf, _ := setupOfflineBlockfrost(t)
+	assert.Equal(t, int(constants.PREPROD), bf.Network())
+
+	epoch, err := bf.Epoch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 180, epoch)
+}
+
+func TestOfflineGetTip(t *testing.T) {
+	bf, _ := setupOfflineBlockfrost(t)
+
+	tip, err := bf.GetTip(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(94000000), tip.Slot)
+	assert.Equal(t, uint64(3600000), tip.Height)
+	assert.Len(t, tip.Hash, 64)
+}
+
+func TestOfflineGetUtxos(t *testing.T) {
+	bf, _ := setupOfflineBlockfrost(t)
+
+	utxos, err := bf.GetUtxosByAddress(context.Background(), offlineDiscoveryAddr)
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 1)
+	assert.True(t, tests.UtxosEqual(utxos[0], tests.ApolloDiscoveryUTxO), tests.UtxoDiff(utxos[0], tests.ApolloDiscoveryUTxO))
+
+	utxos, err = bf.GetUtxosByAddress(context.Background(), tests.AddressToQuery)
+	assert.NoError(t, err)
+	assert.Empty(t, utxos)
+}
+
+func TestOfflineGetUtxosWithUnit(t *testing.T) {
+	bf, _ := setupOfflineBlockfrost(t)
+
+	utxos, err := bf.GetUtxosWithUnit(context.Background(), offlineDiscoveryAddr, offlineDiscoveryUnit)
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 1)
+	assert.True(t, tests.UtxosEqual(utxos[0], tests.ApolloDiscoveryUTxO), tests.UtxoDiff(utxos[0], tests.ApolloDiscoveryUTxO))
+}
+
+func TestOfflineGetUtxoByUnit(t *testing.T) {
+	bf, _ := setupOfflineBlockfrost(t)
+
+	utxo, err := bf.GetUtxoByUnit(context.Background(), offlineDiscoveryUnit)
+	assert.NoError(t, err)
+	assert.NotNil(t, utxo)
+	assert.True(t, tests.UtxosEqual(*utxo, tests.ApolloDiscoveryUTxO), tests.UtxoDiff(*utxo, tests.ApolloDiscoveryUTxO))
+}
+
+func TestOfflineGetUtxosByOutRef(t *testing.T) {
+	bf, _ := setupOfflineBlockfrost(t)
+
+	missing := connector.OutRef{TxHash: offlineAwaitTxHash, Index: 3}
+	utxos, err := bf.GetUtxosByOutRef(context.Background(), []connector.OutRef{
+		missing,
+		{TxHash: offlineDiscoveryTxHash, Index: 0},
+		{TxHash: offlineDiscoveryTxHash, Index: 0},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 1)
+	assert.True(t, tests.UtxosEqual(utxos[0], tests.ApolloDiscoveryUTxO), tests.UtxoDiff(utxos[0], tests.ApolloDiscoveryUTxO))
+}
+
+func TestOfflineGetUtxosByOutRefStrict(t *testing.T) {
+	_, srv := setupOfflineBlockfrost(t)
+	bf, err := New(Config{BaseURL: srv.URL, NetworkName: "preprod", StrictOutRefs: true})
+	assert.NoError(t, err)
+
+	missingTx := connector.OutRef{TxHash: offlineAwaitTxHash, Index: 3}
+	missingIndex := connector.OutRef{TxHash: offlineDiscoveryTxHash, Index: 7}
+	utxos, err := bf.GetUtxosByOutRef(context.Background(), []connector.OutRef{
+		missingTx,
+		{TxHash: offlineDiscoveryTxHash, Index: 0},
+		missingIndex,
+	})
+	var missingErr *connector.MissingOutRefsError
+	assert.True(t, errors.As(err, &missingErr), "got %v", err)
+	assert.True(t, errors.Is(err, connector.ErrNotFound))
+	assert.Equal(t, []connector.OutRef{missingTx, missingIndex}, missingErr.Refs)
+	assert.Len(t, utxos, 1)
+}
+
+func TestOfflineGetDelegation(t *testing.T) {
+	bf, _ := setupOfflineBlockfrost(t)
+
+	delegation, err := bf.GetDelegation(context.Background(), offlineStakeAddr)
+	assert.NoError(t, err)
+	assert.True(t, delegation.Active)
+	assert.Equal(t, uint64(2500), delegation.Rewards)
+	assert.Equal(t, "pool1z22x50lqsrwent6en0llzzs9e577rx7n3mv9kfw7udwa2rf42fa", delegation.PoolId)
+	assert.Equal(t, 150, delegation.Epoch)
+}
+
+func TestOfflineGetDatum(t *testing.T) {
+	bf, _ := setupOfflineBlockfrost(t)
+
+	datum, err := bf.GetDatum(context.Background(), offlineDatumHash)
+	assert.NoError(t, err)
+	datumBytes, err := datum.MarshalCBOR()
+	assert.NoError(t, err)
+	assert.Equal(t, tests.ExpectedDatum, hex.EncodeToString(datumBytes))
+}
+
+func TestOfflineAwaitTx(t *testing.T) {
+	bf, _ := setupOfflineBlockfrost(t)
+
+	isConfirmed, err := bf.AwaitTx(context.Background(), offlineAwaitTxHash, 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, isConfirmed)
+}
+
+func TestOfflineSubmitTxBadRequest(t *testing.T) {
+	bf, srv := setupOfflineBlockfrost(t)
+	srv.Fail("/tx/submit", http.StatusBadRequest, "transaction submit error: DeserialiseFailure 0")
+
+	_, err := bf.SubmitTx(context.Background(), []byte{0x80})
+	assert.True(t, errors.Is(err, connector.ErrTxSubmissionFailed), "got %v", err)
+}
+
+func TestOfflineEvaluateTxSample1(t *testing.T) {
+	bf, srv := setupOfflineBlockfrost(t)
+
+	tx1Bytes, _ := hex.DecodeString(tests.ApolloEvalSample1Transaction)
+	redeemers, err := bf.EvaluateTx(context.Background(), tx1Bytes, tests.ApolloEvalSample1UTxOs)
+	assert.NoError(t, err)
+	ok, diff := tests.RedeemersApproxEqual(redeemers, tests.ApolloEvalSample1RedeemersExUnits, 0.02)
+	assert.True(t, ok, "redeemers mismatch: %s", diff)
+	assert.Equal(t, 1, srv.Requests("/utils/txs/evaluate/utxos"))
+}
+
+func TestOfflineEvaluateTxWithoutAdditionalUtxos(t *testing.T) {
+	tx1Bytes, _ := hex.DecodeString(tests.ApolloEvalSample1Transaction)
+	for _, additional := range [][]common.Utxo{nil, {}} {
+		bf, srv := setupOfflineBlockfrost(t)
+
+		redeemers, err := bf.EvaluateTx(context.Background(), tx1Bytes, additional)
+		assert.NoError(t, err)
+		ok, diff := tests.RedeemersApproxEqual(redeemers, tests.ApolloEvalSample1RedeemersExUnits, 0.02)
+		assert.True(t, ok, "redeemers mismatch: %s", diff)
+		assert.Equal(t, 1, srv.Requests("/utils/txs/evaluate"))
+		assert.Equal(t, 0, srv.Requests("/utils/txs/evaluate/utxos"))
+		assert.Equal(t, tests.ApolloEvalSample1Transaction, string(srv.LastBody("/utils/txs/evaluate")))
+	}
+}
+
+func TestOfflineGetScriptCborByScriptHash(t *testing.T) {
+	bf, _ := setupOfflineBlockfrost(t)
+
+	scriptCbor, err := bf.GetScriptCborByScriptHash(context.Background(), tests.ScriptHashToQuery)
+	assert.NoError(t, err)
+	assert.Equal(t, tests.ExpectedScriptCbor, scriptCbor)
+}
+
+func TestOfflineGetNetworkInfo(t *testing.T) {
+	bf, _ := setupOfflineBlockfrost(t)
+
+	info, err := bf.GetNetworkInfo(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, connector.NetworkInfo{
+		Supply: connector.NetworkSupply{
+			Max:         45000000000000000,
+			Total:       37105630785934829,
+			Circulating: 36925481016283469,
+			Locked:      8540358437474,
+			Treasury:    1488212011622452,
+			Reserves:    7894369214065171,
+		},
+		Stake: connector.NetworkStake{Live: 21962546434327378, Active: 21950437268587186},
+	}, info)
+}
+
+func TestOfflineGetNetworkInfoRejectsBadAmount(t *testing.T) {
+	fixtures := offlineFixtures()
+	fixtures.Routes["/network"] = strings.Replace(fixtures.Routes["/network"], `"locked": "8540358437474"`, `"locked": null`, 1)
+	bf, _ := newOfflineBlockfrost(t, fixtures, Config{})
+
+	_, err := bf.GetNetworkInfo(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "supply.locked")
+}
+
+func TestOfflineInjectedFailureAndLatency(t *testing.T) {
+	bf, srv := setupOfflineBlockfrost(t)
+
+	srv.Fail("/blocks", http.StatusTooManyRequests, "Project over limit")
+	_, err := bf.GetTip(context.Background())
+	assert.True(t, errors.Is(err, connector.ErrRateLimited), "got %v", err)
+
+	srv.ClearFailures()
+	srv.SetLatency(time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = bf.GetTip(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+}
@@ -0,0 +1,111 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// pageOptions overrides paginate's defaults for a single listing.
+type pageOptions struct {
+	// order, when set, replaces the provider's configured order.
+	order string
+	// notFoundErr returns a 404 on the first page as an error rather than
+	// an empty result, for endpoints where it means the resource is unknown.
+	notFoundErr bool
+	// from and to, when non-zero, bound the listing to a block height range.
+	from, to uint64
+}
+
+// queryPageOptions translates caller query options for an endpoint. Only
+// ranged endpoints accept from/to; elsewhere the range is dropped so an
+// unsupported option never changes the request.
+func queryPageOptions(opts []connector.QueryOption, ranged bool) (pageOptions, error) {
+	q, err := connector.NewQueryOptions(opts...)
+	if err != nil {
+		return pageOptions{}, err
+	}
+	po := pageOptions{order: string(q.Order)}
+	if ranged {
+		po.from, po.to = q.From, q.To
+	}
+	return po, nil
+}
+
+// paginate fetches every page of the Blockfrost list endpoint at basePath
+// using the provider's page size, ordering and page limit. It stops at the
+// first short or empty page; a 404 on the first page is an empty result.
+// When the page limit is hit with results remaining, the items fetched so far
+// are returned together with an error wrapping connector.ErrTruncated.
+func paginate[T any](ctx context.Context, b *BlockfrostProvider, basePath string) ([]T, error) {
+	return paginateWith[T](ctx, b, basePath, pageOptions{})
+}
+
+// paginateWith is paginate with the defaults overridden by opts.
+func paginateWith[T any](
+	ctx context.Context,
+	b *BlockfrostProvider,
+	basePath string,
+	opts pageOptions,
+) ([]T, error) {
+	if opts.order == "" {
+		opts.order = b.order
+	}
+	items := make([]T, 0, b.pageSize)
+
+	for page := 1; ; page++ {
+		var pageItems []T
+		err := b.doRequest(ctx, "GET", b.pagePath(basePath, page, opts), nil, &pageItems)
+		if err != nil {
+			if page == 1 && !opts.notFoundErr && errors.Is(err, connector.ErrNotFound) {
+				return items, nil
+			}
+			return nil, err
+		}
+
+		if len(pageItems) == 0 {
+			break
+		}
+		if b.maxPages > 0 && page > b.maxPages {
+			return items, fmt.Errorf(
+				"%w: more than %d pages of %d items at %s",
+				connector.ErrTruncated,
+				b.maxPages,
+				b.pageSize,
+				basePath,
+			)
+		}
+
+		items = append(items, pageItems...)
+
+		if len(pageItems) < b.pageSize {
+			break
+		}
+	}
+
+	return items, nil
+}
+
+// pagePath appends the pagination, ordering and range query parameters for
+// the given page to a Blockfrost list path.
+func (b *BlockfrostProvider) pagePath(basePath string, page int, opts pageOptions) string {
+	sep := "?"
+	if strings.Contains(basePath, "?") {
+		sep = "&"
+	}
+	path := fmt.Sprintf("%s%scount=%d&page=%d", basePath, sep, b.pageSize, page)
+	if opts.order != "" {
+		path += "&order=" + opts.order
+	}
+	if opts.from != 0 {
+		path += "&from=" + strconv.FormatUint(opts.from, 10)
+	}
+	if opts.to != 0 {
+		path += "&to=" + strconv.FormatUint(opts.to, 10)
+	}
+	return path
+}
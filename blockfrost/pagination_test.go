@@ -0,0 +1,194 @@
+package blockfrost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// newPagedUtxoServer serves total UTxOs for testAddr, honouring count= and
+// page=, and records the number of requests and the last order= seen.
+func newPagedUtxoServer(t *testing.T, total int, requests *atomic.Int32, order *atomic.Value) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if order != nil {
+			order.Store(r.URL.Query().Get("order"))
+		}
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if count == 0 || page == 0 {
+			t.Errorf("missing count/page in %s", r.URL.RawQuery)
+		}
+		items := []bfAddressUTxO{}
+		for i := (page - 1) * count; i < total && i < page*count; i++ {
+			items = append(items, bfAddressUTxO{
+				Address:     testAddr,
+				TxHash:      fmt.Sprintf("%064x", i+1),
+				OutputIndex: 0,
+				Amount:      []bfAddressAmount{{Unit: "lovelace", Quantity: "1000000"}},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(items)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGetUtxosByAddressFullFinalPage(t *testing.T) {
+	var requests atomic.Int32
+	srv := newPagedUtxoServer(t, 4, &requests, nil)
+
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test", PageSize: 2})
+	assert.NoError(t, err)
+
+	utxos, err := provider.GetUtxosByAddress(context.Background(), testAddr)
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 4)
+	// Two full pages, then an empty page terminates pagination.
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestGetUtxosByAddressMaxPagesExactFit(t *testing.T) {
+	var requests atomic.Int32
+	srv := newPagedUtxoServer(t, 4, &requests, nil)
+
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test", PageSize: 2, MaxPages: 2})
+	assert.NoError(t, err)
+
+	utxos, err := provider.GetUtxosByAddress(context.Background(), testAddr)
+	assert.NoError(t, err, "results that exactly fill the page limit are not truncated")
+	assert.Len(t, utxos, 4)
+}
+
+func TestGetUtxosWithUnitMaxPagesTruncates(t *testing.T) {
+	var requests atomic.Int32
+	order := &atomic.Value{}
+	srv := newPagedUtxoServer(t, 5, &requests, order)
+
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test", PageSize: 2, MaxPages: 2, Order: "DESC"})
+	assert.NoError(t, err)
+
+	utxos, err := provider.GetUtxosWithUnit(context.Background(), testAddr, "lovelace")
+	assert.True(t, errors.Is(err, connector.ErrTruncated), "got %v", err)
+	assert.Len(t, utxos, 4, "partial results are returned alongside ErrTruncated")
+	assert.Equal(t, "desc", order.Load())
+}
+
+func TestNewRejectsInvalidPagination(t *testing.T) {
+	for _, cfg := range []Config{
+		{BaseURL: "http://localhost", PageSize: 101},
+		{BaseURL: "http://localhost", PageSize: -1},
+		{BaseURL: "http://localhost", MaxPages: -1},
+		{BaseURL: "http://localhost", Order: "newest"},
+	} {
+		_, err := New(cfg)
+		assert.Error(t, err, "%+v", cfg)
+	}
+}
+
+// newPageServer serves total integers in pages of count= and answers failPage
+// (when non-zero) with a 500.
+func newPageServer(t *testing.T, total, failPage int, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == failPage {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"status_code":500,"error":"Internal Server Error","message":"boom"}`))
+			return
+		}
+		items := []int{}
+		for i := (page - 1) * count; i < total && i < page*count; i++ {
+			items = append(items, i)
+		}
+		_ = json.NewEncoder(w).Encode(items)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestPaginateExactMultipleOfPageSize(t *testing.T) {
+	var requests atomic.Int32
+	srv := newPageServer(t, 6, 0, &requests)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test", PageSize: 3})
+	assert.NoError(t, err)
+
+	items, err := paginate[int](context.Background(), provider, "/items")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, items)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestPaginateShortPageStops(t *testing.T) {
+	var requests atomic.Int32
+	srv := newPageServer(t, 5, 0, &requests)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test", PageSize: 3})
+	assert.NoError(t, err)
+
+	items, err := paginate[int](context.Background(), provider, "/items")
+	assert.NoError(t, err)
+	assert.Len(t, items, 5)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestPaginateEmptyFirstPage(t *testing.T) {
+	var requests atomic.Int32
+	srv := newPageServer(t, 0, 0, &requests)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	items, err := paginate[int](context.Background(), provider, "/items")
+	assert.NoError(t, err)
+	assert.NotNil(t, items)
+	assert.Empty(t, items)
+}
+
+func TestPaginateNotFoundOnFirstPage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"status_code":404,"error":"Not Found","message":"The requested component has not been found."}`))
+	}))
+	defer srv.Close()
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	items, err := paginate[int](context.Background(), provider, "/items")
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestPaginateErrorOnThirdPage(t *testing.T) {
+	var requests atomic.Int32
+	srv := newPageServer(t, 10, 3, &requests)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test", PageSize: 2})
+	assert.NoError(t, err)
+
+	items, err := paginate[int](context.Background(), provider, "/items")
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, connector.ErrTruncated))
+	assert.Nil(t, items)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestPaginateMaxPagesGuard(t *testing.T) {
+	var requests atomic.Int32
+	srv := newPageServer(t, 10, 0, &requests)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test", PageSize: 2, MaxPages: 2})
+	assert.NoError(t, err)
+
+	items, err := paginate[int](context.Background(), provider, "/items")
+	assert.True(t, errors.Is(err, connector.ErrTruncated), "got %v", err)
+	assert.Equal(t, []int{0, 1, 2, 3}, items)
+}
@@ -0,0 +1,92 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+var _ connector.PoolProvider = (*BlockfrostProvider)(nil)
+
+// bfPoolMetadata is the /pools/{pool_id}/metadata response. Blockfrost
+// answers {} or null for pools that never registered a metadata URL, and
+// leaves the off-chain fields null when the document could not be fetched.
+type bfPoolMetadata struct {
+	PoolId      string  `json:"pool_id"`
+	URL         *string `json:"url"`
+	Hash        *string `json:"hash"`
+	Ticker      *string `json:"ticker"`
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Homepage    *string `json:"homepage"`
+}
+
+// bfPoolUpdate is an entry of /pools/{pool_id}/updates.
+type bfPoolUpdate struct {
+	TxHash    string `json:"tx_hash"`
+	CertIndex int    `json:"cert_index"`
+	Action    string `json:"action"`
+}
+
+// GetPoolMetadata returns the pool's metadata. A pool without off-chain
+// metadata is not an error; only the fields Blockfrost knows are set.
+func (b *BlockfrostProvider) GetPoolMetadata(ctx context.Context, poolId string) (connector.PoolMetadata, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+	var metadata bfPoolMetadata
+	if err := b.doRequest(ctx, "GET", "/pools/"+poolId+"/metadata", nil, &metadata); err != nil {
+		return connector.PoolMetadata{}, fmt.Errorf("failed to get metadata of pool %s: %w", poolId, err)
+	}
+	if metadata.PoolId == "" {
+		metadata.PoolId = poolId
+	}
+	return connector.PoolMetadata{
+		PoolId:      metadata.PoolId,
+		URL:         stringOrEmpty(metadata.URL),
+		Hash:        stringOrEmpty(metadata.Hash),
+		Ticker:      stringOrEmpty(metadata.Ticker),
+		Name:        stringOrEmpty(metadata.Name),
+		Description: stringOrEmpty(metadata.Description),
+		Homepage:    stringOrEmpty(metadata.Homepage),
+	}, nil
+}
+
+// GetPoolUpdates returns the pool's registration and retirement certificates,
+// oldest first whatever Config.Order says. When Config.MaxPages cuts the list
+// short, the updates fetched so far are returned with an error wrapping
+// connector.ErrTruncated.
+func (b *BlockfrostProvider) GetPoolUpdates(ctx context.Context, poolId string) ([]connector.PoolUpdate, error) {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+	entries, pageErr := paginateWith[bfPoolUpdate](ctx, b, "/pools/"+poolId+"/updates", pageOptions{
+		order:       "asc",
+		notFoundErr: true,
+	})
+	if pageErr != nil && !errors.Is(pageErr, connector.ErrTruncated) {
+		return nil, fmt.Errorf("failed to get updates of pool %s: %w", poolId, pageErr)
+	}
+
+	updates := make([]connector.PoolUpdate, 0, len(entries))
+	for _, entry := range entries {
+		switch entry.Action {
+		case connector.PoolActionRegistered, connector.PoolActionDeregistered:
+		default:
+			return nil, fmt.Errorf("unknown action %q in updates of pool %s at tx %s", entry.Action, poolId, entry.TxHash)
+		}
+		updates = append(updates, connector.PoolUpdate{
+			TxHash:    entry.TxHash,
+			CertIndex: entry.CertIndex,
+			Action:    entry.Action,
+		})
+	}
+	return updates, pageErr
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,94 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+const (
+	activePool  = "pool1pu5jlj4q9w9jlxeu370a3c9myx47md5j5m2str0naunn2q3lkdy"
+	retiredPool = "pool1z5uqdk7dzdxaae5633fqfcu2eqzy3a3rgtuvy087fdld7yws0xt"
+)
+
+func poolFixtures() map[string]string {
+	return map[string]string{
+		"/pools/" + activePool + "/metadata": `{
+			"pool_id": "` + activePool + `",
+			"hex": "0f292fcaa02b8b2f9b3c8f9fd8e0bb21abedb692a6d5058df3ef2735",
+			"url": "https://stakenuts.com/mainnet.json",
+			"hash": "47c0c68cb57f4a5b4a87bad896fc274678e7aea98e200fa14a1cb40c0cab1d8c",
+			"ticker": "NUTS",
+			"name": "Stake Nuts",
+			"description": "The best pool ever",
+			"homepage": "https://stakenuts.com/"
+		}`,
+		"/pools/" + activePool + "/updates": `[
+			{"tx_hash": "` + strings.Repeat("01", 32) + `", "cert_index": 0, "action": "registered"}
+		]`,
+		"/pools/" + retiredPool + "/metadata": `null`,
+		"/pools/" + retiredPool + "/updates": `[
+			{"tx_hash": "` + strings.Repeat("02", 32) + `", "cert_index": 0, "action": "registered"},
+			{"tx_hash": "` + strings.Repeat("03", 32) + `", "cert_index": 1, "action": "deregistered"}
+		]`,
+	}
+}
+
+func newPoolBlockfrost(t *testing.T) *BlockfrostProvider {
+	t.Helper()
+	fixtures := offlineFixtures()
+	for path, body := range poolFixtures() {
+		fixtures.Routes[path] = body
+	}
+	bf, _ := newOfflineBlockfrost(t, fixtures, Config{})
+	return bf
+}
+
+func TestGetPoolMetadata(t *testing.T) {
+	bf := newPoolBlockfrost(t)
+
+	metadata, err := bf.GetPoolMetadata(context.Background(), activePool)
+	assert.NoError(t, err)
+	assert.Equal(t, connector.PoolMetadata{
+		PoolId:      activePool,
+		URL:         "https://stakenuts.com/mainnet.json",
+		Hash:        "47c0c68cb57f4a5b4a87bad896fc274678e7aea98e200fa14a1cb40c0cab1d8c",
+		Ticker:      "NUTS",
+		Name:        "Stake Nuts",
+		Description: "The best pool ever",
+		Homepage:    "https://stakenuts.com/",
+	}, metadata)
+
+	metadata, err = bf.GetPoolMetadata(context.Background(), retiredPool)
+	assert.NoError(t, err)
+	assert.Equal(t, connector.PoolMetadata{PoolId: retiredPool}, metadata)
+}
+
+func TestGetPoolUpdates(t *testing.T) {
+	bf := newPoolBlockfrost(t)
+
+	updates, err := bf.GetPoolUpdates(context.Background(), retiredPool)
+	assert.NoError(t, err)
+	assert.Equal(t, []connector.PoolUpdate{
+		{TxHash: strings.Repeat("02", 32), CertIndex: 0, Action: connector.PoolActionRegistered},
+		{TxHash: strings.Repeat("03", 32), CertIndex: 1, Action: connector.PoolActionDeregistered},
+	}, updates)
+
+	updates, err = bf.GetPoolUpdates(context.Background(), activePool)
+	assert.NoError(t, err)
+	assert.Len(t, updates, 1)
+}
+
+func TestGetPoolUnknown(t *testing.T) {
+	bf := newPoolBlockfrost(t)
+	unknown := "pool1unknown"
+
+	_, err := bf.GetPoolMetadata(context.Background(), unknown)
+	assert.True(t, errors.Is(err, connector.ErrNotFound), "got %v", err)
+	_, err = bf.GetPoolUpdates(context.Background(), unknown)
+	assert.True(t, errors.Is(err, connector.ErrNotFound), "got %v", err)
+}
@@ -0,0 +1,109 @@
+package blockfrost
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Salvionied/apollo/v2/backend"
+)
+
+// defaultProtocolParamsTTL bounds how stale cached protocol parameters can be.
+// They only change at epoch boundaries, so a few minutes is plenty.
+const defaultProtocolParamsTTL = 5 * time.Minute
+
+// protocolParamsCache caches protocol parameters for a TTL and collapses
+// concurrent fetches into a single upstream request. A nil cache, or one with
+// a zero TTL, always fetches.
+type protocolParamsCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	timeout  time.Duration // bounds each fetch; zero means unbounded
+	params   backend.ProtocolParameters
+	expires  time.Time
+	inflight *protocolParamsCall
+	gen      uint64 // bumped by invalidate so in-flight results are not stored
+}
+
+type protocolParamsCall struct {
+	done   chan struct{}
+	gen    uint64
+	params backend.ProtocolParameters
+	err    error
+}
+
+func newProtocolParamsCache(ttl, timeout time.Duration) *protocolParamsCache {
+	return &protocolParamsCache{ttl: ttl, timeout: timeout}
+}
+
+// get returns the cached parameters or joins/starts a fetch. The fetch runs
+// detached from ctx, bounded by the cache's timeout instead, so that one
+// caller giving up does not fail the others waiting on it; each caller still
+// returns as soon as its own ctx is done.
+func (c *protocolParamsCache) get(
+	ctx context.Context,
+	fetch func(context.Context) (backend.ProtocolParameters, error),
+) (backend.ProtocolParameters, error) {
+	if c == nil || c.ttl <= 0 {
+		return fetch(ctx)
+	}
+
+	c.mu.Lock()
+	if time.Now().Before(c.expires) {
+		params := c.params
+		c.mu.Unlock()
+		return params, nil
+	}
+	call := c.inflight
+	if call == nil {
+		call = &protocolParamsCall{done: make(chan struct{}), gen: c.gen}
+		c.inflight = call
+		go c.run(context.WithoutCancel(ctx), call, fetch)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return backend.ProtocolParameters{}, ctx.Err()
+	case <-call.done:
+		return call.params, call.err
+	}
+}
+
+func (c *protocolParamsCache) run(
+	ctx context.Context,
+	call *protocolParamsCall,
+	fetch func(context.Context) (backend.ProtocolParameters, error),
+) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	call.params, call.err = fetch(ctx)
+
+	c.mu.Lock()
+	if call.err == nil && call.gen == c.gen {
+		c.params = call.params
+		c.expires = time.Now().Add(c.ttl)
+	}
+	if c.inflight == call {
+		c.inflight = nil
+	}
+	c.mu.Unlock()
+	close(call.done)
+}
+
+// invalidate drops the cached parameters; a fetch already in flight is still
+// returned to its callers but not cached.
+func (c *protocolParamsCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expires = time.Time{}
+	c.gen++
+	// The next caller starts a fresh fetch instead of joining a stale one.
+	c.inflight = nil
+}
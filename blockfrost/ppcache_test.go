@@ -0,0 +1,140 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Salvionied/apollo/v2/backend"
+	"github.com/tj/assert"
+	"github.com/zenGate-Global/cardano-connector-go/blockfrost/blockfrosttest"
+)
+
+const protocolParamsPath = "/epochs/latest/parameters"
+
+func newProtocolParamsServer(t *testing.T) *blockfrosttest.Server {
+	t.Helper()
+	srv := blockfrosttest.NewServer(blockfrosttest.Fixtures{
+		ProtocolParameters: offlineFixtures().ProtocolParameters,
+	})
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestProtocolParamsCacheSharesConcurrentFetch(t *testing.T) {
+	srv := newProtocolParamsServer(t)
+	srv.SetLatency(50 * time.Millisecond)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	const callers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pp, err := provider.GetProtocolParameters(context.Background())
+			if err == nil && pp.MinFeeCoefficient != 44 {
+				t.Errorf("unexpected min fee coefficient %v", pp.MinFeeCoefficient)
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, srv.Requests(protocolParamsPath))
+
+	// Within the TTL, later calls are served from the cache.
+	_, err = provider.GetProtocolParameters(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, srv.Requests(protocolParamsPath))
+}
+
+func TestProtocolParamsCacheInvalidate(t *testing.T) {
+	srv := newProtocolParamsServer(t)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	_, err = provider.GetProtocolParameters(context.Background())
+	assert.NoError(t, err)
+	provider.InvalidateProtocolParameters()
+	_, err = provider.GetProtocolParameters(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, srv.Requests(protocolParamsPath))
+}
+
+func TestProtocolParamsCacheExpires(t *testing.T) {
+	srv := newProtocolParamsServer(t)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test", ProtocolParamsTTL: 20 * time.Millisecond})
+	assert.NoError(t, err)
+
+	_, err = provider.GetProtocolParameters(context.Background())
+	assert.NoError(t, err)
+	time.Sleep(40 * time.Millisecond)
+	_, err = provider.GetProtocolParameters(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, srv.Requests(protocolParamsPath))
+}
+
+func TestProtocolParamsCacheDisabled(t *testing.T) {
+	srv := newProtocolParamsServer(t)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test", DisableProtocolParamsCache: true})
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = provider.GetProtocolParameters(context.Background())
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 3, srv.Requests(protocolParamsPath))
+}
+
+func TestProtocolParamsCacheIsPerProvider(t *testing.T) {
+	srv := newProtocolParamsServer(t)
+	for i := 0; i < 2; i++ {
+		provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+		assert.NoError(t, err)
+		_, err = provider.GetProtocolParameters(context.Background())
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, srv.Requests(protocolParamsPath))
+}
+
+func TestProtocolParamsCacheDoesNotCacheErrors(t *testing.T) {
+	srv := newProtocolParamsServer(t)
+	srv.Fail(protocolParamsPath, 500, "boom")
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	_, err = provider.GetProtocolParameters(context.Background())
+	assert.Error(t, err)
+	srv.ClearFailures()
+	_, err = provider.GetProtocolParameters(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestProtocolParamsCacheBoundsDetachedFetch(t *testing.T) {
+	cache := newProtocolParamsCache(time.Minute, 20*time.Millisecond)
+	done := make(chan error, 1)
+	go func() {
+		// The fetch outlives its caller's context, so only the cache's
+		// timeout can end it.
+		_, err := cache.get(context.Background(), func(ctx context.Context) (backend.ProtocolParameters, error) {
+			<-ctx.Done()
+			return backend.ProtocolParameters{}, ctx.Err()
+		})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("detached fetch was not bounded")
+	}
+}
@@ -0,0 +1,78 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+func TestProjectErrorStatuses(t *testing.T) {
+	cases := []struct {
+		status   int
+		message  string
+		code     string
+		sentinel error
+	}{
+		{http.StatusPaymentRequired, "Project over limit", ProviderCodeQuotaExceeded, connector.ErrRateLimited},
+		{http.StatusForbidden, "Invalid project token.", ProviderCodeInvalidProjectToken, ErrInvalidProjectKey},
+		{http.StatusTeapot, "Client has been auto-banned for flooding too much requests", ProviderCodeBanned, connector.ErrRateLimited},
+		{http.StatusTooEarly, "Mempool is full, please try resubmitting again later.", ProviderCodeMempoolFull, connector.ErrTxSubmissionFailed},
+		{http.StatusTooManyRequests, "Project over limit", ProviderCodeRateLimited, connector.ErrRateLimited},
+	}
+
+	for _, tc := range cases {
+		t.Run(http.StatusText(tc.status), func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = fmt.Fprintf(w, `{"status_code":%d,"error":%q,"message":%q}`, tc.status, http.StatusText(tc.status), tc.message)
+			}))
+			defer srv.Close()
+
+			provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+			assert.NoError(t, err)
+
+			_, err = provider.GetTip(context.Background())
+			var apiErr *connector.APIError
+			assert.True(t, errors.As(err, &apiErr), "got %v", err)
+			assert.Equal(t, tc.status, apiErr.StatusCode)
+			assert.Equal(t, tc.code, apiErr.ProviderCode)
+			assert.Equal(t, tc.message, apiErr.Message)
+			if tc.sentinel != nil {
+				assert.True(t, errors.Is(err, tc.sentinel), "expected %v, got %v", tc.sentinel, err)
+			}
+			assert.False(t, errors.Is(err, connector.ErrNotFound))
+		})
+	}
+}
+
+func TestProjectErrorMempoolFullOnSubmit(t *testing.T) {
+	bf := newSubmitEndpoint(t, http.StatusTooEarly, `{"status_code":425,"error":"Mempool Full","message":"Mempool is full, please try resubmitting again later."}`, nil)
+	provider := newSubmitProvider(t, bf, SubmitPrimaryThenFallback)
+
+	_, err := provider.SubmitTx(context.Background(), []byte{0x84})
+	assert.True(t, errors.Is(err, connector.ErrTxSubmissionFailed), "got %v", err)
+	var apiErr *connector.APIError
+	assert.True(t, errors.As(err, &apiErr), "got %v", err)
+	assert.Equal(t, ProviderCodeMempoolFull, apiErr.ProviderCode)
+}
+
+func TestProjectErrorOnEvaluate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"status_code":429,"error":"Too Many Requests","message":"Project over limit"}`))
+	}))
+	defer srv.Close()
+
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	_, err = provider.EvaluateTx(context.Background(), []byte{0x84}, nil)
+	assert.True(t, errors.Is(err, connector.ErrRateLimited), "got %v", err)
+	assert.False(t, errors.Is(err, connector.ErrEvaluationFailed), "got %v", err)
+}
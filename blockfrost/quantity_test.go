@@ -0,0 +1,90 @@
+package blockfrost
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Salvionied/apollo/v2/backend"
+	"github.com/tj/assert"
+)
+
+const (
+	quantityPolicy = "c0ffee00c0ffee00c0ffee00c0ffee00c0ffee00c0ffee00c0ffee00"
+	quantityName   = "746f6b656e"
+	// aboveInt64 is 2^63, one past the largest int64.
+	aboveInt64 = "9223372036854775808"
+)
+
+func quantityUtxo(lovelace, assetQty string) bfAddressUTxO {
+	return bfAddressUTxO{
+		TxHash:      strings.Repeat("ab", 32),
+		OutputIndex: 0,
+		Amount: []bfAddressAmount{
+			{Unit: "lovelace", Quantity: lovelace},
+			{Unit: quantityPolicy + quantityName, Quantity: assetQty},
+		},
+	}
+}
+
+func TestToUtxoQuantityAboveInt64(t *testing.T) {
+	raw := quantityUtxo(aboveInt64, aboveInt64)
+	utxo, err := raw.toUtxo(mustTestAddr(t))
+	assert.NoError(t, err)
+
+	assert.Equal(t, aboveInt64, utxo.Output.Amount().String())
+	policyId, assetName, err := backend.ParseAssetUnit(quantityPolicy + quantityName)
+	assert.NoError(t, err)
+	assert.Equal(t, aboveInt64, utxo.Output.Assets().Asset(policyId, assetName.Bytes()).String())
+
+	// The additional-UTxO encoding for EvaluateTx keeps the full quantity.
+	item, err := bfAdditionalUtxoItemFromUtxo(utxo)
+	assert.NoError(t, err)
+	encoded, err := json.Marshal(item)
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), `"lovelace":`+aboveInt64)
+	assert.Contains(t, string(encoded), `"`+quantityName+`":`+aboveInt64)
+}
+
+func TestToUtxoRejectsInvalidQuantities(t *testing.T) {
+	cases := []struct {
+		name     string
+		lovelace string
+		asset    string
+	}{
+		{"lovelace overflow", "18446744073709551616", "1"},
+		{"asset overflow", "1", "18446744073709551616"},
+		{"negative lovelace", "-1", "1"},
+		{"negative asset", "1", "-1"},
+		{"malformed asset", "1", "1e6"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			raw := quantityUtxo(tc.lovelace, tc.asset)
+			_, err := raw.toUtxo(mustTestAddr(t))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestAdaptDelegationRejectsInvalidWithdrawable(t *testing.T) {
+	delegation, err := adaptBlockfrostAccountToDelegation(BlockfrostAccountDetails{WithdrawableAmount: aboveInt64})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(9223372036854775808), delegation.Rewards)
+
+	_, err = adaptBlockfrostAccountToDelegation(BlockfrostAccountDetails{WithdrawableAmount: "not-a-number"})
+	assert.Error(t, err)
+}
+
+func TestParseQuantity(t *testing.T) {
+	qty, err := parseQuantity("18446744073709551615")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(18446744073709551615), qty)
+
+	_, err = parseQuantity("18446744073709551616")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "overflows uint64")
+
+	_, err = parseQuantity("12abc")
+	assert.Error(t, err)
+}
@@ -0,0 +1,117 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// newQueryRecorder records the query string of every request and serves
+// three transactions at /addresses/{testAddr}/transactions, paged by count
+// and page, and an empty list anywhere else.
+func newQueryRecorder(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		if r.URL.Path != "/addresses/"+testAddr+"/transactions" {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		body := "["
+		for i := (page - 1) * count; i < 3 && i < page*count; i++ {
+			if body != "[" {
+				body += ","
+			}
+			body += fmt.Sprintf(`{"tx_hash": "%064x", "tx_index": %d, "block_height": %d, "block_time": %d}`, i+1, i, 100+i, 1700000000+i)
+		}
+		_, _ = w.Write([]byte(body + "]"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), queries...)
+	}
+}
+
+func TestGetAddressTransactionsQueryString(t *testing.T) {
+	srv, queries := newQueryRecorder(t)
+	provider, err := New(Config{BaseURL: srv.URL, PageSize: 2})
+	assert.NoError(t, err)
+
+	txs, err := provider.GetAddressTransactions(context.Background(), testAddr,
+		connector.WithOrder(connector.OrderDesc), connector.WithFromTo(100, 200))
+	assert.NoError(t, err)
+	assert.Len(t, txs, 3)
+	assert.Equal(t, connector.AddressTransaction{
+		TxHash:      fmt.Sprintf("%064x", 3),
+		TxIndex:     2,
+		BlockHeight: 102,
+		BlockTime:   1700000002,
+	}, txs[2])
+	assert.Equal(t, []string{
+		"count=2&page=1&order=desc&from=100&to=200",
+		"count=2&page=2&order=desc&from=100&to=200",
+	}, queries())
+}
+
+func TestGetAddressTransactionsOpenRange(t *testing.T) {
+	srv, queries := newQueryRecorder(t)
+	provider, err := New(Config{BaseURL: srv.URL, PageSize: 5})
+	assert.NoError(t, err)
+
+	_, err = provider.GetAddressTransactions(context.Background(), testAddr, connector.WithFromTo(101, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"count=5&page=1&from=101"}, queries())
+}
+
+func TestQueryOptionsIgnoredWhereUnsupported(t *testing.T) {
+	srv, queries := newQueryRecorder(t)
+	provider, err := New(Config{BaseURL: srv.URL, PageSize: 5})
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = provider.GetUtxosByAddressWith(ctx, testAddr,
+		connector.WithOrder(connector.OrderDesc), connector.WithFromTo(1, 2))
+	assert.NoError(t, err)
+	_, err = provider.GetUtxosByAddressWith(ctx, testAddr)
+	assert.NoError(t, err)
+	_, err = provider.GetAssetHistory(ctx, historyUnit, connector.WithFromTo(1, 2))
+	assert.NoError(t, err)
+	_, err = provider.GetAssetHistory(ctx, historyUnit, connector.WithOrder(connector.OrderDesc))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"count=5&page=1&order=desc",
+		"count=5&page=1",
+		"count=5&page=1&order=asc",
+		"count=5&page=1&order=desc",
+	}, queries())
+}
+
+func TestQueryOptionsRejectInvalid(t *testing.T) {
+	srv, queries := newQueryRecorder(t)
+	provider, err := New(Config{BaseURL: srv.URL})
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = provider.GetAddressTransactions(ctx, testAddr, connector.WithFromTo(200, 100))
+	assert.True(t, errors.Is(err, connector.ErrInvalidInput), "got %v", err)
+	_, err = provider.GetUtxosByAddressWith(ctx, testAddr, connector.WithOrder("newest"))
+	assert.True(t, errors.Is(err, connector.ErrInvalidInput), "got %v", err)
+	assert.Empty(t, queries())
+}
@@ -0,0 +1,59 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+)
+
+func TestRateLimiterPacesRequestsAfterBurst(t *testing.T) {
+	var mu sync.Mutex
+	var arrivals []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"epoch": 1}`))
+	}))
+	defer srv.Close()
+
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test", RateLimit: 20, RateBurst: 2})
+	assert.NoError(t, err)
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		_, err := provider.Epoch(context.Background())
+		assert.NoError(t, err)
+	}
+	elapsed := time.Since(start)
+
+	// Two requests fit in the burst; the remaining four are paced at 50ms each.
+	assert.True(t, elapsed >= 180*time.Millisecond, "requests were not paced: %v", elapsed)
+	assert.Len(t, arrivals, 6)
+	assert.True(t, arrivals[1].Sub(arrivals[0]) < 40*time.Millisecond, "burst requests should not wait")
+}
+
+func TestRateLimiterRespectsContextCancellation(t *testing.T) {
+	limiter := newRateLimiter(1, 1)
+	assert.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := limiter.Wait(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+	assert.True(t, time.Since(start) < 500*time.Millisecond, "wait should stop at the context deadline")
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	provider, err := New(Config{BaseURL: "http://localhost", DisableRateLimit: true})
+	assert.NoError(t, err)
+	assert.Nil(t, provider.limiter)
+	assert.NoError(t, provider.limiter.Wait(context.Background()))
+}
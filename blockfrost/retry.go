@@ -0,0 +1,25 @@
+package blockfrost
+
+import (
+	"net/http"
+	"strings"
+)
+
+// retryable reports whether a request that failed with status may be sent
+// again. GETs are idempotent and retried on transient server errors. A
+// submission is only retried on 503, which Blockfrost's edge returns before
+// forwarding the transaction; after a 500, 502 or 504 it may already have
+// reached the node. 4xx responses, and failures without a response, are final.
+func retryable(method, path string, status int) bool {
+	switch method {
+	case http.MethodGet:
+		switch status {
+		case http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	case http.MethodPost:
+		return strings.HasSuffix(path, "/tx/submit") && status == http.StatusServiceUnavailable
+	}
+	return false
+}
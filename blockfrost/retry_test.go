@@ -0,0 +1,140 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// newFlakyServer answers the first failures requests with status and every
+// later one with body.
+func newFlakyServer(t *testing.T, failures int32, status int, body string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) <= failures {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"status_code": 0, "error": "flaky", "message": "flaky"}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func newRetryProvider(t *testing.T, baseURL string, retry connector.RetryPolicy) *BlockfrostProvider {
+	t.Helper()
+	provider, err := New(Config{BaseURL: baseURL, DisableRateLimit: true, Retry: retry})
+	assert.NoError(t, err)
+	return provider
+}
+
+var fastRetry = connector.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, Jitter: 0.5}
+
+func TestRetryGetRecovers(t *testing.T) {
+	for _, status := range []int{500, 502, 503, 504} {
+		srv, hits := newFlakyServer(t, 2, status, `{"epoch": 9}`)
+		bf := newRetryProvider(t, srv.URL, fastRetry)
+
+		epoch, err := bf.Epoch(context.Background())
+		assert.NoError(t, err, "status %d", status)
+		assert.Equal(t, 9, epoch)
+		assert.Equal(t, int32(3), hits.Load())
+	}
+}
+
+func TestRetryGetGivesUp(t *testing.T) {
+	srv, hits := newFlakyServer(t, 10, http.StatusBadGateway, `{"epoch": 9}`)
+	bf := newRetryProvider(t, srv.URL, fastRetry)
+
+	_, err := bf.Epoch(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, int32(3), hits.Load())
+}
+
+func TestRetryStopsOn4xx(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusNotFound, http.StatusForbidden, http.StatusTooManyRequests} {
+		srv, hits := newFlakyServer(t, 10, status, `{"epoch": 9}`)
+		bf := newRetryProvider(t, srv.URL, fastRetry)
+
+		_, err := bf.Epoch(context.Background())
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), hits.Load(), "status %d", status)
+	}
+}
+
+func TestRetryDisabledByDefault(t *testing.T) {
+	srv, hits := newFlakyServer(t, 1, http.StatusBadGateway, `{"epoch": 9}`)
+	bf := newRetryProvider(t, srv.URL, connector.RetryPolicy{})
+
+	_, err := bf.Epoch(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), hits.Load())
+}
+
+func TestRetrySubmitOnlyWhenNotForwarded(t *testing.T) {
+	txHash := `"` + offlineDiscoveryTxHash + `"`
+
+	srv, hits := newFlakyServer(t, 1, http.StatusServiceUnavailable, txHash)
+	bf := newRetryProvider(t, srv.URL, fastRetry)
+	got, err := bf.SubmitTx(context.Background(), []byte{0x84})
+	assert.NoError(t, err)
+	assert.Equal(t, offlineDiscoveryTxHash, got)
+	assert.Equal(t, int32(2), hits.Load())
+
+	for _, status := range []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout} {
+		srv, hits := newFlakyServer(t, 1, status, txHash)
+		bf := newRetryProvider(t, srv.URL, fastRetry)
+		_, err := bf.SubmitTx(context.Background(), []byte{0x84})
+		assert.True(t, errors.Is(err, connector.ErrTxSubmissionFailed), "got %v", err)
+		assert.Equal(t, int32(1), hits.Load(), "status %d", status)
+	}
+}
+
+func TestRetryRespectsDeadline(t *testing.T) {
+	srv, hits := newFlakyServer(t, 10, http.StatusServiceUnavailable, `{"epoch": 9}`)
+	bf := newRetryProvider(t, srv.URL, connector.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := bf.Epoch(ctx)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+	assert.Equal(t, int32(1), hits.Load())
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p, err := connector.RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}.WithDefaults()
+	assert.NoError(t, err)
+	assert.Equal(t, 100*time.Millisecond, p.Delay(1))
+	assert.Equal(t, 200*time.Millisecond, p.Delay(2))
+	assert.Equal(t, 800*time.Millisecond, p.Delay(4))
+	assert.Equal(t, time.Second, p.Delay(5))
+	assert.Equal(t, time.Second, p.Delay(64))
+
+	p.Jitter = 0.25
+	for range 100 {
+		d := p.Delay(1)
+		assert.True(t, d >= 75*time.Millisecond && d <= 125*time.Millisecond, "delay %v", d)
+	}
+}
+
+func TestNewRejectsInvalidRetryPolicy(t *testing.T) {
+	for _, p := range []connector.RetryPolicy{
+		{MaxAttempts: -1},
+		{MaxAttempts: 3, BaseDelay: -time.Second},
+		{MaxAttempts: 3, Jitter: 1.5},
+	} {
+		_, err := New(Config{NetworkName: "preprod", Retry: p})
+		assert.True(t, errors.Is(err, connector.ErrInvalidInput), "%+v: got %v", p, err)
+	}
+}
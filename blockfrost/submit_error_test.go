@@ -0,0 +1,90 @@
+package blockfrost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// Rejection bodies as returned by Blockfrost's /tx/submit when the node
+// refuses a transaction.
+const (
+	bfRejectBadInputsBabbage = `{"error":"Bad Request","message":"\"transaction submit error ShelleyTxValidationError ShelleyBasedEraBabbage (ApplyTxError [UtxowFailure (UtxoFailure (FromAlonzoUtxoFail (BadInputsUTxO (fromList [TxIn (TxId {_unTxId = SafeHash \\\"1a8f6b0bd2d1e5a7b3a36b9a2bbee3b1a3a8f4d6c9e1f0b2a4c6d8e0f1a3b5c7\\\"}) (TxIx 0)]))))])\"","status_code":400}`
+
+	bfRejectValueAndFeeConway = `{"error":"Bad Request","message":"{\"contents\":{\"contents\":{\"contents\":{\"era\":\"ShelleyBasedEraConway\",\"error\":[\"ConwayUtxowFailure (UtxoFailure (ValueNotConservedUTxO (MaryValue (Coin 9000000) (MultiAsset (fromList []))) (MaryValue (Coin 10000000) (MultiAsset (fromList [])))))\",\"ConwayUtxowFailure (UtxoFailure (FeeTooSmallUTxO (Coin 168317) (Coin 100000)))\"],\"kind\":\"ShelleyTxValidationError\"},\"tag\":\"TxValidationErrorInCardanoMode\"},\"tag\":\"TxCmdTxSubmitValidationError\"},\"tag\":\"TxSubmitFail\"}","status_code":400}`
+
+	bfRejectOutsideValidity = `{"error":"Bad Request","message":"\"transaction submit error ShelleyTxValidationError ShelleyBasedEraConway (ApplyTxError (ConwayUtxowFailure (UtxoFailure (OutsideValidityIntervalUTxO (ValidityInterval {invalidBefore = SNothing, invalidHereafter = SJust (SlotNo 71536123)}) (SlotNo 71540000))) :| []))\"","status_code":400}`
+
+	bfRejectMaxTxSize = `{"error":"Bad Request","message":"\"transaction submit error ShelleyTxValidationError ShelleyBasedEraConway (ApplyTxError (ConwayUtxowFailure (UtxoFailure (MaxTxSizeUTxO 17250 16384)) :| []))\"","status_code":400}`
+
+	bfRejectScriptFailure = `{"error":"Bad Request","message":"\"transaction submit error ShelleyTxValidationError ShelleyBasedEraConway (ApplyTxError (ConwayUtxowFailure (UtxoFailure (UtxosFailure (ValidationTagMismatch (IsValid True) (FailedUnexpectedly (PlutusFailure \\\"The machine terminated because of an error\\\" :| []))))) :| []))\"","status_code":400}`
+
+	bfRejectUnclassified = `{"error":"Bad Request","message":"\"transaction submit error ShelleyTxValidationError ShelleyBasedEraConway (ApplyTxError (ConwayUtxowFailure (MissingVKeyWitnessesUTXOW (fromList [])) :| []))\"","status_code":400}`
+)
+
+func bfRejectMessage(t *testing.T, body string) string {
+	t.Helper()
+	var bfErr struct {
+		Message string `json:"message"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(body), &bfErr))
+	return bfErr.Message
+}
+
+func TestParseSubmitError(t *testing.T) {
+	cases := []struct {
+		name    string
+		body    string
+		kind    error
+		reasons []string
+	}{
+		{"bad inputs", bfRejectBadInputsBabbage, connector.ErrBadInputs, []string{"BadInputsUTxO"}},
+		{
+			"value not conserved and fee too small",
+			bfRejectValueAndFeeConway,
+			connector.ErrValueNotConserved,
+			[]string{"ValueNotConservedUTxO", "FeeTooSmallUTxO"},
+		},
+		{
+			"outside validity interval",
+			bfRejectOutsideValidity,
+			connector.ErrOutsideValidityInterval,
+			[]string{"OutsideValidityIntervalUTxO"},
+		},
+		{"max tx size", bfRejectMaxTxSize, connector.ErrTxTooLarge, []string{"MaxTxSizeUTxO"}},
+		{"script failure", bfRejectScriptFailure, connector.ErrEvaluationFailed, []string{"ValidationTagMismatch"}},
+		{"unclassified", bfRejectUnclassified, nil, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			subErr := parseSubmitError(http.StatusBadRequest, bfRejectMessage(t, tc.body))
+			assert.Equal(t, tc.reasons, subErr.Reasons)
+			assert.True(t, errors.Is(subErr, connector.ErrTxSubmissionFailed))
+			if tc.kind != nil {
+				assert.True(t, errors.Is(subErr, tc.kind), "expected %v, got %v", tc.kind, subErr)
+			} else {
+				assert.Nil(t, subErr.Kind)
+			}
+		})
+	}
+}
+
+func TestSubmitTxReturnsStructuredRejection(t *testing.T) {
+	bf := newSubmitEndpoint(t, http.StatusBadRequest, bfRejectBadInputsBabbage, nil)
+	provider := newSubmitProvider(t, bf, SubmitPrimaryThenFallback)
+
+	_, err := provider.SubmitTx(context.Background(), []byte{0x84})
+	assert.True(t, errors.Is(err, connector.ErrTxSubmissionFailed), "got %v", err)
+	assert.True(t, errors.Is(err, connector.ErrBadInputs), "got %v", err)
+
+	var subErr *connector.SubmissionError
+	assert.True(t, errors.As(err, &subErr))
+	assert.Equal(t, http.StatusBadRequest, subErr.StatusCode)
+	assert.Equal(t, []string{"BadInputsUTxO"}, subErr.Reasons)
+}
@@ -0,0 +1,155 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+const submitTestTxHash = "8ae470ef0000000000000000000000000000000000000000000000000000beef"
+
+func newSubmitEndpoint(t *testing.T, status int, body string, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits != nil {
+			hits.Add(1)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newSubmitProvider(t *testing.T, blockfrost *httptest.Server, strategy SubmitStrategy, endpoints ...string) *BlockfrostProvider {
+	t.Helper()
+	provider, err := New(Config{
+		BaseURL:                   blockfrost.URL,
+		ProjectID:                 "test",
+		CustomSubmissionEndpoints: endpoints,
+		SubmitStrategy:            strategy,
+	})
+	assert.NoError(t, err)
+	return provider
+}
+
+func TestSubmitTxCustomEndpointSuccess(t *testing.T) {
+	var bfHits atomic.Int32
+	bf := newSubmitEndpoint(t, http.StatusBadRequest, `{"status_code":400,"message":"should not be called"}`, &bfHits)
+	custom := newSubmitEndpoint(t, http.StatusAccepted, `"`+submitTestTxHash+`"`, nil)
+
+	provider := newSubmitProvider(t, bf, SubmitPrimaryThenFallback, custom.URL)
+	txHash, err := provider.SubmitTx(context.Background(), []byte{0x84})
+	assert.NoError(t, err)
+	assert.Equal(t, submitTestTxHash, txHash)
+	assert.Equal(t, int32(0), bfHits.Load())
+}
+
+func TestSubmitTxMixedCustomEndpoints(t *testing.T) {
+	failing := newSubmitEndpoint(t, http.StatusInternalServerError, "boom", nil)
+	ok := newSubmitEndpoint(t, http.StatusOK, submitTestTxHash, nil)
+	bf := newSubmitEndpoint(t, http.StatusInternalServerError, "unused", nil)
+
+	provider := newSubmitProvider(t, bf, SubmitPrimaryThenFallback, failing.URL, ok.URL)
+	txHash, err := provider.SubmitTx(context.Background(), []byte{0x84})
+	assert.NoError(t, err)
+	assert.Equal(t, submitTestTxHash, txHash)
+}
+
+func TestSubmitTxFallsBackToBlockfrost(t *testing.T) {
+	failing := newSubmitEndpoint(t, http.StatusServiceUnavailable, "down", nil)
+	bf := newSubmitEndpoint(t, http.StatusOK, `"`+submitTestTxHash+`"`, nil)
+
+	provider := newSubmitProvider(t, bf, SubmitPrimaryThenFallback, failing.URL)
+	txHash, err := provider.SubmitTx(context.Background(), []byte{0x84})
+	assert.NoError(t, err)
+	assert.Equal(t, submitTestTxHash, txHash)
+}
+
+func TestSubmitTxAllFailJoinsErrors(t *testing.T) {
+	first := newSubmitEndpoint(t, http.StatusInternalServerError, "first endpoint down", nil)
+	second := newSubmitEndpoint(t, http.StatusBadGateway, "second endpoint down", nil)
+	bf := newSubmitEndpoint(t, http.StatusInternalServerError, `{"status_code":500,"message":"blockfrost down"}`, nil)
+
+	for _, strategy := range []SubmitStrategy{SubmitPrimaryThenFallback, SubmitFirstSuccess} {
+		provider := newSubmitProvider(t, bf, strategy, first.URL, second.URL)
+		_, err := provider.SubmitTx(context.Background(), []byte{0x84})
+		assert.True(t, errors.Is(err, connector.ErrTxSubmissionFailed), "got %v", err)
+		for _, want := range []string{first.URL, "first endpoint down", second.URL, "second endpoint down", "blockfrost down"} {
+			assert.True(t, strings.Contains(err.Error(), want), "error %q should mention %q", err, want)
+		}
+	}
+}
+
+func TestSubmitTxFirstSuccessIncludesBlockfrost(t *testing.T) {
+	failing := newSubmitEndpoint(t, http.StatusInternalServerError, "down", nil)
+	bf := newSubmitEndpoint(t, http.StatusOK, `"`+submitTestTxHash+`"`, nil)
+
+	provider := newSubmitProvider(t, bf, SubmitFirstSuccess, failing.URL)
+	txHash, err := provider.SubmitTx(context.Background(), []byte{0x84})
+	assert.NoError(t, err)
+	assert.Equal(t, submitTestTxHash, txHash)
+}
+
+func TestSubmitTxLosingAttemptsOutliveMethodTimeout(t *testing.T) {
+	// finished reports whether the slow endpoint answered or saw its request
+	// aborted.
+	finished := make(chan bool, 1)
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+			finished <- true
+			_, _ = w.Write([]byte(`"` + submitTestTxHash + `"`))
+		case <-r.Context().Done():
+			finished <- false
+		}
+	}))
+	t.Cleanup(slow.Close)
+	fast := newSubmitEndpoint(t, http.StatusOK, `"`+submitTestTxHash+`"`, nil)
+	bf := newSubmitEndpoint(t, http.StatusInternalServerError, "unused", nil)
+
+	provider, err := New(Config{
+		BaseURL:                   bf.URL,
+		ProjectID:                 "test",
+		CustomSubmissionEndpoints: []string{slow.URL, fast.URL},
+		MethodTimeout:             5 * time.Second,
+	})
+	assert.NoError(t, err)
+
+	txHash, err := provider.SubmitTx(context.Background(), []byte{0x84})
+	assert.NoError(t, err)
+	assert.Equal(t, submitTestTxHash, txHash)
+
+	select {
+	case answered := <-finished:
+		assert.True(t, answered, "the slow endpoint's submission was aborted")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the slow endpoint never finished")
+	}
+}
+
+func TestSubmitTxStopsWaitingAtCallerDeadline(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(slow.Close)
+	t.Cleanup(func() { close(release) })
+	bf := newSubmitEndpoint(t, http.StatusInternalServerError, "unused", nil)
+
+	provider := newSubmitProvider(t, bf, SubmitFirstSuccess, slow.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := provider.SubmitTx(ctx, []byte{0x84})
+	assert.True(t, errors.Is(err, connector.ErrTimeout), "got %v", err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,43 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// methodContext bounds a provider method by Config.MethodTimeout when the
+// caller's context carries no deadline of its own.
+func (b *BlockfrostProvider) methodContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if b.methodTimeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, b.methodTimeout)
+}
+
+// requestContext bounds a single HTTP request by Config.RequestTimeout.
+func (b *BlockfrostProvider) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if b.requestTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, b.requestTimeout)
+}
+
+// timeoutError wraps err with connector.ErrTimeout when it stems from an
+// expired deadline or a client-side network timeout.
+func timeoutError(err error) error {
+	if err == nil || errors.Is(err, connector.ErrTimeout) {
+		return err
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w: %w", connector.ErrTimeout, err)
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	bf, srv := newOfflineBlockfrost(t, offlineFixtures(), Config{RequestTimeout: 50 * time.Millisecond})
+	srv.SetLatency(time.Second)
+
+	start := time.Now()
+	_, err := bf.Epoch(context.Background())
+	assert.True(t, errors.Is(err, connector.ErrTimeout), "got %v", err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	srv.SetLatency(0)
+	_, err = bf.Epoch(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestMethodTimeout(t *testing.T) {
+	bf, srv := newOfflineBlockfrost(t, offlineFixtures(), Config{MethodTimeout: 50 * time.Millisecond})
+	srv.SetLatency(time.Second)
+
+	start := time.Now()
+	_, err := bf.GetTip(context.Background())
+	assert.True(t, errors.Is(err, connector.ErrTimeout), "got %v", err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	_, err = bf.GetProtocolParameters(context.Background())
+	assert.True(t, errors.Is(err, connector.ErrTimeout), "got %v", err)
+}
+
+func TestMethodTimeoutDefersToCallerDeadline(t *testing.T) {
+	bf, srv := newOfflineBlockfrost(t, offlineFixtures(), Config{MethodTimeout: 50 * time.Millisecond})
+	srv.SetLatency(150 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := bf.GetTip(ctx)
+	assert.NoError(t, err)
+}
+
+func TestCallerCancellationIsNotTimeout(t *testing.T) {
+	bf, srv := newOfflineBlockfrost(t, offlineFixtures(), Config{})
+	srv.SetLatency(time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	_, err := bf.GetTip(ctx)
+	assert.True(t, errors.Is(err, context.Canceled), "got %v", err)
+	assert.False(t, errors.Is(err, connector.ErrTimeout), "got %v", err)
+}
+
+func TestNewRejectsNegativeTimeouts(t *testing.T) {
+	_, err := New(Config{NetworkName: "preprod", RequestTimeout: -time.Second})
+	assert.Error(t, err)
+	_, err = New(Config{NetworkName: "preprod", MethodTimeout: -time.Second})
+	assert.Error(t, err)
+}
@@ -0,0 +1,58 @@
+package blockfrost
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultRequestTimeout      = 30 * time.Second
+	defaultMaxIdleConnsPerHost = 16
+	defaultIdleConnTimeout     = 90 * time.Second
+)
+
+// newHTTPClient builds the client used when Config.HTTPClient is not set: a
+// keep-alive transport sized for many concurrent requests to one host.
+// Compression is negotiated by doRequest itself, so the transport's implicit
+// gzip handling is disabled.
+func newHTTPClient(config Config) (*http.Client, error) {
+	maxIdleConnsPerHost := config.MaxIdleConnsPerHost
+	if maxIdleConnsPerHost == 0 {
+		maxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	}
+	idleConnTimeout := config.IdleConnTimeout
+	if idleConnTimeout == 0 {
+		idleConnTimeout = defaultIdleConnTimeout
+	}
+	if maxIdleConnsPerHost < 0 || idleConnTimeout < 0 {
+		return nil, fmt.Errorf(
+			"max idle conns per host and idle conn timeout must not be negative, got %d and %v",
+			maxIdleConnsPerHost,
+			idleConnTimeout,
+		)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = max(transport.MaxIdleConns, maxIdleConnsPerHost)
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	transport.IdleConnTimeout = idleConnTimeout
+	transport.DisableCompression = true
+	return &http.Client{Timeout: defaultRequestTimeout, Transport: transport}, nil
+}
+
+// readBody reads a response body, decompressing it when the server answered
+// with Content-Encoding: gzip.
+func readBody(resp *http.Response) ([]byte, error) {
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		return io.ReadAll(resp.Body)
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip response: %w", err)
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
@@ -0,0 +1,161 @@
+package blockfrost
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+const transportTestAddr = "addr_test1wpgexmeunzsykesf42d4eqet5yvzeap6trjnflxqtkcf66g0kpnxt"
+
+// utxoPageJSON renders a full page of lovelace-only UTxOs at transportTestAddr.
+func utxoPageJSON(page, size int) []byte {
+	var sb strings.Builder
+	sb.WriteString("[")
+	for i := 0; i < size; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, `{"address":%q,"tx_hash":"%064x","output_index":%d,`+
+			`"amount":[{"unit":"lovelace","quantity":"2000000"}],"inline_datum":null,"reference_script_hash":null}`,
+			transportTestAddr, page, i)
+	}
+	sb.WriteString("]")
+	return []byte(sb.String())
+}
+
+// newCompressingUtxoServer serves pages UTxO pages, gzip-compressing them when
+// the request accepts gzip, and counts the response bytes put on the wire.
+func newCompressingUtxoServer(tb testing.TB, pages int, wireBytes *atomic.Int64) *httptest.Server {
+	tb.Helper()
+	bodies := make([][]byte, pages)
+	for i := range bodies {
+		bodies[i] = utxoPageJSON(i+1, maxPageSize)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		body := []byte(`[]`)
+		if page >= 1 && page <= pages {
+			body = bodies[page-1]
+		}
+		counter := &countingWriter{w: w, n: wireBytes}
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			_, _ = counter.Write(body)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(counter)
+		_, _ = zw.Write(body)
+		_ = zw.Close()
+	}))
+	tb.Cleanup(srv.Close)
+	return srv
+}
+
+type countingWriter struct {
+	w http.ResponseWriter
+	n *atomic.Int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.n != nil {
+		c.n.Add(int64(len(p)))
+	}
+	return c.w.Write(p)
+}
+
+func TestDoRequestDecompressesGzip(t *testing.T) {
+	var wire atomic.Int64
+	srv := newCompressingUtxoServer(t, 2, &wire)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test", DisableRateLimit: true})
+	assert.NoError(t, err)
+
+	utxos, err := provider.GetUtxosByAddress(context.Background(), transportTestAddr)
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 2*maxPageSize)
+	assert.True(t, wire.Load() < int64(2*len(utxoPageJSON(1, maxPageSize))), "responses were not compressed")
+}
+
+func TestDoRequestCompressionOptOut(t *testing.T) {
+	var sawGzip atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") == "gzip" {
+			sawGzip.Store(true)
+		}
+		_, _ = w.Write([]byte(`{"epoch": 1}`))
+	}))
+	defer srv.Close()
+
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test", DisableCompression: true})
+	assert.NoError(t, err)
+	_, err = provider.Epoch(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, sawGzip.Load())
+
+	// A caller-supplied client is used as is; its transport decides.
+	client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	provider, err = New(Config{BaseURL: srv.URL, ProjectID: "test", HTTPClient: client})
+	assert.NoError(t, err)
+	_, err = provider.Epoch(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, sawGzip.Load())
+}
+
+func TestEvaluateTxReportsUnreadableBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write([]byte("not gzip"))
+	}))
+	defer srv.Close()
+
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test", DisableRateLimit: true})
+	assert.NoError(t, err)
+	_, err = provider.EvaluateTx(context.Background(), []byte{0x84}, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read response body")
+}
+
+func TestNewRejectsNegativeTransportSettings(t *testing.T) {
+	_, err := New(Config{BaseURL: "http://localhost", MaxIdleConnsPerHost: -1})
+	assert.Error(t, err)
+}
+
+func BenchmarkGetUtxosByAddressCompression(b *testing.B) {
+	for _, tc := range []struct {
+		name    string
+		disable bool
+	}{
+		{"gzip", false},
+		{"identity", true},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			var wire atomic.Int64
+			srv := newCompressingUtxoServer(b, 5, &wire)
+			provider, err := New(Config{
+				BaseURL:            srv.URL,
+				ProjectID:          "test",
+				DisableRateLimit:   true,
+				DisableCompression: tc.disable,
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := provider.GetUtxosByAddress(context.Background(), transportTestAddr); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(wire.Load())/float64(b.N), "wire-B/op")
+		})
+	}
+}
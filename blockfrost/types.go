@@ -2,7 +2,11 @@ package blockfrost
 
 import (
 	"encoding/json"
+	"log/slog"
 	"net/http"
+	"time"
+
+	connector "github.com/zenGate-Global/cardano-connector-go"
 )
 
 type BlockfrostProvider struct {
@@ -12,6 +16,23 @@ type BlockfrostProvider struct {
 	networkName               string // e.g., "mainnet", "preprod" (used for default URL)
 	networkId                 int
 	customSubmissionEndpoints []string
+	submitStrategy            SubmitStrategy
+	pageSize                  int
+	maxPages                  int
+	order                     string
+	limiter                   *rateLimiter
+	confirmationDepth         int
+	awaitTxCheckMempool       bool
+	gzip                      bool
+	ppCache                   *protocolParamsCache
+	strictOutRefs             bool
+	lenientEvaluation         bool
+	requestTimeout            time.Duration
+	methodTimeout             time.Duration
+	unitCache                 *unitAddressCache
+	retry                     connector.RetryPolicy
+	logger                    *slog.Logger
+	debugHTTP                 bool
 }
 
 // --- BlockFrost evaluate-with-utxos request types ---
@@ -33,7 +54,7 @@ type bfTxIn struct {
 // bfValue is the Ogmios-v6 value object. The "ada" entry carries lovelace under
 // "lovelace"; every other entry is keyed by policy id hex and maps asset name
 // hex (empty string for the empty asset name) to quantity.
-type bfValue map[string]map[string]int64
+type bfValue map[string]map[string]uint64
 
 type bfScriptRef struct {
 	PlutusV1 *string `json:"plutus:v1,omitempty"`
@@ -67,12 +88,116 @@ type bfScriptCbor struct {
 type Config struct {
 	ProjectID                 string
 	NetworkName               string // e.g., "mainnet", "preprod", "preview"
-	NetworkId                 int
-	BaseURL                   string // Optional: if you need to override default Blockfrost URL
+	NetworkId                 int    // apollo constants.Network; derived from NetworkName when zero
+	BaseURL                   string // Optional: API root used verbatim, e.g. https://cardano-preprod.blockfrost.io/api/v0
 	HTTPClient                *http.Client
 	CustomSubmissionEndpoints []string // For custom tx submission
+	SubmitStrategy            SubmitStrategy
+	// PageSize is the number of items requested per page (count=) by paginated
+	// queries, between 1 and 100. Defaults to 100.
+	PageSize int
+	// MaxPages bounds the number of pages a paginated query may fetch; 0 means
+	// unlimited. When more results remain, the query returns the results fetched
+	// so far together with an error wrapping connector.ErrTruncated.
+	MaxPages int
+	// Order is the ordering of paginated results, "asc" (default) or "desc".
+	Order string
+	// RateLimit is the client-side request rate in requests per second shared
+	// by all calls. Defaults to Blockfrost's limit of 10.
+	RateLimit float64
+	// RateBurst is the number of requests that may be sent back to back before
+	// RateLimit applies. Defaults to Blockfrost's burst of 500.
+	RateBurst int
+	// DisableRateLimit turns the client-side rate limiter off, e.g. for
+	// self-hosted backends without Blockfrost's limits.
+	DisableRateLimit bool
+	// ConfirmationDepth is the number of blocks, counting the one that
+	// includes the transaction, that AwaitTx waits for before reporting a
+	// transaction as confirmed. Defaults to 1.
+	ConfirmationDepth int
+	// AwaitTxCheckMempool makes AwaitTx query the mempool while a transaction
+	// is not yet in a block, so that a timeout reports whether it was still
+	// pending or never seen. Costs one extra request per poll.
+	AwaitTxCheckMempool bool
+	// MaxIdleConnsPerHost, IdleConnTimeout and DisableCompression configure
+	// the client built by New and are ignored when HTTPClient is set; a custom
+	// client's transport is used as is and gzip is never requested for it.
+	//
+	// MaxIdleConnsPerHost is the number of keep-alive connections kept open to
+	// the Blockfrost host. Defaults to 16.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle keep-alive connection is kept open.
+	// Defaults to 90s.
+	IdleConnTimeout time.Duration
+	// DisableCompression stops requesting gzip-compressed responses.
+	DisableCompression bool
+	// ProtocolParamsTTL is how long GetProtocolParameters reuses a fetched
+	// result. Defaults to 5 minutes.
+	ProtocolParamsTTL time.Duration
+	// DisableProtocolParamsCache makes every GetProtocolParameters call hit
+	// the API.
+	DisableProtocolParamsCache bool
+	// StrictOutRefs makes GetUtxosByOutRef fail with a
+	// *connector.MissingOutRefsError, alongside the UTxOs it did resolve, when
+	// any requested ref does not exist instead of silently omitting it.
+	StrictOutRefs bool
+	// LenientEvaluation makes EvaluateTx drop, with a logged warning,
+	// redeemers whose key or purpose it cannot parse instead of failing. The
+	// returned ex-units are then incomplete; only use it to keep working
+	// against an evaluator that reports purposes this version does not know.
+	LenientEvaluation bool
+	// APIVersionPath is appended to BaseURL, e.g. "/v0" for
+	// BaseURL "https://cardano-preprod.blockfrost.io/api" or "/api/v1" for a
+	// Yaci Store host. Leave it empty when BaseURL already includes the prefix.
+	APIVersionPath string
+	// RequestTimeout bounds each individual HTTP request, including reading
+	// its body. Zero means no per-request limit beyond the HTTP client's own.
+	RequestTimeout time.Duration
+	// MethodTimeout bounds a whole provider call, e.g. every page of a
+	// paginated UTxO scan, when the caller's context has no deadline. A
+	// caller-supplied deadline always takes precedence. AwaitTx is exempt.
+	// Zero means no default deadline.
+	MethodTimeout time.Duration
+	// UnitAddressCacheTTL enables remembering, for this long, which address
+	// holds a unit looked up with GetUtxoByUnit, saving the
+	// /assets/{unit}/addresses request on later calls. Zero disables it.
+	UnitAddressCacheTTL time.Duration
+	// UnitAddressCacheSize caps how many units are remembered. Defaults to 1024.
+	UnitAddressCacheSize int
+	// Retry retries idempotent requests that fail with a 5xx status, and
+	// submissions Blockfrost answers with 503, backing off exponentially
+	// between attempts. It is disabled unless Retry.MaxAttempts is above one.
+	Retry connector.RetryPolicy
+	// Logger receives the provider's diagnostics. Defaults to a logger that
+	// discards everything.
+	Logger *slog.Logger
+	// DebugHTTP logs every HTTP request to Logger: method, URL, status and
+	// duration at info level, plus the truncated response body at debug
+	// level. The project key is always redacted.
+	DebugHTTP bool
+	// ValidateOnNew makes New call Validate, so a wrong project key, base URL
+	// or network fails construction rather than the first real query.
+	ValidateOnNew bool
 }
 
+// RetryPolicy is the type of Config.Retry. It is connector.RetryPolicy, shared
+// with the other providers; the name is kept so that code written against
+// blockfrost.RetryPolicy still compiles.
+type RetryPolicy = connector.RetryPolicy
+
+// SubmitStrategy controls how SubmitTx combines CustomSubmissionEndpoints with
+// the Blockfrost submit endpoint.
+type SubmitStrategy int
+
+const (
+	// SubmitPrimaryThenFallback submits to all custom endpoints concurrently and
+	// only falls back to Blockfrost when every custom endpoint failed.
+	SubmitPrimaryThenFallback SubmitStrategy = iota
+	// SubmitFirstSuccess submits to the custom endpoints and Blockfrost
+	// concurrently and returns as soon as any of them succeeds.
+	SubmitFirstSuccess
+)
+
 type BlockfrostAccountDetails struct {
 	StakeAddress       string  `json:"stake_address"`
 	Active             bool    `json:"active"`
@@ -84,7 +209,33 @@ type BlockfrostAccountDetails struct {
 	TreasurySum        string  `json:"treasury_sum"`
 	WithdrawableAmount string  `json:"withdrawable_amount"` // This is key for rewards
 	PoolId             *string `json:"pool_id"`             // Nullable; this is the delegation target
-	DeRepId            *string `json:"derep_id"`            // Nullable; this is the delegation target
+	DeRepId            *string `json:"drep_id"`             // Nullable; the voting power delegation target
+}
+
+// bfAddressInfo is the /addresses/{address} response. StakeAddress is null
+// for addresses without a stake part.
+type bfAddressInfo struct {
+	Address      string            `json:"address"`
+	Amount       []bfAddressAmount `json:"amount"`
+	StakeAddress *string           `json:"stake_address"`
+	Type         string            `json:"type"`
+	Script       bool              `json:"script"`
+}
+
+// bfNetwork is the /network response. Amounts are lovelace as decimal strings.
+type bfNetwork struct {
+	Supply struct {
+		Max         string `json:"max"`
+		Total       string `json:"total"`
+		Circulating string `json:"circulating"`
+		Locked      string `json:"locked"`
+		Treasury    string `json:"treasury"`
+		Reserves    string `json:"reserves"`
+	} `json:"supply"`
+	Stake struct {
+		Live   string `json:"live"`
+		Active string `json:"active"`
+	} `json:"stake"`
 }
 
 // bfProtocolParams is the BlockFrost /epochs/latest/parameters response.
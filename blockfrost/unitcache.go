@@ -0,0 +1,150 @@
+package blockfrost
+
+import (
+	"container/list"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// defaultUnitAddressCacheSize bounds how many units GetUtxoByUnit remembers
+// when Config.UnitAddressCacheSize is zero.
+const defaultUnitAddressCacheSize = 1024
+
+// unitAddressCache remembers which address held a unit, and in which UTxO, so
+// GetUtxoByUnit can skip /assets/{unit}/addresses. Entries expire after ttl
+// and the least recently used one is evicted once size is reached. A nil
+// cache remembers nothing.
+type unitAddressCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	size    int
+	entries map[string]*list.Element
+	lru     *list.List // front is most recently used
+}
+
+type unitAddressEntry struct {
+	unit    string
+	address string
+	outRef  connector.OutRef
+	expires time.Time
+}
+
+func newUnitAddressCache(ttl time.Duration, size int) *unitAddressCache {
+	if ttl <= 0 || size <= 0 {
+		return nil
+	}
+	return &unitAddressCache{
+		ttl:     ttl,
+		size:    size,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// address returns the cached holding address of unit, if any.
+func (c *unitAddressCache) address(unit string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[unit]
+	if !ok {
+		return "", false
+	}
+	entry := elem.Value.(*unitAddressEntry)
+	if time.Now().After(entry.expires) {
+		c.remove(elem)
+		return "", false
+	}
+	c.lru.MoveToFront(elem)
+	return entry.address, true
+}
+
+// store records that unit is held by the UTxO outRef at address.
+func (c *unitAddressCache) store(unit, address string, outRef connector.OutRef) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &unitAddressEntry{
+		unit:    unit,
+		address: address,
+		outRef:  outRef,
+		expires: time.Now().Add(c.ttl),
+	}
+	if elem, ok := c.entries[unit]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[unit] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+// forget drops the entry for unit.
+func (c *unitAddressCache) forget(unit string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[unit]; ok {
+		c.remove(elem)
+	}
+}
+
+// forgetSpent drops every entry whose UTxO is among spent.
+func (c *unitAddressCache) forgetSpent(spent []connector.OutRef) {
+	if c == nil || len(spent) == 0 {
+		return
+	}
+	spentSet := make(map[connector.OutRef]bool, len(spent))
+	for _, ref := range spent {
+		spentSet[ref] = true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if spentSet[elem.Value.(*unitAddressEntry).outRef] {
+			c.remove(elem)
+		}
+		elem = next
+	}
+}
+
+// remove unlinks elem. The caller holds c.mu.
+func (c *unitAddressCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*unitAddressEntry).unit)
+}
+
+// spentOutRefs returns the inputs spent by a serialised transaction, or nil
+// when it cannot be decoded.
+func spentOutRefs(txBytes []byte) []connector.OutRef {
+	txType, err := ledger.DetermineTransactionType(txBytes)
+	if err != nil {
+		return nil
+	}
+	tx, err := ledger.NewTransactionFromCbor(txType, txBytes)
+	if err != nil {
+		return nil
+	}
+	inputs := tx.Inputs()
+	refs := make([]connector.OutRef, 0, len(inputs))
+	for _, input := range inputs {
+		refs = append(refs, connector.OutRef{
+			TxHash: hex.EncodeToString(input.Id().Bytes()),
+			Index:  input.Index(),
+		})
+	}
+	return refs
+}
@@ -0,0 +1,123 @@
+package blockfrost
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+	"github.com/zenGate-Global/cardano-connector-go/tests"
+)
+
+const unitCacheOtherAddr = "addr_test1wrqlusc0rxkzfz5206j8mvgxqqkyxfl9gtplm3s26eypzqcxsnfs3"
+
+func TestGetUtxoByUnitCachesAddress(t *testing.T) {
+	bf, srv := newOfflineBlockfrost(t, offlineFixtures(), Config{UnitAddressCacheTTL: time.Minute})
+	ctx := context.Background()
+	assetPath := "/assets/" + offlineDiscoveryUnit + "/addresses"
+	utxoPath := "/addresses/" + offlineDiscoveryAddr + "/utxos/" + offlineDiscoveryUnit
+
+	for range 3 {
+		utxo, err := bf.GetUtxoByUnit(ctx, offlineDiscoveryUnit)
+		assert.NoError(t, err)
+		assert.Equal(t, offlineDiscoveryTxHash, hex.EncodeToString(utxo.Id.Id().Bytes()))
+	}
+	assert.Equal(t, 1, srv.Requests(assetPath))
+	assert.Equal(t, 3, srv.Requests(utxoPath))
+}
+
+func TestGetUtxoByUnitRevalidatesMovedUnit(t *testing.T) {
+	bf, srv := newOfflineBlockfrost(t, offlineFixtures(), Config{UnitAddressCacheTTL: time.Minute})
+	ctx := context.Background()
+	assetPath := "/assets/" + offlineDiscoveryUnit + "/addresses"
+
+	_, err := bf.GetUtxoByUnit(ctx, offlineDiscoveryUnit)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, srv.Requests(assetPath))
+
+	moved := strings.ReplaceAll(
+		offlineFixtures().AddressUtxos[offlineDiscoveryAddr],
+		offlineDiscoveryAddr,
+		unitCacheOtherAddr,
+	)
+	srv.SetAddressUtxos(offlineDiscoveryAddr, "")
+	srv.SetAddressUtxos(unitCacheOtherAddr, moved)
+
+	utxo, err := bf.GetUtxoByUnit(ctx, offlineDiscoveryUnit)
+	assert.NoError(t, err)
+	assert.Equal(t, unitCacheOtherAddr, utxo.Output.Address().String())
+	assert.Equal(t, 2, srv.Requests(assetPath))
+
+	// The new location is cached in turn.
+	_, err = bf.GetUtxoByUnit(ctx, offlineDiscoveryUnit)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, srv.Requests(assetPath))
+}
+
+func TestGetUtxoByUnitWithoutCache(t *testing.T) {
+	bf, srv := newOfflineBlockfrost(t, offlineFixtures(), Config{})
+	ctx := context.Background()
+
+	for range 2 {
+		_, err := bf.GetUtxoByUnit(ctx, offlineDiscoveryUnit)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, srv.Requests("/assets/"+offlineDiscoveryUnit+"/addresses"))
+}
+
+func TestSubmitTxForgetsSpentUnits(t *testing.T) {
+	fixtures := offlineFixtures()
+	fixtures.SubmitTxHash = strings.Repeat("ab", 32)
+	bf, _ := newOfflineBlockfrost(t, fixtures, Config{UnitAddressCacheTTL: time.Minute})
+
+	spent := connector.OutRef{
+		TxHash: "35509191d07f018849fa7d32217b70ae33b49983aea21339850d6fcda31b030b",
+		Index:  0,
+	}
+	bf.unitCache.store("spent", offlineDiscoveryAddr, spent)
+	bf.unitCache.store("kept", offlineDiscoveryAddr, connector.OutRef{TxHash: offlineDiscoveryTxHash})
+
+	txBytes, err := hex.DecodeString(tests.ApolloEvalSample1Transaction)
+	assert.NoError(t, err)
+	_, err = bf.SubmitTx(context.Background(), txBytes)
+	assert.NoError(t, err)
+
+	_, ok := bf.unitCache.address("spent")
+	assert.False(t, ok)
+	_, ok = bf.unitCache.address("kept")
+	assert.True(t, ok)
+}
+
+func TestUnitAddressCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newUnitAddressCache(time.Minute, 2)
+	c.store("a", "addr-a", connector.OutRef{})
+	c.store("b", "addr-b", connector.OutRef{})
+	_, _ = c.address("a")
+	c.store("c", "addr-c", connector.OutRef{})
+
+	_, ok := c.address("b")
+	assert.False(t, ok)
+	addr, ok := c.address("a")
+	assert.True(t, ok)
+	assert.Equal(t, "addr-a", addr)
+	_, ok = c.address("c")
+	assert.True(t, ok)
+}
+
+func TestUnitAddressCacheExpires(t *testing.T) {
+	c := newUnitAddressCache(time.Millisecond, 8)
+	c.store("a", "addr-a", connector.OutRef{})
+	time.Sleep(5 * time.Millisecond)
+	_, ok := c.address("a")
+	assert.False(t, ok)
+}
+
+func TestNewRejectsNegativeUnitAddressCache(t *testing.T) {
+	_, err := New(Config{NetworkName: "preprod", UnitAddressCacheTTL: -time.Second})
+	assert.Error(t, err)
+	_, err = New(Config{NetworkName: "preprod", UnitAddressCacheSize: -1})
+	assert.Error(t, err)
+}
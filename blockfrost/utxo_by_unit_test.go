@@ -0,0 +1,89 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+const (
+	unitTestPolicy = "4a83e031d4c37fc7ca6177a2f3581a8eec2ce155da91f59cfdb3bb28"
+	unitTestName   = "546f6b656e"
+	unitTestUnit   = unitTestPolicy + unitTestName
+)
+
+// newUnitServer serves /assets/{unit}/addresses with the given holders and
+// /addresses/{addr}/utxos/{unit} with one UTxO per entry in quantities.
+func newUnitServer(t *testing.T, holders string, quantities ...string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/assets/"):
+			_, _ = w.Write([]byte(holders))
+		case strings.HasPrefix(r.URL.Path, "/addresses/"):
+			if r.URL.Query().Get("page") != "1" {
+				_, _ = w.Write([]byte(`[]`))
+				return
+			}
+			items := make([]string, 0, len(quantities))
+			for i, qty := range quantities {
+				items = append(items, fmt.Sprintf(`{
+					"address": %q,
+					"tx_hash": "%064x",
+					"output_index": 0,
+					"amount": [{"unit":"lovelace","quantity":"2000000"},{"unit":%q,"quantity":%q}]
+				}`, testAddr, i+1, unitTestUnit, qty))
+			}
+			_, _ = w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGetUtxoByUnitFungibleInSingleUtxo(t *testing.T) {
+	srv := newUnitServer(t, `[{"address":"`+testAddr+`","quantity":"1000000"}]`, "1000000")
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	utxo, err := provider.GetUtxoByUnit(context.Background(), unitTestUnit)
+	assert.NoError(t, err)
+	assert.NotNil(t, utxo)
+	assert.Equal(t, fmt.Sprintf("%064x", 1), utxo.Id.Id().String())
+}
+
+func TestGetUtxoByUnitSingleAddressMultipleUtxos(t *testing.T) {
+	srv := newUnitServer(t, `[{"address":"`+testAddr+`","quantity":"1000"}]`, "400", "600")
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	_, err = provider.GetUtxoByUnit(context.Background(), unitTestUnit)
+	assert.True(t, errors.Is(err, connector.ErrMultipleUTXOs), "got %v", err)
+	assert.True(t, strings.Contains(err.Error(), "total quantity 1000"), "got %v", err)
+}
+
+func TestGetUtxoByUnitMultipleAddresses(t *testing.T) {
+	srv := newUnitServer(t, `[{"address":"`+testAddr+`","quantity":"1"},{"address":"`+testAddr+`","quantity":"1"}]`)
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "test"})
+	assert.NoError(t, err)
+
+	_, err = provider.GetUtxoByUnit(context.Background(), unitTestUnit)
+	assert.True(t, errors.Is(err, connector.ErrMultipleUTXOs), "got %v", err)
+}
+
+func TestGetUtxoByUnitRejectsInvalidUnit(t *testing.T) {
+	provider, err := New(Config{BaseURL: "http://localhost", ProjectID: "test"})
+	assert.NoError(t, err)
+
+	_, err = provider.GetUtxoByUnit(context.Background(), "lovelace")
+	assert.True(t, errors.Is(err, connector.ErrInvalidUnit), "got %v", err)
+}
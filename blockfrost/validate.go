@@ -0,0 +1,37 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// bfHealth is the /health response.
+type bfHealth struct {
+	IsHealthy bool `json:"is_healthy"`
+}
+
+// Validate checks that the backend is reachable and healthy, that it accepts
+// the project key and that it serves the configured network. Failures wrap
+// ErrUnreachable, ErrUnhealthy, ErrInvalidProjectKey or ErrNetworkMismatch.
+func (b *BlockfrostProvider) Validate(ctx context.Context) error {
+	ctx, cancel := b.methodContext(ctx)
+	defer cancel()
+
+	var health bfHealth
+	if err := b.doRequest(ctx, "GET", "/health", nil, &health); err != nil {
+		var apiErr *connector.APIError
+		if errors.As(err, &apiErr) {
+			return err
+		}
+		return fmt.Errorf("%w: %s: %w", ErrUnreachable, b.baseURL, err)
+	}
+	if !health.IsHealthy {
+		return fmt.Errorf("%w: %s", ErrUnhealthy, b.baseURL)
+	}
+
+	// /genesis needs a valid key, so this also covers keys /health ignores.
+	return b.VerifyNetwork(ctx)
+}
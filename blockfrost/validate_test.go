@@ -0,0 +1,86 @@
+package blockfrost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+// newValidateServer serves /health and a /genesis reporting networkMagic to
+// requests carrying projectID, and 403 to any other key.
+func newValidateServer(t *testing.T, healthy bool, projectID string, networkMagic int) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("project_id") != projectID {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"status_code":403,"error":"Forbidden","message":"Invalid project token."}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/health":
+			if healthy {
+				_, _ = w.Write([]byte(`{"is_healthy": true}`))
+			} else {
+				_, _ = w.Write([]byte(`{"is_healthy": false}`))
+			}
+		case "/genesis":
+			_, _ = fmt.Fprintf(w, `{"network_magic": %d, "max_lovelace_supply": "45000000000000000"}`, networkMagic)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestValidate(t *testing.T) {
+	srv := newValidateServer(t, true, "preprodKey", 1)
+
+	provider, err := New(Config{BaseURL: srv.URL, ProjectID: "preprodKey", NetworkName: "preprod", ValidateOnNew: true})
+	assert.NoError(t, err)
+	assert.NoError(t, provider.Validate(context.Background()))
+}
+
+func TestValidateWrongKey(t *testing.T) {
+	srv := newValidateServer(t, true, "preprodKey", 1)
+
+	_, err := New(Config{BaseURL: srv.URL, ProjectID: "preprodOther", NetworkName: "preprod", ValidateOnNew: true})
+	assert.True(t, errors.Is(err, ErrInvalidProjectKey), "got %v", err)
+
+	_, err = New(Config{BaseURL: srv.URL, ProjectID: "mainnetKey", NetworkName: "preprod", ValidateOnNew: true})
+	assert.True(t, errors.Is(err, ErrNetworkMismatch), "got %v", err)
+}
+
+func TestValidateWrongNetwork(t *testing.T) {
+	srv := newValidateServer(t, true, "key", 2)
+
+	_, err := New(Config{BaseURL: srv.URL, ProjectID: "key", NetworkName: "preprod", ValidateOnNew: true})
+	assert.True(t, errors.Is(err, ErrNetworkMismatch), "got %v", err)
+}
+
+func TestValidateUnhealthy(t *testing.T) {
+	srv := newValidateServer(t, false, "key", 1)
+
+	_, err := New(Config{BaseURL: srv.URL, ProjectID: "key", NetworkName: "preprod", ValidateOnNew: true})
+	assert.True(t, errors.Is(err, ErrUnhealthy), "got %v", err)
+}
+
+func TestValidateUnreachable(t *testing.T) {
+	srv := newValidateServer(t, true, "key", 1)
+	srv.Close()
+
+	_, err := New(Config{BaseURL: srv.URL, ProjectID: "key", NetworkName: "preprod", ValidateOnNew: true})
+	assert.True(t, errors.Is(err, ErrUnreachable), "got %v", err)
+}
+
+func TestNewSkipsValidationByDefault(t *testing.T) {
+	srv := newValidateServer(t, false, "key", 2)
+
+	_, err := New(Config{BaseURL: srv.URL, ProjectID: "wrong", NetworkName: "preprod"})
+	assert.NoError(t, err)
+}
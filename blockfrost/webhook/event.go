@@ -0,0 +1,161 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Event types sent by Blockfrost Secure Webhooks.
+const (
+	EventTypeTransaction = "transaction"
+	EventTypeBlock       = "block"
+	EventTypeDelegation  = "delegation"
+	EventTypeEpoch       = "epoch"
+)
+
+// ErrUnexpectedEventType indicates a payload accessor called on an event of
+// another type.
+var ErrUnexpectedEventType = errors.New("webhook: unexpected event type")
+
+// Event is a webhook request body. Payload depends on Type and is decoded by
+// Transactions, Block or Delegations.
+type Event struct {
+	ID         string `json:"id"`
+	WebhookID  string `json:"webhook_id"`
+	Created    int64  `json:"created"`
+	APIVersion int    `json:"api_version"`
+	Type       string `json:"type"`
+	// Payload is the event's data, as sent.
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Amount is a quantity of lovelace or of a native asset unit.
+type Amount struct {
+	Unit     string `json:"unit"`
+	Quantity string `json:"quantity"`
+}
+
+// Transaction is the summary Blockfrost sends for a transaction, as returned
+// by /txs/{hash}.
+type Transaction struct {
+	Hash          string   `json:"hash"`
+	Block         string   `json:"block"`
+	BlockHeight   uint64   `json:"block_height"`
+	BlockTime     int64    `json:"block_time"`
+	Slot          uint64   `json:"slot"`
+	Index         int      `json:"index"`
+	OutputAmount  []Amount `json:"output_amount"`
+	Fees          string   `json:"fees"`
+	Size          int      `json:"size"`
+	ValidContract bool     `json:"valid_contract"`
+}
+
+// TxInput is a transaction input with the output it spends.
+type TxInput struct {
+	Address     string   `json:"address"`
+	Amount      []Amount `json:"amount"`
+	TxHash      string   `json:"tx_hash"`
+	OutputIndex uint32   `json:"output_index"`
+	Collateral  bool     `json:"collateral"`
+	Reference   bool     `json:"reference"`
+}
+
+// TxOutput is a transaction output.
+type TxOutput struct {
+	Address     string   `json:"address"`
+	Amount      []Amount `json:"amount"`
+	OutputIndex uint32   `json:"output_index"`
+	DataHash    *string  `json:"data_hash"`
+	InlineDatum *string  `json:"inline_datum"`
+}
+
+// TransactionEvent is a transaction of a "transaction" event.
+type TransactionEvent struct {
+	Tx      Transaction `json:"tx"`
+	Inputs  []TxInput   `json:"inputs"`
+	Outputs []TxOutput  `json:"outputs"`
+}
+
+// BlockEvent is the block of a "block" event, as returned by /blocks/{hash}.
+type BlockEvent struct {
+	Time          int64   `json:"time"`
+	Height        uint64  `json:"height"`
+	Hash          string  `json:"hash"`
+	Slot          uint64  `json:"slot"`
+	Epoch         uint64  `json:"epoch"`
+	EpochSlot     uint64  `json:"epoch_slot"`
+	SlotLeader    string  `json:"slot_leader"`
+	Size          int     `json:"size"`
+	TxCount       int     `json:"tx_count"`
+	Output        *string `json:"output"`
+	Fees          *string `json:"fees"`
+	PreviousBlock string  `json:"previous_block"`
+	Confirmations int     `json:"confirmations"`
+}
+
+// Delegation is a stake delegation certificate.
+type Delegation struct {
+	Index       int    `json:"index"`
+	CertIndex   int    `json:"cert_index"`
+	Address     string `json:"address"`
+	PoolID      string `json:"pool_id"`
+	ActiveEpoch uint64 `json:"active_epoch"`
+}
+
+// DelegationEvent is a transaction of a "delegation" event with its
+// delegation certificates.
+type DelegationEvent struct {
+	Tx          Transaction  `json:"tx"`
+	Delegations []Delegation `json:"delegations"`
+}
+
+// ParseEvent decodes a webhook request body. Verify the body with
+// VerifySignature first.
+func ParseEvent(body []byte) (*Event, error) {
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("webhook: invalid event: %w", err)
+	}
+	if event.Type == "" {
+		return nil, errors.New("webhook: invalid event: missing type")
+	}
+	return &event, nil
+}
+
+// Transactions decodes the payload of a "transaction" event.
+func (e *Event) Transactions() ([]TransactionEvent, error) {
+	var txs []TransactionEvent
+	if err := e.decode(EventTypeTransaction, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// Block decodes the payload of a "block" event.
+func (e *Event) Block() (BlockEvent, error) {
+	var block BlockEvent
+	if err := e.decode(EventTypeBlock, &block); err != nil {
+		return BlockEvent{}, err
+	}
+	return block, nil
+}
+
+// Delegations decodes the payload of a "delegation" event.
+func (e *Event) Delegations() ([]DelegationEvent, error) {
+	var delegations []DelegationEvent
+	if err := e.decode(EventTypeDelegation, &delegations); err != nil {
+		return nil, err
+	}
+	return delegations, nil
+}
+
+func (e *Event) decode(eventType string, target any) error {
+	if e.Type != eventType {
+		return fmt.Errorf("%w: %s event, not %s", ErrUnexpectedEventType, e.Type, eventType)
+	}
+	if err := json.Unmarshal(e.Payload, target); err != nil {
+		return fmt.Errorf("webhook: invalid %s payload: %w", eventType, err)
+	}
+	return nil
+}
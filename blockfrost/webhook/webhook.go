@@ -0,0 +1,119 @@
+// Package webhook verifies and decodes Blockfrost Secure Webhooks events, so a
+// service can learn about confirmed transactions, new blocks and delegations
+// as they happen instead of polling.
+//
+//	http.HandleFunc("/blockfrost", func(w http.ResponseWriter, r *http.Request) {
+//		body, err := io.ReadAll(r.Body)
+//		if err != nil {
+//			w.WriteHeader(http.StatusBadRequest)
+//			return
+//		}
+//		if err := webhook.VerifySignature(r.Header.Get(webhook.SignatureHeader), body, secret, 0); err != nil {
+//			w.WriteHeader(http.StatusUnauthorized)
+//			return
+//		}
+//		event, err := webhook.ParseEvent(body)
+//		if err != nil {
+//			w.WriteHeader(http.StatusBadRequest)
+//			return
+//		}
+//		if event.Type == webhook.EventTypeTransaction {
+//			txs, _ := event.Transactions()
+//			// ...
+//		}
+//		w.WriteHeader(http.StatusOK)
+//	})
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader is the HTTP header carrying an event's signature.
+const SignatureHeader = "Blockfrost-Signature"
+
+// DefaultTolerance is the replay window used when VerifySignature is given a
+// zero tolerance.
+const DefaultTolerance = 10 * time.Minute
+
+var (
+	// ErrInvalidHeader indicates a signature header without a timestamp or
+	// without any v1 signature.
+	ErrInvalidHeader = errors.New("webhook: malformed signature header")
+	// ErrSignatureMismatch indicates that no v1 signature matches the body.
+	ErrSignatureMismatch = errors.New("webhook: signature does not match")
+	// ErrTimestampOutOfTolerance indicates a correctly signed event whose
+	// timestamp is outside the replay window.
+	ErrTimestampOutOfTolerance = errors.New("webhook: timestamp outside the tolerance window")
+)
+
+// VerifySignature checks the Blockfrost-Signature header of an event against
+// its raw body and the webhook's auth token. The header has the form
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">", possibly with
+// several v1 entries while a token is rotated. Events signed more than
+// tolerance away from now are rejected to prevent replays; a zero tolerance
+// means DefaultTolerance.
+func VerifySignature(header string, body []byte, secret string, tolerance time.Duration) error {
+	return verifySignatureAt(header, body, secret, tolerance, time.Now())
+}
+
+func verifySignatureAt(header string, body []byte, secret string, tolerance time.Duration, now time.Time) error {
+	if tolerance == 0 {
+		tolerance = DefaultTolerance
+	}
+
+	var timestamp string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			// Undecodable signatures simply never match.
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return ErrInvalidHeader
+	}
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp %q", ErrInvalidHeader, timestamp)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+
+	matched := false
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return ErrSignatureMismatch
+	}
+
+	// Only a genuine signature tells whether the timestamp can be trusted.
+	if age := now.Sub(time.Unix(signedAt, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: signed at %d, %s from now", ErrTimestampOutOfTolerance, signedAt, age.Round(time.Second))
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+)
+
+const (
+	testSecret    = "59a1eb46-96f4-4f0b-8a03-b4d26e70593a"
+	testTimestamp = 1650013856
+)
+
+const transactionEvent = `{
+	"id": "47668401-c3a4-42d4-bac1-ad46515924a3",
+	"webhook_id": "cf68eb9c-635f-415e-a5a8-6233638f28d7",
+	"created": 1650013856,
+	"api_version": 1,
+	"type": "transaction",
+	"payload": [
+		{
+			"tx": {
+				"hash": "1a0570af966fb355a7160e4f82d5a80b8681b7955f5d44bec0dce628516157f0",
+				"block": "356b7d7dbb696ccd12775c016941057a9dc70898d87a63fc752271bb46856940",
+				"block_height": 123456,
+				"block_time": 1635505891,
+				"slot": 42000000,
+				"index": 1,
+				"output_amount": [{"unit": "lovelace", "quantity": "42000000"}],
+				"fees": "182485",
+				"size": 433,
+				"valid_contract": true
+			},
+			"inputs": [
+				{
+					"address": "addr_test1wpgexmeunzsykesf42d4eqet5yvzeap6trjnflxqtkcf66g0kpnxt",
+					"amount": [{"unit": "lovelace", "quantity": "42182485"}],
+					"tx_hash": "1e043f100dce12d107f679685acd2fc0610e10f72a92d412794c9773d11d8477",
+					"output_index": 0,
+					"collateral": false,
+					"reference": false
+				}
+			],
+			"outputs": [
+				{
+					"address": "addr_test1wrzqlyffcf5yq3htqge9h9k29zv6d7ny0rqam6d4c5eqdfgg0h7yw",
+					"amount": [{"unit": "lovelace", "quantity": "42000000"}],
+					"output_index": 0,
+					"data_hash": null,
+					"inline_datum": null
+				}
+			]
+		}
+	]
+}`
+
+const blockEvent = `{
+	"id": "b8f6a3b4-8f6e-4d43-9d2b-3a0f6c1e5d21",
+	"webhook_id": "cf68eb9c-635f-415e-a5a8-6233638f28d7",
+	"created": 1650013900,
+	"api_version": 1,
+	"type": "block",
+	"payload": {
+		"time": 1650013890,
+		"height": 3500000,
+		"hash": "4ea1ba291e8eef538635a53e59fddba7810d1679631cc3aed7c8e6c4091a516a",
+		"slot": 58000000,
+		"epoch": 200,
+		"epoch_slot": 12000,
+		"slot_leader": "pool1pu5jlj4q9w9jlxeu370a3c9myx47md5j5m2str0naunn2q3lkdy",
+		"size": 3,
+		"tx_count": 1,
+		"output": "128314491794",
+		"fees": "592661",
+		"previous_block": "43ebccb3ac72c7cebd0d9b755a4b08412c9f5dcb81b8a0ad1e3c197d29d47b05",
+		"confirmations": 0
+	}
+}`
+
+func sign(secret string, timestamp int64, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = fmt.Fprintf(mac, "%d.%s", timestamp, body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	good := sign(testSecret, testTimestamp, transactionEvent)
+	signedAt := time.Unix(testTimestamp, 0)
+
+	cases := []struct {
+		name   string
+		header string
+		body   string
+		secret string
+		now    time.Time
+		want   error
+	}{
+		{"valid", fmt.Sprintf("t=%d,v1=%s", testTimestamp, good), transactionEvent, testSecret, signedAt, nil},
+		{"valid among rotated signatures", fmt.Sprintf("t=%d,v1=%s,v1=%s", testTimestamp, strings.Repeat("00", 32), good), transactionEvent, testSecret, signedAt, nil},
+		{"within tolerance", fmt.Sprintf("t=%d,v1=%s", testTimestamp, good), transactionEvent, testSecret, signedAt.Add(9 * time.Minute), nil},
+		{"tampered body", fmt.Sprintf("t=%d,v1=%s", testTimestamp, good), strings.Replace(transactionEvent, "42000000", "420000000", 1), testSecret, signedAt, ErrSignatureMismatch},
+		{"tampered timestamp", fmt.Sprintf("t=%d,v1=%s", testTimestamp+1, good), transactionEvent, testSecret, signedAt, ErrSignatureMismatch},
+		{"wrong secret", fmt.Sprintf("t=%d,v1=%s", testTimestamp, good), transactionEvent, "other", signedAt, ErrSignatureMismatch},
+		{"replayed", fmt.Sprintf("t=%d,v1=%s", testTimestamp, good), transactionEvent, testSecret, signedAt.Add(11 * time.Minute), ErrTimestampOutOfTolerance},
+		{"from the future", fmt.Sprintf("t=%d,v1=%s", testTimestamp, good), transactionEvent, testSecret, signedAt.Add(-11 * time.Minute), ErrTimestampOutOfTolerance},
+		{"missing timestamp", "v1=" + good, transactionEvent, testSecret, signedAt, ErrInvalidHeader},
+		{"missing signature", fmt.Sprintf("t=%d", testTimestamp), transactionEvent, testSecret, signedAt, ErrInvalidHeader},
+		{"non-numeric timestamp", "t=yesterday,v1=" + good, transactionEvent, testSecret, signedAt, ErrInvalidHeader},
+		{"empty header", "", transactionEvent, testSecret, signedAt, ErrInvalidHeader},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := verifySignatureAt(tc.header, []byte(tc.body), tc.secret, 0, tc.now)
+			if tc.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, tc.want), "expected %v, got %v", tc.want, err)
+		})
+	}
+}
+
+func TestVerifySignatureCustomTolerance(t *testing.T) {
+	header := fmt.Sprintf("t=%d,v1=%s", testTimestamp, sign(testSecret, testTimestamp, blockEvent))
+	now := time.Unix(testTimestamp, 0).Add(time.Hour)
+
+	err := verifySignatureAt(header, []byte(blockEvent), testSecret, 0, now)
+	assert.True(t, errors.Is(err, ErrTimestampOutOfTolerance), "got %v", err)
+	assert.NoError(t, verifySignatureAt(header, []byte(blockEvent), testSecret, 2*time.Hour, now))
+}
+
+func TestParseTransactionEvent(t *testing.T) {
+	event, err := ParseEvent([]byte(transactionEvent))
+	assert.NoError(t, err)
+	assert.Equal(t, EventTypeTransaction, event.Type)
+	assert.Equal(t, int64(1650013856), event.Created)
+
+	txs, err := event.Transactions()
+	assert.NoError(t, err)
+	if assert.Len(t, txs, 1) {
+		assert.Equal(t, "1a0570af966fb355a7160e4f82d5a80b8681b7955f5d44bec0dce628516157f0", txs[0].Tx.Hash)
+		assert.Equal(t, uint64(123456), txs[0].Tx.BlockHeight)
+		assert.Equal(t, "42182485", txs[0].Inputs[0].Amount[0].Quantity)
+		assert.Equal(t, "addr_test1wrzqlyffcf5yq3htqge9h9k29zv6d7ny0rqam6d4c5eqdfgg0h7yw", txs[0].Outputs[0].Address)
+	}
+
+	_, err = event.Block()
+	assert.True(t, errors.Is(err, ErrUnexpectedEventType), "got %v", err)
+}
+
+func TestParseBlockEvent(t *testing.T) {
+	event, err := ParseEvent([]byte(blockEvent))
+	assert.NoError(t, err)
+
+	block, err := event.Block()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3500000), block.Height)
+	assert.Equal(t, uint64(200), block.Epoch)
+	assert.Equal(t, 1, block.TxCount)
+
+	_, err = event.Transactions()
+	assert.True(t, errors.Is(err, ErrUnexpectedEventType), "got %v", err)
+}
+
+func TestParseEventRejectsInvalidBodies(t *testing.T) {
+	for _, body := range []string{``, `[]`, `{"id": "x"}`} {
+		_, err := ParseEvent([]byte(body))
+		assert.Error(t, err, "body %q", body)
+	}
+}
+
+// TestHandler runs the handler from the package documentation.
+func TestHandler(t *testing.T) {
+	var received []TransactionEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := VerifySignature(r.Header.Get(SignatureHeader), body, testSecret, 0); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		event, err := ParseEvent(body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if event.Type == EventTypeTransaction {
+			txs, err := event.Transactions()
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			received = append(received, txs...)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	post := func(header, body string) int {
+		req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set(SignatureHeader, header)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	now := time.Now().Unix()
+	assert.Equal(t, http.StatusOK, post(fmt.Sprintf("t=%d,v1=%s", now, sign(testSecret, now, transactionEvent)), transactionEvent))
+	assert.Equal(t, http.StatusUnauthorized, post(fmt.Sprintf("t=%d,v1=%s", now, sign("other", now, transactionEvent)), transactionEvent))
+	assert.Len(t, received, 1)
+}
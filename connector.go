@@ -2,10 +2,13 @@ package connector
 
 import (
 	"context"
+	"math/big"
+	"slices"
 	"time"
 
 	"github.com/Salvionied/apollo/v2/backend"
 	"github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/blinklabs-io/gouroboros/ledger/mary"
 )
 
 type OutRef struct {
@@ -17,7 +20,11 @@ type Delegation struct {
 	Active  bool   `json:"active"`
 	Rewards uint64 `json:"rewards"`
 	PoolId  string `json:"pool_id"`
-	Epoch   int    `json:"epoch,omitempty"`
+	// Epoch is the epoch from which the delegation to PoolId is active. It
+	// is zero when the provider cannot tell.
+	Epoch int `json:"epoch,omitempty"`
+	// DRepId is the DRep the account delegates its voting power to, if any.
+	DRepId string `json:"drep_id,omitempty"`
 }
 
 type Tip struct {
@@ -26,6 +33,128 @@ type Tip struct {
 	Hash   string `json:"hash"`
 }
 
+// NetworkInfo summarises the network's ADA supply and stake, in lovelace.
+type NetworkInfo struct {
+	Supply NetworkSupply `json:"supply"`
+	Stake  NetworkStake  `json:"stake"`
+}
+
+type NetworkSupply struct {
+	Max         uint64 `json:"max"`
+	Total       uint64 `json:"total"`
+	Circulating uint64 `json:"circulating"`
+	Locked      uint64 `json:"locked"`
+	Treasury    uint64 `json:"treasury"`
+	Reserves    uint64 `json:"reserves"`
+}
+
+type NetworkStake struct {
+	Live   uint64 `json:"live"`
+	Active uint64 `json:"active"`
+}
+
+// AssetAction is a mint or burn of a unit.
+type AssetAction struct {
+	TxHash string `json:"tx_hash"`
+	// Action is AssetActionMinted or AssetActionBurned.
+	Action string `json:"action"`
+	// Quantity is positive for mints and negative for burns.
+	Quantity *big.Int `json:"quantity"`
+}
+
+const (
+	AssetActionMinted = "minted"
+	AssetActionBurned = "burned"
+)
+
+// PoolMetadata is a stake pool's metadata. URL and Hash come from the pool's
+// on-chain registration; the remaining fields from the off-chain document
+// and are empty when it could not be fetched.
+type PoolMetadata struct {
+	PoolId      string `json:"pool_id"`
+	URL         string `json:"url,omitempty"`
+	Hash        string `json:"hash,omitempty"`
+	Ticker      string `json:"ticker,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Homepage    string `json:"homepage,omitempty"`
+}
+
+// PoolUpdate is a pool registration or retirement certificate.
+type PoolUpdate struct {
+	TxHash    string `json:"tx_hash"`
+	CertIndex int    `json:"cert_index"`
+	// Action is PoolActionRegistered or PoolActionDeregistered.
+	Action string `json:"action"`
+}
+
+const (
+	PoolActionRegistered   = "registered"
+	PoolActionDeregistered = "deregistered"
+)
+
+// ChainPoint identifies a block by its slot and header hash.
+type ChainPoint struct {
+	Slot uint64 `json:"slot"`
+	Hash string `json:"hash"`
+}
+
+// TxStageEvent reports the progress of a transaction followed by a
+// TxWatcher.
+type TxStageEvent struct {
+	TxHash string `json:"tx_hash"`
+	// Stage is one of the TxStage constants, reached in that order.
+	Stage string `json:"stage,omitempty"`
+	// Err, when set, ends the watch before the transaction was confirmed.
+	// Stage is then empty.
+	Err error `json:"-"`
+}
+
+const (
+	// TxStageAcknowledged means the provider accepted the transaction.
+	TxStageAcknowledged = "acknowledged"
+	// TxStageMempool means the transaction is in the node's mempool.
+	TxStageMempool = "mempool"
+	// TxStageNetwork means the transaction has been propagated to peers.
+	TxStageNetwork = "network"
+	// TxStageConfirmed means the transaction is in a block.
+	TxStageConfirmed = "confirmed"
+)
+
+// BlockEvent is a change to the chain followed by a BlockStreamer.
+type BlockEvent struct {
+	// Type is BlockEventRollForward or BlockEventRollBackward.
+	Type string `json:"type"`
+	// Point is the block rolled forward to, or the point rolled back to. A
+	// rollback to the chain origin has the zero Point.
+	Point ChainPoint `json:"point"`
+	// Height is the block height of a roll-forward.
+	Height uint64 `json:"height,omitempty"`
+	// TxHashes are the transactions of a rolled-forward block, in block
+	// order.
+	TxHashes []string `json:"tx_hashes,omitempty"`
+	// Err, when set, is the reason the stream ends with this event, such as
+	// an error wrapping ErrNotFound when a rollback went deeper than the
+	// points the stream could resume from. Type is then empty.
+	Err error `json:"-"`
+}
+
+const (
+	BlockEventRollForward  = "roll_forward"
+	BlockEventRollBackward = "roll_backward"
+)
+
+// UtxoWithProvenance is a UTxO together with the block that created it and,
+// once it has been spent, the slot that spent it. Indexers use it to order
+// UTxOs and to detect rollbacks.
+type UtxoWithProvenance struct {
+	Utxo                common.Utxo `json:"utxo"`
+	CreatedAtSlot       uint64      `json:"created_at_slot"`
+	CreatedAtHeaderHash string      `json:"created_at_header_hash"`
+	// SpentAtSlot is zero while the output is unspent.
+	SpentAtSlot uint64 `json:"spent_at_slot,omitempty"`
+}
+
 type Provider interface {
 	// GetProtocolParameters fetches the current protocol parameters.
 	GetProtocolParameters(ctx context.Context) (backend.ProtocolParameters, error)
@@ -86,10 +215,10 @@ type Provider interface {
 	// EvaluateTx evaluates a transaction's scripts and returns the execution units,
 	// keyed by redeemer (tag + index).
 	// additionalUTxOs can be provided for inputs not yet on-chain; the ogmios
-	// (kupmios), blockfrost, and maestro backends honor them. The utxorpc backend
-	// IGNORES additionalUTxOs (its EvalTx proto has no field for resolved UTxOs)
-	// and can only evaluate transactions whose inputs are already visible
-	// on-chain.
+	// (kupmios), blockfrost, and maestro backends honor them. The utxorpc
+	// backend cannot forward them (its EvalTx proto has no field for resolved
+	// UTxOs): it accepts additionalUTxOs that are already visible on-chain and
+	// returns an error wrapping ErrNotImplemented for any that are not.
 	EvaluateTx(
 		ctx context.Context,
 		tx []byte,
@@ -102,3 +231,169 @@ type Provider interface {
 		scriptHash string,
 	) (string, error)
 }
+
+// MempoolWatcher is an optional capability of providers that can inspect
+// transactions pending in the mempool. Check for it with a type assertion:
+//
+//	if mw, ok := provider.(connector.MempoolWatcher); ok { ... }
+type MempoolWatcher interface {
+	// InMempool reports whether the transaction is currently pending in the
+	// mempool. Unknown transactions return (false, nil).
+	InMempool(ctx context.Context, txHash string) (bool, error)
+
+	// ListMempoolByAddress returns the hashes of pending transactions that
+	// involve the given Bech32 address.
+	ListMempoolByAddress(ctx context.Context, addr string) ([]string, error)
+}
+
+// BalanceProvider is an optional capability of providers that can report an
+// address's balance without the caller summing its UTxOs.
+type BalanceProvider interface {
+	// GetBalance returns the lovelace and native assets held by the given
+	// Bech32 address. An address never seen on-chain has a zero balance.
+	GetBalance(ctx context.Context, addr string) (mary.MaryTransactionOutputValue, error)
+}
+
+// NetworkInfoProvider is an optional capability of providers that can report
+// network-wide supply and stake figures.
+type NetworkInfoProvider interface {
+	// GetNetworkInfo returns the current supply and stake of the network.
+	GetNetworkInfo(ctx context.Context) (NetworkInfo, error)
+}
+
+// AssetHistoryProvider is an optional capability of providers that can list
+// the mint and burn events of a unit.
+type AssetHistoryProvider interface {
+	// GetAssetHistory returns the mints and burns of unit, oldest first
+	// unless opts say otherwise. Unknown units yield ErrNotFound.
+	GetAssetHistory(ctx context.Context, unit string, opts ...QueryOption) ([]AssetAction, error)
+}
+
+// AccountAddressesProvider is an optional capability of providers that can
+// list the payment addresses of a stake account.
+type AccountAddressesProvider interface {
+	// GetAccountAddresses returns the addresses associated with the Bech32
+	// stake address. An account never seen on-chain has none.
+	GetAccountAddresses(ctx context.Context, stakeAddr string) ([]string, error)
+}
+
+// PoolProvider is an optional capability of providers that can describe
+// stake pools.
+type PoolProvider interface {
+	// GetPoolMetadata returns the metadata of the Bech32 or hex pool id.
+	// Unknown pools yield ErrNotFound.
+	GetPoolMetadata(ctx context.Context, poolId string) (PoolMetadata, error)
+
+	// GetPoolUpdates returns the pool's registration and retirement
+	// certificates, oldest first. Unknown pools yield ErrNotFound.
+	GetPoolUpdates(ctx context.Context, poolId string) ([]PoolUpdate, error)
+}
+
+// SlotConverter is an optional capability of providers that can convert
+// between absolute slots and wall-clock time using the chain's era history,
+// which is correct across era boundaries where the slot length changed.
+type SlotConverter interface {
+	// SlotToTime returns the start time of slot.
+	SlotToTime(ctx context.Context, slot uint64) (time.Time, error)
+
+	// TimeToSlot returns the slot in progress at t. Times before the
+	// network's system start yield ErrInvalidInput.
+	TimeToSlot(ctx context.Context, t time.Time) (uint64, error)
+}
+
+// BlockStreamer is an optional capability of providers that can follow the
+// chain as blocks are added and rolled back.
+type BlockStreamer interface {
+	// StreamBlocks delivers the blocks after from, then follows the tip. The
+	// zero ChainPoint starts at the current tip. A from that is not on the
+	// chain yields ErrNotFound. The channel is closed once ctx ends or the
+	// provider is closed, or after an event with Err set when the stream
+	// cannot go on, e.g. because it lost its connection and none of the
+	// points it could resume from is on the chain any more.
+	StreamBlocks(ctx context.Context, from ChainPoint) (<-chan BlockEvent, error)
+}
+
+// MaxResumePoints bounds how many recently delivered points ResumePoints
+// keeps, and so how deep a rollback that happens while a block stream is
+// reconnecting can be followed.
+const MaxResumePoints = 16
+
+// ResumePoints tracks the points a BlockStreamer implementation recently
+// delivered, so that after losing its connection it can ask its backend to
+// resume at the most recent of them that is still on the chain.
+type ResumePoints struct {
+	// points are the delivered points, oldest first.
+	points []ChainPoint
+}
+
+// NewResumePoints returns ResumePoints holding start, the point a stream
+// delivers the blocks after.
+func NewResumePoints(start ChainPoint) *ResumePoints {
+	return &ResumePoints{points: []ChainPoint{start}}
+}
+
+// Points returns the tracked points, oldest first.
+func (r *ResumePoints) Points() []ChainPoint {
+	return slices.Clone(r.points)
+}
+
+// IsResumeEcho reports whether event rolls back to the most recently
+// delivered point. Chain-sync backends open a resumed stream with such a
+// rollback to the intersection, which a stream should not deliver again.
+func (r *ResumePoints) IsResumeEcho(event BlockEvent) bool {
+	return event.Type == BlockEventRollBackward &&
+		len(r.points) > 0 &&
+		event.Point == r.points[len(r.points)-1]
+}
+
+// Track records a delivered event. A roll-forward adds its point, dropping
+// the oldest beyond MaxResumePoints; a rollback drops the points after its
+// target and adds the target unless it is the origin or already tracked.
+func (r *ResumePoints) Track(event BlockEvent) {
+	if event.Type == BlockEventRollBackward {
+		r.points = slices.DeleteFunc(r.points, func(point ChainPoint) bool {
+			return point.Slot > event.Point.Slot
+		})
+		if event.Point == (ChainPoint{}) {
+			return
+		}
+		if len(r.points) > 0 && r.points[len(r.points)-1] == event.Point {
+			return
+		}
+	}
+	r.points = append(r.points, event.Point)
+	if len(r.points) > MaxResumePoints {
+		r.points = slices.Delete(r.points, 0, len(r.points)-MaxResumePoints)
+	}
+}
+
+// DatumBatchResolver is an optional capability of providers that can resolve
+// many datums by hash in one call.
+type DatumBatchResolver interface {
+	// GetDatums returns the datums stored under hashes, keyed by hash. Each
+	// distinct hash is looked up once; hashes the provider does not know are
+	// absent from the map.
+	GetDatums(ctx context.Context, hashes []string) (map[string]common.Datum, error)
+}
+
+// TxWatcher is an optional capability of providers that can report the
+// stages a transaction goes through after submission.
+type TxWatcher interface {
+	// SubmitAndWatch submits tx and delivers an event for each stage it
+	// reaches, starting with TxStageAcknowledged. The channel is closed after
+	// the TxStageConfirmed event, after an event carrying Err, or once ctx
+	// ends. A transaction the provider rejects yields an error as SubmitTx
+	// does, and no channel.
+	SubmitAndWatch(ctx context.Context, tx []byte) (<-chan TxStageEvent, error)
+}
+
+// ProvenanceProvider is an optional capability of providers that can report
+// where on the chain each UTxO was created and spent.
+type ProvenanceProvider interface {
+	// GetUtxosByAddressExtended returns the same UTxOs as GetUtxosByAddress,
+	// each with its provenance.
+	GetUtxosByAddressExtended(
+		ctx context.Context,
+		addr string,
+	) ([]UtxoWithProvenance, error)
+}
@@ -0,0 +1,51 @@
+package connector
+
+import "strings"
+
+// Canonical keys of backend.ProtocolParameters.CostModels, the form apollo's
+// ComputeScriptDataHash looks cost models up by. Every provider returns cost
+// models under these keys.
+const (
+	CostModelPlutusV1 = "PlutusV1"
+	CostModelPlutusV2 = "PlutusV2"
+	CostModelPlutusV3 = "PlutusV3"
+)
+
+// CostModelKey maps a backend's spelling of a cost model key, such as
+// "plutus:v2" (Ogmios), "plutus_v2" (Maestro) or "PlutusScriptV2", onto the
+// canonical key. Keys for unknown languages are returned unchanged.
+func CostModelKey(key string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(key) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	switch b.String() {
+	case "plutusv1", "plutusscriptv1":
+		return CostModelPlutusV1
+	case "plutusv2", "plutusscriptv2":
+		return CostModelPlutusV2
+	case "plutusv3", "plutusscriptv3":
+		return CostModelPlutusV3
+	default:
+		return key
+	}
+}
+
+// NormalizeCostModels returns a copy of models keyed by CostModelKey. When
+// two keys name the same language, the one already in canonical form wins.
+func NormalizeCostModels(models map[string][]int64) map[string][]int64 {
+	if models == nil {
+		return nil
+	}
+	normalized := make(map[string][]int64, len(models))
+	for key, costs := range models {
+		canonical := CostModelKey(key)
+		if _, taken := normalized[canonical]; taken && key != canonical {
+			continue
+		}
+		normalized[canonical] = costs
+	}
+	return normalized
+}
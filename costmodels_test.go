@@ -0,0 +1,47 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCostModelKey(t *testing.T) {
+	cases := map[string]string{
+		"plutus:v1":      "PlutusV1",
+		"plutus:v2":      "PlutusV2",
+		"plutus:v3":      "PlutusV3",
+		"plutus_v1":      "PlutusV1",
+		"plutus_v2":      "PlutusV2",
+		"plutus_v3":      "PlutusV3",
+		"PlutusV2":       "PlutusV2",
+		"PlutusScriptV3": "PlutusV3",
+		"unknown":        "unknown",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, CostModelKey(in), in)
+	}
+}
+
+func TestNormalizeCostModels(t *testing.T) {
+	assert.Nil(t, NormalizeCostModels(nil))
+
+	assert.Equal(t, map[string][]int64{
+		"PlutusV1": {1},
+		"PlutusV2": {2},
+		"unknown":  {9},
+	}, NormalizeCostModels(map[string][]int64{
+		"plutus:v1": {1},
+		"plutus_v2": {2},
+		"unknown":   {9},
+	}))
+
+	// Two keys for the same language: the canonical one wins, whichever
+	// the map yields first.
+	for range 20 {
+		assert.Equal(t, map[string][]int64{"PlutusV2": {2}}, NormalizeCostModels(map[string][]int64{
+			"plutus:v2": {1},
+			"PlutusV2":  {2},
+		}))
+	}
+}
@@ -0,0 +1,14 @@
+package connector
+
+import "github.com/blinklabs-io/gouroboros/ledger/common"
+
+// InlineDatumCbor returns the CBOR of an inline datum's PlutusData (not the
+// datum option wrapping it). The bytes the datum was decoded from are preferred
+// so a non-canonical on-chain encoding is forwarded unchanged; re-encoding it
+// would alter the datum hash scripts may depend on.
+func InlineDatumCbor(datum *common.Datum) ([]byte, error) {
+	if raw := datum.Cbor(); len(raw) > 0 {
+		return raw, nil
+	}
+	return datum.MarshalCBOR()
+}
@@ -3,6 +3,7 @@ package connector
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // Common error values returned by Provider implementations.
@@ -68,6 +69,20 @@ var (
 	// ErrBadInputs indicates that some of the transaction inputs are bad (e.g. already spent).
 	ErrBadInputs = errors.New("connector: bad transaction inputs")
 
+	// ErrOutsideValidityInterval indicates that the transaction was submitted outside
+	// of its validity interval (e.g. its TTL has already passed).
+	ErrOutsideValidityInterval = errors.New(
+		"connector: transaction outside its validity interval",
+	)
+
+	// ErrFeeTooSmall indicates that the transaction fee is below the required minimum.
+	ErrFeeTooSmall = errors.New("connector: transaction fee too small")
+
+	// ErrTruncated indicates that a paginated query hit a configured page or result
+	// limit before the provider ran out of results. Methods returning it may also
+	// return the partial results fetched so far.
+	ErrTruncated = errors.New("connector: result truncated at configured limit")
+
 	// ErrMultipleUTXOs indicates that multiple UTXOs were found for a given unit.
 	ErrMultipleUTXOs = errors.New(
 		"connector: multiple UTXOs found for a given unit",
@@ -114,6 +129,116 @@ func (e *APIError) Unwrap() error {
 	return e.UnderlyingErr
 }
 
+// SubmissionError describes a transaction rejected by the node or provider on
+// submission. It unwraps to ErrTxSubmissionFailed and, when the rejection could
+// be classified, to the matching sentinel (e.g. ErrBadInputs), so callers can
+// branch with errors.Is and still inspect the details with errors.As.
+type SubmissionError struct {
+	// StatusCode is the HTTP status code from the API provider, if applicable.
+	StatusCode int
+	// Reasons lists the ledger rule failures named in the rejection, in order of
+	// appearance (e.g. "BadInputsUTxO", "FeeTooSmallUTxO").
+	Reasons []string
+	// Message is the rejection message as returned by the provider.
+	Message string
+	// Kind is the sentinel matching the first classified reason, or nil.
+	Kind error
+	// BadInputs lists the inputs the rejection named as unknown or already
+	// spent, when the provider reports them.
+	BadInputs []OutRef
+}
+
+// Error implements the error interface for SubmissionError.
+func (e *SubmissionError) Error() string {
+	if len(e.Reasons) > 0 {
+		return fmt.Sprintf(
+			"transaction rejected (%s): %s",
+			strings.Join(e.Reasons, ", "),
+			e.Message,
+		)
+	}
+	return "transaction rejected: " + e.Message
+}
+
+// Unwrap provides compatibility for errors.Is and errors.As.
+func (e *SubmissionError) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{ErrTxSubmissionFailed}
+	}
+	return []error{ErrTxSubmissionFailed, e.Kind}
+}
+
+// RedeemerFailure is a redeemer the evaluator reported as failing.
+type RedeemerFailure struct {
+	// Purpose is the redeemer purpose as reported, e.g. "spend" or "mint".
+	Purpose string
+	Index   uint32
+	// Reason is the evaluator's explanation, e.g. the validation error and
+	// traces, or the budget the script ran out of.
+	Reason string
+	// Logs are the script's trace messages, when the evaluator reports them
+	// separately from Reason.
+	Logs []string
+}
+
+// EvaluationError describes a failed script evaluation. It unwraps to
+// ErrEvaluationFailed and, when the provider answered with an HTTP error, to
+// the *APIError holding the raw response, so callers can branch with
+// errors.Is and inspect the failing redeemers with errors.As.
+type EvaluationError struct {
+	// Message is the evaluator's summary of the failure.
+	Message string
+	// Failures lists the failing redeemers, when the evaluator named them.
+	Failures []RedeemerFailure
+	// API is the provider's HTTP error response, if any.
+	API *APIError
+}
+
+// Error implements the error interface for EvaluationError.
+func (e *EvaluationError) Error() string {
+	msg := "script evaluation failed"
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if len(e.Failures) == 0 {
+		return msg
+	}
+	failures := make([]string, len(e.Failures))
+	for i, f := range e.Failures {
+		failures[i] = fmt.Sprintf("%s:%d: %s", f.Purpose, f.Index, f.Reason)
+	}
+	return msg + " (" + strings.Join(failures, "; ") + ")"
+}
+
+// Unwrap provides compatibility for errors.Is and errors.As.
+func (e *EvaluationError) Unwrap() []error {
+	if e.API == nil {
+		return []error{ErrEvaluationFailed}
+	}
+	return []error{ErrEvaluationFailed, e.API}
+}
+
+// MissingOutRefsError lists the output references a UTxO lookup could not
+// resolve, because the transaction or the output index does not exist. It
+// unwraps to ErrNotFound.
+type MissingOutRefsError struct {
+	Refs []OutRef
+}
+
+// Error implements the error interface for MissingOutRefsError.
+func (e *MissingOutRefsError) Error() string {
+	refs := make([]string, len(e.Refs))
+	for i, ref := range e.Refs {
+		refs[i] = fmt.Sprintf("%s#%d", ref.TxHash, ref.Index)
+	}
+	return fmt.Sprintf("%d output reference(s) not found: %s", len(e.Refs), strings.Join(refs, ", "))
+}
+
+// Unwrap makes errors.Is(err, ErrNotFound) hold.
+func (e *MissingOutRefsError) Unwrap() error {
+	return ErrNotFound
+}
+
 // --- Helper functions for error checking ---
 
 // IsNotFound checks if an error is, or wraps, ErrNotFound.
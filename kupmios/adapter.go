@@ -4,9 +4,7 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"log/slog"
 	"math"
 	"math/big"
 	"strconv"
@@ -23,25 +21,66 @@ import (
 	"github.com/blinklabs-io/gouroboros/ledger/common"
 	"github.com/blinklabs-io/gouroboros/ledger/mary"
 	"github.com/blinklabs-io/gouroboros/ledger/shelley"
+	connector "github.com/zenGate-Global/cardano-connector-go"
 )
 
 // chainFetcher resolves datums and reference scripts by hash. It is implemented
-// by *kugo.Client via the Kupo /v1/datums/{hash} and /v1/scripts/{hash}
-// endpoints. Kupo's /matches response carries only the datum hash and script
-// hash (not the resolved datum/script bytes), so both must be fetched
-// separately.
+// by *kupoFetcher via the Kupo /v1/datums/{hash} and /v1/scripts/{hash}
+// endpoints, where an unknown hash yields an error wrapping
+// connector.ErrNotFound. Kupo's /matches response carries only the datum hash
+// and script hash (not the resolved datum/script bytes), so both must be
+// fetched separately.
 type chainFetcher interface {
 	Datum(ctx context.Context, datumHash string) (string, error)
 	Script(ctx context.Context, scriptHash string) (*kugo.Script, error)
 }
 
+// scriptRefHandler is told about a reference script that could not be
+// resolved or parsed. Returning nil keeps the UTxO with an unresolved (nil)
+// reference script; returning an error aborts the adaptation.
+type scriptRefHandler func(
+	txHash string,
+	outputIndex int,
+	scriptHash string,
+	err error,
+) error
+
+// quantityHandler is told about a lovelace quantity beyond the int64 range.
+// Returning nil saturates the quantity to math.MaxInt64; returning an error
+// aborts the adaptation. A nil handler always aborts.
+type quantityHandler func(
+	txHash string,
+	outputIndex int,
+	qty *big.Int,
+) error
+
 // matchToUtxo converts a kugo.Match into a gouroboros common.Utxo. Inline
 // datums are resolved (and hash-verified) via the supplied datumFetcher.
+type skipResolutionKey struct{}
+
+// WithoutDatumResolution returns a context that makes the methods reading
+// UTxOs from Kupo skip the per-output datum and script lookups, for callers
+// that only need values. Inline datums are then represented by their datum
+// hash and reference scripts Kupo does not inline are omitted.
+// GetUtxosByOutRef ignores it and always resolves both.
+func WithoutDatumResolution(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipResolutionKey{}, true)
+}
+
+// skipResolution reports whether ctx was prepared with
+// WithoutDatumResolution.
+func skipResolution(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipResolutionKey{}).(bool)
+	return skip
+}
+
 func matchToUtxo(
 	ctx context.Context,
 	match kugo.Match,
 	address common.Address,
 	fetcher chainFetcher,
+	onScriptErr scriptRefHandler,
+	onOverflow quantityHandler,
 ) (common.Utxo, error) {
 	hashBytes, err := hex.DecodeString(match.TransactionID)
 	if err != nil {
@@ -73,6 +112,7 @@ func matchToUtxo(
 		uint32(match.OutputIndex),
 		shared.Value(match.Value),
 		address,
+		onOverflow,
 	)
 	if err != nil {
 		return common.Utxo{}, err
@@ -88,15 +128,19 @@ func matchToUtxo(
 	// Set datum option from kupo match data. Kupo only returns the datum hash
 	// in matches; its datum_type discriminator says whether the on-chain
 	// output carried an inline datum or just the hash.
+	//
+	// With WithoutDatumResolution an inline datum is represented by its hash
+	// rather than fetched.
+	skip := skipResolution(ctx)
 	if match.DatumHash != "" {
-		switch match.DatumType {
-		case "inline":
+		switch {
+		case match.DatumType == "inline" && !skip:
 			opt, err := fetchInlineDatumOption(ctx, fetcher, match.DatumHash)
 			if err != nil {
 				return common.Utxo{}, err
 			}
 			output.DatumOption = opt
-		case "hash":
+		case match.DatumType == "inline", match.DatumType == "hash":
 			opt, err := parseDatumOption(match.DatumHash)
 			if err != nil {
 				return common.Utxo{}, fmt.Errorf(
@@ -119,17 +163,18 @@ func matchToUtxo(
 	// present we resolve the script via /v1/scripts/{hash}. The resolved bytes
 	// are verified against the claimed hash by kupoScriptToScriptRef.
 	//
-	// Chain-read hydration is best-effort: if the script cannot be resolved
-	// (empty/invalid body, transient failure) or parsed, do NOT abort the whole
-	// fetch; keep the UTxO with an unresolved (nil) reference script.
+	// Whether a script that cannot be resolved (empty/invalid body, transient
+	// failure) or parsed aborts the fetch is up to onScriptErr. With
+	// WithoutDatumResolution the script is not fetched and the output carries
+	// no reference script.
 	script := match.Script
-	if script.Script == "" && match.ScriptHash != "" {
+	if script.Script == "" && match.ScriptHash != "" && !skip {
 		fetched, err := fetcher.Script(ctx, match.ScriptHash)
 		if err != nil {
-			slog.Warn("kupmios: leaving reference script unresolved during hydration",
-				"script_hash", match.ScriptHash,
-				"utxo", fmt.Sprintf("%s#%d", match.TransactionID, match.OutputIndex),
-				"err", err)
+			err = fmt.Errorf("failed to fetch reference script: %w", err)
+			if err := onScriptErr(match.TransactionID, match.OutputIndex, match.ScriptHash, err); err != nil {
+				return common.Utxo{}, err
+			}
 		} else if fetched != nil {
 			script = *fetched
 		}
@@ -137,10 +182,10 @@ func matchToUtxo(
 	if script.Script != "" {
 		ref, err := kupoScriptToScriptRef(script, match.ScriptHash)
 		if err != nil {
-			slog.Warn("kupmios: leaving reference script unresolved during hydration (parse failed)",
-				"script_hash", match.ScriptHash,
-				"utxo", fmt.Sprintf("%s#%d", match.TransactionID, match.OutputIndex),
-				"err", err)
+			err = fmt.Errorf("failed to parse reference script: %w", err)
+			if err := onScriptErr(match.TransactionID, match.OutputIndex, match.ScriptHash, err); err != nil {
+				return common.Utxo{}, err
+			}
 		} else {
 			output.TxOutScriptRef = ref
 		}
@@ -198,7 +243,8 @@ func fetchInlineDatumOption(
 	datumBytes, err := hex.DecodeString(datumCborHex)
 	if err != nil {
 		return nil, fmt.Errorf(
-			"invalid inline datum CBOR hex %q: %w",
+			"%w: invalid inline datum CBOR hex %q: %w",
+			connector.ErrProviderInternal,
 			datumCborHex,
 			err,
 		)
@@ -218,21 +264,36 @@ func fetchInlineDatumOption(
 func ogmiosUtxoToCommon(
 	raw shared.Utxo,
 	addr common.Address,
+	onScriptErr scriptRefHandler,
+	onOverflow quantityHandler,
 ) (common.Utxo, error) {
 	hashBytes, err := hex.DecodeString(raw.Transaction.ID)
 	if err != nil {
-		return common.Utxo{}, err
+		return common.Utxo{}, fmt.Errorf(
+			"%w: invalid transaction.id %q: %w",
+			connector.ErrProviderInternal,
+			raw.Transaction.ID,
+			err,
+		)
 	}
 	if len(hashBytes) != common.Blake2b256Size {
 		return common.Utxo{}, fmt.Errorf(
-			"invalid tx hash length: expected %d bytes, got %d",
+			"%w: invalid transaction.id %q: expected %d bytes, got %d",
+			connector.ErrProviderInternal,
+			raw.Transaction.ID,
 			common.Blake2b256Size,
 			len(hashBytes),
 		)
 	}
 	var txId common.Blake2b256
 	copy(txId[:], hashBytes)
-	utxo, err := sharedValueToUtxo(txId, raw.Index, raw.Value, addr)
+	utxo, err := sharedValueToUtxo(
+		txId,
+		raw.Index,
+		raw.Value,
+		addr,
+		onOverflow,
+	)
 	if err != nil {
 		return common.Utxo{}, err
 	}
@@ -266,15 +327,15 @@ func ogmiosUtxoToCommon(
 		output.DatumOption = opt
 	}
 
-	// Set script reference from ogmios UTxO data. Chain-read hydration is
-	// best-effort: a malformed reference script must not abort the fetch; keep
-	// the UTxO with an unresolved (nil) reference script.
+	// Set script reference from ogmios UTxO data. Whether a malformed
+	// reference script aborts the fetch is up to onScriptErr.
 	if len(raw.Script) > 0 && string(raw.Script) != "null" {
 		ref, err := ogmiosScriptToScriptRef(raw.Script)
 		if err != nil {
-			slog.Warn("kupmios: leaving reference script unresolved during hydration (ogmios script parse failed)",
-				"utxo", fmt.Sprintf("%s#%d", raw.Transaction.ID, raw.Index),
-				"err", err)
+			err = fmt.Errorf("failed to parse reference script: %w", err)
+			if err := onScriptErr(raw.Transaction.ID, int(raw.Index), "", err); err != nil {
+				return common.Utxo{}, err
+			}
 		} else if ref != nil {
 			output.TxOutScriptRef = ref
 		}
@@ -285,12 +346,15 @@ func ogmiosUtxoToCommon(
 
 // sharedValueToUtxo builds a common.Utxo from an ogmigo shared.Value and the
 // owning address. The output is a babbage output with no datum/script set;
-// callers attach those afterwards.
+// callers attach those afterwards. Native asset quantities keep their full
+// magnitude; a lovelace quantity beyond the int64 range is passed to
+// onOverflow.
 func sharedValueToUtxo(
 	txId common.Blake2b256,
 	outputIndex uint32,
 	value shared.Value,
 	addr common.Address,
+	onOverflow quantityHandler,
 ) (common.Utxo, error) {
 	input := shelley.ShelleyTransactionInput{
 		TxId:        txId,
@@ -300,13 +364,18 @@ func sharedValueToUtxo(
 	// Require int64 range (not just uint64) to keep downstream signed lovelace
 	// arithmetic safe.
 	lovelaceBig := value.AdaLovelace().BigInt()
-	if lovelaceBig.Sign() < 0 || !lovelaceBig.IsInt64() {
+	if lovelaceBig.Sign() < 0 {
 		return common.Utxo{}, fmt.Errorf(
 			"invalid lovelace quantity %s",
 			lovelaceBig.String(),
 		)
 	}
-	lovelace := lovelaceBig.Uint64()
+	lovelace := uint64(math.MaxInt64)
+	if lovelaceBig.IsInt64() {
+		lovelace = lovelaceBig.Uint64()
+	} else if err := overflowError(onOverflow, txId, outputIndex, lovelaceBig); err != nil {
+		return common.Utxo{}, err
+	}
 	assetData := make(map[common.Blake2b224]map[cbor.ByteString]*big.Int)
 
 	for policyIdStr, assets := range value {
@@ -377,6 +446,27 @@ func sharedValueToUtxo(
 	}, nil
 }
 
+// overflowError reports an out-of-range lovelace quantity to onOverflow, or
+// fails when there is no handler.
+func overflowError(
+	onOverflow quantityHandler,
+	txId common.Blake2b256,
+	outputIndex uint32,
+	qty *big.Int,
+) error {
+	txHash := hex.EncodeToString(txId.Bytes())
+	if onOverflow == nil {
+		return fmt.Errorf(
+			"%w: lovelace quantity %s of %s#%d exceeds int64 range",
+			connector.ErrProviderInternal,
+			qty.String(),
+			txHash,
+			outputIndex,
+		)
+	}
+	return onOverflow(txHash, int(outputIndex), qty)
+}
+
 // parseDatumOption constructs a BabbageTransactionOutputDatumOption from a
 // datum hash hex string (datum hash reference, type 0).
 func parseDatumOption(
@@ -385,14 +475,17 @@ func parseDatumOption(
 	hashBytes, err := hex.DecodeString(datumHashHex)
 	if err != nil {
 		return nil, fmt.Errorf(
-			"invalid datum hash hex %q: %w",
+			"%w: invalid datum hash hex %q: %w",
+			connector.ErrProviderInternal,
 			datumHashHex,
 			err,
 		)
 	}
 	if len(hashBytes) != common.Blake2b256Size {
 		return nil, fmt.Errorf(
-			"invalid datum hash length: expected %d bytes, got %d",
+			"%w: invalid datum hash length for %q: expected %d bytes, got %d",
+			connector.ErrProviderInternal,
+			datumHashHex,
 			common.Blake2b256Size,
 			len(hashBytes),
 		)
@@ -419,7 +512,8 @@ func parseInlineDatumCbor(
 	datumBytes, err := hex.DecodeString(datumCborHex)
 	if err != nil {
 		return nil, fmt.Errorf(
-			"invalid datum CBOR hex %q: %w",
+			"%w: invalid datum CBOR hex %q: %w",
+			connector.ErrProviderInternal,
 			datumCborHex,
 			err,
 		)
@@ -437,7 +531,9 @@ func parseInlineDatumCbor(
 	var opt babbage.BabbageTransactionOutputDatumOption
 	if err := opt.UnmarshalCBOR(cborBytes); err != nil {
 		return nil, fmt.Errorf(
-			"failed to unmarshal inline datum option: %w",
+			"%w: malformed inline datum CBOR %q: %w",
+			connector.ErrProviderInternal,
+			datumCborHex,
 			err,
 		)
 	}
@@ -476,6 +572,12 @@ func kupoScriptToScriptRef(
 		)
 	}
 
+	if scriptType != common.ScriptRefTypeNativeScript {
+		scriptBytes, err = canonicalPlutusScript(scriptBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return backend.ScriptRefFromBytes(scriptType, scriptBytes, expectedHashHex)
 }
 
@@ -524,9 +626,49 @@ func ogmiosScriptToScriptRef(
 		)
 	}
 
+	if scriptType != common.ScriptRefTypeNativeScript {
+		scriptBytes, err = canonicalPlutusScript(scriptBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return backend.ScriptRefFromBytes(scriptType, scriptBytes, "")
 }
 
+// canonicalPlutusScript returns a Plutus script in the form the ledger hashes
+// and the other providers return: the flat-encoded program wrapped in exactly
+// one CBOR byte string. Kupo and Ogmios deployments differ in whether they
+// strip that layer or add another, so bare and doubly wrapped programs are
+// accepted too.
+func canonicalPlutusScript(script []byte) ([]byte, error) {
+	for {
+		inner, ok := cborByteString(script)
+		if !ok {
+			// A flat program starts with its version, never a byte string.
+			wrapped, err := cbor.Encode(script)
+			if err != nil {
+				return nil, fmt.Errorf("failed to wrap plutus script: %w", err)
+			}
+			return wrapped, nil
+		}
+		if _, nested := cborByteString(inner); !nested {
+			return script, nil
+		}
+		script = inner
+	}
+}
+
+// cborByteString decodes data as a single CBOR byte string with nothing after
+// it.
+func cborByteString(data []byte) ([]byte, bool) {
+	var content []byte
+	n, err := cbor.Decode(data, &content)
+	if err != nil || n != len(data) {
+		return nil, false
+	}
+	return content, true
+}
+
 // commonUtxosToShared converts resolved gouroboros UTxOs into the ogmigo
 // shared.Utxo wire form expected by EvaluateTxWithAdditionalUtxos.
 func commonUtxosToShared(utxos []common.Utxo) ([]shared.Utxo, error) {
@@ -589,7 +731,7 @@ func commonUtxoToShared(utxo common.Utxo) (shared.Utxo, error) {
 	// Datum: inline datum CBOR hex goes in Datum, a bare datum hash in
 	// DatumHash.
 	if datum := out.Datum(); datum != nil {
-		datumCbor, err := datum.MarshalCBOR()
+		datumCbor, err := connector.InlineDatumCbor(datum)
 		if err != nil {
 			return shared.Utxo{}, fmt.Errorf(
 				"failed to encode inline datum: %w",
@@ -668,19 +810,88 @@ func parseRedeemerPurpose(purpose string) (common.RedeemerTag, error) {
 	}
 }
 
+// submitErrorCodes maps Ogmios submitTransaction error codes to the ledger
+// rule they report and the matching connector sentinel.
+var submitErrorCodes = map[int]struct {
+	reason   string
+	sentinel error
+}{
+	3010: {"ScriptExecutionFailure", connector.ErrEvaluationFailed},
+	3117: {"UnknownOutputReferences", connector.ErrBadInputs},
+	3118: {"OutsideOfValidityInterval", connector.ErrOutsideValidityInterval},
+	3119: {"TransactionTooLarge", connector.ErrTxTooLarge},
+	3121: {"EmptyInputSet", connector.ErrBadInputs},
+	3122: {"TransactionFeeTooSmall", connector.ErrFeeTooSmall},
+	3123: {"ValueNotConserved", connector.ErrValueNotConserved},
+}
+
+// submitTxError converts an Ogmios submission rejection into a
+// connector.SubmissionError, classified by its error code when known.
+func submitTxError(e *ogmigo.SubmitTxError) *connector.SubmissionError {
+	subErr := &connector.SubmissionError{
+		Message: fmt.Sprintf("ogmios error %d: %s", e.Code, e.Message),
+	}
+	if len(e.Data) > 0 && string(e.Data) != "null" {
+		subErr.Message += " " + string(e.Data)
+	}
+	if known, ok := submitErrorCodes[e.Code]; ok {
+		subErr.Reasons = []string{known.reason}
+		subErr.Kind = known.sentinel
+	}
+	subErr.BadInputs = unknownOutputReferences(e.Data)
+	return subErr
+}
+
+// unknownOutputReferences extracts the inputs an UnknownOutputReferences
+// rejection names. Its data is {"unknownOutputReferences": [{"transaction":
+// {"id": ...}, "index": ...}]}; anything else yields nil.
+func unknownOutputReferences(data json.RawMessage) []connector.OutRef {
+	var payload struct {
+		UnknownOutputReferences []struct {
+			Transaction struct {
+				ID string `json:"id"`
+			} `json:"transaction"`
+			Index uint32 `json:"index"`
+		} `json:"unknownOutputReferences"`
+	}
+	if len(data) == 0 || json.Unmarshal(data, &payload) != nil {
+		return nil
+	}
+	var refs []connector.OutRef
+	for _, ref := range payload.UnknownOutputReferences {
+		refs = append(refs, connector.OutRef{
+			TxHash: ref.Transaction.ID,
+			Index:  ref.Index,
+		})
+	}
+	return refs
+}
+
+// ErrEmptyEvaluation indicates that Ogmios answered an evaluation with
+// neither execution units nor an error. It wraps
+// connector.ErrProviderInternal.
+var ErrEmptyEvaluation = fmt.Errorf(
+	"%w: kupmios: evaluation returned no results",
+	connector.ErrProviderInternal,
+)
+
 // evaluateResponseToExUnits converts an ogmigo EvaluateTxResponse into a
-// redeemer ExUnits map. A response with zero evaluation results is an error.
+// redeemer ExUnits map. An Ogmios error becomes a *connector.EvaluationError
+// and a response with zero evaluation results ErrEmptyEvaluation.
 func evaluateResponseToExUnits(
 	resp *ogmigo.EvaluateTxResponse,
 ) (map[common.RedeemerKey]common.ExUnits, error) {
 	if resp == nil {
-		return nil, errors.New("empty evaluate response")
+		return nil, fmt.Errorf(
+			"%w: empty Ogmios evaluate response",
+			connector.ErrProviderInternal,
+		)
 	}
 	if resp.Error != nil {
-		return nil, fmt.Errorf("evaluate tx error: %s", resp.Error.Message)
+		return nil, evaluateTxError(resp.Error)
 	}
 	if len(resp.ExUnits) == 0 {
-		return nil, errors.New("script evaluation returned no results")
+		return nil, ErrEmptyEvaluation
 	}
 
 	result := make(map[common.RedeemerKey]common.ExUnits, len(resp.ExUnits))
@@ -715,6 +926,49 @@ func evaluateResponseToExUnits(
 	return result, nil
 }
 
+// evaluateTxError converts an Ogmios evaluation error into a
+// connector.EvaluationError. For script failures its data lists the failing
+// validators: [{"validator": {"purpose": ..., "index": ...}, "error":
+// {"code": ..., "message": ..., "data": {"validationError": ..., "traces":
+// [...]}}}]. Data in any other shape is appended to the message.
+func evaluateTxError(e *ogmigo.EvaluateTxError) *connector.EvaluationError {
+	evalErr := &connector.EvaluationError{
+		Message: fmt.Sprintf("ogmios error %d: %s", e.Code, e.Message),
+	}
+	var items []struct {
+		Validator struct {
+			Purpose string `json:"purpose"`
+			Index   uint32 `json:"index"`
+		} `json:"validator"`
+		Error struct {
+			Message string `json:"message"`
+			Data    struct {
+				ValidationError string   `json:"validationError"`
+				Traces          []string `json:"traces"`
+			} `json:"data"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(e.Data, &items); err != nil || len(items) == 0 {
+		if len(e.Data) > 0 && string(e.Data) != "null" {
+			evalErr.Message += " " + string(e.Data)
+		}
+		return evalErr
+	}
+	for _, item := range items {
+		reason := item.Error.Message
+		if item.Error.Data.ValidationError != "" {
+			reason += ": " + item.Error.Data.ValidationError
+		}
+		evalErr.Failures = append(evalErr.Failures, connector.RedeemerFailure{
+			Purpose: item.Validator.Purpose,
+			Index:   item.Validator.Index,
+			Reason:  reason,
+			Logs:    item.Error.Data.Traces,
+		})
+	}
+	return evalErr
+}
+
 // toProtocolParams maps the Ogmios protocol parameter response onto the
 // apollo v2 backend.ProtocolParameters struct.
 func (p *ogmiosProtocolParams) toProtocolParams() (backend.ProtocolParameters, error) {
@@ -774,7 +1028,7 @@ func (p *ogmiosProtocolParams) toProtocolParams() (backend.ProtocolParameters, e
 		MonetaryExpansion:                monetaryExpansion,
 		TreasuryExpansion:                treasuryExpansion,
 		MinPoolCost:                      strconv.FormatInt(p.MinPoolCost.lovelace(), 10),
-		MinUtxo:                          strconv.FormatInt(p.MinUtxoConstant.lovelace(), 10),
+		MinUtxo:                          strconv.FormatInt(p.MinUtxoDeposit, 10),
 		ProtocolMajorVersion:             p.Version.Major,
 		ProtocolMinorVersion:             p.Version.Minor,
 		PriceMem:                         priceMem,
@@ -794,37 +1048,17 @@ func (p *ogmiosProtocolParams) toProtocolParams() (backend.ProtocolParameters, e
 		MinFeeReferenceScriptsMultiplier: int(p.MinFeeRefScripts.Multiplier),
 	}
 
-	// Parse cost models from Ogmios JSON. Ogmios uses keys like "plutus:v1",
-	// "plutus:v2", "plutus:v3"; ComputeScriptDataHash expects "PlutusV1",
-	// "PlutusV2", "PlutusV3".
+	// Ogmios keys cost models as "plutus:v1", "plutus:v2" and "plutus:v3".
 	if len(p.CostModels) > 0 {
-		pp.CostModels = make(map[string][]int64, len(p.CostModels))
-		for key, costs := range p.CostModels {
-			pp.CostModels[ogmiosCostModelKey(key)] = costs
-		}
+		pp.CostModels = connector.NormalizeCostModels(p.CostModels)
 	}
 
 	return pp, nil
 }
 
-// ogmiosCostModelKey translates Ogmios cost model keys to the canonical form
-// expected by ComputeScriptDataHash ("PlutusV1", "PlutusV2", "PlutusV3").
-func ogmiosCostModelKey(key string) string {
-	switch key {
-	case "plutus:v1":
-		return "PlutusV1"
-	case "plutus:v2":
-		return "PlutusV2"
-	case "plutus:v3":
-		return "PlutusV3"
-	default:
-		return key
-	}
-}
-
 // toGenesisParams maps the Ogmios shelley genesis configuration onto the
 // apollo v2 backend.GenesisParameters struct.
-func (g *ogmiosGenesisConfig) toGenesisParams() (backend.GenesisParameters, error) {
+func (g *ShelleyGenesis) toGenesisParams() (backend.GenesisParameters, error) {
 	activeSlots, err := backend.ParseFraction(g.ActiveSlots)
 	if err != nil {
 		return backend.GenesisParameters{}, fmt.Errorf(
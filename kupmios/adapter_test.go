@@ -0,0 +1,507 @@
+package kupmios
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/SundaeSwap-finance/kugo"
+	"github.com/SundaeSwap-finance/ogmigo/v6/ouroboros/chainsync/num"
+	"github.com/SundaeSwap-finance/ogmigo/v6/ouroboros/shared"
+	"github.com/blinklabs-io/gouroboros/cbor"
+	"github.com/blinklabs-io/gouroboros/ledger/babbage"
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+	"github.com/zenGate-Global/cardano-connector-go/tests"
+)
+
+const adapterTestAddr = "addr_test1wrzqlyffcf5yq3htqge9h9k29zv6d7ny0rqam6d4c5eqdfgg0h7yw"
+
+// staticFetcher serves fixed datums by hash and fails every script lookup
+// with scriptErr.
+type staticFetcher struct {
+	datums    map[string]string
+	scriptErr error
+}
+
+func (f staticFetcher) Datum(_ context.Context, datumHash string) (string, error) {
+	return f.datums[datumHash], nil
+}
+
+func (f staticFetcher) Script(context.Context, string) (*kugo.Script, error) {
+	return nil, f.scriptErr
+}
+
+func newLoggingProvider(t *testing.T, buf *bytes.Buffer, strict bool) *KupmiosProvider {
+	t.Helper()
+	provider, err := New(withLocalEndpoints(Config{
+		NetworkId:        preprodNetworkId,
+		Logger:           slog.New(slog.NewTextHandler(buf, nil)),
+		StrictScriptRefs: strict,
+	}))
+	assert.NoError(t, err)
+	return provider
+}
+
+// unresolvedScriptMatch references a script that staticFetcher cannot fetch.
+func unresolvedScriptMatch() kugo.Match {
+	return kugo.Match{
+		TransactionID: strings.Repeat("03", 32),
+		OutputIndex:   4,
+		Address:       adapterTestAddr,
+		Value:         kugo.Value(shared.CreateAdaValue(2_000_000)),
+		ScriptHash:    strings.Repeat("ab", 28),
+	}
+}
+
+func adapterTestAddress(t *testing.T) common.Address {
+	t.Helper()
+	addr, err := common.NewAddress(adapterTestAddr)
+	assert.NoError(t, err)
+	return addr
+}
+
+func TestOgmiosUtxoToCommonCorruptDatum(t *testing.T) {
+	cases := []struct {
+		name      string
+		datum     string
+		datumHash string
+	}{
+		{name: "inline not hex", datum: "zz"},
+		{name: "inline not cbor", datum: "ff00"},
+		{name: "hash not hex", datumHash: "not-a-hash"},
+		{name: "hash wrong length", datumHash: "abcd"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			raw := shared.Utxo{
+				Transaction: shared.UtxoTxID{ID: strings.Repeat("01", 32)},
+				Address:     adapterTestAddr,
+				Value:       shared.CreateAdaValue(2_000_000),
+				Datum:       tc.datum,
+				DatumHash:   tc.datumHash,
+			}
+			_, err := ogmiosUtxoToCommon(raw, adapterTestAddress(t), nil, nil)
+			assert.True(t, errors.Is(err, connector.ErrProviderInternal), "got %v", err)
+			assert.Contains(t, err.Error(), tc.datum+tc.datumHash)
+		})
+	}
+}
+
+func TestMatchToUtxoCorruptInlineDatum(t *testing.T) {
+	// The bytes hash correctly, so only CBOR decoding can reject them.
+	corrupt := "ff00"
+	corruptBytes, err := hex.DecodeString(corrupt)
+	assert.NoError(t, err)
+	datumHash := hex.EncodeToString(common.Blake2b256Hash(corruptBytes).Bytes())
+
+	match := kugo.Match{
+		TransactionID: strings.Repeat("02", 32),
+		Address:       adapterTestAddr,
+		Value:         kugo.Value(shared.CreateAdaValue(2_000_000)),
+		DatumHash:     datumHash,
+		DatumType:     "inline",
+	}
+	_, err = matchToUtxo(
+		context.Background(),
+		match,
+		adapterTestAddress(t),
+		staticFetcher{datums: map[string]string{datumHash: corrupt}},
+		nil,
+		nil,
+	)
+	assert.True(t, errors.Is(err, connector.ErrProviderInternal), "got %v", err)
+	assert.Contains(t, err.Error(), corrupt)
+}
+
+func TestMatchToUtxoUnresolvedScriptLogs(t *testing.T) {
+	var buf bytes.Buffer
+	kp := newLoggingProvider(t, &buf, false)
+
+	match := unresolvedScriptMatch()
+	utxo, err := matchToUtxo(
+		context.Background(),
+		match,
+		adapterTestAddress(t),
+		staticFetcher{scriptErr: errors.New("kupo unavailable")},
+		kp.scriptRefError,
+		nil,
+	)
+	assert.NoError(t, err)
+	assert.Nil(t, utxo.Output.ScriptRef())
+
+	out := buf.String()
+	assert.Contains(t, out, "level=WARN")
+	assert.Contains(t, out, "leaving reference script unresolved")
+	assert.Contains(t, out, "tx_hash="+match.TransactionID)
+	assert.Contains(t, out, "output_index=4")
+	assert.Contains(t, out, "script_hash="+match.ScriptHash)
+	assert.Contains(t, out, "kupo unavailable")
+}
+
+func TestMatchToUtxoUnresolvedScriptStrict(t *testing.T) {
+	var buf bytes.Buffer
+	kp := newLoggingProvider(t, &buf, true)
+
+	_, err := matchToUtxo(
+		context.Background(),
+		unresolvedScriptMatch(),
+		adapterTestAddress(t),
+		staticFetcher{scriptErr: errors.New("kupo unavailable")},
+		kp.scriptRefError,
+		nil,
+	)
+	assert.True(t, errors.Is(err, connector.ErrProviderInternal), "got %v", err)
+	assert.Contains(t, err.Error(), "kupo unavailable")
+	assert.Empty(t, buf.String())
+}
+
+func TestOgmiosUtxoToCommonMalformedScriptLogs(t *testing.T) {
+	var buf bytes.Buffer
+	kp := newLoggingProvider(t, &buf, false)
+
+	raw := shared.Utxo{
+		Transaction: shared.UtxoTxID{ID: strings.Repeat("04", 32)},
+		Index:       1,
+		Address:     adapterTestAddr,
+		Value:       shared.CreateAdaValue(2_000_000),
+		Script:      []byte(`{"language":"plutus:v9","cbor":"00"}`),
+	}
+	utxo, err := ogmiosUtxoToCommon(raw, adapterTestAddress(t), kp.scriptRefError, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, utxo.Output.ScriptRef())
+	assert.Contains(t, buf.String(), "tx_hash="+raw.Transaction.ID)
+	assert.Contains(t, buf.String(), "output_index=1")
+}
+
+func TestNewDefaultLoggerDiscards(t *testing.T) {
+	provider, err := New(withLocalEndpoints(Config{}))
+	assert.NoError(t, err)
+	assert.NotNil(t, provider.logger)
+	assert.False(t, provider.logger.Enabled(context.Background(), slog.LevelError))
+}
+
+// overflowValue holds 2^63 lovelace, one more than fits in an int64.
+func overflowValue(t *testing.T) shared.Value {
+	t.Helper()
+	qty, ok := num.New("9223372036854775808")
+	assert.True(t, ok)
+	return shared.Value{shared.AdaPolicy: {shared.AdaAsset: qty}}
+}
+
+func TestMatchToUtxoLovelaceOverflowStrict(t *testing.T) {
+	var buf bytes.Buffer
+	kp := newLoggingProvider(t, &buf, false)
+
+	match := kugo.Match{
+		TransactionID: strings.Repeat("05", 32),
+		Address:       adapterTestAddr,
+		Value:         kugo.Value(overflowValue(t)),
+	}
+	_, err := matchToUtxo(
+		context.Background(),
+		match,
+		adapterTestAddress(t),
+		staticFetcher{},
+		kp.scriptRefError,
+		kp.quantityOverflow,
+	)
+	assert.True(t, errors.Is(err, connector.ErrProviderInternal), "got %v", err)
+	assert.Contains(t, err.Error(), "9223372036854775808")
+	assert.Empty(t, buf.String())
+}
+
+func TestOgmiosUtxoToCommonLovelaceOverflowLenient(t *testing.T) {
+	var buf bytes.Buffer
+	kp, err := New(withLocalEndpoints(Config{
+		Logger:            slog.New(slog.NewTextHandler(&buf, nil)),
+		LenientQuantities: true,
+	}))
+	assert.NoError(t, err)
+
+	raw := shared.Utxo{
+		Transaction: shared.UtxoTxID{ID: strings.Repeat("06", 32)},
+		Index:       2,
+		Address:     adapterTestAddr,
+		Value:       overflowValue(t),
+	}
+	utxo, err := ogmiosUtxoToCommon(
+		raw,
+		adapterTestAddress(t),
+		kp.scriptRefError,
+		kp.quantityOverflow,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, utxo.Output.Amount().Cmp(big.NewInt(math.MaxInt64)))
+	assert.Contains(t, buf.String(), "level=WARN")
+	assert.Contains(t, buf.String(), "quantity=9223372036854775808")
+	assert.Contains(t, buf.String(), "output_index=2")
+}
+
+func TestSharedValueToUtxoKeepsLargeAssetQuantities(t *testing.T) {
+	qty, ok := num.New("9223372036854775808")
+	assert.True(t, ok)
+	value := shared.CreateAdaValue(2_000_000)
+	value[outRefPolicy] = map[string]num.Int{ftName: qty}
+
+	utxo, err := sharedValueToUtxo(
+		common.Blake2b256{},
+		0,
+		value,
+		adapterTestAddress(t),
+		nil,
+	)
+	assert.NoError(t, err)
+
+	unit, err := newUnitMatcher(outRefPolicy + ftName)
+	assert.NoError(t, err)
+	got := utxo.Output.Assets().Asset(unit.policyId, unit.assetName)
+	assert.Equal(t, "9223372036854775808", got.String())
+}
+
+func TestOgmiosUtxoToCommonTruncatedTxId(t *testing.T) {
+	raw := shared.Utxo{
+		Transaction: shared.UtxoTxID{ID: strings.Repeat("07", 31)},
+		Address:     adapterTestAddr,
+		Value:       shared.CreateAdaValue(2_000_000),
+	}
+	_, err := ogmiosUtxoToCommon(raw, adapterTestAddress(t), nil, nil)
+	assert.True(t, errors.Is(err, connector.ErrProviderInternal), "got %v", err)
+	assert.Contains(t, err.Error(), "transaction.id")
+	assert.Contains(t, err.Error(), "got 31")
+}
+
+// scriptFetcher serves one script for every hash.
+type scriptFetcher struct {
+	script kugo.Script
+}
+
+func (scriptFetcher) Datum(context.Context, string) (string, error) {
+	return "", nil
+}
+
+func (f scriptFetcher) Script(context.Context, string) (*kugo.Script, error) {
+	return &f.script, nil
+}
+
+func TestCanonicalPlutusScript(t *testing.T) {
+	single := tests.ApolloDiscoveryUTxO.Output.ScriptRef().RawScriptBytes()
+	var bare []byte
+	_, err := cbor.Decode(single, &bare)
+	assert.NoError(t, err)
+	double, err := cbor.Encode(single)
+	assert.NoError(t, err)
+
+	for name, framed := range map[string][]byte{
+		"bare":   bare,
+		"single": single,
+		"double": double,
+	} {
+		got, err := canonicalPlutusScript(framed)
+		assert.NoError(t, err, name)
+		assert.Equal(t, hex.EncodeToString(single), hex.EncodeToString(got), name)
+	}
+}
+
+func TestScriptRefFramingAgreesAcrossSources(t *testing.T) {
+	want := tests.ApolloDiscoveryUTxO.Output.ScriptRef()
+	single := want.RawScriptBytes()
+	var bare []byte
+	_, err := cbor.Decode(single, &bare)
+	assert.NoError(t, err)
+	double, err := cbor.Encode(single)
+	assert.NoError(t, err)
+
+	// Kupo serves the bare program, Ogmios a doubly wrapped one.
+	match := kugo.Match{
+		TransactionID: strings.Repeat("08", 32),
+		Address:       adapterTestAddr,
+		Value:         kugo.Value(shared.CreateAdaValue(2_000_000)),
+		ScriptHash:    hex.EncodeToString(want.Hash().Bytes()),
+	}
+	fromKupo, err := matchToUtxo(
+		context.Background(),
+		match,
+		adapterTestAddress(t),
+		scriptFetcher{script: kugo.Script{
+			Language: kugo.ScriptLanguagePlutusV2,
+			Script:   hex.EncodeToString(bare),
+		}},
+		nil,
+		nil,
+	)
+	assert.NoError(t, err)
+
+	raw := shared.Utxo{
+		Transaction: shared.UtxoTxID{ID: match.TransactionID},
+		Address:     adapterTestAddr,
+		Value:       shared.CreateAdaValue(2_000_000),
+		Script: []byte(fmt.Sprintf(
+			`{"language":"plutus:v2","cbor":%q}`,
+			hex.EncodeToString(double),
+		)),
+	}
+	fromOgmios, err := ogmiosUtxoToCommon(raw, adapterTestAddress(t), nil, nil)
+	assert.NoError(t, err)
+
+	for _, utxo := range []common.Utxo{fromKupo, fromOgmios} {
+		got := utxo.Output.ScriptRef()
+		assert.NotNil(t, got)
+		assert.IsType(t, want, got)
+		assert.Equal(t, hex.EncodeToString(single), hex.EncodeToString(got.RawScriptBytes()))
+	}
+	assert.True(t, tests.UtxosEqual(fromKupo, fromOgmios), tests.UtxoDiff(fromKupo, fromOgmios))
+}
+
+// unitDatumHash is the hash of the datum d87980 (unit, Constr 0 []).
+const unitDatumHash = "923918e403bf43c34b4ef6b48eb2ee04babed17320d8d1b9ff9ad086e86f44ec"
+
+// resolvingAddress serves n outputs at adapterTestAddr that each carry an
+// inline datum and a reference script Kupo does not inline, and returns the
+// datum and script paths. The script does not match its hash, so it is
+// fetched but not attached.
+func resolvingAddress(kupo *mockKupo, n int) (string, string) {
+	scriptHash := strings.Repeat("cd", 28)
+	matches := make([]string, n)
+	for i := range matches {
+		matches[i] = fmt.Sprintf(`{
+			"transaction_id": "%064x",
+			"output_index": 0,
+			"address": %q,
+			"value": {"coins": 2000000},
+			"datum_hash": %q,
+			"datum_type": "inline",
+			"script_hash": %q,
+			"created_at": {"slot_no": 10, "header_hash": %q}
+		}`, i+1, adapterTestAddr, unitDatumHash, scriptHash, strings.Repeat("01", 32))
+	}
+	kupo.route("/v1/matches/"+adapterTestAddr, "["+strings.Join(matches, ",")+"]")
+	kupo.route("/v1/datums/"+unitDatumHash, `{"datum": "d87980"}`)
+	kupo.route("/v1/scripts/"+scriptHash, `{"language": "plutus:v2", "script": "4e4d01000033222220051200120011"}`)
+	return "/v1/datums/" + unitDatumHash, "/v1/scripts/" + scriptHash
+}
+
+func TestWithoutDatumResolution(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{DisableChainCache: true})
+	datumPath, scriptPath := resolvingAddress(kupo, 3)
+
+	utxos, err := kp.GetUtxosByAddress(
+		WithoutDatumResolution(context.Background()),
+		adapterTestAddr,
+	)
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 3)
+	assert.Equal(t, 0, kupo.Requests(datumPath))
+	assert.Equal(t, 0, kupo.Requests(scriptPath))
+	for _, utxo := range utxos {
+		assert.Equal(t, unitDatumHash, hex.EncodeToString(utxo.Output.DatumHash().Bytes()))
+		assert.Nil(t, utxo.Output.Datum())
+		assert.Nil(t, utxo.Output.ScriptRef())
+	}
+
+	utxos, err = kp.GetUtxosByAddress(context.Background(), adapterTestAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, kupo.Requests(datumPath))
+	assert.Equal(t, 3, kupo.Requests(scriptPath))
+	assert.NotNil(t, utxos[0].Output.Datum())
+}
+
+func BenchmarkWithoutDatumResolution(b *testing.B) {
+	for _, skip := range []bool{false, true} {
+		b.Run(fmt.Sprintf("WithoutDatumResolution=%v", skip), func(b *testing.B) {
+			kp, _, kupo := newMockKupmios(b, Config{DisableChainCache: true})
+			resolvingAddress(kupo, 500)
+			ctx := context.Background()
+			if skip {
+				ctx = WithoutDatumResolution(ctx)
+			}
+			b.ResetTimer()
+			for range b.N {
+				if _, err := kp.GetUtxosByAddress(ctx, adapterTestAddr); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func inlineDatumOption(t *testing.T, datumHex string) *babbage.BabbageTransactionOutputDatumOption {
+	t.Helper()
+	datumBytes, err := hex.DecodeString(datumHex)
+	assert.NoError(t, err)
+	optCbor, err := cbor.Encode([]any{1, cbor.Tag{Number: 24, Content: datumBytes}})
+	assert.NoError(t, err)
+	var opt babbage.BabbageTransactionOutputDatumOption
+	assert.NoError(t, opt.UnmarshalCBOR(optCbor))
+	return &opt
+}
+
+// TestCommonUtxoToSharedSendsExactInlineDatumCbor asserts that an additional
+// UTxO's inline datum is forwarded as the raw PlutusData CBOR it was decoded
+// from (here an indefinite-length constructor), not the datum option wrapper
+// or a re-encoding, matching the Blockfrost evaluation path.
+func TestCommonUtxoToSharedSendsExactInlineDatumCbor(t *testing.T) {
+	const datumHex = "d8799f4100ff" // Constr 0 [h'00'], indefinite-length
+
+	utxo := tests.ApolloEvalSample1UTxOs[0]
+	su, err := commonUtxoToShared(utxo)
+	assert.NoError(t, err)
+	assert.Equal(t, "d87981581c9fc430ea1f3adc20eebb813b2649e85c934ea5bc13d7b7fbe2b24e50", su.Datum)
+	assert.Empty(t, su.DatumHash, "datum and datumHash are mutually exclusive")
+
+	out := *utxo.Output.(*babbage.BabbageTransactionOutput)
+	out.DatumOption = inlineDatumOption(t, datumHex)
+	su, err = commonUtxoToShared(common.Utxo{Id: utxo.Id, Output: &out})
+	assert.NoError(t, err)
+	assert.Equal(t, datumHex, su.Datum)
+	assert.Empty(t, su.DatumHash)
+}
+
+func TestGetProtocolParametersCanonicalCostModelKeys(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	ogmios.handle("queryLedgerState/protocolParameters", func(json.RawMessage) any {
+		return json.RawMessage(`{
+			"scriptExecutionPrices": {"memory": "577/10000", "cpu": "721/10000000"},
+			"plutusCostModels": {
+				"plutus:v1": [197209, 0, 1, 1],
+				"plutus:v2": [205665, 812, 1, 1],
+				"plutus:v3": [100788, 420, 1, 1]
+			}
+		}`)
+	})
+
+	pp, err := kp.GetProtocolParameters(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]int64{
+		connector.CostModelPlutusV1: {197209, 0, 1, 1},
+		connector.CostModelPlutusV2: {205665, 812, 1, 1},
+		connector.CostModelPlutusV3: {100788, 420, 1, 1},
+	}, pp.CostModels)
+}
+
+func TestGetProtocolParametersMinUtxoPerByte(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	ogmios.handle("queryLedgerState/protocolParameters", func(json.RawMessage) any {
+		return json.RawMessage(`{
+			"scriptExecutionPrices": {"memory": "577/10000", "cpu": "721/10000000"},
+			"minUtxoDepositCoefficient": 4310,
+			"minUtxoDepositConstant": {"ada": {"lovelace": 0}}
+		}`)
+	})
+
+	pp, err := kp.GetProtocolParameters(context.Background())
+	assert.NoError(t, err)
+	// Like Blockfrost, MinUtxo carries the per-byte price.
+	assert.Equal(t, "4310", pp.MinUtxo)
+	assert.Equal(t, "4310", pp.CoinsPerUtxoByte)
+	assert.Equal(t, "4310", pp.CoinsPerUtxoWord)
+}
@@ -0,0 +1,202 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+var awaitTxHash = strings.Repeat("0d", 32)
+
+// serveAdvancingTip answers queryLedgerState/tip with a tip that starts at
+// slot from and moves step slots forward on every query.
+func serveAdvancingTip(ogmios *mockOgmios, from, step uint64) {
+	var slot atomic.Uint64
+	slot.Store(from - step)
+	ogmios.handle("queryLedgerState/tip", func(json.RawMessage) any {
+		return map[string]any{"slot": slot.Add(step), "id": strings.Repeat("0e", 32)}
+	})
+}
+
+func kupoTxPath(txHash string) string {
+	return "/v1/matches/*@" + txHash
+}
+
+// awaitTxMatch renders a Kupo match for an output of awaitTxHash created in
+// slot.
+func awaitTxMatch(slot uint64) string {
+	return fmt.Sprintf(`[{
+		"transaction_id": %q,
+		"output_index": 0,
+		"address": %q,
+		"value": {"coins": 2000000},
+		"created_at": {"slot_no": %d, "header_hash": %q}
+	}]`, awaitTxHash, adapterTestAddr, slot, strings.Repeat("01", 32))
+}
+
+func TestAwaitTxWaitsForConfirmationSlots(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{ConfirmationSlots: 30})
+	serveAdvancingTip(ogmios, 100, 10)
+	kupo.route(kupoTxPath(awaitTxHash), awaitTxMatch(100))
+
+	ok, err := kp.AwaitTx(context.Background(), awaitTxHash, time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	// Tips 100, 110 and 120 are too shallow; 130 is 30 slots deep.
+	assert.Len(t, ogmios.Calls("queryLedgerState/tip"), 4)
+}
+
+func TestAwaitTxOgmiosFallback(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{
+		ConfirmationSlots:     20,
+		AwaitTxOgmiosFallback: true,
+	})
+	serveAdvancingTip(ogmios, 500, 10)
+	serveLedgerUtxos(ogmios, connector.OutRef{TxHash: awaitTxHash})
+
+	ok, err := kp.AwaitTx(context.Background(), awaitTxHash, time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	// First seen at 500, confirmed once the tip reaches 520.
+	assert.Len(t, ogmios.Calls("queryLedgerState/tip"), 3)
+	assert.Equal(t, 3, kupo.Requests(kupoTxPath(awaitTxHash)))
+}
+
+func TestAwaitTxWithoutFallbackIgnoresLedger(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	serveAdvancingTip(ogmios, 500, 10)
+	serveLedgerUtxos(ogmios, connector.OutRef{TxHash: awaitTxHash})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	ok, err := kp.AwaitTx(ctx, awaitTxHash, time.Millisecond)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+	assert.False(t, ok)
+	assert.Empty(t, ogmios.Calls("queryLedgerState/utxo"))
+}
+
+func TestAwaitTxRecheckWaitsAgainAfterRollback(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{AwaitTxRecheck: true})
+	serveAdvancingTip(ogmios, 100, 20)
+	// Seen in slot 100, rolled back, then included again in slot 130.
+	kupo.sequence(kupoTxPath(awaitTxHash),
+		awaitTxMatch(100), "[]", "[]", awaitTxMatch(130))
+
+	ok, err := kp.AwaitTx(context.Background(), awaitTxHash, time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	// Tip 120 is behind slot 130, tip 140 reaches it and tip 160 re-checks.
+	assert.Equal(t, 6, kupo.Requests(kupoTxPath(awaitTxHash)))
+	assert.Len(t, ogmios.Calls("queryLedgerState/tip"), 4)
+}
+
+func TestAwaitTxRecheckRestartsWhenSlotChanges(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{
+		ConfirmationSlots: 10,
+		AwaitTxRecheck:    true,
+	})
+	serveAdvancingTip(ogmios, 110, 10)
+	kupo.sequence(kupoTxPath(awaitTxHash), awaitTxMatch(100), awaitTxMatch(120))
+
+	ok, err := kp.AwaitTx(context.Background(), awaitTxHash, time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	// Tip 110 reaches slot 100 but the re-check finds slot 120, which tip 130
+	// reaches and tip 140 confirms.
+	assert.Len(t, ogmios.Calls("queryLedgerState/tip"), 4)
+}
+
+func TestAwaitTxWithoutRecheckReportsFirstSighting(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{})
+	serveAdvancingTip(ogmios, 100, 20)
+	kupo.sequence(kupoTxPath(awaitTxHash), awaitTxMatch(100), "[]")
+
+	ok, err := kp.AwaitTx(context.Background(), awaitTxHash, time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, kupo.Requests(kupoTxPath(awaitTxHash)))
+}
+
+// serveMempool answers the mempool monitoring requests, reporting the
+// transaction as pending for the first pendingPolls hasTransaction queries.
+func serveMempool(ogmios *mockOgmios, pendingPolls int64) {
+	var polls atomic.Int64
+	ogmios.handle("acquireMempool", func(json.RawMessage) any {
+		return map[string]any{"acquired": "mempool", "slot": 100}
+	})
+	ogmios.handle("hasTransaction", func(json.RawMessage) any {
+		return polls.Add(1) <= pendingPolls
+	})
+	ogmios.handle("releaseMempool", func(json.RawMessage) any {
+		return map[string]any{"released": "mempool"}
+	})
+}
+
+func TestAwaitTxMempoolSkipsChainWhilePending(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{AwaitTxMempool: true})
+	serveAdvancingTip(ogmios, 100, 10)
+	serveMempool(ogmios, 2)
+	kupo.route(kupoTxPath(awaitTxHash), awaitTxMatch(100))
+
+	ok, err := kp.AwaitTx(context.Background(), awaitTxHash, time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Len(t, ogmios.Calls("hasTransaction"), 3)
+	assert.Equal(t, 1, kupo.Requests(kupoTxPath(awaitTxHash)))
+
+	var params struct {
+		ID string `json:"id"`
+	}
+	assert.NoError(t, json.Unmarshal(ogmios.Calls("hasTransaction")[0], &params))
+	assert.Equal(t, awaitTxHash, params.ID)
+}
+
+func TestAwaitTxMempoolReportsStatusOnTimeout(t *testing.T) {
+	cases := []struct {
+		name         string
+		pendingPolls int64
+		status       string
+	}{
+		{name: "pending", pendingPolls: 1 << 30, status: "pending in the mempool"},
+		{name: "unknown", pendingPolls: 0, status: "not seen in the mempool or on chain"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			kp, ogmios, _ := newMockKupmios(t, Config{AwaitTxMempool: true})
+			serveAdvancingTip(ogmios, 100, 10)
+			serveMempool(ogmios, tc.pendingPolls)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			ok, err := kp.AwaitTx(ctx, awaitTxHash, time.Millisecond)
+			assert.False(t, ok)
+			assert.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+			assert.Contains(t, err.Error(), tc.status)
+		})
+	}
+}
+
+func TestAwaitTxMempoolUnavailableFallsBackToChain(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{AwaitTxMempool: true})
+	serveAdvancingTip(ogmios, 100, 10)
+	kupo.route(kupoTxPath(awaitTxHash), awaitTxMatch(100))
+
+	ok, err := kp.AwaitTx(context.Background(), awaitTxHash, time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Len(t, ogmios.Calls("acquireMempool"), 1)
+	assert.Empty(t, ogmios.Calls("hasTransaction"))
+}
+
+func TestNewRejectsNegativeConfirmationSlots(t *testing.T) {
+	_, err := New(withLocalEndpoints(Config{ConfirmationSlots: -1}))
+	assert.True(t, errors.Is(err, connector.ErrInvalidInput), "got %v", err)
+}
@@ -0,0 +1,88 @@
+package kupmios
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SundaeSwap-finance/kugo"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// matches runs a Kupo match query through the kugo client, reporting it to
+// Config.Instrumentation and retrying transient failures according to
+// Config.Retry.
+func (kp *KupmiosProvider) matches(
+	ctx context.Context,
+	filters ...kugo.MatchesFilter,
+) ([]kugo.Match, error) {
+	return connector.Retry(ctx, kp.retry, retryable, func() ([]kugo.Match, error) {
+		return instrumented(ctx, kp.instrumentation, ComponentKupo, "matches", func() ([]kugo.Match, error) {
+			return kp.kugoClient.Matches(ctx, filters...)
+		})
+	})
+}
+
+// kupoMatches calls visit with the Kupo matches of pattern, Kupo's matching
+// pattern for filter, honouring WithSpentMatches. what describes the query in
+// errors; errors returned by visit are passed through unchanged.
+//
+// Without Config.KupoChunkSlots the matches come from a single request. With
+// it, the query is split by creation slot into windows of that many slots,
+// from the origin up to Kupo's most recent checkpoint plus an open-ended
+// window past it. The windows are requested one after the other, oldest
+// first, so only one window's matches are held at a time.
+func (kp *KupmiosProvider) kupoMatches(
+	ctx context.Context,
+	what string,
+	pattern string,
+	filter kugo.MatchesFilter,
+	visit func([]kugo.Match) error,
+) error {
+	if kp.kupoChunkSlots == 0 {
+		matches, err := kp.matches(ctx, spentFilter(ctx), filter)
+		if err != nil {
+			return fmt.Errorf("kupmios: Kupo request for %s failed: %w", what, err)
+		}
+		return visit(matches)
+	}
+
+	status, err := kp.GetKupoSyncStatus(ctx)
+	if err != nil {
+		return err
+	}
+	for from := uint64(0); ; from += kp.kupoChunkSlots {
+		// Kupo's created_after and created_before bounds are both exclusive.
+		var query []string
+		if !includeSpent(ctx) {
+			query = append(query, "unspent")
+		}
+		if from > 0 {
+			query = append(query, fmt.Sprintf("created_after=%d", from-1))
+		}
+		last := from+kp.kupoChunkSlots > status.MostRecentCheckpoint
+		if !last {
+			query = append(query, fmt.Sprintf("created_before=%d", from+kp.kupoChunkSlots))
+		}
+
+		path := "/v1/matches/" + pattern
+		if len(query) > 0 {
+			path += "?" + strings.Join(query, "&")
+		}
+		var matches []kugo.Match
+		if err := kp.kupo.get(ctx, path, &matches); err != nil {
+			return fmt.Errorf(
+				"kupmios: Kupo request for %s created from slot %d failed: %w",
+				what,
+				from,
+				err,
+			)
+		}
+		if err := visit(matches); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
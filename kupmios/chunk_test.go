@@ -0,0 +1,115 @@
+package kupmios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+func TestGetUtxosByAddressChunked(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{KupoChunkSlots: 100})
+	serveHealth(ogmios, kupo, 250, 250, 250)
+	path := "/v1/matches/" + adapterTestAddr
+	kupo.sequence(path, kupoMatchJSON(outRefLive), "[]", kupoMatchJSON(outRefSpent))
+
+	utxos, err := kp.GetUtxosByAddress(context.Background(), adapterTestAddr)
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 2)
+	assert.Equal(t, outRefLive.TxHash, utxos[0].Id.Id().String())
+	assert.Equal(t, outRefSpent.TxHash, utxos[1].Id.Id().String())
+	assert.Equal(t, []string{
+		"unspent&created_before=100",
+		"unspent&created_after=99&created_before=200",
+		"unspent&created_after=199",
+	}, kupo.Queries(path))
+}
+
+func TestGetUtxosByPolicyChunkedWithSpent(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{KupoChunkSlots: 100})
+	serveHealth(ogmios, kupo, 150, 150, 150)
+	path := "/v1/matches/" + outRefPolicy + ".*"
+	kupo.sequence(path, kupoMatchJSON(outRefSpent), kupoMatchJSON(outRefLive))
+
+	utxos, err := kp.GetUtxosByPolicy(WithSpentMatches(context.Background()), outRefPolicy)
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 2)
+	assert.Equal(t, []string{"created_before=100", "created_after=99"}, kupo.Queries(path))
+}
+
+func TestGetUtxosByAddressChunkFails(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{KupoChunkSlots: 100})
+	serveHealth(ogmios, kupo, 250, 250, 250)
+	kupo.fail("/v1/matches/"+adapterTestAddr, http.StatusServiceUnavailable)
+
+	_, err := kp.GetUtxosByAddress(context.Background(), adapterTestAddr)
+	assert.True(t, errors.Is(err, connector.ErrProviderInternal), "got %v", err)
+	assert.Contains(t, err.Error(), "from slot 0")
+}
+
+func TestNewRejectsNegativeKupoChunkSlots(t *testing.T) {
+	_, err := New(withLocalEndpoints(Config{KupoChunkSlots: -1}))
+	assert.True(t, errors.Is(err, connector.ErrInvalidInput), "got %v", err)
+}
+
+// syntheticMatches renders n ADA-only Kupo matches at adapterTestAddr,
+// numbered from first.
+func syntheticMatches(first, n int) string {
+	var b strings.Builder
+	b.WriteString("[")
+	for i := range n {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `{
+			"transaction_id": "%064x",
+			"output_index": 0,
+			"address": %q,
+			"value": {"coins": 2000000},
+			"created_at": {"slot_no": %d, "header_hash": %q}
+		}`, first+i+1, adapterTestAddr, first+i, strings.Repeat("01", 32))
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// BenchmarkGetUtxosByAddressLarge scans an address with 50k matches, fetched
+// at once or in 50 windows of 1000.
+func BenchmarkGetUtxosByAddressLarge(b *testing.B) {
+	const (
+		total  = 50000
+		window = 1000
+	)
+	path := "/v1/matches/" + adapterTestAddr
+	windows := make([]string, 0, total/window)
+	for first := 0; first < total; first += window {
+		windows = append(windows, syntheticMatches(first, window))
+	}
+
+	for _, chunk := range []int{0, window} {
+		b.Run(fmt.Sprintf("KupoChunkSlots=%d", chunk), func(b *testing.B) {
+			kp, ogmios, kupo := newMockKupmios(b, Config{KupoChunkSlots: chunk})
+			serveHealth(ogmios, kupo, total-window, total-window, total-window)
+			kupo.route(path, syntheticMatches(0, total))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				if chunk > 0 {
+					kupo.sequence(path, windows...)
+				}
+				utxos, err := kp.GetUtxosByAddress(context.Background(), adapterTestAddr)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(utxos) != total {
+					b.Fatalf("got %d UTxOs, want %d", len(utxos), total)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package kupmios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+	"golang.org/x/sync/errgroup"
+)
+
+var _ connector.DatumBatchResolver = (*KupmiosProvider)(nil)
+
+// GetDatums resolves the datums stored under hashes through Kupo, keyed by
+// hash. Each distinct hash is fetched once, up to Config.MaxConcurrentRequests
+// at a time, through the same cache that serves UTxO reads. Hashes Kupo does
+// not know are absent from the map, or fail the call with
+// connector.ErrNotFound naming them all when Config.StrictDatums is set.
+func (kp *KupmiosProvider) GetDatums(
+	ctx context.Context,
+	hashes []string,
+) (map[string]common.Datum, error) {
+	unique := make([]string, 0, len(hashes))
+	seen := make(map[string]bool, len(hashes))
+	for _, hash := range hashes {
+		if !seen[hash] {
+			seen[hash] = true
+			unique = append(unique, hash)
+		}
+	}
+
+	var mu sync.Mutex
+	datums := make(map[string]common.Datum, len(unique))
+	var unknown []string
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(kp.maxConcurrentRequests)
+	for _, hash := range unique {
+		g.Go(func() error {
+			datum, err := kp.GetDatum(gctx, hash)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case errors.Is(err, connector.ErrNotFound):
+				unknown = append(unknown, hash)
+				return nil
+			case err != nil:
+				return err
+			}
+			datums[hash] = datum
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	if kp.strictDatums && len(unknown) > 0 {
+		slices.Sort(unknown)
+		return nil, fmt.Errorf(
+			"kupmios: %w: Kupo knows no datums %s",
+			connector.ErrNotFound,
+			strings.Join(unknown, ", "),
+		)
+	}
+	return datums, nil
+}
@@ -0,0 +1,70 @@
+package kupmios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// serveDatums routes n datum hashes, every tenth of them unknown to Kupo, and
+// returns the hashes.
+func serveDatums(kupo *mockKupo, n int) []string {
+	hashes := make([]string, n)
+	for i := range hashes {
+		hashes[i] = fmt.Sprintf("%064x", i+1)
+		if i%10 != 0 {
+			kupo.route("/v1/datums/"+hashes[i], `{"datum": "d87980"}`)
+		}
+	}
+	return hashes
+}
+
+func TestGetDatumsDeduplicates(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{DisableChainCache: true})
+	hashes := serveDatums(kupo, 100)
+
+	datums, err := kp.GetDatums(context.Background(), append(hashes, hashes...))
+	assert.NoError(t, err)
+	assert.Len(t, datums, 90)
+	for i, hash := range hashes {
+		_, ok := datums[hash]
+		assert.Equal(t, i%10 != 0, ok, "datum %s", hash)
+		assert.Equal(t, 1, kupo.Requests("/v1/datums/"+hash), "datum %s", hash)
+	}
+}
+
+func TestGetDatumsStrict(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{StrictDatums: true})
+	hashes := serveDatums(kupo, 100)
+
+	_, err := kp.GetDatums(context.Background(), hashes)
+	assert.True(t, errors.Is(err, connector.ErrNotFound), "got %v", err)
+	assert.Contains(t, err.Error(), hashes[0])
+	assert.Contains(t, err.Error(), hashes[90])
+
+	datums, err := kp.GetDatums(context.Background(), hashes[1:10])
+	assert.NoError(t, err)
+	assert.Len(t, datums, 9)
+}
+
+func TestGetDatumsFailure(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{})
+	hashes := serveDatums(kupo, 5)
+	kupo.fail("/v1/datums/"+hashes[3], http.StatusInternalServerError)
+
+	_, err := kp.GetDatums(context.Background(), hashes)
+	assert.True(t, errors.Is(err, connector.ErrProviderInternal), "got %v", err)
+}
+
+func TestGetDatumsEmpty(t *testing.T) {
+	kp, _, _ := newMockKupmios(t, Config{})
+
+	datums, err := kp.GetDatums(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, datums)
+}
@@ -0,0 +1,168 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"slices"
+
+	"github.com/SundaeSwap-finance/kugo"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+const (
+	// maxDelegationCandidates bounds how many of the transactions paying a
+	// reward account are searched for the certificate of its delegation,
+	// most recent first.
+	maxDelegationCandidates = 10
+	// maxDelegationScanBlocks bounds how many blocks are read from the
+	// nearest Kupo checkpoint to reach the block of a candidate transaction.
+	maxDelegationScanBlocks = 500
+	// delegationActivationEpochs is how many epochs after its certificate a
+	// delegation enters the stake distribution rewards are computed from.
+	delegationActivationEpochs = 2
+)
+
+// ogmiosCertificate is a certificate of a transaction in an Ogmios block.
+type ogmiosCertificate struct {
+	Type       string `json:"type"`
+	Credential string `json:"credential"`
+	StakePool  *struct {
+		ID string `json:"id"`
+	} `json:"stakePool"`
+}
+
+// delegationEpoch returns the epoch from which the delegation of the reward
+// account addrStr, whose stake credential hash is credential, to pool is
+// active. It is the epoch of the certificate that made the delegation plus
+// delegationActivationEpochs. The certificate is looked for in the most
+// recent transactions that pay an address delegating to the account; zero
+// means none of them carries it.
+func (kp *KupmiosProvider) delegationEpoch(
+	ctx context.Context,
+	addrStr string,
+	credential []byte,
+	pool string,
+) (int, error) {
+	var matches []kugo.Match
+	path := "/v1/matches/" + addrStr + "?order=most_recent_first"
+	if err := kp.kupo.get(ctx, path, &matches); err != nil {
+		return 0, fmt.Errorf(
+			"kupmios: Kupo request for the transactions of %s failed: %w",
+			addrStr,
+			err,
+		)
+	}
+
+	wanted := hex.EncodeToString(credential)
+	searched := make(map[string]bool)
+	for _, match := range matches {
+		if searched[match.TransactionID] {
+			continue
+		}
+		if len(searched) == maxDelegationCandidates {
+			break
+		}
+		searched[match.TransactionID] = true
+
+		point := connector.ChainPoint{
+			Slot: uint64(match.CreatedAt.SlotNo),
+			Hash: match.CreatedAt.HeaderHash,
+		}
+		certificates, err := kp.txCertificates(ctx, point, match.TransactionID)
+		if err != nil {
+			return 0, err
+		}
+		// The last delegation certificate of a transaction is the one that
+		// holds.
+		for _, cert := range slices.Backward(certificates) {
+			if cert.Type != "stakeDelegation" || cert.Credential != wanted ||
+				cert.StakePool == nil {
+				continue
+			}
+			if cert.StakePool.ID != pool {
+				// The current delegation was made later, by a transaction
+				// that did not pay the account.
+				return 0, nil
+			}
+			eras, err := kp.GetEraSummaries(ctx)
+			if err != nil {
+				return 0, err
+			}
+			return int(slotToEpoch(eras, point.Slot)) + delegationActivationEpochs, nil
+		}
+	}
+	return 0, nil
+}
+
+// txCertificates returns the certificates of transaction txID in the block at
+// point. The block is read over chain-sync from the nearest Kupo checkpoint
+// before it; a block that cannot be reached within maxDelegationScanBlocks,
+// or that is no longer on the chain, yields no certificates.
+func (kp *KupmiosProvider) txCertificates(
+	ctx context.Context,
+	point connector.ChainPoint,
+	txID string,
+) ([]ogmiosCertificate, error) {
+	var from []connector.ChainPoint
+	if point.Slot > 0 {
+		var checkpoint *kupoCheckpoint
+		path := fmt.Sprintf("/v1/checkpoints/%d?strict=false", point.Slot-1)
+		if err := kp.kupo.get(ctx, path, &checkpoint); err != nil {
+			return nil, fmt.Errorf(
+				"kupmios: Kupo request for the checkpoint before slot %d failed: %w",
+				point.Slot,
+				err,
+			)
+		}
+		if checkpoint != nil {
+			from = append(from, connector.ChainPoint{
+				Slot: checkpoint.SlotNo,
+				Hash: checkpoint.HeaderHash,
+			})
+		}
+	}
+	conn, err := kp.findIntersection(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	// Closing the connection unblocks a pending read once ctx ends.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	// The first answer after an intersection is a rollback to it.
+	for range maxDelegationScanBlocks + 1 {
+		var response ogmiosNextBlock
+		if err := ogmiosCall(conn, "nextBlock", nil, &response); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("kupmios: %w", err)
+		}
+		if _, err := blockEvent(response); err != nil {
+			return nil, fmt.Errorf("kupmios: %w", err)
+		}
+		block := response.Result.Block
+		if response.Result.Direction != "forward" || block.Slot < point.Slot {
+			continue
+		}
+		if block.ID != point.Hash {
+			return nil, nil
+		}
+		for _, tx := range block.Transactions {
+			if tx.ID == txID {
+				return tx.Certificates, nil
+			}
+		}
+		return nil, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,221 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+const (
+	preprodStakeAddr = "stake_test17zt3vxfjx9pjnpnapa65lx375p2utwxmpc8afj053h0l3vgc8a3g3"
+	mainnetStakeAddr = "stake17xt3vxfjx9pjnpnapa65lx375p2utwxmpc8afj053h0l3vgldhnvv"
+)
+
+func TestGetDelegationRejectsOtherNetwork(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{NetworkId: preprodNetworkId})
+
+	_, err := kp.GetDelegation(context.Background(), mainnetStakeAddr)
+	assert.True(t, errors.Is(err, connector.ErrInvalidAddress), "got %v", err)
+	assert.Empty(t, ogmios.Calls("queryLedgerState/rewardAccountSummaries"))
+}
+
+func TestGetDelegationRejectsNonRewardAddresses(t *testing.T) {
+	kp, _, _ := newMockKupmios(t, Config{NetworkId: preprodNetworkId})
+
+	for _, addr := range []string{
+		adapterTestAddr,
+		"stake_test1notbech32",
+		preprodStakeAddr[:len(preprodStakeAddr)-1] + "q",
+	} {
+		_, err := kp.GetDelegation(context.Background(), addr)
+		assert.True(t, errors.Is(err, connector.ErrInvalidAddress), "%s: got %v", addr, err)
+	}
+}
+
+func TestGetDelegationWithoutCertificate(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{NetworkId: preprodNetworkId})
+	ogmios.handle("queryLedgerState/rewardAccountSummaries", func(json.RawMessage) any {
+		return map[string]any{
+			"97161932314329867d0f754f9a3ea055c5b8db0e0fd4c9f48ddff8b1": map[string]any{
+				"delegate": map[string]any{"id": "pool1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq"},
+				"rewards":  map[string]any{"ada": map[string]any{"lovelace": 1500000}},
+			},
+		}
+	})
+
+	delegation, err := kp.GetDelegation(context.Background(), preprodStakeAddr)
+	assert.NoError(t, err)
+	assert.True(t, delegation.Active)
+	assert.Equal(t, uint64(1500000), delegation.Rewards)
+	assert.Equal(t, 0, delegation.Epoch)
+	assert.Empty(t, ogmios.Calls("findIntersection"))
+}
+
+func TestGetDelegationUnregistered(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{NetworkId: preprodNetworkId})
+	ogmios.handle("queryLedgerState/rewardAccountSummaries", func(json.RawMessage) any {
+		return map[string]any{}
+	})
+
+	delegation, err := kp.GetDelegation(context.Background(), preprodStakeAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, connector.Delegation{}, delegation)
+	assert.Empty(t, ogmios.Calls("queryLedgerState/epoch"))
+}
+
+// delegationPool is the pool the test reward account delegates to.
+const delegationPool = "pool1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq"
+
+// serveDelegatedAccount makes Ogmios report preprodStakeAddr as delegated to
+// delegationPool.
+func serveDelegatedAccount(ogmios *mockOgmios) {
+	ogmios.handle("queryLedgerState/rewardAccountSummaries", func(json.RawMessage) any {
+		return map[string]any{
+			"97161932314329867d0f754f9a3ea055c5b8db0e0fd4c9f48ddff8b1": map[string]any{
+				"delegate": map[string]any{"id": delegationPool},
+				"rewards":  map[string]any{"ada": map[string]any{"lovelace": 0}},
+			},
+		}
+	})
+}
+
+func TestGetDelegationEpochFromCertificate(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{NetworkId: preprodNetworkId})
+	serveDelegatedAccount(ogmios)
+	servePreprodEras(ogmios)
+
+	payment := strings.Repeat("aa", 32)
+	certified := strings.Repeat("bb", 32)
+	paymentBlock := connector.ChainPoint{Slot: 600010, Hash: strings.Repeat("02", 32)}
+	certBlock := connector.ChainPoint{Slot: 600000, Hash: strings.Repeat("01", 32)}
+	kupo.route("/v1/matches/"+preprodStakeAddr, fmt.Sprintf(`[
+		{"transaction_id": %q, "output_index": 0, "created_at": {"slot_no": %d, "header_hash": %q}},
+		{"transaction_id": %q, "output_index": 1, "created_at": {"slot_no": %d, "header_hash": %q}},
+		{"transaction_id": %q, "output_index": 0, "created_at": {"slot_no": %d, "header_hash": %q}}
+	]`,
+		payment, paymentBlock.Slot, paymentBlock.Hash,
+		certified, certBlock.Slot, certBlock.Hash,
+		certified, certBlock.Slot, certBlock.Hash,
+	))
+	beforePayment := connector.ChainPoint{Slot: 600005, Hash: strings.Repeat("05", 32)}
+	beforeCert := connector.ChainPoint{Slot: 599990, Hash: strings.Repeat("09", 32)}
+	kupo.route("/v1/checkpoints/600009", checkpointJSON(beforePayment))
+	kupo.route("/v1/checkpoints/599999", checkpointJSON(beforeCert))
+
+	// Each connection replays the blocks following its intersection.
+	blocks := map[uint64][]any{
+		beforePayment.Slot: {
+			backwardMsg(beforePayment),
+			forwardMsg(paymentBlock, 2, payment),
+		},
+		beforeCert.Slot: {
+			backwardMsg(beforeCert),
+			forwardMsg(connector.ChainPoint{Slot: 599995, Hash: strings.Repeat("03", 32)}, 1),
+			map[string]any{
+				"direction": "forward",
+				"block": map[string]any{
+					"id":     certBlock.Hash,
+					"slot":   certBlock.Slot,
+					"height": 1,
+					"transactions": []any{
+						map[string]any{"id": strings.Repeat("cc", 32)},
+						map[string]any{
+							"id": certified,
+							"certificates": []any{
+								map[string]any{
+									"type":       "stakeCredentialRegistration",
+									"credential": "97161932314329867d0f754f9a3ea055c5b8db0e0fd4c9f48ddff8b1",
+								},
+								map[string]any{
+									"type":       "stakeDelegation",
+									"credential": "97161932314329867d0f754f9a3ea055c5b8db0e0fd4c9f48ddff8b1",
+									"stakePool":  map[string]any{"id": delegationPool},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	var (
+		mu      sync.Mutex
+		pending []any
+	)
+	ogmios.handle("findIntersection", func(params json.RawMessage) any {
+		var req struct {
+			Points []struct {
+				Slot uint64 `json:"slot"`
+			} `json:"points"`
+		}
+		assert.NoError(t, json.Unmarshal(params, &req))
+		mu.Lock()
+		defer mu.Unlock()
+		pending = blocks[req.Points[0].Slot]
+		return map[string]any{}
+	})
+	ogmios.handle("nextBlock", func(json.RawMessage) any {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(pending) == 0 {
+			return mockRPCError{Code: -32000, Message: "no more blocks"}
+		}
+		msg := pending[0]
+		pending = pending[1:]
+		return msg
+	})
+
+	delegation, err := kp.GetDelegation(context.Background(), preprodStakeAddr)
+	assert.NoError(t, err)
+	assert.True(t, delegation.Active)
+	// Slot 600000 is in epoch 5; the delegation is active two epochs later.
+	assert.Equal(t, 7, delegation.Epoch)
+	assert.Len(t, ogmios.Calls("findIntersection"), 2)
+}
+
+func TestGetDelegationSupersededCertificate(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{NetworkId: preprodNetworkId})
+	serveDelegatedAccount(ogmios)
+
+	certified := strings.Repeat("bb", 32)
+	certBlock := connector.ChainPoint{Slot: 600000, Hash: strings.Repeat("01", 32)}
+	kupo.route("/v1/matches/"+preprodStakeAddr, fmt.Sprintf(
+		`[{"transaction_id": %q, "output_index": 0, "created_at": {"slot_no": %d, "header_hash": %q}}]`,
+		certified, certBlock.Slot, certBlock.Hash,
+	))
+	kupo.route("/v1/checkpoints/599999", "null")
+	serveChainSync(ogmios,
+		backwardMsg(connector.ChainPoint{}),
+		map[string]any{
+			"direction": "forward",
+			"block": map[string]any{
+				"id":   certBlock.Hash,
+				"slot": certBlock.Slot,
+				"transactions": []any{map[string]any{
+					"id": certified,
+					"certificates": []any{map[string]any{
+						"type":       "stakeDelegation",
+						"credential": "97161932314329867d0f754f9a3ea055c5b8db0e0fd4c9f48ddff8b1",
+						"stakePool":  map[string]any{"id": "pool1another"},
+					}},
+				}},
+			},
+		},
+	)
+
+	delegation, err := kp.GetDelegation(context.Background(), preprodStakeAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, delegationPool, delegation.PoolId)
+	assert.Equal(t, 0, delegation.Epoch)
+}
+
+func checkpointJSON(point connector.ChainPoint) string {
+	return fmt.Sprintf(`{"slot_no": %d, "header_hash": %q}`, point.Slot, point.Hash)
+}
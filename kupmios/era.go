@@ -0,0 +1,203 @@
+package kupmios
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+var _ connector.SlotConverter = (*KupmiosProvider)(nil)
+
+// EraSummary describes one era of the chain's history as reported by Ogmios.
+type EraSummary struct {
+	Start EraBound
+	// End is nil when Ogmios reports no end for the era. For the current era
+	// it is the safe-zone horizon, not a scheduled hard fork.
+	End         *EraBound
+	SlotLength  time.Duration
+	EpochLength uint64
+	SafeZone    uint64
+}
+
+// EraBound is the first slot and epoch of an era boundary, and its time
+// relative to the network's system start.
+type EraBound struct {
+	Time  time.Duration
+	Slot  uint64
+	Epoch uint64
+}
+
+// GetEraSummaries returns the chain's era history, oldest era first.
+func (kp *KupmiosProvider) GetEraSummaries(
+	ctx context.Context,
+) ([]EraSummary, error) {
+	history, err := ogmiosRead(ctx, kp, "queryLedgerState/eraSummaries",
+		kp.ogmigoClient.EraSummaries)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"kupmios: failed to get era summaries from Ogmios: %w",
+			err,
+		)
+	}
+	if len(history.Summaries) == 0 {
+		return nil, fmt.Errorf(
+			"kupmios: %w: Ogmios returned no era summaries",
+			connector.ErrProviderInternal,
+		)
+	}
+
+	eras := make([]EraSummary, len(history.Summaries))
+	for i, raw := range history.Summaries {
+		eras[i] = EraSummary{
+			Start: EraBound{
+				Time:  time.Duration(raw.Start.Time.Seconds.Int64()) * time.Second,
+				Slot:  raw.Start.Slot,
+				Epoch: raw.Start.Epoch,
+			},
+			SlotLength:  time.Duration(raw.Parameters.SlotLength.Milliseconds.Int64()) * time.Millisecond,
+			EpochLength: raw.Parameters.EpochLength,
+			SafeZone:    raw.Parameters.SafeZone,
+		}
+		// ogmigo decodes a missing end as the zero bound, which can only
+		// be a real end for an empty era at genesis.
+		if raw.End.Slot > raw.Start.Slot {
+			eras[i].End = &EraBound{
+				Time:  time.Duration(raw.End.Time.Seconds.Int64()) * time.Second,
+				Slot:  raw.End.Slot,
+				Epoch: raw.End.Epoch,
+			}
+		}
+		if eras[i].SlotLength <= 0 {
+			return nil, fmt.Errorf(
+				"kupmios: %w: era starting at slot %d has slot length %v",
+				connector.ErrProviderInternal,
+				raw.Start.Slot,
+				eras[i].SlotLength,
+			)
+		}
+	}
+	return eras, nil
+}
+
+// SystemStart returns the time at which the chain started, i.e. the start of
+// slot 0. It never changes, so it is only queried once.
+func (kp *KupmiosProvider) SystemStart(ctx context.Context) (time.Time, error) {
+	kp.systemStartMu.Lock()
+	defer kp.systemStartMu.Unlock()
+	if !kp.systemStart.IsZero() {
+		return kp.systemStart, nil
+	}
+
+	raw, err := ogmiosRead(ctx, kp, "queryNetwork/startTime",
+		kp.ogmigoClient.StartTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(
+			"kupmios: failed to get system start from Ogmios: %w",
+			err,
+		)
+	}
+	start, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(
+			"kupmios: %w: invalid system start %q: %w",
+			connector.ErrProviderInternal,
+			raw,
+			err,
+		)
+	}
+	kp.systemStart = start.UTC()
+	return kp.systemStart, nil
+}
+
+// SlotToTime returns the start time of slot according to the era history.
+// Slots past the last known era boundary are extrapolated with the current
+// era's slot length.
+func (kp *KupmiosProvider) SlotToTime(
+	ctx context.Context,
+	slot uint64,
+) (time.Time, error) {
+	start, eras, err := kp.slotClock(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return slotToTime(start, eras, slot), nil
+}
+
+// TimeToSlot returns the slot in progress at t according to the era history.
+func (kp *KupmiosProvider) TimeToSlot(
+	ctx context.Context,
+	t time.Time,
+) (uint64, error) {
+	start, eras, err := kp.slotClock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if t.Before(start) {
+		return 0, fmt.Errorf(
+			"%w: time %s is before system start %s",
+			connector.ErrInvalidInput,
+			t.UTC().Format(time.RFC3339),
+			start.Format(time.RFC3339),
+		)
+	}
+	return timeToSlot(start, eras, t), nil
+}
+
+func (kp *KupmiosProvider) slotClock(
+	ctx context.Context,
+) (time.Time, []EraSummary, error) {
+	start, err := kp.SystemStart(ctx)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	eras, err := kp.GetEraSummaries(ctx)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	return start, eras, nil
+}
+
+// slotToTime converts slot with the era that contains it, or the last era.
+// eras must be non-empty and ordered.
+func slotToTime(start time.Time, eras []EraSummary, slot uint64) time.Time {
+	era := eraAt(eras, slot)
+	elapsed := era.Start.Time + time.Duration(slot-era.Start.Slot)*era.SlotLength
+	return start.Add(elapsed)
+}
+
+// slotToEpoch returns the epoch of slot with the era that contains it, or the
+// last era. eras must be non-empty and ordered.
+func slotToEpoch(eras []EraSummary, slot uint64) uint64 {
+	era := eraAt(eras, slot)
+	if era.EpochLength == 0 {
+		return era.Start.Epoch
+	}
+	return era.Start.Epoch + (slot-era.Start.Slot)/era.EpochLength
+}
+
+// eraAt returns the era that contains slot, or the last era. eras must be
+// non-empty and ordered.
+func eraAt(eras []EraSummary, slot uint64) EraSummary {
+	for _, e := range eras {
+		if e.End != nil && slot < e.End.Slot {
+			return e
+		}
+	}
+	return eras[len(eras)-1]
+}
+
+// timeToSlot converts t, which must not be before start, with the era that
+// contains it, or the last era. eras must be non-empty and ordered.
+func timeToSlot(start time.Time, eras []EraSummary, t time.Time) uint64 {
+	elapsed := t.Sub(start)
+	era := eras[len(eras)-1]
+	for _, e := range eras {
+		if e.End != nil && elapsed < e.End.Time {
+			era = e
+			break
+		}
+	}
+	return era.Start.Slot + uint64((elapsed-era.Start.Time)/era.SlotLength)
+}
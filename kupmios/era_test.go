@@ -0,0 +1,110 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// preprodSystemStart is the Byron system start of preprod.
+var preprodSystemStart = time.Unix(1654041600, 0).UTC()
+
+// servePreprodEras answers the era history of preprod up to its Shelley
+// horizon: a 4-epoch Byron era of 20-second slots followed by Shelley.
+func servePreprodEras(ogmios *mockOgmios) {
+	ogmios.handle("queryNetwork/startTime", func(json.RawMessage) any {
+		return preprodSystemStart.Format(time.RFC3339)
+	})
+	ogmios.handle("queryLedgerState/eraSummaries", func(json.RawMessage) any {
+		return json.RawMessage(`[
+			{
+				"start": {"time": {"seconds": 0}, "slot": 0, "epoch": 0},
+				"end": {"time": {"seconds": 1728000}, "slot": 86400, "epoch": 4},
+				"parameters": {"epochLength": 21600, "slotLength": {"milliseconds": 20000}, "safeZone": 4320}
+			},
+			{
+				"start": {"time": {"seconds": 1728000}, "slot": 86400, "epoch": 4},
+				"end": {"time": {"seconds": 2160000}, "slot": 518400, "epoch": 5},
+				"parameters": {"epochLength": 432000, "slotLength": {"milliseconds": 1000}, "safeZone": 129600}
+			}
+		]`)
+	})
+}
+
+func TestGetEraSummaries(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	servePreprodEras(ogmios)
+
+	eras, err := kp.GetEraSummaries(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, eras, 2)
+	assert.Equal(t, 20*time.Second, eras[0].SlotLength)
+	assert.Equal(t, uint64(86400), eras[0].End.Slot)
+	assert.Equal(t, EraBound{Time: 1728000 * time.Second, Slot: 86400, Epoch: 4}, eras[1].Start)
+	assert.Equal(t, time.Second, eras[1].SlotLength)
+	assert.Equal(t, uint64(432000), eras[1].EpochLength)
+}
+
+func TestSlotConversionAcrossByronBoundary(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	servePreprodEras(ogmios)
+	ctx := context.Background()
+
+	cases := []struct {
+		slot uint64
+		unix int64
+	}{
+		{slot: 0, unix: 1654041600},
+		{slot: 100, unix: 1654041600 + 2000},
+		// First Shelley slot; plutigo's preprod anchor.
+		{slot: 86400, unix: 1655769600},
+		{slot: 86401, unix: 1655769601},
+		// Past the horizon, extrapolated with 1-second slots.
+		{slot: 600000, unix: 1655769600 + 600000 - 86400},
+	}
+	for _, c := range cases {
+		got, err := kp.SlotToTime(ctx, c.slot)
+		assert.NoError(t, err)
+		assert.Equal(t, c.unix, got.Unix(), "slot %d", c.slot)
+
+		slot, err := kp.TimeToSlot(ctx, got)
+		assert.NoError(t, err)
+		assert.Equal(t, c.slot, slot)
+	}
+
+	// A time inside a Byron slot maps to that slot.
+	slot, err := kp.TimeToSlot(ctx, preprodSystemStart.Add(2019*time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), slot)
+
+	_, err = kp.TimeToSlot(ctx, preprodSystemStart.Add(-time.Second))
+	assert.True(t, errors.Is(err, connector.ErrInvalidInput), "got %v", err)
+
+	// The system start is queried once.
+	assert.Len(t, ogmios.Calls("queryNetwork/startTime"), 1)
+}
+
+func TestGetGenesisParamsUsesSystemStart(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	servePreprodEras(ogmios)
+	// Shelley genesis start of a network that did not begin in Shelley.
+	ogmios.handle("queryNetwork/genesisConfiguration", func(json.RawMessage) any {
+		return map[string]any{
+			"startTime":              "2022-06-21T00:00:00Z",
+			"networkMagic":           1,
+			"epochLength":            432000,
+			"activeSlotsCoefficient": "1/20",
+			"slotLength":             map[string]any{"milliseconds": 1000},
+		}
+	})
+
+	params, err := kp.GetGenesisParams(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, preprodSystemStart.Unix(), params.SystemStart)
+	assert.Equal(t, 1, params.SlotLength)
+}
@@ -0,0 +1,107 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+func TestEvaluateTxScriptFailure(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	ogmios.handle("evaluateTransaction", func(json.RawMessage) any {
+		return mockRPCError{
+			Code:    3010,
+			Message: "Some scripts of the transactions terminated with error(s).",
+			Data: []map[string]any{
+				{
+					"validator": map[string]any{"purpose": "spend", "index": 1},
+					"error": map[string]any{
+						"code":    3012,
+						"message": "Some of the scripts failed to evaluate to a positive outcome.",
+						"data": map[string]any{
+							"validationError": "An error has occurred: The machine terminated because of an error.",
+							"traces":          []string{"checking owner", "owner mismatch"},
+						},
+					},
+				},
+				{
+					"validator": map[string]any{"purpose": "mint", "index": 0},
+					"error": map[string]any{
+						"code":    3011,
+						"message": "Missing required redeemer.",
+					},
+				},
+			},
+		}
+	})
+
+	_, err := kp.EvaluateTx(context.Background(), []byte{0x84}, nil)
+	assert.True(t, errors.Is(err, connector.ErrEvaluationFailed), "got %v", err)
+
+	var evalErr *connector.EvaluationError
+	assert.True(t, errors.As(err, &evalErr), "got %T", err)
+	assert.Contains(t, evalErr.Message, "3010")
+	assert.Equal(t, []connector.RedeemerFailure{
+		{
+			Purpose: "spend",
+			Index:   1,
+			Reason:  "Some of the scripts failed to evaluate to a positive outcome.: An error has occurred: The machine terminated because of an error.",
+			Logs:    []string{"checking owner", "owner mismatch"},
+		},
+		{
+			Purpose: "mint",
+			Index:   0,
+			Reason:  "Missing required redeemer.",
+		},
+	}, evalErr.Failures)
+}
+
+func TestEvaluateTxUnstructuredFailure(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	ogmios.handle("evaluateTransaction", func(json.RawMessage) any {
+		return mockRPCError{
+			Code:    3000,
+			Message: "Unknown transaction inputs.",
+			Data:    map[string]any{"unknownOutputReferences": []any{}},
+		}
+	})
+
+	_, err := kp.EvaluateTx(context.Background(), []byte{0x84}, nil)
+	var evalErr *connector.EvaluationError
+	assert.True(t, errors.As(err, &evalErr), "got %v", err)
+	assert.Empty(t, evalErr.Failures)
+	assert.Contains(t, evalErr.Message, "unknownOutputReferences")
+}
+
+func TestEvaluateTxEmptyResult(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	ogmios.handle("evaluateTransaction", func(json.RawMessage) any {
+		return []any{}
+	})
+
+	_, err := kp.EvaluateTx(context.Background(), []byte{0x84}, nil)
+	assert.True(t, errors.Is(err, ErrEmptyEvaluation), "got %v", err)
+	assert.True(t, errors.Is(err, connector.ErrProviderInternal), "got %v", err)
+	assert.False(t, errors.Is(err, connector.ErrEvaluationFailed), "got %v", err)
+}
+
+func TestEvaluateTxResult(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	ogmios.handle("evaluateTransaction", func(json.RawMessage) any {
+		return []map[string]any{{
+			"validator": map[string]any{"purpose": "spend", "index": 0},
+			"budget":    map[string]any{"memory": 26285, "cpu": 7850649},
+		}}
+	})
+
+	exUnits, err := kp.EvaluateTx(context.Background(), []byte{0x84}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[common.RedeemerKey]common.ExUnits{
+		{Tag: common.RedeemerTagSpend, Index: 0}: {Memory: 26285, Steps: 7850649},
+	}, exUnits)
+}
@@ -0,0 +1,150 @@
+package kupmios
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/SundaeSwap-finance/kugo"
+)
+
+// defaultChainCacheSize bounds how many datums and how many scripts the
+// provider remembers when Config.ChainCacheSize is zero.
+const defaultChainCacheSize = 1024
+
+// cachingFetcher wraps a chainFetcher with caches keyed by hash. Datums and
+// scripts are content-addressed, so a hit never goes stale. Concurrent lookups
+// of the same hash share one upstream request.
+type cachingFetcher struct {
+	next    chainFetcher
+	datums  *hashCache[string]
+	scripts *hashCache[*kugo.Script]
+}
+
+func newCachingFetcher(next chainFetcher, size int) *cachingFetcher {
+	return &cachingFetcher{
+		next:    next,
+		datums:  newHashCache[string](size),
+		scripts: newHashCache[*kugo.Script](size),
+	}
+}
+
+// Datum returns the datum CBOR hex for datumHash. An empty answer (Kupo does
+// not know the datum yet) is not cached.
+func (f *cachingFetcher) Datum(
+	ctx context.Context,
+	datumHash string,
+) (string, error) {
+	return f.datums.get(ctx, datumHash, func(ctx context.Context) (string, bool, error) {
+		datum, err := f.next.Datum(ctx, datumHash)
+		return datum, err == nil && datum != "", err
+	})
+}
+
+// Script returns the script for scriptHash. A missing script is not cached.
+func (f *cachingFetcher) Script(
+	ctx context.Context,
+	scriptHash string,
+) (*kugo.Script, error) {
+	return f.scripts.get(ctx, scriptHash, func(ctx context.Context) (*kugo.Script, bool, error) {
+		script, err := f.next.Script(ctx, scriptHash)
+		return script, err == nil && script != nil && script.Script != "", err
+	})
+}
+
+// hashCache is a size-bounded LRU cache of immutable values keyed by hash
+// that collapses concurrent misses for the same key into a single fetch.
+type hashCache[V any] struct {
+	mu       sync.Mutex
+	size     int
+	entries  map[string]*list.Element
+	lru      *list.List // front is most recently used
+	inflight map[string]*hashCall[V]
+}
+
+type hashEntry[V any] struct {
+	key   string
+	value V
+}
+
+type hashCall[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+func newHashCache[V any](size int) *hashCache[V] {
+	return &hashCache[V]{
+		size:     size,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		inflight: make(map[string]*hashCall[V]),
+	}
+}
+
+// get returns the cached value for key or joins/starts a fetch. fetch reports
+// whether its result may be cached. The fetch runs detached from ctx so that
+// one caller giving up does not fail the others waiting on it; each caller
+// still returns as soon as its own ctx is done.
+func (c *hashCache[V]) get(
+	ctx context.Context,
+	key string,
+	fetch func(context.Context) (V, bool, error),
+) (V, error) {
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(elem)
+		value := elem.Value.(*hashEntry[V]).value
+		c.mu.Unlock()
+		return value, nil
+	}
+	call, ok := c.inflight[key]
+	if !ok {
+		call = &hashCall[V]{done: make(chan struct{})}
+		c.inflight[key] = call
+		go c.run(context.WithoutCancel(ctx), key, call, fetch)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+func (c *hashCache[V]) run(
+	ctx context.Context,
+	key string,
+	call *hashCall[V],
+	fetch func(context.Context) (V, bool, error),
+) {
+	value, cacheable, err := fetch(ctx)
+	call.value, call.err = value, err
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	if cacheable {
+		c.store(key, value)
+	}
+	c.mu.Unlock()
+	close(call.done)
+}
+
+// store records value under key, evicting the least recently used entry once
+// size is reached. The caller holds c.mu.
+func (c *hashCache[V]) store(key string, value V) {
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*hashEntry[V]).value = value
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&hashEntry[V]{key: key, value: value})
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*hashEntry[V]).key)
+	}
+}
@@ -0,0 +1,203 @@
+package kupmios
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SundaeSwap-finance/kugo"
+	"github.com/tj/assert"
+)
+
+// countingFetcher answers every lookup after delay and counts the upstream
+// calls it receives per hash.
+type countingFetcher struct {
+	delay time.Duration
+	datum string
+	calls sync.Map // hash -> *atomic.Int32
+}
+
+func (f *countingFetcher) count(hash string) *atomic.Int32 {
+	n, _ := f.calls.LoadOrStore(hash, new(atomic.Int32))
+	return n.(*atomic.Int32)
+}
+
+func (f *countingFetcher) Datum(_ context.Context, hash string) (string, error) {
+	f.count(hash).Add(1)
+	time.Sleep(f.delay)
+	return f.datum, nil
+}
+
+func (f *countingFetcher) Script(_ context.Context, hash string) (*kugo.Script, error) {
+	f.count(hash).Add(1)
+	time.Sleep(f.delay)
+	return &kugo.Script{Language: kugo.ScriptLanguagePlutusV2, Script: "4e4d01000033222220051200120011"}, nil
+}
+
+func TestCachingFetcherCoalescesConcurrentLookups(t *testing.T) {
+	upstream := &countingFetcher{delay: 20 * time.Millisecond}
+	fetcher := newCachingFetcher(upstream, 8)
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			script, err := fetcher.Script(context.Background(), "s1")
+			assert.NoError(t, err)
+			assert.NotNil(t, script)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), upstream.count("s1").Load())
+
+	_, err := fetcher.Script(context.Background(), "s1")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), upstream.count("s1").Load())
+}
+
+func TestCachingFetcherEvictsLeastRecentlyUsed(t *testing.T) {
+	upstream := &countingFetcher{datum: "d87980"}
+	fetcher := newCachingFetcher(upstream, 2)
+	ctx := context.Background()
+
+	for _, hash := range []string{"a", "b", "a", "c", "a", "b"} {
+		_, err := fetcher.Datum(ctx, hash)
+		assert.NoError(t, err)
+	}
+	// "b" was evicted by "c" and fetched again; "a" stayed hot.
+	assert.Equal(t, int32(1), upstream.count("a").Load())
+	assert.Equal(t, int32(2), upstream.count("b").Load())
+	assert.Equal(t, int32(1), upstream.count("c").Load())
+}
+
+func TestCachingFetcherSkipsEmptyDatums(t *testing.T) {
+	upstream := &countingFetcher{}
+	fetcher := newCachingFetcher(upstream, 2)
+
+	for range 3 {
+		datum, err := fetcher.Datum(context.Background(), "unknown")
+		assert.NoError(t, err)
+		assert.Equal(t, "", datum)
+	}
+	assert.Equal(t, int32(3), upstream.count("unknown").Load())
+}
+
+func TestCachingFetcherWaiterCancellation(t *testing.T) {
+	upstream := &countingFetcher{delay: time.Second}
+	fetcher := newCachingFetcher(upstream, 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := fetcher.Script(ctx, "slow")
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+// sharedScriptAddress serves n outputs at adapterTestAddr that all carry the
+// same reference script hash, and the script itself.
+func sharedScriptAddress(kupo *mockKupo, n int) string {
+	scriptHash := strings.Repeat("cd", 28)
+	matches := make([]string, n)
+	for i := range matches {
+		matches[i] = fmt.Sprintf(`{
+			"transaction_id": "%064x",
+			"output_index": 0,
+			"address": %q,
+			"value": {"coins": 2000000},
+			"script_hash": %q,
+			"created_at": {"slot_no": 10, "header_hash": %q}
+		}`, i+1, adapterTestAddr, scriptHash, strings.Repeat("01", 32))
+	}
+	kupo.route("/v1/matches/"+adapterTestAddr, "["+strings.Join(matches, ",")+"]")
+	kupo.route("/v1/scripts/"+scriptHash, `{"language": "plutus:v2", "script": "4e4d01000033222220051200120011"}`)
+	return "/v1/scripts/" + scriptHash
+}
+
+func TestGetUtxosByAddressSharesScriptLookups(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{})
+	scriptPath := sharedScriptAddress(kupo, 200)
+
+	utxos, err := kp.GetUtxosByAddress(context.Background(), adapterTestAddr)
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 200)
+	assert.Equal(t, 1, kupo.Requests(scriptPath))
+
+	uncached, _, kupo := newMockKupmios(t, Config{DisableChainCache: true})
+	scriptPath = sharedScriptAddress(kupo, 200)
+	_, err = uncached.GetUtxosByAddress(context.Background(), adapterTestAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, kupo.Requests(scriptPath))
+}
+
+func BenchmarkGetUtxosByAddressSharedScript(b *testing.B) {
+	for _, disable := range []bool{false, true} {
+		b.Run(fmt.Sprintf("DisableChainCache=%v", disable), func(b *testing.B) {
+			kp, _, kupo := newMockKupmios(b, Config{DisableChainCache: disable})
+			scriptPath := sharedScriptAddress(kupo, 200)
+			b.ResetTimer()
+			for range b.N {
+				if _, err := kp.GetUtxosByAddress(context.Background(), adapterTestAddr); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(kupo.Requests(scriptPath))/float64(b.N), "script-fetches/op")
+		})
+	}
+}
+
+func TestGetUtxosByAddressKeepsMatchOrder(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{MaxConcurrentRequests: 16})
+	sharedScriptAddress(kupo, 300)
+
+	utxos, err := kp.GetUtxosByAddress(context.Background(), adapterTestAddr)
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 300)
+	for i, utxo := range utxos {
+		assert.Equal(t, fmt.Sprintf("%064x", i+1), utxo.Id.Id().String())
+	}
+}
+
+// TestGetUtxosByAddressConcurrentScans runs overlapping scans that share the
+// datum and script caches; run it with -race.
+func TestGetUtxosByAddressConcurrentScans(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{})
+	resolvingAddress(kupo, 100)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = kp.GetUtxosByAddress(context.Background(), adapterTestAddr)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+}
+
+func BenchmarkGetUtxosByAddressConcurrency(b *testing.B) {
+	for _, limit := range []int{1, 8, 32} {
+		b.Run(fmt.Sprintf("MaxConcurrentRequests=%d", limit), func(b *testing.B) {
+			kp, _, kupo := newMockKupmios(b, Config{
+				MaxConcurrentRequests: limit,
+				DisableChainCache:     true,
+			})
+			resolvingAddress(kupo, 2000)
+			b.ResetTimer()
+			for range b.N {
+				if _, err := kp.GetUtxosByAddress(context.Background(), adapterTestAddr); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// GenesisConfig is the genesis configuration of one era as served by Ogmios.
+// The field matching Era is set; Raw holds the response as received.
+type GenesisConfig struct {
+	Era     string
+	Shelley *ShelleyGenesis
+	Alonzo  *AlonzoGenesis
+	Conway  *ConwayGenesis
+	Raw     json.RawMessage
+}
+
+// ShelleyGenesis is the Ogmios shelley genesis configuration, the subset of
+// it that maps onto backend.GenesisParameters.
+type ShelleyGenesis struct {
+	StartTime         string `json:"startTime"`
+	NetworkMagic      int    `json:"networkMagic"`
+	EpochLength       int    `json:"epochLength"`
+	SlotsPerKesPeriod int    `json:"slotsPerKesPeriod"`
+	MaxKesEvolutions  int    `json:"maxKesEvolutions"`
+	SecurityParam     int    `json:"securityParameter"`
+	UpdateQuorum      int    `json:"updateQuorum"`
+	// ActiveSlotsCoefficient is a fraction like "1/20".
+	ActiveSlots       string `json:"activeSlotsCoefficient"`
+	MaxLovelaceSupply int64  `json:"maxLovelaceSupply"`
+	SlotLength        struct {
+		Milliseconds int `json:"milliseconds"`
+	} `json:"slotLength"`
+}
+
+// AlonzoGenesis is the Ogmios alonzo genesis configuration, which introduced
+// Plutus and its initial cost models.
+type AlonzoGenesis struct {
+	UpdatableParameters struct {
+		MinUtxoDepositCoefficient int64          `json:"minUtxoDepositCoefficient"`
+		CollateralPercentage      int            `json:"collateralPercentage"`
+		MaxCollateralInputs       int            `json:"maxCollateralInputs"`
+		MaxValueSize              GenesisBytes   `json:"maxValueSize"`
+		ScriptExecutionPrices     GenesisPrices  `json:"scriptExecutionPrices"`
+		MaxExecutionUnitsPerTx    GenesisExUnits `json:"maxExecutionUnitsPerTransaction"`
+		MaxExecutionUnitsPerBlock GenesisExUnits `json:"maxExecutionUnitsPerBlock"`
+		// PlutusCostModels is keyed by language, e.g. "plutus:v1".
+		PlutusCostModels map[string][]int64 `json:"plutusCostModels"`
+	} `json:"updatableParameters"`
+}
+
+// ConwayGenesis is the Ogmios conway genesis configuration, which carries the
+// initial governance settings.
+type ConwayGenesis struct {
+	Constitution struct {
+		Metadata struct {
+			URL  string `json:"url"`
+			Hash string `json:"hash"`
+		} `json:"metadata"`
+		// Guardrails is nil when the constitution has no guardrails script.
+		Guardrails *struct {
+			Hash string `json:"hash"`
+		} `json:"guardrails"`
+	} `json:"constitution"`
+	ConstitutionalCommittee struct {
+		Members []struct {
+			ID      string `json:"id"`
+			Mandate struct {
+				Epoch uint64 `json:"epoch"`
+			} `json:"mandate"`
+		} `json:"members"`
+		// Quorum is a fraction like "2/3".
+		Quorum string `json:"quorum"`
+	} `json:"constitutionalCommittee"`
+	UpdatableParameters struct {
+		StakePoolVotingThresholds            GenesisVotingThresholds `json:"stakePoolVotingThresholds"`
+		DelegateRepresentativeThresholds     GenesisVotingThresholds `json:"delegateRepresentativeVotingThresholds"`
+		ConstitutionalCommitteeMinSize       int                     `json:"constitutionalCommitteeMinSize"`
+		ConstitutionalCommitteeMaxTermLength int                     `json:"constitutionalCommitteeMaxTermLength"`
+		GovernanceActionLifetime             int                     `json:"governanceActionLifetime"`
+		GovernanceActionDeposit              GenesisLovelace         `json:"governanceActionDeposit"`
+		DelegateRepresentativeDeposit        GenesisLovelace         `json:"delegateRepresentativeDeposit"`
+		DelegateRepresentativeMaxIdleTime    int                     `json:"delegateRepresentativeMaxIdleTime"`
+		// PlutusCostModels is keyed by language, e.g. "plutus:v3".
+		PlutusCostModels map[string][]int64 `json:"plutusCostModels"`
+	} `json:"updatableParameters"`
+}
+
+// GenesisVotingThresholds are the fractions of stake a governance action
+// needs from one voting body. Thresholds a body does not vote on are empty.
+type GenesisVotingThresholds struct {
+	NoConfidence            string `json:"noConfidence"`
+	ConstitutionalCommittee struct {
+		Default             string `json:"default"`
+		StateOfNoConfidence string `json:"stateOfNoConfidence"`
+	} `json:"constitutionalCommittee"`
+	Constitution       string `json:"constitution"`
+	HardForkInitiation string `json:"hardForkInitiation"`
+	// ProtocolParametersUpdate is keyed by parameter group, e.g. "security"
+	// or "economic".
+	ProtocolParametersUpdate map[string]string `json:"protocolParametersUpdate"`
+	TreasuryWithdrawals      string            `json:"treasuryWithdrawals"`
+}
+
+// GenesisLovelace is an Ogmios ada amount, nested as {"ada":{"lovelace":N}}.
+type GenesisLovelace struct {
+	Ada struct {
+		Lovelace int64 `json:"lovelace"`
+	} `json:"ada"`
+}
+
+// GenesisBytes is an Ogmios size in bytes.
+type GenesisBytes struct {
+	Bytes int `json:"bytes"`
+}
+
+// GenesisPrices are the Plutus execution prices as fractions like
+// "577/10000".
+type GenesisPrices struct {
+	Memory string `json:"memory"`
+	CPU    string `json:"cpu"`
+}
+
+// GenesisExUnits is an execution budget.
+type GenesisExUnits struct {
+	Memory int64 `json:"memory"`
+	CPU    int64 `json:"cpu"`
+}
+
+// GetGenesisConfig queries the genesis configuration of era, one of
+// "shelley", "alonzo" and "conway". Other eras fail with
+// connector.ErrInvalidInput.
+func (kp *KupmiosProvider) GetGenesisConfig(
+	ctx context.Context,
+	era string,
+) (GenesisConfig, error) {
+	config := GenesisConfig{Era: strings.ToLower(era)}
+	var target any
+	switch config.Era {
+	case "shelley":
+		config.Shelley = &ShelleyGenesis{}
+		target = config.Shelley
+	case "alonzo":
+		config.Alonzo = &AlonzoGenesis{}
+		target = config.Alonzo
+	case "conway":
+		config.Conway = &ConwayGenesis{}
+		target = config.Conway
+	default:
+		return GenesisConfig{}, fmt.Errorf(
+			"%w: no genesis configuration for era %q, expected shelley, alonzo or conway",
+			connector.ErrInvalidInput,
+			era,
+		)
+	}
+
+	raw, err := ogmiosRead(ctx, kp, "queryNetwork/genesisConfiguration",
+		func(ctx context.Context) (json.RawMessage, error) {
+			return kp.ogmigoClient.GenesisConfig(ctx, config.Era)
+		},
+	)
+	if err != nil {
+		return GenesisConfig{}, fmt.Errorf(
+			"kupmios: failed to get %s genesis configuration: %w",
+			config.Era,
+			err,
+		)
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return GenesisConfig{}, fmt.Errorf(
+			"kupmios: failed to parse %s genesis configuration: %w",
+			config.Era,
+			err,
+		)
+	}
+	config.Raw = raw
+	return config, nil
+}
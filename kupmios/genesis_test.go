@@ -0,0 +1,136 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+const alonzoGenesisFixture = `{
+	"era": "alonzo",
+	"updatableParameters": {
+		"minUtxoDepositCoefficient": 34482,
+		"collateralPercentage": 150,
+		"plutusCostModels": {"plutus:v1": [197209, 0, 1, 1]},
+		"maxCollateralInputs": 3,
+		"maxExecutionUnitsPerBlock": {"memory": 50000000, "cpu": 40000000000},
+		"maxExecutionUnitsPerTransaction": {"memory": 10000000, "cpu": 10000000000},
+		"maxValueSize": {"bytes": 5000},
+		"scriptExecutionPrices": {"memory": "577/10000", "cpu": "721/10000000"}
+	}
+}`
+
+const conwayGenesisFixture = `{
+	"era": "conway",
+	"constitution": {
+		"metadata": {"url": "", "hash": "0000000000000000000000000000000000000000000000000000000000000000"},
+		"guardrails": null
+	},
+	"constitutionalCommittee": {
+		"members": [{"id": "7ceede7d6a89e006408e6b7c6acb44dd311ac0b1e9b7eef8ad7d4fee", "from": "verificationKey", "mandate": {"epoch": 580}}],
+		"quorum": "2/3"
+	},
+	"updatableParameters": {
+		"stakePoolVotingThresholds": {
+			"noConfidence": "51/100",
+			"constitutionalCommittee": {"default": "51/100", "stateOfNoConfidence": "51/100"},
+			"hardForkInitiation": "51/100",
+			"protocolParametersUpdate": {"security": "51/100"}
+		},
+		"delegateRepresentativeVotingThresholds": {
+			"noConfidence": "67/100",
+			"constitutionalCommittee": {"default": "67/100", "stateOfNoConfidence": "3/5"},
+			"constitution": "3/4",
+			"hardForkInitiation": "3/5",
+			"protocolParametersUpdate": {"network": "67/100", "economic": "67/100", "technical": "67/100", "governance": "3/4"},
+			"treasuryWithdrawals": "67/100"
+		},
+		"constitutionalCommitteeMinSize": 7,
+		"constitutionalCommitteeMaxTermLength": 146,
+		"governanceActionLifetime": 6,
+		"governanceActionDeposit": {"ada": {"lovelace": 100000000000}},
+		"delegateRepresentativeDeposit": {"ada": {"lovelace": 500000000}},
+		"delegateRepresentativeMaxIdleTime": 20,
+		"plutusCostModels": {"plutus:v3": [100788, 420, 1, 1]}
+	}
+}`
+
+// serveGenesis answers genesisConfiguration queries with the fixture of the
+// requested era.
+func serveGenesis(ogmios *mockOgmios) {
+	ogmios.handle("queryNetwork/genesisConfiguration", func(params json.RawMessage) any {
+		var query struct {
+			Era string `json:"era"`
+		}
+		_ = json.Unmarshal(params, &query)
+		switch query.Era {
+		case "alonzo":
+			return json.RawMessage(alonzoGenesisFixture)
+		case "conway":
+			return json.RawMessage(conwayGenesisFixture)
+		}
+		return mockRPCError{Code: -32602, Message: "unknown era " + query.Era}
+	})
+}
+
+func TestGetGenesisConfigAlonzo(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	serveGenesis(ogmios)
+
+	config, err := kp.GetGenesisConfig(context.Background(), "alonzo")
+	assert.NoError(t, err)
+	assert.Equal(t, "alonzo", config.Era)
+	assert.Nil(t, config.Shelley)
+	assert.Nil(t, config.Conway)
+	assert.NotNil(t, config.Alonzo)
+
+	params := config.Alonzo.UpdatableParameters
+	assert.Equal(t, int64(34482), params.MinUtxoDepositCoefficient)
+	assert.Equal(t, 150, params.CollateralPercentage)
+	assert.Equal(t, 5000, params.MaxValueSize.Bytes)
+	assert.Equal(t, "577/10000", params.ScriptExecutionPrices.Memory)
+	assert.Equal(t, int64(10000000000), params.MaxExecutionUnitsPerTx.CPU)
+	assert.Equal(t, []int64{197209, 0, 1, 1}, params.PlutusCostModels["plutus:v1"])
+	assert.NotEmpty(t, config.Raw)
+}
+
+func TestGetGenesisConfigConway(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	serveGenesis(ogmios)
+
+	config, err := kp.GetGenesisConfig(context.Background(), "Conway")
+	assert.NoError(t, err)
+	assert.Equal(t, "conway", config.Era)
+	assert.NotNil(t, config.Conway)
+
+	conway := config.Conway
+	assert.Nil(t, conway.Constitution.Guardrails)
+	assert.Equal(t, "2/3", conway.ConstitutionalCommittee.Quorum)
+	assert.Len(t, conway.ConstitutionalCommittee.Members, 1)
+	assert.Equal(t, uint64(580), conway.ConstitutionalCommittee.Members[0].Mandate.Epoch)
+
+	params := conway.UpdatableParameters
+	assert.Equal(t, 7, params.ConstitutionalCommitteeMinSize)
+	assert.Equal(t, 6, params.GovernanceActionLifetime)
+	assert.Equal(t, int64(100000000000), params.GovernanceActionDeposit.Ada.Lovelace)
+	assert.Equal(t, int64(500000000), params.DelegateRepresentativeDeposit.Ada.Lovelace)
+	assert.Equal(t, "51/100", params.StakePoolVotingThresholds.ProtocolParametersUpdate["security"])
+	assert.Equal(t, "", params.StakePoolVotingThresholds.Constitution)
+	assert.Equal(t, "3/5", params.DelegateRepresentativeThresholds.ConstitutionalCommittee.StateOfNoConfidence)
+	assert.Equal(t, []int64{100788, 420, 1, 1}, params.PlutusCostModels["plutus:v3"])
+}
+
+func TestGetGenesisConfigUnknownEra(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	serveGenesis(ogmios)
+
+	for _, era := range []string{"byron", "babbage", ""} {
+		_, err := kp.GetGenesisConfig(context.Background(), era)
+		assert.True(t, errors.Is(err, connector.ErrInvalidInput), "%q: got %v", era, err)
+	}
+	assert.Empty(t, ogmios.Calls("queryNetwork/genesisConfiguration"))
+}
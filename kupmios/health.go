@@ -0,0 +1,325 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// defaultMaxKupoLag is how many slots Kupo may trail the node tip before
+// HealthCheck reports it unhealthy when Config.MaxKupoLag is zero.
+const defaultMaxKupoLag = 120
+
+// ErrUnhealthy indicates that Ogmios or Kupo is unreachable, disconnected
+// from its node, or that Kupo trails the node tip by more than the
+// configured lag.
+var ErrUnhealthy = errors.New("kupmios: backend unhealthy")
+
+// kupoSyncCacheTTL is how long the Kupo sync status fetched for the
+// Config.KupoSyncGuardSlots check is reused before Kupo is asked again.
+const kupoSyncCacheTTL = 5 * time.Second
+
+// validateTimeout bounds the checks New runs with Config.ValidateOnNew.
+const validateTimeout = 10 * time.Second
+
+// mainnetNetworkMagic is the network magic of mainnet, the only network with
+// network id 1.
+const mainnetNetworkMagic = 764824073
+
+// ErrProviderSyncing indicates that Kupo trails its node tip by more than
+// Config.KupoSyncGuardSlots, so its UTxO set may be incomplete. It wraps
+// connector.ErrProviderInternal.
+var ErrProviderSyncing = fmt.Errorf(
+	"%w: kupmios: Kupo is still syncing",
+	connector.ErrProviderInternal,
+)
+
+// ErrNetworkMismatch indicates that Ogmios follows a network other than the
+// configured one, or that Kupo indexes a different chain than Ogmios follows.
+// It wraps connector.ErrInvalidInput.
+var ErrNetworkMismatch = fmt.Errorf(
+	"%w: kupmios: network mismatch",
+	connector.ErrInvalidInput,
+)
+
+// chainCheckPoints is how many of Kupo's most recent checkpoints the startup
+// check offers Ogmios, so that a rollback of the latest ones does not make
+// the services look like they follow different chains.
+const chainCheckPoints = 10
+
+// kupoCheckpoint is an entry of the Kupo /v1/checkpoints response.
+type kupoCheckpoint struct {
+	SlotNo     uint64 `json:"slot_no"`
+	HeaderHash string `json:"header_hash"`
+}
+
+// Health is the state of the Ogmios and Kupo backends.
+type Health struct {
+	Healthy bool         `json:"healthy"`
+	Ogmios  OgmiosHealth `json:"ogmios"`
+	Kupo    KupoHealth   `json:"kupo"`
+	// SlotLag is how many slots Kupo's most recent checkpoint trails the
+	// furthest node tip Ogmios or Kupo has seen. It can exceed Kupo.Lag
+	// when Kupo's node falls behind Ogmios'.
+	SlotLag uint64 `json:"slot_lag"`
+}
+
+// OgmiosHealth is the subset of the Ogmios /health response that describes
+// its sync status.
+type OgmiosHealth struct {
+	ConnectionStatus       string  `json:"connectionStatus"`
+	NetworkSynchronization float64 `json:"networkSynchronization"`
+	CurrentEra             string  `json:"currentEra"`
+	LastKnownTip           struct {
+		Slot   uint64 `json:"slot"`
+		ID     string `json:"id"`
+		Height uint64 `json:"height"`
+	} `json:"lastKnownTip"`
+}
+
+// KupoHealth is the subset of the Kupo /health response that describes its
+// sync status.
+type KupoHealth struct {
+	ConnectionStatus       string  `json:"connection_status"`
+	MostRecentCheckpoint   uint64  `json:"most_recent_checkpoint"`
+	MostRecentNodeTip      uint64  `json:"most_recent_node_tip"`
+	NetworkSynchronization float64 `json:"network_synchronization"`
+}
+
+// Lag is how many slots the most recent checkpoint trails Kupo's view of the
+// node tip. Config.KupoSyncGuardSlots bounds it; HealthCheck measures
+// Health.SlotLag instead.
+func (h KupoHealth) Lag() uint64 {
+	if h.MostRecentNodeTip > h.MostRecentCheckpoint {
+		return h.MostRecentNodeTip - h.MostRecentCheckpoint
+	}
+	return 0
+}
+
+// HealthCheck queries the health endpoints of Ogmios and Kupo. It always
+// returns what it learned; the error wraps ErrUnhealthy and lists every
+// problem found when either backend is unreachable or disconnected, or when
+// Kupo trails the node tip by more than Config.MaxKupoLag slots.
+func (kp *KupmiosProvider) HealthCheck(ctx context.Context) (Health, error) {
+	var (
+		health   Health
+		problems []string
+	)
+
+	ogmiosURL, ogmiosErr := ogmiosHealthURL(kp.ogmiosEndpoint)
+	if ogmiosErr == nil {
+		ogmiosErr = getHealth(ctx, kp.httpClient, ogmiosURL, kp.ogmiosHeaders, &health.Ogmios)
+	}
+	switch {
+	case ogmiosErr != nil:
+		problems = append(problems, fmt.Sprintf("ogmios: %v", ogmiosErr))
+	case health.Ogmios.ConnectionStatus != "connected":
+		problems = append(problems, fmt.Sprintf(
+			"ogmios: node connection %q",
+			health.Ogmios.ConnectionStatus,
+		))
+	}
+
+	kupoURL := strings.TrimSuffix(kp.kupoEndpoint, "/") + "/health"
+	kupoErr := getHealth(ctx, kp.httpClient, kupoURL, kp.kupoHeaders, &health.Kupo)
+	switch {
+	case kupoErr != nil:
+		problems = append(problems, fmt.Sprintf("kupo: %v", kupoErr))
+	case health.Kupo.ConnectionStatus != "connected":
+		problems = append(problems, fmt.Sprintf(
+			"kupo: node connection %q",
+			health.Kupo.ConnectionStatus,
+		))
+	}
+
+	// Ogmios and Kupo may follow different nodes; measure against whichever
+	// has seen the furthest tip.
+	nodeTip := max(health.Ogmios.LastKnownTip.Slot, health.Kupo.MostRecentNodeTip)
+	if nodeTip > health.Kupo.MostRecentCheckpoint {
+		health.SlotLag = nodeTip - health.Kupo.MostRecentCheckpoint
+	}
+	if kupoErr == nil && health.SlotLag > kp.maxKupoLag {
+		problems = append(problems, fmt.Sprintf(
+			"kupo: checkpoint %d trails node tip %d by %d slots",
+			health.Kupo.MostRecentCheckpoint,
+			nodeTip,
+			health.SlotLag,
+		))
+	}
+
+	if len(problems) > 0 {
+		return health, fmt.Errorf("%w: %s", ErrUnhealthy, strings.Join(problems, "; "))
+	}
+	health.Healthy = true
+	return health, nil
+}
+
+// GetKupoSyncStatus queries Kupo's health endpoint for its most recent
+// checkpoint and its view of the node tip.
+func (kp *KupmiosProvider) GetKupoSyncStatus(ctx context.Context) (KupoHealth, error) {
+	var status KupoHealth
+	kupoURL := strings.TrimSuffix(kp.kupoEndpoint, "/") + "/health"
+	if err := getHealth(ctx, kp.httpClient, kupoURL, kp.kupoHeaders, &status); err != nil {
+		return KupoHealth{}, fmt.Errorf("kupmios: Kupo health request failed: %w", err)
+	}
+	return status, nil
+}
+
+// checkKupoSynced fails with ErrProviderSyncing when Config.KupoSyncGuardSlots
+// is set and KupoHealth.Lag exceeds it. The sync status is cached for
+// kupoSyncCacheTTL so that guarded queries do not each cost an extra request.
+func (kp *KupmiosProvider) checkKupoSynced(ctx context.Context) error {
+	if kp.kupoSyncGuardSlots == 0 {
+		return nil
+	}
+
+	kp.kupoSyncMu.Lock()
+	defer kp.kupoSyncMu.Unlock()
+	if time.Since(kp.kupoSyncAt) > kupoSyncCacheTTL {
+		status, err := kp.GetKupoSyncStatus(ctx)
+		if err != nil {
+			return err
+		}
+		kp.kupoSync = status
+		kp.kupoSyncAt = time.Now()
+	}
+
+	if lag := kp.kupoSync.Lag(); lag > kp.kupoSyncGuardSlots {
+		return fmt.Errorf(
+			"%w: checkpoint %d trails node tip %d by %d slots",
+			ErrProviderSyncing,
+			kp.kupoSync.MostRecentCheckpoint,
+			kp.kupoSync.MostRecentNodeTip,
+			lag,
+		)
+	}
+	return nil
+}
+
+// checkConnectivity fails with ErrUnhealthy naming the backend that does not
+// answer its health endpoint. Unless Config.SkipNetworkCheck is set, it fails
+// with ErrNetworkMismatch when the network Ogmios follows does not match the
+// configured network id or Kupo indexes a different chain.
+func (kp *KupmiosProvider) checkConnectivity(ctx context.Context) error {
+	var ogmiosHealth OgmiosHealth
+	ogmiosURL, err := ogmiosHealthURL(kp.ogmiosEndpoint)
+	if err == nil {
+		err = getHealth(ctx, kp.httpClient, ogmiosURL, kp.ogmiosHeaders, &ogmiosHealth)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: ogmios at %s: %w", ErrUnhealthy, redactEndpoint(kp.ogmiosEndpoint), err)
+	}
+
+	var kupoHealth KupoHealth
+	kupoURL := strings.TrimSuffix(kp.kupoEndpoint, "/") + "/health"
+	if err := getHealth(ctx, kp.httpClient, kupoURL, kp.kupoHeaders, &kupoHealth); err != nil {
+		return fmt.Errorf("%w: kupo at %s: %w", ErrUnhealthy, redactEndpoint(kp.kupoEndpoint), err)
+	}
+
+	if kp.skipNetworkCheck {
+		return nil
+	}
+	genesis, err := kp.GetGenesisConfig(ctx, "shelley")
+	if err != nil {
+		return fmt.Errorf("%w: ogmios at %s: %w", ErrUnhealthy, redactEndpoint(kp.ogmiosEndpoint), err)
+	}
+	magic := genesis.Shelley.NetworkMagic
+	if (magic == mainnetNetworkMagic) != (kp.networkId == 1) {
+		return fmt.Errorf(
+			"%w: Ogmios follows the network with magic %d, which does not have network id %d",
+			ErrNetworkMismatch,
+			magic,
+			kp.networkId,
+		)
+	}
+	return kp.checkSameChain(ctx)
+}
+
+// checkSameChain fails with ErrNetworkMismatch when none of Kupo's most recent
+// checkpoints is on the chain Ogmios follows. A Kupo that has no checkpoint
+// yet passes.
+func (kp *KupmiosProvider) checkSameChain(ctx context.Context) error {
+	var checkpoints []kupoCheckpoint
+	if err := kp.kupo.get(ctx, "/v1/checkpoints", &checkpoints); err != nil {
+		return fmt.Errorf("%w: kupo at %s: %w", ErrUnhealthy, redactEndpoint(kp.kupoEndpoint), err)
+	}
+	if len(checkpoints) == 0 {
+		return nil
+	}
+
+	// Kupo lists its checkpoints most recent first.
+	points := make([]connector.ChainPoint, 0, chainCheckPoints)
+	for _, checkpoint := range checkpoints[:min(len(checkpoints), chainCheckPoints)] {
+		points = append(points, connector.ChainPoint{
+			Slot: checkpoint.SlotNo,
+			Hash: checkpoint.HeaderHash,
+		})
+	}
+	conn, err := kp.findIntersection(ctx, points)
+	if errors.Is(err, connector.ErrNotFound) {
+		return fmt.Errorf(
+			"%w: Kupo at %s indexes block %s at slot %d, which is not on the chain Ogmios at %s follows",
+			ErrNetworkMismatch,
+			redactEndpoint(kp.kupoEndpoint),
+			points[0].Hash,
+			points[0].Slot,
+			redactEndpoint(kp.ogmiosEndpoint),
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: ogmios at %s: %w", ErrUnhealthy, redactEndpoint(kp.ogmiosEndpoint), err)
+	}
+	conn.Close()
+	return nil
+}
+
+// ogmiosHealthURL maps the Ogmios websocket endpoint onto its HTTP /health
+// endpoint.
+func ogmiosHealthURL(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/health"
+	return u.String(), nil
+}
+
+// getHealth decodes the JSON health report served at endpoint. Both services
+// answer with a report even when they reply with an error status.
+func getHealth(
+	ctx context.Context,
+	client *http.Client,
+	endpoint string,
+	headers http.Header,
+	out any,
+) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	setHeaders(req, headers)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("unreadable health report (HTTP %d): %w", resp.StatusCode, err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package kupmios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// serveHealth makes Ogmios report a node tip at ogmiosTip and Kupo a
+// checkpoint at checkpoint, with Kupo's own view of the node tip at kupoTip.
+func serveHealth(ogmios *mockOgmios, kupo *mockKupo, ogmiosTip, kupoTip, checkpoint uint64) {
+	ogmios.serveHealth(fmt.Sprintf(`{
+		"connectionStatus": "connected",
+		"currentEra": "conway",
+		"networkSynchronization": 0.99999,
+		"lastKnownTip": {"slot": %d, "id": "aa", "height": 100}
+	}`, ogmiosTip))
+	kupo.route("/health", fmt.Sprintf(`{
+		"connection_status": "connected",
+		"most_recent_checkpoint": %d,
+		"most_recent_node_tip": %d,
+		"network_synchronization": 0.99999
+	}`, checkpoint, kupoTip))
+}
+
+func TestHealthCheckHealthy(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{})
+	serveHealth(ogmios, kupo, 1000, 1000, 990)
+
+	health, err := kp.HealthCheck(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, health.Healthy)
+	assert.Equal(t, uint64(10), health.SlotLag)
+	assert.Equal(t, "conway", health.Ogmios.CurrentEra)
+	assert.Equal(t, uint64(1000), health.Ogmios.LastKnownTip.Slot)
+	assert.Equal(t, uint64(990), health.Kupo.MostRecentCheckpoint)
+}
+
+func TestHealthCheckKupoBehindOgmios(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{MaxKupoLag: 50})
+	// Kupo's node is stuck too, so only the Ogmios tip shows the lag.
+	serveHealth(ogmios, kupo, 5000, 4000, 4000)
+
+	health, err := kp.HealthCheck(context.Background())
+	assert.True(t, errors.Is(err, ErrUnhealthy), "got %v", err)
+	assert.Contains(t, err.Error(), "trails node tip 5000 by 1000 slots")
+	assert.False(t, health.Healthy)
+	assert.Equal(t, uint64(1000), health.SlotLag)
+	assert.Equal(t, "connected", health.Kupo.ConnectionStatus)
+}
+
+func TestHealthCheckWithinMaxLag(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{MaxKupoLag: 50})
+	serveHealth(ogmios, kupo, 5000, 5010, 4960)
+
+	health, err := kp.HealthCheck(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(50), health.SlotLag)
+}
+
+func TestHealthCheckReportsEveryProblem(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{})
+	ogmios.serveHealth(`{"connectionStatus": "disconnected", "lastKnownTip": {"slot": 100}}`)
+	kupo.fail("/health", 503)
+
+	health, err := kp.HealthCheck(context.Background())
+	assert.True(t, errors.Is(err, ErrUnhealthy), "got %v", err)
+	assert.Contains(t, err.Error(), `ogmios: node connection "disconnected"`)
+	assert.Contains(t, err.Error(), "kupo:")
+	assert.False(t, health.Healthy)
+	assert.Equal(t, "disconnected", health.Ogmios.ConnectionStatus)
+}
+
+func TestHealthCheckUnreachable(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{})
+	serveHealth(ogmios, kupo, 1000, 1000, 1000)
+	ogmios.Close()
+
+	_, err := kp.HealthCheck(context.Background())
+	assert.True(t, errors.Is(err, ErrUnhealthy), "got %v", err)
+	assert.Contains(t, err.Error(), "ogmios:")
+	assert.NotContains(t, err.Error(), "kupo:")
+}
+
+func TestNewRejectsNegativeMaxKupoLag(t *testing.T) {
+	_, err := New(withLocalEndpoints(Config{MaxKupoLag: -1}))
+	assert.True(t, errors.Is(err, connector.ErrInvalidInput), "got %v", err)
+}
+
+func TestNewRejectsNegativeKupoSyncGuardSlots(t *testing.T) {
+	_, err := New(withLocalEndpoints(Config{KupoSyncGuardSlots: -1}))
+	assert.True(t, errors.Is(err, connector.ErrInvalidInput), "got %v", err)
+}
+
+func TestGetKupoSyncStatus(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{})
+	serveHealth(ogmios, kupo, 0, 5000, 4990)
+
+	status, err := kp.GetKupoSyncStatus(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4990), status.MostRecentCheckpoint)
+	assert.Equal(t, uint64(5000), status.MostRecentNodeTip)
+	assert.Equal(t, uint64(10), status.Lag())
+}
+
+func TestKupoSyncGuardLagging(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{KupoSyncGuardSlots: 100})
+	serveHealth(ogmios, kupo, 0, 5000, 1000)
+
+	_, err := kp.GetUtxosByAddress(context.Background(), adapterTestAddr)
+	assert.True(t, errors.Is(err, ErrProviderSyncing), "got %v", err)
+	assert.True(t, errors.Is(err, connector.ErrProviderInternal), "got %v", err)
+	assert.Equal(t, 0, kupo.Requests("/v1/matches/"+adapterTestAddr))
+
+	_, err = kp.GetUtxosByPolicy(context.Background(), outRefPolicy)
+	assert.True(t, errors.Is(err, ErrProviderSyncing), "got %v", err)
+	assert.Equal(t, 1, kupo.Requests("/health"))
+}
+
+func TestKupoSyncGuardInSync(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{KupoSyncGuardSlots: 100})
+	serveHealth(ogmios, kupo, 0, 5000, 4990)
+
+	for range 3 {
+		utxos, err := kp.GetUtxosByAddress(context.Background(), adapterTestAddr)
+		assert.NoError(t, err)
+		assert.Empty(t, utxos)
+	}
+	assert.Equal(t, 3, kupo.Requests("/v1/matches/"+adapterTestAddr))
+	assert.Equal(t, 1, kupo.Requests("/health"))
+}
+
+func TestKupoSyncGuardDisabled(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{})
+	serveHealth(ogmios, kupo, 0, 5000, 1000)
+
+	_, err := kp.GetUtxosByAddress(context.Background(), adapterTestAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, kupo.Requests("/health"))
+}
@@ -0,0 +1,96 @@
+package kupmios
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// Components reported to Instrumentation.
+const (
+	ComponentOgmios = "ogmios"
+	ComponentKupo   = "kupo"
+)
+
+// Instrumentation observes the requests the provider sends to Ogmios and
+// Kupo, e.g. to export their latency and error rate as metrics.
+//
+// component is ComponentOgmios or ComponentKupo. operation is the Ogmios
+// method, such as "queryNetwork/tip" or "submitTransaction", or the Kupo
+// resource, such as "matches", "datums", "scripts" or "checkpoints". Every
+// attempt of a retried read is reported on its own. Block streams and the
+// probes of HealthCheck are not reported.
+//
+// The callbacks are invoked on the calling goroutine, concurrently for
+// concurrent requests, and should return quickly.
+type Instrumentation interface {
+	OnRequestStart(ctx context.Context, component, operation string)
+	OnRequestEnd(
+		ctx context.Context,
+		component, operation string,
+		duration time.Duration,
+		err error,
+	)
+}
+
+// observe reports the start of a request to inst and returns the function
+// that reports its end. Without inst nothing is reported.
+func observe(
+	ctx context.Context,
+	inst Instrumentation,
+	component, operation string,
+) func(error) {
+	if inst == nil {
+		return ignoreEnd
+	}
+	inst.OnRequestStart(ctx, component, operation)
+	start := time.Now()
+	return func(err error) {
+		inst.OnRequestEnd(ctx, component, operation, time.Since(start), err)
+	}
+}
+
+func ignoreEnd(error) {}
+
+// instrumented calls send, reporting it to inst when inst is not nil.
+func instrumented[T any](
+	ctx context.Context,
+	inst Instrumentation,
+	component, operation string,
+	send func() (T, error),
+) (T, error) {
+	if inst == nil {
+		return send()
+	}
+	end := observe(ctx, inst, component, operation)
+	result, err := send()
+	end(err)
+	return result, err
+}
+
+// ogmiosRead sends the Ogmios query operation through read, reporting every
+// attempt to Config.Instrumentation and retrying transient failures
+// according to Config.Retry.
+func ogmiosRead[T any](
+	ctx context.Context,
+	kp *KupmiosProvider,
+	operation string,
+	read func(context.Context) (T, error),
+) (T, error) {
+	return connector.Retry(ctx, kp.retry, retryable, func() (T, error) {
+		return instrumented(ctx, kp.instrumentation, ComponentOgmios, operation, func() (T, error) {
+			return read(ctx)
+		})
+	})
+}
+
+// kupoOperation names the Kupo resource path belongs to, e.g. "datums" for
+// /v1/datums/{hash}.
+func kupoOperation(path string) string {
+	operation := strings.TrimPrefix(strings.TrimPrefix(path, "/"), "v1/")
+	operation, _, _ = strings.Cut(operation, "/")
+	operation, _, _ = strings.Cut(operation, "?")
+	return operation
+}
@@ -0,0 +1,107 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+)
+
+// recordedRequest is a request reported to recordingInstrumentation.
+type recordedRequest struct {
+	component string
+	operation string
+	failed    bool
+}
+
+// recordingInstrumentation records the requests whose end it is told about
+// and counts the starts.
+type recordingInstrumentation struct {
+	mu       sync.Mutex
+	starts   int
+	requests []recordedRequest
+}
+
+func (r *recordingInstrumentation) OnRequestStart(_ context.Context, _, _ string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.starts++
+}
+
+func (r *recordingInstrumentation) OnRequestEnd(
+	_ context.Context,
+	component, operation string,
+	duration time.Duration,
+	err error,
+) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if duration < 0 {
+		panic("negative duration")
+	}
+	r.requests = append(r.requests, recordedRequest{component, operation, err != nil})
+}
+
+func (r *recordingInstrumentation) recorded() (int, []recordedRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.starts, r.requests
+}
+
+func TestInstrumentationKupo(t *testing.T) {
+	inst := &recordingInstrumentation{}
+	kp, _, kupo := newMockKupmios(t, Config{Instrumentation: inst})
+	kupo.route("/v1/matches/"+adapterTestAddr, kupoMatchJSON(outRefLive))
+
+	_, err := kp.GetUtxosByAddress(context.Background(), adapterTestAddr)
+	assert.NoError(t, err)
+	starts, requests := inst.recorded()
+	assert.Equal(t, 1, starts)
+	assert.Equal(t, []recordedRequest{{ComponentKupo, "matches", false}}, requests)
+}
+
+func TestInstrumentationOgmios(t *testing.T) {
+	inst := &recordingInstrumentation{}
+	kp, ogmios, _ := newMockKupmios(t, Config{Instrumentation: inst})
+	ogmios.handle("submitTransaction", func(json.RawMessage) any {
+		return map[string]any{"transaction": map[string]any{"id": strings.Repeat("0f", 32)}}
+	})
+
+	_, err := kp.SubmitTx(context.Background(), []byte{0x84})
+	assert.NoError(t, err)
+	starts, requests := inst.recorded()
+	assert.Equal(t, 1, starts)
+	assert.Equal(t, []recordedRequest{{ComponentOgmios, "submitTransaction", false}}, requests)
+}
+
+func TestInstrumentationReportsEachAttempt(t *testing.T) {
+	inst := &recordingInstrumentation{}
+	kp, _, kupo := newMockKupmios(t, Config{Instrumentation: inst, Retry: fastRetry})
+	path := retryDatumPath + retryDatumHash
+	kupo.route(path, `{"datum": "d87980"}`)
+	kupo.flaky(path, http.StatusServiceUnavailable)
+
+	_, err := kp.kupo.Datum(context.Background(), retryDatumHash)
+	assert.NoError(t, err)
+	_, requests := inst.recorded()
+	assert.Equal(t, []recordedRequest{
+		{ComponentKupo, "datums", true},
+		{ComponentKupo, "datums", false},
+	}, requests)
+}
+
+func TestKupoOperation(t *testing.T) {
+	for path, want := range map[string]string{
+		"/v1/datums/" + retryDatumHash: "datums",
+		"/v1/matches/addr?unspent":     "matches",
+		"/v1/checkpoints":              "checkpoints",
+		"/health":                      "health",
+	} {
+		assert.Equal(t, want, kupoOperation(path), path)
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math"
+	"math/big"
+	"net/url"
 	"strings"
 	"time"
 
@@ -18,30 +21,259 @@ import (
 	"github.com/blinklabs-io/gouroboros/ledger/common"
 	"github.com/gorilla/websocket"
 	connector "github.com/zenGate-Global/cardano-connector-go"
+	"golang.org/x/sync/errgroup"
 )
 
-var _ connector.Provider = (*KupmiosProvider)(nil)
+var (
+	_ connector.Provider           = (*KupmiosProvider)(nil)
+	_ connector.ProvenanceProvider = (*KupmiosProvider)(nil)
+)
+
+// defaultMaxConcurrentRequests bounds the Kupo requests a single call issues
+// in parallel when Config.MaxConcurrentRequests is zero.
+const defaultMaxConcurrentRequests = 8
 
 func New(config Config) (*KupmiosProvider, error) {
-	ogmiosClient := ogmigo.New(
-		ogmigo.WithEndpoint(config.OgmigoEndpoint),
-	)
-	kugoClient := kugo.New(
-		kugo.WithEndpoint(config.KupoEndpoint),
-	)
+	if config.OgmigoEndpoint == "" {
+		return nil, fmt.Errorf(
+			"%w: OgmigoEndpoint must be set",
+			connector.ErrInvalidInput,
+		)
+	}
+	if config.KupoEndpoint == "" {
+		return nil, fmt.Errorf(
+			"%w: KupoEndpoint must be set",
+			connector.ErrInvalidInput,
+		)
+	}
+	if err := checkEndpoint("OgmigoEndpoint", config.OgmigoEndpoint); err != nil {
+		return nil, err
+	}
+	if err := checkEndpoint("KupoEndpoint", config.KupoEndpoint); err != nil {
+		return nil, err
+	}
 
-	return &KupmiosProvider{
-		ogmigoClient:   ogmiosClient,
-		kugoClient:     kugoClient,
-		ogmiosEndpoint: config.OgmigoEndpoint,
-		networkId:      config.NetworkId,
-	}, nil
+	ogmiosClient := ogmigo.New(append(
+		[]ogmigo.Option{ogmigo.WithEndpoint(config.OgmigoEndpoint)},
+		config.OgmigoOptions...,
+	)...)
+	kugoClient := kugo.New(append(
+		[]kugo.Option{kugo.WithEndpoint(config.KupoEndpoint)},
+		config.KugoOptions...,
+	)...)
+
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	maxConcurrentRequests := config.MaxConcurrentRequests
+	if maxConcurrentRequests < 0 {
+		return nil, fmt.Errorf(
+			"%w: MaxConcurrentRequests must not be negative, got %d",
+			connector.ErrInvalidInput,
+			maxConcurrentRequests,
+		)
+	}
+	if maxConcurrentRequests == 0 {
+		maxConcurrentRequests = defaultMaxConcurrentRequests
+	}
+	chainCacheSize := config.ChainCacheSize
+	if chainCacheSize < 0 {
+		return nil, fmt.Errorf(
+			"%w: ChainCacheSize must not be negative, got %d",
+			connector.ErrInvalidInput,
+			chainCacheSize,
+		)
+	}
+	if chainCacheSize == 0 {
+		chainCacheSize = defaultChainCacheSize
+	}
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = newKupoClient(config.TLSConfig)
+	}
+	retry, err := config.Retry.WithDefaults()
+	if err != nil {
+		return nil, err
+	}
+	kupoHeaders := httpHeaders(config.KupoHeaders)
+	kupo := &kupoFetcher{
+		endpoint: config.KupoEndpoint,
+		client:   httpClient,
+		headers:  kupoHeaders,
+		retry:    retry,
+		inst:     config.Instrumentation,
+	}
+	var fetcher chainFetcher = kupo
+	if !config.DisableChainCache {
+		fetcher = newCachingFetcher(fetcher, chainCacheSize)
+	}
+
+	if config.ConfirmationSlots < 0 {
+		return nil, fmt.Errorf(
+			"%w: ConfirmationSlots must not be negative, got %d",
+			connector.ErrInvalidInput,
+			config.ConfirmationSlots,
+		)
+	}
+
+	maxKupoLag := config.MaxKupoLag
+	if maxKupoLag < 0 {
+		return nil, fmt.Errorf(
+			"%w: MaxKupoLag must not be negative, got %d",
+			connector.ErrInvalidInput,
+			maxKupoLag,
+		)
+	}
+	if maxKupoLag == 0 {
+		maxKupoLag = defaultMaxKupoLag
+	}
+	if config.KupoChunkSlots < 0 {
+		return nil, fmt.Errorf(
+			"%w: KupoChunkSlots must not be negative, got %d",
+			connector.ErrInvalidInput,
+			config.KupoChunkSlots,
+		)
+	}
+	if config.KupoSyncGuardSlots < 0 {
+		return nil, fmt.Errorf(
+			"%w: KupoSyncGuardSlots must not be negative, got %d",
+			connector.ErrInvalidInput,
+			config.KupoSyncGuardSlots,
+		)
+	}
+
+	kp := &KupmiosProvider{
+		ogmigoClient:          ogmiosClient,
+		kugoClient:            kugoClient,
+		httpClient:            httpClient,
+		ogmiosDialer:          newOgmiosDialer(config.TLSConfig),
+		ogmiosHeaders:         httpHeaders(config.OgmiosHeaders),
+		kupoHeaders:           kupoHeaders,
+		fetcher:               fetcher,
+		kupo:                  kupo,
+		ogmiosEndpoint:        config.OgmigoEndpoint,
+		kupoEndpoint:          config.KupoEndpoint,
+		networkId:             config.NetworkId,
+		logger:                logger,
+		strictScripts:         config.StrictScriptRefs,
+		strictDatums:          config.StrictDatums,
+		lenientOutRefs:        config.LenientOutRefs,
+		lenientQuantities:     config.LenientQuantities,
+		maxConcurrentRequests: maxConcurrentRequests,
+		confirmationSlots:     uint64(config.ConfirmationSlots),
+		awaitTxOgmiosFallback: config.AwaitTxOgmiosFallback,
+		awaitTxMempool:        config.AwaitTxMempool,
+		awaitTxRecheck:        config.AwaitTxRecheck,
+		maxKupoLag:            uint64(maxKupoLag),
+		kupoSyncGuardSlots:    uint64(config.KupoSyncGuardSlots),
+		skipNetworkCheck:      config.SkipNetworkCheck,
+		kupoChunkSlots:        uint64(config.KupoChunkSlots),
+		retry:                 retry,
+		instrumentation:       config.Instrumentation,
+		done:                  make(chan struct{}),
+	}
+
+	if config.ValidateOnNew {
+		ctx, cancel := context.WithTimeout(context.Background(), validateTimeout)
+		defer cancel()
+		if err := kp.checkConnectivity(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return kp, nil
+}
+
+// checkEndpoint fails with ErrInvalidInput unless endpoint is an absolute
+// http, https, ws or wss URL.
+func checkEndpoint(name, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf(
+			"%w: %s %q is not a URL: %w",
+			connector.ErrInvalidInput,
+			name,
+			endpoint,
+			err,
+		)
+	}
+	switch u.Scheme {
+	case "http", "https", "ws", "wss":
+	default:
+		return fmt.Errorf(
+			"%w: %s %q must use http, https, ws or wss",
+			connector.ErrInvalidInput,
+			name,
+			u.Redacted(),
+		)
+	}
+	if u.Host == "" {
+		return fmt.Errorf(
+			"%w: %s %q has no host",
+			connector.ErrInvalidInput,
+			name,
+			u.Redacted(),
+		)
+	}
+	return nil
+}
+
+// scriptRefError handles a reference script that could not be resolved during
+// UTxO hydration: an error when Config.StrictScriptRefs is set, otherwise a
+// logged warning that leaves the script unresolved.
+func (kp *KupmiosProvider) scriptRefError(
+	txHash string,
+	outputIndex int,
+	scriptHash string,
+	err error,
+) error {
+	if kp.strictScripts {
+		return fmt.Errorf(
+			"%w: unresolved reference script for %s#%d: %w",
+			connector.ErrProviderInternal,
+			txHash,
+			outputIndex,
+			err,
+		)
+	}
+	kp.logger.Warn("kupmios: leaving reference script unresolved during hydration",
+		"tx_hash", txHash,
+		"output_index", outputIndex,
+		"script_hash", scriptHash,
+		"err", err)
+	return nil
+}
+
+// quantityOverflow handles a lovelace quantity beyond the int64 range: an
+// error unless Config.LenientQuantities is set, otherwise a logged warning
+// that lets the quantity saturate.
+func (kp *KupmiosProvider) quantityOverflow(
+	txHash string,
+	outputIndex int,
+	qty *big.Int,
+) error {
+	if !kp.lenientQuantities {
+		return fmt.Errorf(
+			"%w: lovelace quantity %s of %s#%d exceeds int64 range",
+			connector.ErrProviderInternal,
+			qty.String(),
+			txHash,
+			outputIndex,
+		)
+	}
+	kp.logger.Warn("kupmios: saturating lovelace quantity beyond int64 range",
+		"tx_hash", txHash,
+		"output_index", outputIndex,
+		"quantity", qty.String())
+	return nil
 }
 
 func (kp *KupmiosProvider) GetProtocolParameters(
 	ctx context.Context,
 ) (backend.ProtocolParameters, error) {
-	raw, err := kp.ogmigoClient.CurrentProtocolParameters(ctx)
+	raw, err := ogmiosRead(ctx, kp, "queryLedgerState/protocolParameters",
+		kp.ogmigoClient.CurrentProtocolParameters)
 	if err != nil {
 		return backend.ProtocolParameters{}, fmt.Errorf(
 			"kupmios: failed to get current protocol parameters from Ogmios: %w",
@@ -63,31 +295,35 @@ func (kp *KupmiosProvider) GetProtocolParameters(
 func (kp *KupmiosProvider) GetGenesisParams(
 	ctx context.Context,
 ) (backend.GenesisParameters, error) {
-	raw, err := kp.ogmigoClient.GenesisConfig(ctx, "shelley")
+	genesis, err := kp.GetGenesisConfig(ctx, "shelley")
 	if err != nil {
-		return backend.GenesisParameters{}, fmt.Errorf(
-			"kupmios: failed to get shelley genesis parameters: %w",
-			err,
-		)
+		return backend.GenesisParameters{}, err
 	}
 
-	var genesis ogmiosGenesisConfig
-	if err := json.Unmarshal(raw, &genesis); err != nil {
-		return backend.GenesisParameters{}, fmt.Errorf(
-			"kupmios: failed to parse shelley genesis parameters: %w",
-			err,
-		)
+	params, err := genesis.Shelley.toGenesisParams()
+	if err != nil {
+		return backend.GenesisParameters{}, err
 	}
 
-	return genesis.toGenesisParams()
+	// The shelley genesis start time is only the chain's start on networks
+	// that began in Shelley; Ogmios reports the actual system start.
+	start, err := kp.SystemStart(ctx)
+	if err != nil {
+		return backend.GenesisParameters{}, err
+	}
+	params.SystemStart = start.Unix()
+	return params, nil
 }
 
+// Network returns Config.NetworkId, the address header network id: 1 for
+// mainnet and 0 for the test networks.
 func (kp *KupmiosProvider) Network() int {
 	return kp.networkId
 }
 
 func (kp *KupmiosProvider) Epoch(ctx context.Context) (int, error) {
-	ogmigoEpoch, err := kp.ogmigoClient.CurrentEpoch(ctx)
+	ogmigoEpoch, err := ogmiosRead(ctx, kp, "queryLedgerState/epoch",
+		kp.ogmigoClient.CurrentEpoch)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get current epoch: %w", err)
 	}
@@ -99,7 +335,7 @@ func (kp *KupmiosProvider) Epoch(ctx context.Context) (int, error) {
 }
 
 func (kp *KupmiosProvider) GetTip(ctx context.Context) (connector.Tip, error) {
-	point, err := kp.ogmigoClient.ChainTip(ctx)
+	point, err := ogmiosRead(ctx, kp, "queryNetwork/tip", kp.ogmigoClient.ChainTip)
 	if err != nil {
 		return connector.Tip{}, fmt.Errorf(
 			"kupmios: failed to get tip: %w",
@@ -137,6 +373,25 @@ func (kp *KupmiosProvider) GetUtxosByAddress(
 	ctx context.Context,
 	addr string,
 ) ([]common.Utxo, error) {
+	extended, err := kp.GetUtxosByAddressExtended(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	utxos := make([]common.Utxo, len(extended))
+	for i, utxo := range extended {
+		utxos[i] = utxo.Utxo
+	}
+	return utxos, nil
+}
+
+// GetUtxosByAddressExtended returns the UTxOs at addr with the slot and header
+// hash of the block that created each, and the slot that spent it for the
+// spent outputs included via WithSpentMatches.
+func (kp *KupmiosProvider) GetUtxosByAddressExtended(
+	ctx context.Context,
+	addr string,
+) ([]connector.UtxoWithProvenance, error) {
 	address, err := common.NewAddress(addr)
 	if err != nil {
 		return nil, fmt.Errorf(
@@ -147,39 +402,80 @@ func (kp *KupmiosProvider) GetUtxosByAddress(
 		)
 	}
 
-	matches, err := kp.kugoClient.Matches(
+	if err := kp.checkKupoSynced(ctx); err != nil {
+		return nil, err
+	}
+
+	utxos := []connector.UtxoWithProvenance{}
+	err = kp.kupoMatches(
 		ctx,
-		kugo.OnlyUnspent(),
+		"address UTxOs at "+addr,
+		addr,
 		kugo.Address(addr),
+		func(matches []kugo.Match) error {
+			adapted, err := kp.adaptMatches(ctx, matches, address)
+			if err != nil {
+				return err
+			}
+			for i, match := range matches {
+				utxos = append(utxos, connector.UtxoWithProvenance{
+					Utxo:                adapted[i],
+					CreatedAtSlot:       uint64(match.CreatedAt.SlotNo),
+					CreatedAtHeaderHash: match.CreatedAt.HeaderHash,
+					SpentAtSlot:         uint64(match.SpentAt.SlotNo),
+				})
+			}
+			return nil
+		},
 	)
 	if err != nil {
-		return nil, fmt.Errorf(
-			"kupmios: Kupo request for address UTxOs failed for %s: %w",
-			addr,
-			err,
-		)
-	}
-
-	if len(matches) == 0 {
-		return []common.Utxo{}, nil
+		return nil, err
 	}
+	return utxos, nil
+}
 
-	utxos := make([]common.Utxo, 0, len(matches))
-	for _, match := range matches {
-		utxo, err := matchToUtxo(ctx, match, address, kp.kugoClient)
-		if err != nil {
-			return nil, fmt.Errorf(
-				"kupmios: failed to adapt kupo match %s#%d: %w",
-				match.TransactionID,
-				match.OutputIndex,
-				err,
+// adaptMatches converts Kupo matches at address into UTxOs, with up to
+// Config.MaxConcurrentRequests conversions, and so datum and script lookups,
+// in flight. The result keeps the order of matches; the first conversion to
+// fail cancels the rest.
+func (kp *KupmiosProvider) adaptMatches(
+	ctx context.Context,
+	matches []kugo.Match,
+	address common.Address,
+) ([]common.Utxo, error) {
+	utxos := make([]common.Utxo, len(matches))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(kp.maxConcurrentRequests)
+	for i, match := range matches {
+		g.Go(func() error {
+			utxo, err := matchToUtxo(
+				gctx,
+				match,
+				address,
+				kp.fetcher,
+				kp.scriptRefError,
+				kp.quantityOverflow,
 			)
-		}
-		utxos = append(utxos, utxo)
+			if err != nil {
+				return fmt.Errorf(
+					"kupmios: failed to adapt kupo match %s#%d: %w",
+					match.TransactionID,
+					match.OutputIndex,
+					err,
+				)
+			}
+			utxos[i] = utxo
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
 	return utxos, nil
 }
 
+// GetUtxosWithUnit returns the UTxOs at address that hold some of unit. Every
+// output carries ADA, so for "lovelace" that is every UTxO at the address.
 func (kp *KupmiosProvider) GetUtxosWithUnit(
 	ctx context.Context,
 	address string,
@@ -192,19 +488,14 @@ func (kp *KupmiosProvider) GetUtxosWithUnit(
 		)
 	}
 
-	utxos, err := kp.GetUtxosByAddress(ctx, address)
+	matcher, err := newUnitMatcher(unit)
 	if err != nil {
 		return nil, err
 	}
 
-	matcher, err := newUnitMatcher(unit)
+	utxos, err := kp.GetUtxosByAddress(ctx, address)
 	if err != nil {
-		return nil, fmt.Errorf(
-			"%w: invalid unit format %q: %s",
-			connector.ErrInvalidInput,
-			unit,
-			err,
-		)
+		return nil, err
 	}
 
 	result := make([]common.Utxo, 0, len(utxos))
@@ -217,22 +508,30 @@ func (kp *KupmiosProvider) GetUtxosWithUnit(
 	return result, nil
 }
 
+// GetUtxoByUnit returns the single unspent output, at any address, that holds
+// unit. "lovelace" is rejected with connector.ErrInvalidUnit: ADA is held by
+// every output, so it never identifies one.
 func (kp *KupmiosProvider) GetUtxoByUnit(
 	ctx context.Context,
 	unit string,
 ) (*common.Utxo, error) {
 	matcher, err := newUnitMatcher(unit)
 	if err != nil {
+		return nil, err
+	}
+	if matcher.lovelace {
 		return nil, fmt.Errorf(
-			"%w: invalid unit format %q: %s",
-			connector.ErrInvalidInput,
-			unit,
-			err,
+			"%w: lovelace does not identify a single UTxO",
+			connector.ErrInvalidUnit,
 		)
 	}
 
+	if err := kp.checkKupoSynced(ctx); err != nil {
+		return nil, err
+	}
+
 	// Kupo can index matches by asset across all addresses.
-	matches, err := kp.kugoClient.Matches(ctx,
+	matches, err := kp.matches(ctx,
 		kugo.OnlyUnspent(),
 		kugo.AssetID(shared.AssetID(matcher.kugoAssetID)),
 	)
@@ -256,7 +555,14 @@ func (kp *KupmiosProvider) GetUtxoByUnit(
 				err,
 			)
 		}
-		utxo, err := matchToUtxo(ctx, match, address, kp.kugoClient)
+		utxo, err := matchToUtxo(
+			ctx,
+			match,
+			address,
+			kp.fetcher,
+			kp.scriptRefError,
+			kp.quantityOverflow,
+		)
 		if err != nil {
 			return nil, fmt.Errorf(
 				"kupmios: failed to adapt Kupo match for unit %s (tx: %s#%d): %w",
@@ -271,6 +577,8 @@ func (kp *KupmiosProvider) GetUtxoByUnit(
 		}
 	}
 
+	// Uniqueness is about holdings, not quantity: a fungible token whose whole
+	// supply sits in one output is returned with its full quantity.
 	if len(found) == 0 {
 		return nil, fmt.Errorf(
 			"%w: no UTxO found for unit %s",
@@ -290,6 +598,83 @@ func (kp *KupmiosProvider) GetUtxoByUnit(
 	return &found[0], nil
 }
 
+// GetUtxosByPolicy returns the unspent outputs, at any address, that hold at
+// least one asset of the given policy. See WithSpentMatches to include spent
+// outputs.
+func (kp *KupmiosProvider) GetUtxosByPolicy(
+	ctx context.Context,
+	policyId string,
+) ([]common.Utxo, error) {
+	policyBytes, err := hex.DecodeString(policyId)
+	if err != nil || len(policyBytes) != common.Blake2b224Size {
+		return nil, fmt.Errorf(
+			"%w: policy id must be %d hex characters, got %q",
+			connector.ErrInvalidInput,
+			2*common.Blake2b224Size,
+			policyId,
+		)
+	}
+	policyId = strings.ToLower(policyId)
+
+	if err := kp.checkKupoSynced(ctx); err != nil {
+		return nil, err
+	}
+
+	utxos := []common.Utxo{}
+	err = kp.kupoMatches(
+		ctx,
+		"UTxOs by policy "+policyId,
+		policyId+".*",
+		kugo.PolicyID(policyId),
+		func(matches []kugo.Match) error {
+			for _, match := range matches {
+				address, err := common.NewAddress(match.Address)
+				if err != nil {
+					return fmt.Errorf(
+						"kupmios: invalid address %q in match %s#%d: %w",
+						match.Address,
+						match.TransactionID,
+						match.OutputIndex,
+						err,
+					)
+				}
+				utxo, err := matchToUtxo(
+					ctx,
+					match,
+					address,
+					kp.fetcher,
+					kp.scriptRefError,
+					kp.quantityOverflow,
+				)
+				if err != nil {
+					return fmt.Errorf(
+						"kupmios: failed to adapt Kupo match for policy %s (tx: %s#%d): %w",
+						policyId,
+						match.TransactionID,
+						match.OutputIndex,
+						err,
+					)
+				}
+				utxos = append(utxos, utxo)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return utxos, nil
+}
+
+// GetUtxosByOutRef resolves out-refs from the Ogmios ledger state in a single
+// UtxosByTxIn round trip. Refs Ogmios cannot serve, such as outputs that have
+// already been spent, are looked up in Kupo's index instead. Refs found in
+// neither are omitted; duplicates are returned once, in request order.
+//
+// A ref whose Kupo lookup fails is not silently dropped: the UTxOs that did
+// resolve are returned together with the per-ref errors joined by errors.Join.
+// With Config.LenientOutRefs the failures are logged and skipped instead, as
+// are UTxOs in the Ogmios response that cannot be decoded.
 func (kp *KupmiosProvider) GetUtxosByOutRef(
 	ctx context.Context,
 	outRefs []connector.OutRef,
@@ -297,69 +682,183 @@ func (kp *KupmiosProvider) GetUtxosByOutRef(
 	if len(outRefs) == 0 {
 		return []common.Utxo{}, nil
 	}
+	// Callers resolving specific outputs need their datums and scripts.
+	ctx = context.WithValue(ctx, skipResolutionKey{}, false)
 
-	results := make([]common.Utxo, 0, len(outRefs))
-	seen := make(map[string]bool, len(outRefs))
-
+	refs := make([]connector.OutRef, 0, len(outRefs))
+	queries := make([]chainsync.TxInQuery, 0, len(outRefs))
+	seen := make(map[connector.OutRef]bool, len(outRefs))
 	for _, ref := range outRefs {
-		key := fmt.Sprintf("%s#%d", ref.TxHash, ref.Index)
-		if seen[key] {
+		if seen[ref] {
 			continue
 		}
-		seen[key] = true
-
-		query := chainsync.TxInQuery{
+		seen[ref] = true
+		refs = append(refs, ref)
+		queries = append(queries, chainsync.TxInQuery{
 			Transaction: shared.UtxoTxID{ID: ref.TxHash},
 			Index:       ref.Index,
+		})
+	}
+
+	raws, err := kp.GetOgmiosUtxo(ctx, queries)
+	if err != nil {
+		return nil, err
+	}
+	byRef := make(map[connector.OutRef]shared.Utxo, len(raws))
+	for _, raw := range raws {
+		byRef[connector.OutRef{TxHash: raw.Transaction.ID, Index: raw.Index}] = raw
+	}
+
+	// Look up the refs Ogmios could not serve in Kupo concurrently. Outcomes
+	// are stored by position so the result keeps the request order.
+	fallbacks := make([]*common.Utxo, len(refs))
+	fallbackErrs := make([]error, len(refs))
+	var g errgroup.Group
+	g.SetLimit(kp.maxConcurrentRequests)
+	for i, ref := range refs {
+		if _, ok := byRef[ref]; ok {
+			continue
 		}
-		raws, err := kp.ogmigoClient.UtxosByTxIn(ctx, query)
+		g.Go(func() error {
+			if ctx.Err() != nil {
+				return nil
+			}
+			fallbacks[i], fallbackErrs[i] = kp.kupoUtxoByOutRef(ctx, ref)
+			return nil
+		})
+	}
+	_ = g.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("kupmios: out-ref resolution aborted: %w", err)
+	}
+
+	results := make([]common.Utxo, 0, len(refs))
+	var refErrs []error
+	for i, ref := range refs {
+		key := fmt.Sprintf("%s#%d", ref.TxHash, ref.Index)
+		raw, ok := byRef[ref]
+		if !ok {
+			utxo, err := fallbacks[i], fallbackErrs[i]
+			switch {
+			case err != nil && kp.lenientOutRefs:
+				kp.logger.Warn("kupmios: skipping out-ref that could not be resolved",
+					"tx_hash", ref.TxHash,
+					"output_index", ref.Index,
+					"err", err)
+			case err != nil:
+				refErrs = append(refErrs, err)
+			case utxo != nil:
+				results = append(results, *utxo)
+			}
+			continue
+		}
+
+		utxo, err := kp.adaptOgmiosUtxo(raw)
 		if err != nil {
+			if kp.lenientOutRefs {
+				kp.logger.Warn("kupmios: skipping out-ref whose Ogmios UTxO could not be decoded",
+					"tx_hash", ref.TxHash,
+					"output_index", ref.Index,
+					"err", err)
+				continue
+			}
 			return nil, fmt.Errorf(
-				"kupmios: Ogmios UtxosByTxIn for %s failed: %w",
+				"kupmios: failed to adapt Ogmios UTxO for OutRef %s: %w",
 				key,
 				err,
 			)
 		}
+		results = append(results, utxo)
+	}
 
-		for _, raw := range raws {
-			if raw.Transaction.ID != ref.TxHash || raw.Index != ref.Index {
-				continue
-			}
-			address, err := common.NewAddress(raw.Address)
-			if err != nil {
-				return nil, fmt.Errorf(
-					"kupmios: invalid address %q for OutRef %s: %w",
-					raw.Address,
-					key,
-					err,
-				)
-			}
-			utxo, err := ogmiosUtxoToCommon(raw, address)
-			if err != nil {
-				return nil, fmt.Errorf(
-					"kupmios: failed to adapt Ogmios UTxO for OutRef %s: %w",
-					key,
-					err,
-				)
-			}
-			results = append(results, utxo)
-		}
+	return connector.OrderByOutRefs(results, outRefs), errors.Join(refErrs...)
+}
+
+// adaptOgmiosUtxo decodes an Ogmios ledger UTxO, address included. A field
+// that does not decode fails with connector.ErrProviderInternal.
+func (kp *KupmiosProvider) adaptOgmiosUtxo(raw shared.Utxo) (common.Utxo, error) {
+	address, err := common.NewAddress(raw.Address)
+	if err != nil {
+		return common.Utxo{}, fmt.Errorf(
+			"%w: invalid address %q: %w",
+			connector.ErrProviderInternal,
+			raw.Address,
+			err,
+		)
+	}
+	return ogmiosUtxoToCommon(
+		raw,
+		address,
+		kp.scriptRefError,
+		kp.quantityOverflow,
+	)
+}
+
+// kupoUtxoByOutRef looks an out-ref up in Kupo's index, spent or not. It
+// returns nil and no error when Kupo has no match for the ref.
+func (kp *KupmiosProvider) kupoUtxoByOutRef(
+	ctx context.Context,
+	ref connector.OutRef,
+) (*common.Utxo, error) {
+	key := fmt.Sprintf("%s#%d", ref.TxHash, ref.Index)
+	matches, err := kp.matches(
+		ctx,
+		kugo.TxOut(chainsync.NewTxID(ref.TxHash, int(ref.Index))),
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"kupmios: Kupo request for OutRef %s failed: %w",
+			key,
+			err,
+		)
 	}
 
-	return results, nil
+	for _, match := range matches {
+		if match.TransactionID != ref.TxHash ||
+			match.OutputIndex != int(ref.Index) {
+			continue
+		}
+		address, err := common.NewAddress(match.Address)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"kupmios: invalid address %q for OutRef %s: %w",
+				match.Address,
+				key,
+				err,
+			)
+		}
+		utxo, err := matchToUtxo(
+			ctx,
+			match,
+			address,
+			kp.fetcher,
+			kp.scriptRefError,
+			kp.quantityOverflow,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"kupmios: failed to adapt Kupo match for OutRef %s: %w",
+				key,
+				err,
+			)
+		}
+		return &utxo, nil
+	}
+	return nil, nil
 }
 
+// GetDelegation returns the delegation and reward balance of the reward
+// account addrStr. Epoch is derived from the certificate of the delegation,
+// which is looked for in the most recent transactions paying an address that
+// delegates to the account, as Kupo indexes them; it is zero when none of
+// them carries it.
 func (kp *KupmiosProvider) GetDelegation(
 	ctx context.Context,
 	addrStr string,
 ) (connector.Delegation, error) {
-	if !strings.HasPrefix(addrStr, "stake1") &&
-		!strings.HasPrefix(addrStr, "stake_test1") {
-		return connector.Delegation{}, fmt.Errorf(
-			"%w: expected a stake address (starting with stake1 or stake_test1), got %s",
-			connector.ErrInvalidAddress,
-			addrStr,
-		)
+	credential, err := kp.rewardCredential(addrStr)
+	if err != nil {
+		return connector.Delegation{}, err
 	}
 
 	summaries, err := kp.queryRewardAccountSummaries(ctx, addrStr)
@@ -383,20 +882,87 @@ func (kp *KupmiosProvider) GetDelegation(
 		delegation.PoolId = summary.Delegate.ID
 	}
 	if summary.Rewards != nil {
-		delegation.Rewards = summary.Rewards.AdaLovelace().Uint64()
+		rewards := summary.Rewards.AdaLovelace().BigInt()
+		if rewards.Sign() < 0 || !rewards.IsUint64() {
+			return connector.Delegation{}, fmt.Errorf(
+				"kupmios: %w: reward balance %s for %s is out of range",
+				connector.ErrProviderInternal,
+				rewards.String(),
+				addrStr,
+			)
+		}
+		delegation.Rewards = rewards.Uint64()
 	}
 	delegation.Active = delegation.PoolId != ""
+	if delegation.Active {
+		epoch, err := kp.delegationEpoch(ctx, addrStr, credential, delegation.PoolId)
+		if err != nil {
+			return connector.Delegation{}, fmt.Errorf(
+				"kupmios: delegation lookup failed for %s: %w",
+				addrStr,
+				err,
+			)
+		}
+		delegation.Epoch = epoch
+	}
 
 	return delegation, nil
 }
 
+// rewardCredential returns the stake credential hash of the Bech32 reward
+// address addrStr. It fails with ErrInvalidAddress unless addrStr is one on
+// the provider's network.
+func (kp *KupmiosProvider) rewardCredential(addrStr string) ([]byte, error) {
+	address, err := common.NewAddress(addrStr)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: %s: %w",
+			connector.ErrInvalidAddress,
+			addrStr,
+			err,
+		)
+	}
+	switch address.Type() {
+	case common.AddressTypeNoneKey, common.AddressTypeNoneScript:
+	default:
+		return nil, fmt.Errorf(
+			"%w: expected a stake address, got %s",
+			connector.ErrInvalidAddress,
+			addrStr,
+		)
+	}
+	raw, err := address.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: %s: %w",
+			connector.ErrInvalidAddress,
+			addrStr,
+			err,
+		)
+	}
+	if networkId := int(raw[0] & 0x0f); networkId != kp.networkId {
+		return nil, fmt.Errorf(
+			"%w: %s is for network id %d, the provider is on %d",
+			connector.ErrInvalidAddress,
+			addrStr,
+			networkId,
+			kp.networkId,
+		)
+	}
+	return raw[1:], nil
+}
+
 // GetOgmiosUtxo queries UTxOs directly via Ogmios by transaction input. It is
 // retained for callers that need the raw ogmigo shared.Utxo wire form.
 func (kp *KupmiosProvider) GetOgmiosUtxo(
 	ctx context.Context,
 	txIns []chainsync.TxInQuery,
 ) ([]shared.Utxo, error) {
-	utxos, err := kp.ogmigoClient.UtxosByTxIn(ctx, txIns...)
+	utxos, err := ogmiosRead(ctx, kp, "queryLedgerState/utxo",
+		func(ctx context.Context) ([]shared.Utxo, error) {
+			return kp.ogmigoClient.UtxosByTxIn(ctx, txIns...)
+		},
+	)
 	if err != nil {
 		return nil, fmt.Errorf("kupmios: Ogmios UtxosByTxIn failed: %w", err)
 	}
@@ -408,9 +974,9 @@ func (kp *KupmiosProvider) GetDatum(
 	ctx context.Context,
 	datumHash string,
 ) (common.Datum, error) {
-	datumCBORHex, err := kp.kugoClient.Datum(ctx, datumHash)
+	datumCBORHex, err := kp.fetcher.Datum(ctx, datumHash)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, connector.ErrNotFound) {
 			return common.Datum{}, fmt.Errorf(
 				"kupmios: datum hash %s not found via Kupo: %w",
 				datumHash,
@@ -435,7 +1001,9 @@ func (kp *KupmiosProvider) GetDatum(
 	datumBytes, err := hex.DecodeString(datumCBORHex)
 	if err != nil {
 		return common.Datum{}, fmt.Errorf(
-			"kupmios: invalid datum CBOR hex from Kupo for %s: %w",
+			"kupmios: %w: invalid datum CBOR hex %q from Kupo for %s: %w",
+			connector.ErrProviderInternal,
+			datumCBORHex,
 			datumHash,
 			err,
 		)
@@ -444,7 +1012,9 @@ func (kp *KupmiosProvider) GetDatum(
 	var datum common.Datum
 	if err := datum.UnmarshalCBOR(datumBytes); err != nil {
 		return common.Datum{}, fmt.Errorf(
-			"kupmios: failed to decode datum CBOR for %s: %w",
+			"kupmios: %w: failed to decode datum CBOR %q for %s: %w",
+			connector.ErrProviderInternal,
+			datumCBORHex,
 			datumHash,
 			err,
 		)
@@ -537,23 +1107,46 @@ func (kp *KupmiosProvider) blockHeight(ctx context.Context) (uint64, error) {
 }
 
 // ogmiosRPC issues a single JSON-RPC request over a short-lived Ogmios
-// websocket connection and decodes the response into out.
+// websocket connection and decodes the response into out. A failed connection
+// is retried on a fresh one according to Config.Retry.
 func (kp *KupmiosProvider) ogmiosRPC(
 	ctx context.Context,
 	method string,
 	params any,
 	out any,
 ) error {
-	conn, _, err := websocket.DefaultDialer.DialContext(
-		ctx,
-		kp.ogmiosEndpoint,
-		nil,
-	)
+	_, err := connector.Retry(ctx, kp.retry, retryable, func() (struct{}, error) {
+		return instrumented(ctx, kp.instrumentation, ComponentOgmios, method, func() (struct{}, error) {
+			return struct{}{}, kp.ogmiosRPCOnce(ctx, method, params, out)
+		})
+	})
+	return err
+}
+
+// ogmiosRPCOnce is a single attempt of ogmiosRPC.
+func (kp *KupmiosProvider) ogmiosRPCOnce(
+	ctx context.Context,
+	method string,
+	params any,
+	out any,
+) error {
+	conn, err := kp.dialOgmios(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to connect to Ogmios: %w", err)
 	}
 	defer conn.Close()
 
+	return ogmiosCall(conn, method, params, out)
+}
+
+// ogmiosCall issues a JSON-RPC request on an open Ogmios websocket connection
+// and decodes the response into out.
+func ogmiosCall(
+	conn *websocket.Conn,
+	method string,
+	params any,
+	out any,
+) error {
 	payload := map[string]any{
 		"jsonrpc": "2.0",
 		"method":  method,
@@ -572,6 +1165,27 @@ func (kp *KupmiosProvider) ogmiosRPC(
 	return nil
 }
 
+// AwaitTx waits for a transaction to be confirmed, i.e. for the Ogmios tip to
+// be Config.ConfirmationSlots slots past the slot Kupo recorded for the
+// transaction's outputs. Kupo is asked again on every poll, so a transaction
+// that is rolled back after appearing is waited for again rather than
+// reported as confirmed.
+//
+// With Config.AwaitTxOgmiosFallback set, a transaction Kupo has no match for,
+// e.g. because none of its outputs match Kupo's patterns, is looked for in the
+// Ogmios ledger state by its first output. Ogmios does not report the slot of
+// an output, so the tip at which the transaction was first seen stands in for
+// it; confirmations are counted from there.
+//
+// With Config.AwaitTxMempool set, each poll first asks the Ogmios mempool
+// whether the transaction is still pending and skips the chain lookups while
+// it is. When the context ends, the error says whether the transaction was
+// last seen pending in the mempool or never seen at all.
+//
+// With Config.AwaitTxRecheck set, a transaction that reaches the confirmation
+// depth is only reported on the next poll, once it has been found again in the
+// same slot. This guards against a rollback between Kupo indexing the block
+// and the depth check; a transaction that vanished is waited for again.
 func (kp *KupmiosProvider) AwaitTx(
 	ctx context.Context,
 	txHash string,
@@ -591,48 +1205,172 @@ func (kp *KupmiosProvider) AwaitTx(
 	ticker := time.NewTicker(checkInterval)
 	defer ticker.Stop()
 
+	// ledgerSeenAt is the tip slot at which the Ogmios fallback first saw the
+	// transaction, or zero while it has not.
+	var ledgerSeenAt uint64
+	// recheckSlot is the slot the transaction was created in when it first
+	// reached the confirmation depth, or zero while it has not.
+	var recheckSlot uint64
+	// status describes where the transaction was last seen, for the error
+	// returned when the context ends.
+	status := "not seen in the mempool or on chain"
 	for {
 		select {
 		case <-ctx.Done():
+			if !kp.awaitTxMempool {
+				return false, fmt.Errorf(
+					"AwaitTx for %s cancelled or timed out: %w",
+					txHash,
+					ctx.Err(),
+				)
+			}
 			return false, fmt.Errorf(
-				"AwaitTx for %s cancelled or timed out: %w",
+				"AwaitTx for %s cancelled or timed out (%s): %w",
 				txHash,
+				status,
 				ctx.Err(),
 			)
 		case <-ticker.C:
-			matches, err := kp.kugoClient.Matches(ctx,
-				kugo.Transaction(txHash),
-			)
-			if err != nil {
+			if kp.awaitTxMempool {
+				pending, err := kp.mempoolHasTx(ctx, txHash)
+				if err != nil {
+					kp.logger.Debug("kupmios: Ogmios mempool lookup failed",
+						"tx_hash", txHash,
+						"err", err)
+				} else if pending {
+					recheckSlot = 0
+					status = "pending in the mempool"
+					continue
+				}
+			}
+
+			createdAt, found := kp.kupoTxSlot(ctx, txHash)
+			if !found && !kp.awaitTxOgmiosFallback {
+				recheckSlot = 0
 				continue
 			}
 
-			if len(matches) > 0 {
-				if matches[0].CreatedAt.SlotNo > 0 {
+			tip, err := kp.tipSlot(ctx)
+			if err != nil {
+				return false, fmt.Errorf(
+					"kupmios: failed to get tip while awaiting %s: %w",
+					txHash,
+					err,
+				)
+			}
+
+			if !found {
+				if !kp.ledgerHasTx(ctx, txHash) {
+					ledgerSeenAt = 0
+					recheckSlot = 0
+					continue
+				}
+				if ledgerSeenAt == 0 {
+					ledgerSeenAt = tip
+				}
+				createdAt = ledgerSeenAt
+			}
+
+			if tip >= createdAt && tip-createdAt >= kp.confirmationSlots {
+				if !kp.awaitTxRecheck || recheckSlot == createdAt {
 					return true, nil
 				}
+				recheckSlot = createdAt
+				status = "on chain, awaiting re-check"
+				continue
 			}
+			recheckSlot = 0
+			status = "on chain, awaiting confirmation"
 		}
 	}
 }
 
+// kupoTxSlot returns the slot in which Kupo saw txHash's outputs created. It
+// reports false when Kupo has no match for the transaction or cannot be
+// reached; AwaitTx simply asks again on its next poll.
+func (kp *KupmiosProvider) kupoTxSlot(
+	ctx context.Context,
+	txHash string,
+) (uint64, bool) {
+	matches, err := instrumented(ctx, kp.instrumentation, ComponentKupo, "matches", func() ([]kugo.Match, error) {
+		return kp.kugoClient.Matches(ctx, kugo.Transaction(txHash))
+	})
+	if err != nil {
+		kp.logger.Debug("kupmios: Kupo transaction lookup failed",
+			"tx_hash", txHash,
+			"err", err)
+		return 0, false
+	}
+	for _, match := range matches {
+		if match.CreatedAt.SlotNo > 0 {
+			return uint64(match.CreatedAt.SlotNo), true
+		}
+	}
+	return 0, false
+}
+
+// ledgerHasTx reports whether the first output of txHash is in the Ogmios
+// ledger state. Every transaction has an output 0, but once it is spent the
+// transaction can no longer be found this way.
+func (kp *KupmiosProvider) ledgerHasTx(
+	ctx context.Context,
+	txHash string,
+) bool {
+	utxos, err := kp.GetOgmiosUtxo(ctx, []chainsync.TxInQuery{{
+		Transaction: shared.UtxoTxID{ID: txHash},
+		Index:       0,
+	}})
+	if err != nil {
+		kp.logger.Debug("kupmios: Ogmios transaction lookup failed",
+			"tx_hash", txHash,
+			"err", err)
+		return false
+	}
+	for _, utxo := range utxos {
+		if utxo.Transaction.ID == txHash {
+			return true
+		}
+	}
+	return false
+}
+
+// tipSlot returns the slot of the Ogmios ledger tip.
+func (kp *KupmiosProvider) tipSlot(ctx context.Context) (uint64, error) {
+	point, err := ogmiosRead(ctx, kp, "queryNetwork/tip", kp.ogmigoClient.ChainTip)
+	if err != nil {
+		return 0, err
+	}
+	ps, ok := point.PointStruct()
+	if !ok || ps == nil {
+		return 0, errors.New("chain tip is origin")
+	}
+	return ps.Slot, nil
+}
+
 func (kp *KupmiosProvider) SubmitTx(
 	ctx context.Context,
 	txBytes []byte,
 ) (string, error) {
+	end := observe(ctx, kp.instrumentation, ComponentOgmios, "submitTransaction")
 	resp, err := kp.ogmigoClient.SubmitTx(
 		ctx,
 		hex.EncodeToString(txBytes),
 	)
+	end(err)
 	if err != nil {
+		// The request never got an answer from the node; this is not a
+		// rejection of the transaction.
 		return "", fmt.Errorf("kupmios: Ogmios tx submission failed: %w", err)
 	}
-	if resp.Error != nil {
+	if resp == nil {
 		return "", fmt.Errorf(
-			"kupmios: Ogmios tx submission failed: %s",
-			resp.Error.Message,
+			"kupmios: %w: empty Ogmios submit response",
+			connector.ErrProviderInternal,
 		)
 	}
+	if resp.Error != nil {
+		return "", fmt.Errorf("kupmios: %w", submitTxError(resp.Error))
+	}
 
 	return resp.ID, nil
 }
@@ -644,21 +1382,22 @@ func (kp *KupmiosProvider) EvaluateTx(
 ) (map[common.RedeemerKey]common.ExUnits, error) {
 	txHex := hex.EncodeToString(txBytes)
 
-	var resp *ogmigo.EvaluateTxResponse
-	var err error
+	var sharedUtxos []shared.Utxo
 	if len(additionalUTxOs) > 0 {
-		sharedUtxos, convErr := commonUtxosToShared(additionalUTxOs)
-		if convErr != nil {
-			return nil, convErr
+		var err error
+		sharedUtxos, err = commonUtxosToShared(additionalUTxOs)
+		if err != nil {
+			return nil, err
 		}
-		resp, err = kp.ogmigoClient.EvaluateTxWithAdditionalUtxos(
-			ctx,
-			txHex,
-			sharedUtxos,
-		)
-	} else {
-		resp, err = kp.ogmigoClient.EvaluateTx(ctx, txHex)
 	}
+	resp, err := instrumented(ctx, kp.instrumentation, ComponentOgmios, "evaluateTransaction",
+		func() (*ogmigo.EvaluateTxResponse, error) {
+			if len(sharedUtxos) > 0 {
+				return kp.ogmigoClient.EvaluateTxWithAdditionalUtxos(ctx, txHex, sharedUtxos)
+			}
+			return kp.ogmigoClient.EvaluateTx(ctx, txHex)
+		},
+	)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"kupmios: Ogmios tx evaluation failed: %w. Raw Ogmios Error: %s",
@@ -667,14 +1406,18 @@ func (kp *KupmiosProvider) EvaluateTx(
 		)
 	}
 
-	return evaluateResponseToExUnits(resp)
+	exUnits, err := evaluateResponseToExUnits(resp)
+	if err != nil {
+		return nil, fmt.Errorf("kupmios: %w", err)
+	}
+	return exUnits, nil
 }
 
 func (kp *KupmiosProvider) GetScriptCborByScriptHash(
 	ctx context.Context,
 	scriptHash string,
 ) (string, error) {
-	script, err := kp.kugoClient.Script(ctx, scriptHash)
+	script, err := kp.fetcher.Script(ctx, scriptHash)
 	if err != nil {
 		return "", fmt.Errorf(
 			"kupmios: Kupo request for script %s failed: %w",
@@ -694,7 +1437,10 @@ func (kp *KupmiosProvider) GetScriptCborByScriptHash(
 }
 
 // unitMatcher filters common.Utxo values by an asset unit. The unit is either
-// "lovelace" or a concatenation of the 56-hex policy ID and the asset name hex.
+// "lovelace" or the 56-hex policy ID followed by the asset name hex, which is
+// at most 64 hex characters and may be empty. Kupo's dotted "policy.name"
+// form is accepted too. Kupo has no pattern for ADA, so kugoAssetID is empty
+// for "lovelace".
 type unitMatcher struct {
 	lovelace    bool
 	policyId    common.Blake2b224
@@ -704,15 +1450,15 @@ type unitMatcher struct {
 
 func newUnitMatcher(unit string) (unitMatcher, error) {
 	if unit == "lovelace" {
-		return unitMatcher{lovelace: true, kugoAssetID: "lovelace"}, nil
+		return unitMatcher{lovelace: true}, nil
 	}
 
-	policyId, assetName, err := backend.ParseAssetUnit(unit)
+	policyId, assetName, err := connector.ParseUnit(unit)
 	if err != nil {
 		return unitMatcher{}, err
 	}
 
-	nameHex := hex.EncodeToString(assetName.Bytes())
+	nameHex := hex.EncodeToString(assetName)
 	kugoAssetID := hex.EncodeToString(policyId.Bytes())
 	if nameHex != "" {
 		kugoAssetID = kugoAssetID + "." + nameHex
@@ -720,7 +1466,7 @@ func newUnitMatcher(unit string) (unitMatcher, error) {
 
 	return unitMatcher{
 		policyId:    policyId,
-		assetName:   assetName.Bytes(),
+		assetName:   assetName,
 		kugoAssetID: kugoAssetID,
 	}, nil
 }
@@ -59,6 +59,9 @@ func TestGetProtocolParameters(t *testing.T) {
 	if pp.MaxTxSize == 0 {
 		t.Error("Expected non-zero MaxTxSize")
 	}
+	if diff := tests.CostModelKeysDiff(pp.CostModels); diff != "" {
+		t.Error(diff)
+	}
 }
 
 func TestGetGenesisParams(t *testing.T) {
@@ -102,6 +105,49 @@ func TestGetGenesisParams(t *testing.T) {
 	assert.Equal(t, 2160, gp.SecurityParam, "SecurityParam should be 2160")
 }
 
+func TestGetGenesisConfigConway(t *testing.T) {
+	kupmios := setupKupmios(t)
+	ctx := context.Background()
+
+	config, err := kupmios.GetGenesisConfig(ctx, "conway")
+	if err != nil {
+		t.Fatalf("GetGenesisConfig failed: %v", err)
+	}
+
+	conway := config.Conway
+	assert.NotEmpty(t, conway.ConstitutionalCommittee.Quorum)
+	assert.True(t, conway.UpdatableParameters.GovernanceActionLifetime > 0)
+	assert.True(t, conway.UpdatableParameters.GovernanceActionDeposit.Ada.Lovelace > 0)
+	assert.NotEmpty(t, conway.UpdatableParameters.PlutusCostModels["plutus:v3"])
+}
+
+func TestGetEraSummariesPreprod(t *testing.T) {
+	kupmios := setupKupmios(t)
+	ctx := context.Background()
+
+	eras, err := kupmios.GetEraSummaries(ctx)
+	if err != nil {
+		t.Fatalf("GetEraSummaries failed: %v", err)
+	}
+
+	// Preprod's Byron era ends, and Shelley starts, at slot 86400.
+	var shelley *EraSummary
+	for i := range eras {
+		if eras[i].Start.Slot == 86400 {
+			shelley = &eras[i]
+		}
+	}
+	if shelley == nil {
+		t.Fatalf("no era starts at slot 86400: %+v", eras)
+	}
+	assert.Equal(t, time.Second, shelley.SlotLength, "Shelley slot length should be 1s")
+	assert.Equal(t, 20*time.Second, eras[0].SlotLength, "Byron slot length should be 20s")
+
+	shelleyStart, err := kupmios.SlotToTime(ctx, 86400)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1655769600), shelleyStart.Unix())
+}
+
 func TestNetwork(t *testing.T) {
 	kupmios := setupKupmios(t)
 	assert.Equal(
@@ -148,6 +194,34 @@ func TestGetTip(t *testing.T) {
 	assert.True(t, len(tip.Hash) == 64, "Hash should be 64 characters long")
 }
 
+func TestStreamBlocks(t *testing.T) {
+	kupmios := setupKupmios(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	events, err := kupmios.StreamBlocks(ctx, connector.ChainPoint{})
+	if err != nil {
+		t.Fatalf("StreamBlocks failed: %v", err)
+	}
+
+	var forwards []connector.BlockEvent
+	for event := range events {
+		t.Logf("Event: %s %+v (%d txs)", event.Type, event.Point, len(event.TxHashes))
+		if event.Type == connector.BlockEventRollForward {
+			forwards = append(forwards, event)
+		}
+		if len(forwards) == 2 {
+			break
+		}
+	}
+	if len(forwards) < 2 {
+		t.Fatalf("stream ended after %d blocks: %v", len(forwards), ctx.Err())
+	}
+	assert.True(t, forwards[1].Point.Slot > forwards[0].Point.Slot)
+	assert.Equal(t, forwards[0].Height+1, forwards[1].Height)
+	assert.NoError(t, kupmios.Close())
+}
+
 func TestGetUtxos(t *testing.T) {
 	kupmios := setupKupmios(t)
 	ctx := context.Background()
@@ -211,6 +285,73 @@ func TestGetUtxoByUnit(t *testing.T) {
 	}
 }
 
+func TestGetUtxosByPolicy(t *testing.T) {
+	kupmios := setupKupmios(t)
+	ctx := context.Background()
+
+	utxos, err := kupmios.GetUtxosByPolicy(
+		ctx,
+		"4a83e031d4c37fc7ca6177a2f3581a8eec2ce155da91f59cfdb3bb28",
+	)
+	if err != nil {
+		t.Fatalf("GetUtxosByPolicy failed: %v", err)
+	}
+
+	t.Logf("Found %d UTxOs under the discovery policy", len(utxos))
+
+	found := false
+	for _, utxo := range utxos {
+		if tests.UtxosEqual(utxo, tests.ApolloDiscoveryUTxO) {
+			found = true
+		}
+	}
+	assert.True(t, found, "discovery UTxO should be among the policy's UTxOs")
+}
+
+func TestGetUtxosByAddressExtended(t *testing.T) {
+	kupmios := setupKupmios(t)
+	ctx := context.Background()
+
+	utxos, err := kupmios.GetUtxosByAddressExtended(
+		ctx,
+		"addr_test1wpgexmeunzsykesf42d4eqet5yvzeap6trjnflxqtkcf66g0kpnxt",
+	)
+	if err != nil {
+		t.Fatalf("GetUtxosByAddressExtended failed: %v", err)
+	}
+
+	found := false
+	for _, utxo := range utxos {
+		if !tests.UtxosEqual(utxo.Utxo, tests.ApolloDiscoveryUTxO) {
+			continue
+		}
+		found = true
+		assert.True(t, utxo.CreatedAtSlot > 0, "discovery UTxO should carry its creation slot")
+		assert.Len(t, utxo.CreatedAtHeaderHash, 64)
+		assert.Equal(t, uint64(0), utxo.SpentAtSlot)
+	}
+	assert.True(t, found, "discovery UTxO should be among the address's UTxOs")
+}
+
+func TestGetSpentUtxosByAddress(t *testing.T) {
+	kupmios := setupKupmios(t)
+	ctx := context.Background()
+
+	spent, err := kupmios.GetSpentUtxosByAddress(ctx, tests.AddressToQuery)
+	if err != nil {
+		t.Fatalf("GetSpentUtxosByAddress failed: %v", err)
+	}
+
+	// The wallet has spent outputs since its first funding.
+	if len(spent) == 0 {
+		t.Fatal("Expected at least one spent UTxO")
+	}
+	for _, s := range spent {
+		assert.True(t, s.SpentAt.Slot > 0, "spent output should carry its spending slot")
+		assert.Equal(t, tests.AddressToQuery, s.Utxo.Output.Address().String())
+	}
+}
+
 func TestGetUtxosByOutRef(t *testing.T) {
 	kupmios := setupKupmios(t)
 	ctx := context.Background()
@@ -262,7 +403,12 @@ func TestGetUtxosByOutRefOgmios(t *testing.T) {
 	if err != nil {
 		t.Fatalf("invalid address: %v", err)
 	}
-	utxo, err := ogmiosUtxoToCommon(utxos[0], address)
+	utxo, err := ogmiosUtxoToCommon(
+		utxos[0],
+		address,
+		kupmios.scriptRefError,
+		kupmios.quantityOverflow,
+	)
 	if err != nil {
 		t.Fatalf("ogmiosUtxoToCommon failed: %v", err)
 	}
@@ -322,9 +468,10 @@ func TestGetDatum(t *testing.T) {
 }
 
 func TestAwaitTx(t *testing.T) {
-	t.Skip("Skipping await tx test - kupmios returns error")
 	kupmios := setupKupmios(t)
-	ctx := context.Background()
+	kupmios.awaitTxOgmiosFallback = true
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
 
 	// Test with a known confirmed transaction
 	isConfirmed, err := kupmios.AwaitTx(
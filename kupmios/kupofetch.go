@@ -0,0 +1,131 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/SundaeSwap-finance/kugo"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// defaultKupoTimeout matches kugo's default request timeout and applies to
+// the Kupo requests the provider makes itself when Config.HTTPClient is nil.
+const defaultKupoTimeout = 5 * time.Minute
+
+// kupoFetcher is the chainFetcher backed by Kupo. kugo decodes whatever body
+// Kupo answers with and drops the status, so an error page reads as an empty
+// datum; kupoFetcher looks at the status first.
+type kupoFetcher struct {
+	endpoint string
+	client   *http.Client
+	headers  http.Header
+	retry    connector.RetryPolicy
+	inst     Instrumentation
+}
+
+// Datum returns the datum CBOR hex stored under datumHash.
+func (f *kupoFetcher) Datum(ctx context.Context, datumHash string) (string, error) {
+	var resp *struct {
+		Datum string `json:"datum"`
+	}
+	if err := f.get(ctx, "/v1/datums/"+datumHash, &resp); err != nil {
+		return "", err
+	}
+	if resp == nil || resp.Datum == "" {
+		return "", fmt.Errorf("%w: Kupo knows no datum %s", connector.ErrNotFound, datumHash)
+	}
+	return resp.Datum, nil
+}
+
+// Script returns the script stored under scriptHash.
+func (f *kupoFetcher) Script(ctx context.Context, scriptHash string) (*kugo.Script, error) {
+	var script *kugo.Script
+	if err := f.get(ctx, "/v1/scripts/"+scriptHash, &script); err != nil {
+		return nil, err
+	}
+	if script == nil || script.Script == "" {
+		return nil, fmt.Errorf("%w: Kupo knows no script %s", connector.ErrNotFound, scriptHash)
+	}
+	return script, nil
+}
+
+// get decodes the JSON body Kupo serves at path into out, retrying transient
+// failures according to f.retry. A 404 wraps connector.ErrNotFound; any other
+// non-2xx status wraps connector.ErrProviderInternal along with Kupo's hint.
+func (f *kupoFetcher) get(ctx context.Context, path string, out any) error {
+	_, err := connector.Retry(ctx, f.retry, retryable, func() (struct{}, error) {
+		return instrumented(ctx, f.inst, ComponentKupo, kupoOperation(path), func() (struct{}, error) {
+			return struct{}{}, f.getOnce(ctx, path, out)
+		})
+	})
+	return err
+}
+
+// getOnce is a single attempt of get. 500, 502, 503 and 504 answers are
+// marked transient.
+func (f *kupoFetcher) getOnce(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		strings.TrimSuffix(f.endpoint, "/")+path,
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to build Kupo request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	setHeaders(req, f.headers)
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to Kupo %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading Kupo response for %s: %w", path, err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: Kupo answered 404 for %s", connector.ErrNotFound, path)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		err := fmt.Errorf(
+			"%w: Kupo answered %d for %s: %s",
+			connector.ErrProviderInternal,
+			resp.StatusCode,
+			path,
+			kupoHint(body),
+		)
+		switch resp.StatusCode {
+		case http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return transientError{err}
+		}
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf(
+			"%w: unreadable Kupo response for %s: %w",
+			connector.ErrProviderInternal,
+			path,
+			err,
+		)
+	}
+	return nil
+}
+
+// kupoHint extracts the hint of a Kupo error body, or returns the body.
+func kupoHint(body []byte) string {
+	var kupoErr struct {
+		Hint string `json:"hint"`
+	}
+	if json.Unmarshal(body, &kupoErr) == nil && kupoErr.Hint != "" {
+		return kupoErr.Hint
+	}
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,95 @@
+package kupmios
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+var (
+	fetchDatumHash  = strings.Repeat("d1", 32)
+	fetchScriptHash = strings.Repeat("5c", 28)
+)
+
+func TestGetDatumStatuses(t *testing.T) {
+	cases := []struct {
+		name     string
+		serve    func(*mockKupo)
+		notFound bool
+	}{
+		{
+			name:     "404",
+			serve:    func(*mockKupo) {},
+			notFound: true,
+		},
+		{
+			name: "null",
+			serve: func(kupo *mockKupo) {
+				kupo.route("/v1/datums/"+fetchDatumHash, "null")
+			},
+			notFound: true,
+		},
+		{
+			name: "500",
+			serve: func(kupo *mockKupo) {
+				kupo.fail("/v1/datums/"+fetchDatumHash, http.StatusInternalServerError)
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kp, _, kupo := newMockKupmios(t, Config{})
+			c.serve(kupo)
+
+			_, err := kp.GetDatum(context.Background(), fetchDatumHash)
+			assert.Equal(t, c.notFound, errors.Is(err, connector.ErrNotFound), "got %v", err)
+			assert.Equal(t, !c.notFound, errors.Is(err, connector.ErrProviderInternal), "got %v", err)
+		})
+	}
+}
+
+func TestGetDatumFound(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{})
+	kupo.route("/v1/datums/"+fetchDatumHash, `{"datum": "d87980"}`)
+
+	datum, err := kp.GetDatum(context.Background(), fetchDatumHash)
+	assert.NoError(t, err)
+	assert.NotNil(t, datum.Data)
+}
+
+func TestGetScriptCborStatuses(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{DisableChainCache: true})
+	path := "/v1/scripts/" + fetchScriptHash
+
+	_, err := kp.GetScriptCborByScriptHash(context.Background(), fetchScriptHash)
+	assert.True(t, errors.Is(err, connector.ErrNotFound), "got %v", err)
+
+	kupo.route(path, "null")
+	_, err = kp.GetScriptCborByScriptHash(context.Background(), fetchScriptHash)
+	assert.True(t, errors.Is(err, connector.ErrNotFound), "got %v", err)
+
+	kupo.fail(path, http.StatusServiceUnavailable)
+	_, err = kp.GetScriptCborByScriptHash(context.Background(), fetchScriptHash)
+	assert.True(t, errors.Is(err, connector.ErrProviderInternal), "got %v", err)
+	assert.False(t, errors.Is(err, connector.ErrNotFound), "got %v", err)
+	assert.Contains(t, err.Error(), "mock failure")
+}
+
+func TestHTTPClientTimeout(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{
+		HTTPClient: &http.Client{Timeout: 50 * time.Millisecond},
+	})
+	kupo.slow(time.Minute)
+
+	start := time.Now()
+	_, err := kp.GetDatum(context.Background(), fetchDatumHash)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, connector.ErrNotFound), "got %v", err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,99 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// ledgerStatePrefix is the namespace of the Ogmios ledger-state queries.
+const ledgerStatePrefix = "queryLedgerState/"
+
+// Ogmios fault codes QueryLedgerState translates.
+const (
+	ogmiosMethodNotFound          = -32601
+	ogmiosInvalidParams           = -32602
+	ogmiosUnavailableInCurrentEra = 2002
+)
+
+// ogmiosFault is an Ogmios JSON-RPC error object.
+type ogmiosFault struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// QueryLedgerState sends the Ogmios ledger-state query method with params and
+// decodes its result, in the Ogmios v6 schema, into result. It is an escape
+// hatch for the queries the provider does not wrap, such as proposals,
+// constitution or stakePools. method may be given with or without its
+// "queryLedgerState/" prefix; params and result may be nil.
+//
+// Ogmios faults wrap connector.ErrNotImplemented for unknown queries and
+// queries unavailable in the current era, connector.ErrInvalidInput for
+// rejected params and connector.ErrProviderInternal otherwise. The query is
+// sent over a connection of its own, since ogmigo has no raw query API.
+func (kp *KupmiosProvider) QueryLedgerState(
+	ctx context.Context,
+	method string,
+	params any,
+	result any,
+) error {
+	if method == "" {
+		return fmt.Errorf("%w: ledger-state query cannot be empty", connector.ErrInvalidInput)
+	}
+	if !strings.HasPrefix(method, ledgerStatePrefix) {
+		if strings.Contains(method, "/") {
+			return fmt.Errorf(
+				"%w: %q is not an Ogmios ledger-state query",
+				connector.ErrInvalidInput,
+				method,
+			)
+		}
+		method = ledgerStatePrefix + method
+	}
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *ogmiosFault    `json:"error"`
+	}
+	if err := kp.ogmiosRPC(ctx, method, params, &response); err != nil {
+		return fmt.Errorf("kupmios: %w", err)
+	}
+	if response.Error != nil {
+		return ledgerStateFault(method, response.Error)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf(
+			"%w: kupmios: unreadable Ogmios %s result: %w",
+			connector.ErrProviderInternal,
+			method,
+			err,
+		)
+	}
+	return nil
+}
+
+// ledgerStateFault translates an Ogmios fault answering method.
+func ledgerStateFault(method string, fault *ogmiosFault) error {
+	kind := connector.ErrProviderInternal
+	switch fault.Code {
+	case ogmiosMethodNotFound, ogmiosUnavailableInCurrentEra:
+		kind = connector.ErrNotImplemented
+	case ogmiosInvalidParams:
+		kind = connector.ErrInvalidInput
+	}
+	return fmt.Errorf(
+		"%w: kupmios: ogmios %s failed (code %d): %s",
+		kind,
+		method,
+		fault.Code,
+		fault.Message,
+	)
+}
@@ -0,0 +1,120 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+func TestQueryLedgerState(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	ogmios.handle("queryLedgerState/stakePools", func(json.RawMessage) any {
+		return map[string]any{
+			"pool1abc": map[string]any{"id": "pool1abc", "pledge": map[string]any{"ada": map[string]any{"lovelace": 500}}},
+		}
+	})
+
+	var pools map[string]struct {
+		ID     string `json:"id"`
+		Pledge struct {
+			Ada struct {
+				Lovelace uint64 `json:"lovelace"`
+			} `json:"ada"`
+		} `json:"pledge"`
+	}
+	params := map[string]any{"stakePools": []map[string]string{{"id": "pool1abc"}}}
+	err := kp.QueryLedgerState(context.Background(), "stakePools", params, &pools)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(500), pools["pool1abc"].Pledge.Ada.Lovelace)
+
+	calls := ogmios.Calls("queryLedgerState/stakePools")
+	assert.Len(t, calls, 1)
+	assert.JSONEq(t, `{"stakePools": [{"id": "pool1abc"}]}`, string(calls[0]))
+
+	// The prefixed form reaches the same query.
+	assert.NoError(t, kp.QueryLedgerState(context.Background(), "queryLedgerState/stakePools", nil, nil))
+	assert.Len(t, ogmios.Calls("queryLedgerState/stakePools"), 2)
+}
+
+func TestQueryLedgerStateFaults(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	ogmios.handle("queryLedgerState/proposals", func(json.RawMessage) any {
+		return mockRPCError{Code: -32602, Message: "Invalid params"}
+	})
+	ogmios.handle("queryLedgerState/constitution", func(json.RawMessage) any {
+		return mockRPCError{Code: 2002, Message: "Unavailable in current era"}
+	})
+	ogmios.handle("queryLedgerState/treasuryAndReserves", func(json.RawMessage) any {
+		return mockRPCError{Code: 2003, Message: "Acquired point expired"}
+	})
+
+	cases := map[string]error{
+		"proposals":           connector.ErrInvalidInput,
+		"constitution":        connector.ErrNotImplemented,
+		"treasuryAndReserves": connector.ErrProviderInternal,
+		// The mock answers unknown methods with -32601.
+		"noSuchQuery": connector.ErrNotImplemented,
+	}
+	for method, want := range cases {
+		err := kp.QueryLedgerState(context.Background(), method, nil, nil)
+		assert.True(t, errors.Is(err, want), "%s: got %v", method, err)
+		assert.Contains(t, err.Error(), "queryLedgerState/"+method)
+	}
+}
+
+func TestQueryLedgerStateRejectsOtherNamespaces(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+
+	for _, method := range []string{"", "submitTransaction/x", "queryNetwork/tip"} {
+		err := kp.QueryLedgerState(context.Background(), method, nil, nil)
+		assert.True(t, errors.Is(err, connector.ErrInvalidInput), "%q: got %v", method, err)
+	}
+	assert.Empty(t, ogmios.Calls("queryNetwork/tip"))
+}
+
+func TestQueryLedgerStateUnreadableResult(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	ogmios.handle("queryLedgerState/epoch", func(json.RawMessage) any { return "not a number" })
+
+	var epoch uint64
+	err := kp.QueryLedgerState(context.Background(), "epoch", nil, &epoch)
+	assert.True(t, errors.Is(err, connector.ErrProviderInternal), "got %v", err)
+}
+
+func ExampleKupmiosProvider_QueryLedgerState() {
+	kp, err := New(Config{
+		OgmigoEndpoint: "ws://localhost:1337",
+		KupoEndpoint:   "http://localhost:1442",
+		NetworkId:      preprodNetworkId,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Governance proposals, decoded as far as needed.
+	var proposals []struct {
+		Proposal struct {
+			Transaction struct {
+				ID string `json:"id"`
+			} `json:"transaction"`
+			Index int `json:"index"`
+		} `json:"proposal"`
+		Deposit struct {
+			Ada struct {
+				Lovelace uint64 `json:"lovelace"`
+			} `json:"ada"`
+		} `json:"deposit"`
+	}
+	if err := kp.QueryLedgerState(context.Background(), "proposals", nil, &proposals); err != nil {
+		panic(err)
+	}
+	for _, p := range proposals {
+		fmt.Printf("%s#%d: %d lovelace deposit\n",
+			p.Proposal.Transaction.ID, p.Proposal.Index, p.Deposit.Ada.Lovelace)
+	}
+}
@@ -0,0 +1,79 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gorilla/websocket"
+)
+
+// ogmiosMempoolResponse is the envelope of the Ogmios mempool monitoring
+// responses.
+type ogmiosMempoolResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// mempoolHasTx reports whether txHash is in the node's mempool. It acquires a
+// mempool snapshot over the Ogmios mempool monitoring protocol, asks whether
+// the snapshot holds the transaction and releases the snapshot again. The
+// protocol is stateful, so all three requests share one connection.
+func (kp *KupmiosProvider) mempoolHasTx(
+	ctx context.Context,
+	txHash string,
+) (has bool, err error) {
+	end := observe(ctx, kp.instrumentation, ComponentOgmios, "hasTransaction")
+	defer func() { end(err) }()
+
+	conn, err := kp.dialOgmios(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to Ogmios: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := mempoolCall(conn, "acquireMempool", nil); err != nil {
+		return false, err
+	}
+	result, err := mempoolCall(conn, "hasTransaction", map[string]any{
+		"id": txHash,
+	})
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(result, &has); err != nil {
+		return false, fmt.Errorf(
+			"failed to decode Ogmios hasTransaction result: %w",
+			err,
+		)
+	}
+	// The snapshot is dropped with the connection anyway; releasing it is a
+	// courtesy to the server.
+	_, _ = mempoolCall(conn, "releaseMempool", nil)
+	return has, nil
+}
+
+// mempoolCall issues one mempool monitoring request on conn and returns its
+// result.
+func mempoolCall(
+	conn *websocket.Conn,
+	method string,
+	params any,
+) (json.RawMessage, error) {
+	var response ogmiosMempoolResponse
+	if err := ogmiosCall(conn, method, params, &response); err != nil {
+		return nil, err
+	}
+	if response.Error != nil {
+		return nil, fmt.Errorf(
+			"ogmios %s failed (code %d): %s",
+			method,
+			response.Error.Code,
+			response.Error.Message,
+		)
+	}
+	return response.Result, nil
+}
@@ -0,0 +1,291 @@
+package kupmios
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/tj/assert"
+)
+
+// mockOgmios answers Ogmios JSON-RPC requests over a websocket. Each method is
+// served by a handler that receives the raw params and returns the result, or
+// a mockRPCError to answer with a JSON-RPC error instead. GET /health answers
+// the body set with serveHealth.
+type mockOgmios struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	handlers map[string]func(params json.RawMessage) any
+	calls    map[string][]json.RawMessage
+	headers  map[string]http.Header
+	health   string
+}
+
+func newMockOgmios(t testing.TB) *mockOgmios {
+	t.Helper()
+	m := &mockOgmios{
+		handlers: map[string]func(json.RawMessage) any{},
+		calls:    map[string][]json.RawMessage{},
+		headers:  map[string]http.Header{},
+	}
+	upgrader := websocket.Upgrader{}
+	m.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		m.headers[r.URL.Path] = r.Header.Clone()
+		m.mu.Unlock()
+		if r.URL.Path == "/health" {
+			m.mu.Lock()
+			health := m.health
+			m.mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(health))
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var req struct {
+				Method string          `json:"method"`
+				Params json.RawMessage `json:"params"`
+			}
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			m.mu.Lock()
+			m.calls[req.Method] = append(m.calls[req.Method], req.Params)
+			handler := m.handlers[req.Method]
+			m.mu.Unlock()
+
+			resp := map[string]any{"jsonrpc": "2.0", "method": req.Method}
+			var result any
+			if handler != nil {
+				result = handler(req.Params)
+			}
+			if result == mockDropConnection {
+				return
+			}
+			if handler == nil {
+				resp["error"] = mockRPCError{
+					Code:    -32601,
+					Message: "unknown method " + req.Method,
+				}
+			} else if isRPCError(result) {
+				resp["error"] = result
+			} else {
+				resp["result"] = result
+			}
+			if err := conn.WriteJSON(resp); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(m.Close)
+	return m
+}
+
+// mockRPCError is a JSON-RPC error object returned by a mockOgmios handler.
+type mockRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// mockDropConnection, returned by a mockOgmios handler, closes the connection
+// without answering.
+var mockDropConnection any = struct{ drop bool }{true}
+
+func isRPCError(result any) bool {
+	_, ok := result.(mockRPCError)
+	return ok
+}
+
+// handle registers the result returned for method.
+func (m *mockOgmios) handle(method string, handler func(params json.RawMessage) any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers[method] = handler
+}
+
+// serveHealth sets the body of GET /health.
+func (m *mockOgmios) serveHealth(body string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.health = body
+}
+
+// Calls returns the params of every request received for method.
+func (m *mockOgmios) Calls(method string) []json.RawMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls[method]
+}
+
+// Header returns the headers of the most recent request for path; websocket
+// connections are opened on "/".
+func (m *mockOgmios) Header(path string) http.Header {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.headers[path]
+}
+
+func (m *mockOgmios) endpoint() string {
+	return "ws" + strings.TrimPrefix(m.URL, "http")
+}
+
+// mockKupo serves fixed JSON bodies by request path. Unknown /v1/matches
+// paths answer an empty list and anything else 404.
+type mockKupo struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	routes   map[string]string
+	bodies   map[string][]string
+	failures map[string]int
+	flakes   map[string][]int
+	requests map[string]int
+	queries  map[string][]string
+	headers  map[string]http.Header
+	delay    time.Duration
+}
+
+func newMockKupo(t testing.TB) *mockKupo {
+	t.Helper()
+	m := &mockKupo{
+		routes:   map[string]string{},
+		bodies:   map[string][]string{},
+		failures: map[string]int{},
+		flakes:   map[string][]int{},
+		requests: map[string]int{},
+		queries:  map[string][]string{},
+		headers:  map[string]http.Header{},
+	}
+	m.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		m.requests[r.URL.Path]++
+		m.queries[r.URL.Path] = append(m.queries[r.URL.Path], r.URL.RawQuery)
+		m.headers[r.URL.Path] = r.Header.Clone()
+		body, ok := m.routes[r.URL.Path]
+		if bodies := m.bodies[r.URL.Path]; len(bodies) > 0 {
+			body, ok = bodies[0], true
+			if len(bodies) > 1 {
+				m.bodies[r.URL.Path] = bodies[1:]
+			}
+		}
+		status := m.failures[r.URL.Path]
+		if flakes := m.flakes[r.URL.Path]; len(flakes) > 0 {
+			status = flakes[0]
+			m.flakes[r.URL.Path] = flakes[1:]
+		}
+		delay := m.delay
+		m.mu.Unlock()
+
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case status != 0:
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"hint": "mock failure"}`))
+		case ok:
+			_, _ = w.Write([]byte(body))
+		case strings.HasPrefix(r.URL.Path, "/v1/matches"):
+			_, _ = w.Write([]byte("[]"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(m.Close)
+	return m
+}
+
+func (m *mockKupo) route(path, body string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.routes[path] = body
+}
+
+// sequence makes successive requests for path answer bodies in turn, the
+// last one repeating.
+func (m *mockKupo) sequence(path string, bodies ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bodies[path] = bodies
+}
+
+// fail makes every request for path answer status.
+func (m *mockKupo) fail(path string, status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures[path] = status
+}
+
+// flaky makes the next requests for path answer statuses in turn before path
+// is served as usual.
+func (m *mockKupo) flaky(path string, statuses ...int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.flakes[path] = statuses
+}
+
+// slow delays every response by delay.
+func (m *mockKupo) slow(delay time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.delay = delay
+}
+
+// Requests returns how many times path was requested.
+func (m *mockKupo) Requests(path string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.requests[path]
+}
+
+// Queries returns the raw query string of every request for path.
+func (m *mockKupo) Queries(path string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.queries[path]
+}
+
+// Header returns the headers of the most recent request for path.
+func (m *mockKupo) Header(path string) http.Header {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.headers[path]
+}
+
+// newMockKupmios returns a provider wired to fresh Ogmios and Kupo mocks.
+func newMockKupmios(t testing.TB, config Config) (*KupmiosProvider, *mockOgmios, *mockKupo) {
+	t.Helper()
+	ogmios := newMockOgmios(t)
+	kupo := newMockKupo(t)
+	config.OgmigoEndpoint = ogmios.endpoint()
+	config.KupoEndpoint = kupo.URL
+	provider, err := New(config)
+	assert.NoError(t, err)
+	return provider, ogmios, kupo
+}
+
+// withLocalEndpoints points config at the default local Ogmios and Kupo
+// ports, for tests that never reach either.
+func withLocalEndpoints(config Config) Config {
+	config.OgmigoEndpoint = "ws://127.0.0.1:1337"
+	config.KupoEndpoint = "http://127.0.0.1:1442"
+	return config
+}
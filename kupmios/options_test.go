@@ -0,0 +1,173 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SundaeSwap-finance/kugo"
+	ogmigo "github.com/SundaeSwap-finance/ogmigo/v6"
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+func TestNewRequiresEndpoints(t *testing.T) {
+	_, err := New(Config{KupoEndpoint: "http://127.0.0.1:1442"})
+	assert.True(t, errors.Is(err, connector.ErrInvalidInput), "got %v", err)
+	assert.Contains(t, err.Error(), "OgmigoEndpoint")
+
+	_, err = New(Config{OgmigoEndpoint: "ws://127.0.0.1:1337"})
+	assert.True(t, errors.Is(err, connector.ErrInvalidInput), "got %v", err)
+	assert.Contains(t, err.Error(), "KupoEndpoint")
+}
+
+func TestNewAppliesClientOptions(t *testing.T) {
+	ogmios := newMockOgmios(t)
+	ogmios.handle("queryLedgerState/tip", func(json.RawMessage) any {
+		return map[string]any{"slot": 42, "id": strings.Repeat("ab", 32), "height": 7}
+	})
+	kupo := newMockKupo(t)
+
+	// The endpoint options come last, so they override the unreachable
+	// endpoints below.
+	kp, err := New(Config{
+		OgmigoEndpoint: "ws://127.0.0.1:1",
+		KupoEndpoint:   "http://127.0.0.1:1",
+		OgmigoOptions:  []ogmigo.Option{ogmigo.WithEndpoint(ogmios.endpoint())},
+		KugoOptions:    []kugo.Option{kugo.WithEndpoint(kupo.URL)},
+	})
+	assert.NoError(t, err)
+
+	tip, err := kp.GetTip(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(42), tip.Slot)
+
+	_, err = kp.GetUtxosByAddress(context.Background(), adapterTestAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, kupo.Requests("/v1/matches/"+adapterTestAddr))
+}
+
+func TestNewAppliesKugoTimeout(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{
+		KugoOptions: []kugo.Option{kugo.WithTimeout(50 * time.Millisecond)},
+	})
+	kupo.slow(time.Minute)
+
+	start := time.Now()
+	_, err := kp.GetUtxosByAddress(context.Background(), adapterTestAddr)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestNewRejectsMalformedEndpoints(t *testing.T) {
+	for _, config := range []Config{
+		{OgmigoEndpoint: "127.0.0.1:1337", KupoEndpoint: "http://127.0.0.1:1442"},
+		{OgmigoEndpoint: "ws://127.0.0.1:1337", KupoEndpoint: "ftp://127.0.0.1:1442"},
+		{OgmigoEndpoint: "ws://127.0.0.1:1337", KupoEndpoint: "http://"},
+		{OgmigoEndpoint: "ws://%zz", KupoEndpoint: "http://127.0.0.1:1442"},
+	} {
+		_, err := New(config)
+		assert.True(t, errors.Is(err, connector.ErrInvalidInput), "%+v: got %v", config, err)
+	}
+}
+
+// validatingConfig returns a ValidateOnNew config for fresh healthy mocks
+// that report the network magic of preprod and follow the same chain.
+func validatingConfig(t *testing.T) (Config, *mockOgmios, *mockKupo) {
+	ogmios := newMockOgmios(t)
+	kupo := newMockKupo(t)
+	serveHealth(ogmios, kupo, 1000, 1000, 1000)
+	ogmios.handle("queryNetwork/genesisConfiguration", func(json.RawMessage) any {
+		return map[string]any{"networkMagic": 1, "activeSlotsCoefficient": "1/20"}
+	})
+	kupo.route("/v1/checkpoints", fmt.Sprintf(`[
+		{"slot_no": 1000, "header_hash": %q},
+		{"slot_no": 990, "header_hash": %q}
+	]`, strings.Repeat("10", 32), strings.Repeat("99", 32)))
+	ogmios.handle("findIntersection", func(json.RawMessage) any {
+		return map[string]any{
+			"intersection": map[string]any{"slot": 1000, "id": strings.Repeat("10", 32)},
+		}
+	})
+	return Config{
+		OgmigoEndpoint: ogmios.endpoint(),
+		KupoEndpoint:   kupo.URL,
+		NetworkId:      preprodNetworkId,
+		ValidateOnNew:  true,
+	}, ogmios, kupo
+}
+
+func TestNewValidateOnNew(t *testing.T) {
+	config, _, _ := validatingConfig(t)
+
+	_, err := New(config)
+	assert.NoError(t, err)
+}
+
+func TestNewValidateOnNewOgmiosUnreachable(t *testing.T) {
+	config, ogmios, _ := validatingConfig(t)
+	ogmios.Close()
+
+	_, err := New(config)
+	assert.True(t, errors.Is(err, ErrUnhealthy), "got %v", err)
+	assert.Contains(t, err.Error(), "ogmios at")
+}
+
+func TestNewValidateOnNewKupoUnreachable(t *testing.T) {
+	config, _, kupo := validatingConfig(t)
+	kupo.Close()
+
+	_, err := New(config)
+	assert.True(t, errors.Is(err, ErrUnhealthy), "got %v", err)
+	assert.Contains(t, err.Error(), "kupo at")
+}
+
+func TestNewValidateOnNewNetworkMismatch(t *testing.T) {
+	config, _, _ := validatingConfig(t)
+	config.NetworkId = 1
+
+	_, err := New(config)
+	assert.True(t, errors.Is(err, ErrNetworkMismatch), "got %v", err)
+	assert.True(t, errors.Is(err, connector.ErrInvalidInput), "got %v", err)
+	assert.Contains(t, err.Error(), "magic 1")
+}
+
+func TestNewValidateOnNewChainMismatch(t *testing.T) {
+	config, ogmios, _ := validatingConfig(t)
+	ogmios.handle("findIntersection", func(json.RawMessage) any {
+		return mockRPCError{Code: 1000, Message: "No intersection found."}
+	})
+
+	_, err := New(config)
+	assert.True(t, errors.Is(err, ErrNetworkMismatch), "got %v", err)
+	assert.Contains(t, err.Error(), "slot 1000")
+
+	calls := ogmios.Calls("findIntersection")
+	assert.Len(t, calls, 1)
+	var params struct {
+		Points []struct {
+			Slot uint64 `json:"slot"`
+		} `json:"points"`
+	}
+	assert.NoError(t, json.Unmarshal(calls[0], &params))
+	assert.Len(t, params.Points, 2)
+	assert.Equal(t, uint64(1000), params.Points[0].Slot)
+}
+
+func TestNewSkipNetworkCheck(t *testing.T) {
+	config, ogmios, _ := validatingConfig(t)
+	config.NetworkId = 1
+	config.SkipNetworkCheck = true
+	ogmios.handle("findIntersection", func(json.RawMessage) any {
+		return mockRPCError{Code: 1000, Message: "No intersection found."}
+	})
+
+	_, err := New(config)
+	assert.NoError(t, err)
+	assert.Empty(t, ogmios.Calls("queryNetwork/genesisConfiguration"))
+	assert.Empty(t, ogmios.Calls("findIntersection"))
+}
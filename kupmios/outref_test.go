@@ -0,0 +1,275 @@
+package kupmios
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+	"github.com/zenGate-Global/cardano-connector-go/tests"
+)
+
+const outRefPolicy = "aaaa00000000000000000000000000000000000000000000000000aa"
+
+var (
+	outRefLive  = connector.OutRef{TxHash: strings.Repeat("0a", 32), Index: 0}
+	outRefSpent = connector.OutRef{TxHash: strings.Repeat("0b", 32), Index: 1}
+	outRefNone  = connector.OutRef{TxHash: strings.Repeat("0c", 32), Index: 2}
+)
+
+// ogmiosUtxoJSON renders ref as an Ogmios queryLedgerState/utxo entry.
+func ogmiosUtxoJSON(ref connector.OutRef) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{
+		"transaction": {"id": %q},
+		"index": %d,
+		"address": %q,
+		"value": {"ada": {"lovelace": 2000000}, %q: {"74657374": 5}}
+	}`, ref.TxHash, ref.Index, adapterTestAddr, outRefPolicy))
+}
+
+// kupoMatchJSON renders ref as a Kupo /matches entry holding the same output
+// as ogmiosUtxoJSON.
+func kupoMatchJSON(ref connector.OutRef) string {
+	return fmt.Sprintf(`[{
+		"transaction_id": %q,
+		"output_index": %d,
+		"address": %q,
+		"value": {"coins": 2000000, "assets": {"%s.74657374": 5}},
+		"datum_hash": null,
+		"script_hash": null,
+		"created_at": {"slot_no": 10, "header_hash": %q},
+		"spent_at": {"slot_no": 20, "header_hash": %q}
+	}]`, ref.TxHash, ref.Index, adapterTestAddr, outRefPolicy,
+		strings.Repeat("01", 32), strings.Repeat("02", 32))
+}
+
+func kupoOutRefPath(ref connector.OutRef) string {
+	return fmt.Sprintf("/v1/matches/%d@%s", ref.Index, ref.TxHash)
+}
+
+// serveLedgerUtxos answers queryLedgerState/utxo with the given outputs,
+// whatever was asked for.
+func serveLedgerUtxos(ogmios *mockOgmios, refs ...connector.OutRef) {
+	ogmios.handle("queryLedgerState/utxo", func(json.RawMessage) any {
+		utxos := make([]json.RawMessage, 0, len(refs))
+		for _, ref := range refs {
+			utxos = append(utxos, ogmiosUtxoJSON(ref))
+		}
+		return utxos
+	})
+}
+
+func TestGetUtxosByOutRefBatchesOgmios(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{})
+	serveLedgerUtxos(ogmios, outRefLive)
+	kupo.route(kupoOutRefPath(outRefSpent), kupoMatchJSON(outRefSpent))
+
+	utxos, err := kp.GetUtxosByOutRef(context.Background(), []connector.OutRef{
+		outRefSpent, outRefLive, outRefNone, outRefLive,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 2)
+	assert.Equal(t, outRefSpent.TxHash, utxos[0].Id.Id().String())
+	assert.Equal(t, outRefSpent.Index, utxos[0].Id.Index())
+	assert.Equal(t, outRefLive.TxHash, utxos[1].Id.Id().String())
+
+	calls := ogmios.Calls("queryLedgerState/utxo")
+	assert.Len(t, calls, 1)
+	var params struct {
+		OutputReferences []json.RawMessage `json:"outputReferences"`
+	}
+	assert.NoError(t, json.Unmarshal(calls[0], &params))
+	assert.Len(t, params.OutputReferences, 3)
+
+	assert.Equal(t, 0, kupo.Requests(kupoOutRefPath(outRefLive)))
+	assert.Equal(t, 1, kupo.Requests(kupoOutRefPath(outRefSpent)))
+	assert.Equal(t, 1, kupo.Requests(kupoOutRefPath(outRefNone)))
+}
+
+func TestGetUtxosByOutRefStableOrder(t *testing.T) {
+	unrequested := connector.OutRef{TxHash: strings.Repeat("0d", 32), Index: 3}
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	serveLedgerUtxos(ogmios, outRefSpent, unrequested, outRefLive)
+
+	refs := []connector.OutRef{outRefLive, outRefNone, outRefSpent, outRefLive}
+	for range 20 {
+		utxos, err := kp.GetUtxosByOutRef(context.Background(), refs)
+		assert.NoError(t, err)
+		got := make([]connector.OutRef, len(utxos))
+		for i, utxo := range utxos {
+			got[i] = connector.OutRef{
+				TxHash: utxo.Id.Id().String(),
+				Index:  utxo.Id.Index(),
+			}
+		}
+		assert.Equal(t, []connector.OutRef{outRefLive, outRefSpent}, got)
+	}
+}
+
+func TestGetUtxosByOutRefSourcesAgree(t *testing.T) {
+	fromOgmios, ogmios, _ := newMockKupmios(t, Config{})
+	serveLedgerUtxos(ogmios, outRefSpent)
+	fromKupo, ogmios, kupo := newMockKupmios(t, Config{})
+	serveLedgerUtxos(ogmios)
+	kupo.route(kupoOutRefPath(outRefSpent), kupoMatchJSON(outRefSpent))
+
+	refs := []connector.OutRef{outRefSpent}
+	a, err := fromOgmios.GetUtxosByOutRef(context.Background(), refs)
+	assert.NoError(t, err)
+	b, err := fromKupo.GetUtxosByOutRef(context.Background(), refs)
+	assert.NoError(t, err)
+	assert.Len(t, a, 1)
+	assert.Len(t, b, 1)
+	assert.True(t, tests.UtxosEqual(a[0], b[0]), tests.UtxoDiff(a[0], b[0]))
+}
+
+func TestGetUtxosByOutRefOgmiosError(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{})
+	ogmios.Close()
+
+	_, err := kp.GetUtxosByOutRef(context.Background(), []connector.OutRef{outRefLive})
+	assert.Error(t, err)
+	assert.Equal(t, 0, kupo.Requests(kupoOutRefPath(outRefLive)))
+}
+
+// failOneOfThree serves outRefSpent from Kupo, fails the lookup of outRefLive
+// and knows nothing of outRefNone. Ogmios serves none of them.
+func failOneOfThree(t *testing.T, config Config) *KupmiosProvider {
+	t.Helper()
+	kp, ogmios, kupo := newMockKupmios(t, config)
+	serveLedgerUtxos(ogmios)
+	kupo.route(kupoOutRefPath(outRefSpent), kupoMatchJSON(outRefSpent))
+	kupo.fail(kupoOutRefPath(outRefLive), http.StatusServiceUnavailable)
+	return kp
+}
+
+func TestGetUtxosByOutRefReportsFailedRefs(t *testing.T) {
+	kp := failOneOfThree(t, Config{})
+
+	utxos, err := kp.GetUtxosByOutRef(context.Background(), []connector.OutRef{
+		outRefLive, outRefSpent, outRefNone,
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("%s#%d", outRefLive.TxHash, outRefLive.Index))
+	assert.NotContains(t, err.Error(), outRefNone.TxHash)
+	assert.Len(t, utxos, 1)
+	assert.Equal(t, outRefSpent.TxHash, utxos[0].Id.Id().String())
+}
+
+func TestGetUtxosByOutRefLenientSkipsFailedRefs(t *testing.T) {
+	var buf bytes.Buffer
+	kp := failOneOfThree(t, Config{
+		Logger:         slog.New(slog.NewTextHandler(&buf, nil)),
+		LenientOutRefs: true,
+	})
+
+	utxos, err := kp.GetUtxosByOutRef(context.Background(), []connector.OutRef{
+		outRefLive, outRefSpent, outRefNone,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 1)
+	assert.Contains(t, buf.String(), "skipping out-ref")
+	assert.Contains(t, buf.String(), "tx_hash="+outRefLive.TxHash)
+	assert.NotContains(t, buf.String(), outRefNone.TxHash)
+}
+
+// spentRefs returns n distinct refs, each served only by Kupo.
+func spentRefs(kupo *mockKupo, n int) []connector.OutRef {
+	refs := make([]connector.OutRef, n)
+	for i := range refs {
+		refs[i] = connector.OutRef{TxHash: fmt.Sprintf("%064x", i+1), Index: uint32(i % 3)}
+		kupo.route(kupoOutRefPath(refs[i]), kupoMatchJSON(refs[i]))
+	}
+	return refs
+}
+
+func TestGetUtxosByOutRefConcurrentFallback(t *testing.T) {
+	const latency = 50 * time.Millisecond
+	kp, ogmios, kupo := newMockKupmios(t, Config{MaxConcurrentRequests: 8})
+	serveLedgerUtxos(ogmios)
+	kupo.slow(latency)
+	refs := spentRefs(kupo, 24)
+
+	start := time.Now()
+	utxos, err := kp.GetUtxosByOutRef(context.Background(), refs)
+	elapsed := time.Since(start)
+	assert.NoError(t, err)
+
+	// Sequential lookups would take 24 round trips; eight workers need three.
+	assert.Less(t, elapsed, 12*latency)
+	assert.Len(t, utxos, len(refs))
+	for i, utxo := range utxos {
+		assert.Equal(t, refs[i].TxHash, utxo.Id.Id().String())
+		assert.Equal(t, refs[i].Index, utxo.Id.Index())
+	}
+}
+
+func TestGetUtxosByOutRefCancelled(t *testing.T) {
+	kp, ogmios, kupo := newMockKupmios(t, Config{MaxConcurrentRequests: 2})
+	serveLedgerUtxos(ogmios)
+	kupo.slow(time.Minute)
+	refs := spentRefs(kupo, 10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := kp.GetUtxosByOutRef(ctx, refs)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestNewRejectsNegativeConcurrency(t *testing.T) {
+	_, err := New(withLocalEndpoints(Config{MaxConcurrentRequests: -1}))
+	assert.True(t, errors.Is(err, connector.ErrInvalidInput), "got %v", err)
+
+	kp, err := New(withLocalEndpoints(Config{}))
+	assert.NoError(t, err)
+	assert.Equal(t, defaultMaxConcurrentRequests, kp.maxConcurrentRequests)
+}
+
+// serveUndecodableLedgerUtxo answers queryLedgerState/utxo with outRefLive
+// held at an address that does not decode.
+func serveUndecodableLedgerUtxo(ogmios *mockOgmios) {
+	ogmios.handle("queryLedgerState/utxo", func(json.RawMessage) any {
+		utxo := strings.Replace(
+			string(ogmiosUtxoJSON(outRefLive)),
+			adapterTestAddr,
+			"addr_test1bogus",
+			1,
+		)
+		return []json.RawMessage{json.RawMessage(utxo)}
+	})
+}
+
+func TestGetUtxosByOutRefUndecodableOgmiosUtxo(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	serveUndecodableLedgerUtxo(ogmios)
+
+	_, err := kp.GetUtxosByOutRef(context.Background(), []connector.OutRef{outRefLive})
+	assert.True(t, errors.Is(err, connector.ErrProviderInternal), "got %v", err)
+	assert.Contains(t, err.Error(), "invalid address")
+	assert.Contains(t, err.Error(), "addr_test1bogus")
+}
+
+func TestGetUtxosByOutRefLenientSkipsUndecodableOgmiosUtxo(t *testing.T) {
+	var buf bytes.Buffer
+	kp, ogmios, _ := newMockKupmios(t, Config{
+		Logger:         slog.New(slog.NewTextHandler(&buf, nil)),
+		LenientOutRefs: true,
+	})
+	serveUndecodableLedgerUtxo(ogmios)
+
+	utxos, err := kp.GetUtxosByOutRef(context.Background(), []connector.OutRef{outRefLive})
+	assert.NoError(t, err)
+	assert.Empty(t, utxos)
+	assert.Contains(t, buf.String(), "could not be decoded")
+	assert.Contains(t, buf.String(), "tx_hash="+outRefLive.TxHash)
+}
@@ -0,0 +1,59 @@
+package kupmios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+func TestGetUtxosByPolicyCIP68Pair(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{})
+	// A CIP-68 pair: reference token (100) and user token (222).
+	kupo.route("/v1/matches/"+outRefPolicy+".*", fmt.Sprintf(`[
+		{
+			"transaction_id": %q,
+			"output_index": 0,
+			"address": %q,
+			"value": {"coins": 2000000, "assets": {"%s.000643b04e4654": 1}},
+			"created_at": {"slot_no": 10, "header_hash": %q}
+		},
+		{
+			"transaction_id": %q,
+			"output_index": 1,
+			"address": %q,
+			"value": {"coins": 1500000, "assets": {"%s.000de1404e4654": 1}},
+			"created_at": {"slot_no": 11, "header_hash": %q}
+		}
+	]`, outRefLive.TxHash, adapterTestAddr, outRefPolicy, strings.Repeat("01", 32),
+		outRefSpent.TxHash, adapterTestAddr, outRefPolicy, strings.Repeat("02", 32)))
+
+	utxos, err := kp.GetUtxosByPolicy(context.Background(), strings.ToUpper(outRefPolicy))
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 2)
+	assert.Equal(t, outRefLive.TxHash, utxos[0].Id.Id().String())
+	assert.Equal(t, outRefSpent.TxHash, utxos[1].Id.Id().String())
+	assert.Equal(t, 1, kupo.Requests("/v1/matches/"+outRefPolicy+".*"))
+}
+
+func TestGetUtxosByPolicyNone(t *testing.T) {
+	kp, _, _ := newMockKupmios(t, Config{})
+
+	utxos, err := kp.GetUtxosByPolicy(context.Background(), outRefPolicy)
+	assert.NoError(t, err)
+	assert.Empty(t, utxos)
+}
+
+func TestGetUtxosByPolicyRejectsInvalidIds(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{})
+
+	for _, policyId := range []string{"", "abcd", outRefPolicy + "00", "zz" + outRefPolicy[2:]} {
+		_, err := kp.GetUtxosByPolicy(context.Background(), policyId)
+		assert.True(t, errors.Is(err, connector.ErrInvalidInput), "%q: got %v", policyId, err)
+	}
+	assert.Equal(t, 0, kupo.Requests("/v1/matches/"+outRefPolicy+".*"))
+}
@@ -0,0 +1,40 @@
+package kupmios
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/gorilla/websocket"
+)
+
+// transientError marks a failure that is worth retrying, such as a 503 from
+// Kupo.
+type transientError struct {
+	err error
+}
+
+func (e transientError) Error() string { return e.err.Error() }
+func (e transientError) Unwrap() error { return e.err }
+
+// retryable reports whether a read that failed with err may be attempted
+// again: the failure must be marked transient or be a broken connection.
+// Context errors, and so a caller's deadline, are final.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var transient transientError
+	if errors.As(err, &transient) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var closeErr *websocket.CloseError
+	return errors.As(err, &closeErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
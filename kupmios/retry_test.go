@@ -0,0 +1,143 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+var retryDatumHash = strings.Repeat("d1", 32)
+
+const retryDatumPath = "/v1/datums/"
+
+// fastRetry retries quickly enough for tests.
+var fastRetry = connector.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+func TestRetryKupoTransientFailures(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{Retry: fastRetry})
+	path := retryDatumPath + retryDatumHash
+	kupo.route(path, `{"datum": "d87980"}`)
+	kupo.flaky(path, http.StatusServiceUnavailable, http.StatusBadGateway)
+
+	datum, err := kp.kupo.Datum(context.Background(), retryDatumHash)
+	assert.NoError(t, err)
+	assert.Equal(t, "d87980", datum)
+	assert.Equal(t, 3, kupo.Requests(path))
+}
+
+func TestRetryKupoNotFoundIsFinal(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{Retry: fastRetry})
+	path := retryDatumPath + retryDatumHash
+
+	_, err := kp.kupo.Datum(context.Background(), retryDatumHash)
+	assert.True(t, errors.Is(err, connector.ErrNotFound), "got %v", err)
+	assert.Equal(t, 1, kupo.Requests(path))
+}
+
+func TestRetryKupoGivesUp(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{Retry: fastRetry})
+	path := retryDatumPath + retryDatumHash
+	kupo.fail(path, http.StatusServiceUnavailable)
+
+	_, err := kp.kupo.Datum(context.Background(), retryDatumHash)
+	assert.True(t, errors.Is(err, connector.ErrProviderInternal), "got %v", err)
+	assert.Equal(t, 3, kupo.Requests(path))
+}
+
+func TestRetryDisabledByDefault(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{})
+	path := retryDatumPath + retryDatumHash
+	kupo.route(path, `{"datum": "d87980"}`)
+	kupo.flaky(path, http.StatusServiceUnavailable)
+
+	_, err := kp.kupo.Datum(context.Background(), retryDatumHash)
+	assert.True(t, errors.Is(err, connector.ErrProviderInternal), "got %v", err)
+	assert.Equal(t, 1, kupo.Requests(path))
+}
+
+func TestRetryStopsBeforeDeadline(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{
+		Retry: connector.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour},
+	})
+	path := retryDatumPath + retryDatumHash
+	kupo.fail(path, http.StatusServiceUnavailable)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := kp.kupo.Datum(ctx, retryDatumHash)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, 1, kupo.Requests(path))
+}
+
+func TestRetryOgmiosDroppedConnection(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{Retry: fastRetry})
+	var calls atomic.Int64
+	ogmios.handle("queryNetwork/blockHeight", func(json.RawMessage) any {
+		if calls.Add(1) == 1 {
+			return mockDropConnection
+		}
+		return 42
+	})
+
+	height, err := kp.blockHeight(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(42), height)
+	assert.Len(t, ogmios.Calls("queryNetwork/blockHeight"), 2)
+}
+
+func TestRetryNeverResubmits(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{Retry: fastRetry})
+	ogmios.handle("submitTransaction", func(json.RawMessage) any {
+		return mockDropConnection
+	})
+
+	_, err := kp.SubmitTx(context.Background(), []byte{0x84})
+	assert.Error(t, err)
+	assert.Len(t, ogmios.Calls("submitTransaction"), 1)
+}
+
+func TestRetryable(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"transient", transientError{errors.New("503")}, true},
+		{"wrapped transient", fmt.Errorf("kupmios: %w", transientError{errors.New("502")}), true},
+		{"connection refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"eof", fmt.Errorf("read: %w", io.EOF), true},
+		{"not found", fmt.Errorf("%w: no datum", connector.ErrNotFound), false},
+		{"invalid input", connector.ErrInvalidInput, false},
+		{"canceled", context.Canceled, false},
+		{"deadline", fmt.Errorf("%w", context.DeadlineExceeded), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, retryable(tc.err))
+		})
+	}
+}
+
+func TestNewRejectsInvalidRetry(t *testing.T) {
+	for _, retry := range []connector.RetryPolicy{
+		{MaxAttempts: -1},
+		{BaseDelay: -time.Second},
+		{Jitter: 1.5},
+	} {
+		_, err := New(withLocalEndpoints(Config{Retry: retry}))
+		assert.True(t, errors.Is(err, connector.ErrInvalidInput), "got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package kupmios
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SundaeSwap-finance/kugo"
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// SpentUtxo is an output that has been spent, with where it was spent.
+type SpentUtxo struct {
+	Utxo    common.Utxo
+	SpentAt SpentAt
+}
+
+// SpentAt locates the input that spent an output.
+type SpentAt struct {
+	Slot       uint64
+	HeaderHash string
+	// TxHash and InputIndex identify the spending input. They are empty when
+	// the Kupo server does not report them.
+	TxHash     string
+	InputIndex int
+}
+
+type includeSpentKey struct{}
+
+// WithSpentMatches returns a context that makes GetUtxosByAddress,
+// GetUtxosWithUnit and GetUtxosByPolicy return outputs Kupo has seen spent as
+// well as unspent ones. Only Kupo servers that keep spent outputs (i.e. not
+// run with --prune-utxo) know about them.
+func WithSpentMatches(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeSpentKey{}, true)
+}
+
+// spentFilter selects the outputs a Kupo query returns: unspent ones, unless
+// ctx was prepared with WithSpentMatches.
+func spentFilter(ctx context.Context) kugo.MatchesFilter {
+	if includeSpent(ctx) {
+		return kugo.All()
+	}
+	return kugo.OnlyUnspent()
+}
+
+// includeSpent reports whether ctx was prepared with WithSpentMatches.
+func includeSpent(ctx context.Context) bool {
+	include, _ := ctx.Value(includeSpentKey{}).(bool)
+	return include
+}
+
+// GetSpentUtxosByAddress returns the outputs ever paid to addr that have since
+// been spent, each with the slot and input that spent it.
+func (kp *KupmiosProvider) GetSpentUtxosByAddress(
+	ctx context.Context,
+	addr string,
+) ([]SpentUtxo, error) {
+	address, err := common.NewAddress(addr)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: invalid address %q: %s",
+			connector.ErrInvalidAddress,
+			addr,
+			err,
+		)
+	}
+
+	if err := kp.checkKupoSynced(ctx); err != nil {
+		return nil, err
+	}
+
+	matches, err := kp.matches(
+		ctx,
+		kugo.OnlySpent(),
+		kugo.Address(addr),
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"kupmios: Kupo request for spent address UTxOs failed for %s: %w",
+			addr,
+			err,
+		)
+	}
+
+	spent := make([]SpentUtxo, 0, len(matches))
+	for _, match := range matches {
+		utxo, err := matchToUtxo(
+			ctx,
+			match,
+			address,
+			kp.fetcher,
+			kp.scriptRefError,
+			kp.quantityOverflow,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"kupmios: failed to adapt kupo match %s#%d: %w",
+				match.TransactionID,
+				match.OutputIndex,
+				err,
+			)
+		}
+		spent = append(spent, SpentUtxo{
+			Utxo: utxo,
+			SpentAt: SpentAt{
+				Slot:       uint64(match.SpentAt.SlotNo),
+				HeaderHash: match.SpentAt.HeaderHash,
+				TxHash:     match.SpentAt.TransactionId,
+				InputIndex: match.SpentAt.InputIndex,
+			},
+		})
+	}
+	return spent, nil
+}
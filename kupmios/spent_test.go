@@ -0,0 +1,99 @@
+package kupmios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+const spendingTx = "0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d0d"
+
+func TestGetSpentUtxosByAddressSpendingInput(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{})
+	path := "/v1/matches/" + adapterTestAddr
+	kupo.route(path, fmt.Sprintf(`[{
+		"transaction_id": %q,
+		"output_index": 1,
+		"address": %q,
+		"value": {"coins": 2000000},
+		"created_at": {"slot_no": 10, "header_hash": %q},
+		"spent_at": {"slot_no": 20, "header_hash": %q, "transaction_id": %q, "input_index": 3}
+	}]`, outRefSpent.TxHash, adapterTestAddr, strings.Repeat("01", 32), strings.Repeat("02", 32), spendingTx))
+
+	spent, err := kp.GetSpentUtxosByAddress(context.Background(), adapterTestAddr)
+	assert.NoError(t, err)
+	assert.Len(t, spent, 1)
+	assert.Equal(t, outRefSpent.TxHash, spent[0].Utxo.Id.Id().String())
+	assert.Equal(t, SpentAt{
+		Slot:       20,
+		HeaderHash: strings.Repeat("02", 32),
+		TxHash:     spendingTx,
+		InputIndex: 3,
+	}, spent[0].SpentAt)
+	assert.Equal(t, []string{"spent"}, kupo.Queries(path))
+}
+
+func TestGetSpentUtxosByAddressInvalid(t *testing.T) {
+	kp, _, _ := newMockKupmios(t, Config{})
+
+	_, err := kp.GetSpentUtxosByAddress(context.Background(), "not-an-address")
+	assert.True(t, errors.Is(err, connector.ErrInvalidAddress), "got %v", err)
+}
+
+func TestWithSpentMatches(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{})
+	addressPath := "/v1/matches/" + adapterTestAddr
+	policyPath := "/v1/matches/" + outRefPolicy + ".*"
+
+	_, err := kp.GetUtxosByAddress(context.Background(), adapterTestAddr)
+	assert.NoError(t, err)
+	_, err = kp.GetUtxosByAddress(WithSpentMatches(context.Background()), adapterTestAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"unspent", ""}, kupo.Queries(addressPath))
+
+	_, err = kp.GetUtxosByPolicy(WithSpentMatches(context.Background()), outRefPolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{""}, kupo.Queries(policyPath))
+}
+
+func TestGetUtxosByAddressExtended(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{})
+	kupo.route("/v1/matches/"+adapterTestAddr, fmt.Sprintf(`[{
+		"transaction_id": %q,
+		"output_index": 0,
+		"address": %q,
+		"value": {"coins": 2000000},
+		"created_at": {"slot_no": 10, "header_hash": %q},
+		"spent_at": null
+	}, {
+		"transaction_id": %q,
+		"output_index": 1,
+		"address": %q,
+		"value": {"coins": 3000000},
+		"created_at": {"slot_no": 11, "header_hash": %q},
+		"spent_at": {"slot_no": 20, "header_hash": %q, "transaction_id": %q, "input_index": 0}
+	}]`,
+		outRefSpent.TxHash, adapterTestAddr, strings.Repeat("01", 32),
+		outRefSpent.TxHash, adapterTestAddr, strings.Repeat("03", 32),
+		strings.Repeat("02", 32), spendingTx,
+	))
+
+	utxos, err := kp.GetUtxosByAddressExtended(
+		WithSpentMatches(context.Background()),
+		adapterTestAddr,
+	)
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 2)
+	assert.Equal(t, uint64(10), utxos[0].CreatedAtSlot)
+	assert.Equal(t, strings.Repeat("01", 32), utxos[0].CreatedAtHeaderHash)
+	assert.Equal(t, uint64(0), utxos[0].SpentAtSlot)
+	assert.Equal(t, uint64(11), utxos[1].CreatedAtSlot)
+	assert.Equal(t, strings.Repeat("03", 32), utxos[1].CreatedAtHeaderHash)
+	assert.Equal(t, uint64(20), utxos[1].SpentAtSlot)
+	assert.Equal(t, uint32(1), utxos[1].Utxo.Id.Index())
+}
@@ -0,0 +1,353 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gorilla/websocket"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+var _ connector.BlockStreamer = (*KupmiosProvider)(nil)
+
+const (
+	// streamRetryDelay and maxStreamRetryDelay bound the wait between
+	// reconnection attempts of a block stream.
+	streamRetryDelay    = time.Second
+	maxStreamRetryDelay = 30 * time.Second
+)
+
+// ogmiosIntersectionNotFound is the Ogmios findIntersection error code for
+// points none of which are on the chain.
+const ogmiosIntersectionNotFound = 1000
+
+// ogmiosNextBlock is the Ogmios chain-sync nextBlock response.
+type ogmiosNextBlock struct {
+	Result *struct {
+		Direction string `json:"direction"`
+		Block     *struct {
+			ID           string `json:"id"`
+			Slot         uint64 `json:"slot"`
+			Height       uint64 `json:"height"`
+			Transactions []struct {
+				ID           string              `json:"id"`
+				Certificates []ogmiosCertificate `json:"certificates"`
+			} `json:"transactions"`
+		} `json:"block"`
+		Point json.RawMessage `json:"point"`
+	} `json:"result"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// StreamBlocks follows the chain over the Ogmios chain-sync protocol. A lost
+// connection is re-established with growing delays and resumes from the last
+// delivered block; a rollback that happened meanwhile is delivered as a
+// BlockEventRollBackward. The channel is closed once ctx ends or Close is
+// called, or after a final event whose Err wraps ErrNotFound when none of the
+// points the stream could resume from is on the chain any more.
+func (kp *KupmiosProvider) StreamBlocks(
+	ctx context.Context,
+	from connector.ChainPoint,
+) (<-chan connector.BlockEvent, error) {
+	select {
+	case <-kp.done:
+		return nil, errors.New("kupmios: provider is closed")
+	default:
+	}
+
+	start := from
+	if start == (connector.ChainPoint{}) {
+		tip, err := kp.GetTip(ctx)
+		if err != nil {
+			return nil, err
+		}
+		start = connector.ChainPoint{Slot: tip.Slot, Hash: tip.Hash}
+	}
+
+	conn, err := kp.findIntersection(ctx, []connector.ChainPoint{start})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan connector.BlockEvent)
+	go kp.runBlockStream(ctx, conn, start, events)
+	return events, nil
+}
+
+// Close stops every block stream started with StreamBlocks and makes further
+// calls to it fail. The provider's other methods are unaffected.
+func (kp *KupmiosProvider) Close() error {
+	kp.closeOnce.Do(func() { close(kp.done) })
+	return nil
+}
+
+// runBlockStream delivers the events read from conn, reconnecting whenever
+// the connection fails, until ctx ends, the provider is closed or no resume
+// point is left on the chain.
+func (kp *KupmiosProvider) runBlockStream(
+	ctx context.Context,
+	conn *websocket.Conn,
+	start connector.ChainPoint,
+	events chan<- connector.BlockEvent,
+) {
+	defer close(events)
+
+	resume := connector.NewResumePoints(start)
+	delay := streamRetryDelay
+	for {
+		err := kp.followChain(ctx, conn, resume, events)
+		if kp.streamStopped(ctx) {
+			return
+		}
+		kp.logger.Warn("kupmios: block stream lost its Ogmios connection",
+			"err", err)
+
+		conn = nil
+		for conn == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-kp.done:
+				return
+			case <-time.After(delay):
+			}
+			delay = min(2*delay, maxStreamRetryDelay)
+
+			// Offer the most recently delivered point first.
+			points := resume.Points()
+			slices.Reverse(points)
+			conn, err = kp.findIntersection(ctx, points)
+			if errors.Is(err, connector.ErrNotFound) {
+				// The chain rolled back past every point the stream could
+				// resume from; retrying cannot help.
+				kp.logger.Error("kupmios: block stream cannot resume",
+					"err", err)
+				select {
+				case events <- connector.BlockEvent{Err: err}:
+				case <-ctx.Done():
+				case <-kp.done:
+				}
+				return
+			}
+			if err != nil {
+				kp.logger.Warn("kupmios: block stream failed to reconnect",
+					"err", err)
+			}
+		}
+		delay = streamRetryDelay
+	}
+}
+
+// streamStopped reports whether a block stream should end.
+func (kp *KupmiosProvider) streamStopped(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	case <-kp.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// followChain requests blocks on conn and delivers them until reading fails
+// or the stream is stopped. It always closes conn.
+func (kp *KupmiosProvider) followChain(
+	ctx context.Context,
+	conn *websocket.Conn,
+	resume *connector.ResumePoints,
+	events chan<- connector.BlockEvent,
+) error {
+	// Closing the connection unblocks a pending read once the stream stops.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-kp.done:
+		case <-stop:
+		}
+		conn.Close()
+	}()
+
+	// Ogmios answers the first nextBlock after an intersection with a
+	// rollback to the intersection itself.
+	intersecting := true
+	for {
+		var response ogmiosNextBlock
+		if err := ogmiosCall(conn, "nextBlock", nil, &response); err != nil {
+			return err
+		}
+		event, err := blockEvent(response)
+		if err != nil {
+			return err
+		}
+
+		if intersecting {
+			intersecting = false
+			if resume.IsResumeEcho(event) {
+				continue
+			}
+		}
+		resume.Track(event)
+
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-kp.done:
+			return nil
+		}
+	}
+}
+
+// blockEvent translates a nextBlock response into a BlockEvent.
+func blockEvent(response ogmiosNextBlock) (connector.BlockEvent, error) {
+	if response.Error != nil {
+		return connector.BlockEvent{}, fmt.Errorf(
+			"ogmios nextBlock failed (code %d): %s",
+			response.Error.Code,
+			response.Error.Message,
+		)
+	}
+	result := response.Result
+	if result == nil {
+		return connector.BlockEvent{}, fmt.Errorf(
+			"%w: empty Ogmios nextBlock response",
+			connector.ErrProviderInternal,
+		)
+	}
+
+	switch result.Direction {
+	case "forward":
+		if result.Block == nil {
+			return connector.BlockEvent{}, fmt.Errorf(
+				"%w: Ogmios roll-forward without a block",
+				connector.ErrProviderInternal,
+			)
+		}
+		event := connector.BlockEvent{
+			Type: connector.BlockEventRollForward,
+			Point: connector.ChainPoint{
+				Slot: result.Block.Slot,
+				Hash: result.Block.ID,
+			},
+			Height: result.Block.Height,
+		}
+		for _, tx := range result.Block.Transactions {
+			event.TxHashes = append(event.TxHashes, tx.ID)
+		}
+		return event, nil
+	case "backward":
+		point, err := parseOgmiosPoint(result.Point)
+		if err != nil {
+			return connector.BlockEvent{}, err
+		}
+		return connector.BlockEvent{
+			Type:  connector.BlockEventRollBackward,
+			Point: point,
+		}, nil
+	default:
+		return connector.BlockEvent{}, fmt.Errorf(
+			"%w: unknown Ogmios nextBlock direction %q",
+			connector.ErrProviderInternal,
+			result.Direction,
+		)
+	}
+}
+
+// parseOgmiosPoint decodes an Ogmios point, which is either "origin" or
+// {"slot": ..., "id": ...}. The origin is the zero ChainPoint.
+func parseOgmiosPoint(raw json.RawMessage) (connector.ChainPoint, error) {
+	var origin string
+	if err := json.Unmarshal(raw, &origin); err == nil {
+		if origin != "origin" {
+			return connector.ChainPoint{}, fmt.Errorf(
+				"%w: unknown Ogmios point %q",
+				connector.ErrProviderInternal,
+				origin,
+			)
+		}
+		return connector.ChainPoint{}, nil
+	}
+	var point struct {
+		Slot uint64 `json:"slot"`
+		ID   string `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &point); err != nil {
+		return connector.ChainPoint{}, fmt.Errorf(
+			"%w: invalid Ogmios point %s: %w",
+			connector.ErrProviderInternal,
+			string(raw),
+			err,
+		)
+	}
+	return connector.ChainPoint{Slot: point.Slot, Hash: point.ID}, nil
+}
+
+// findIntersection opens a chain-sync connection positioned at the most
+// recent of points that is on the chain, or at the origin when points is
+// empty. Points are tried most recent first.
+func (kp *KupmiosProvider) findIntersection(
+	ctx context.Context,
+	points []connector.ChainPoint,
+) (*websocket.Conn, error) {
+	conn, err := kp.dialOgmios(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("kupmios: failed to connect to Ogmios: %w", err)
+	}
+
+	wirePoints := make([]any, 0, len(points))
+	for _, point := range points {
+		if point == (connector.ChainPoint{}) {
+			wirePoints = append(wirePoints, "origin")
+			continue
+		}
+		wirePoints = append(wirePoints, map[string]any{
+			"slot": point.Slot,
+			"id":   point.Hash,
+		})
+	}
+	if len(wirePoints) == 0 {
+		wirePoints = append(wirePoints, "origin")
+	}
+
+	var response struct {
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := ogmiosCall(
+		conn,
+		"findIntersection",
+		map[string]any{"points": wirePoints},
+		&response,
+	); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("kupmios: %w", err)
+	}
+	if response.Error != nil {
+		conn.Close()
+		if response.Error.Code == ogmiosIntersectionNotFound {
+			return nil, fmt.Errorf(
+				"kupmios: %w: no intersection with the chain at %v",
+				connector.ErrNotFound,
+				points,
+			)
+		}
+		return nil, fmt.Errorf(
+			"kupmios: ogmios findIntersection failed (code %d): %s",
+			response.Error.Code,
+			response.Error.Message,
+		)
+	}
+	return conn, nil
+}
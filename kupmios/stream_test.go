@@ -0,0 +1,265 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+var (
+	streamStart = connector.ChainPoint{Slot: 10, Hash: strings.Repeat("10", 32)}
+	streamA     = connector.ChainPoint{Slot: 11, Hash: strings.Repeat("11", 32)}
+	streamB     = connector.ChainPoint{Slot: 12, Hash: strings.Repeat("12", 32)}
+	streamB2    = connector.ChainPoint{Slot: 13, Hash: strings.Repeat("13", 32)}
+)
+
+func forwardMsg(point connector.ChainPoint, height uint64, txs ...string) any {
+	transactions := make([]map[string]any, len(txs))
+	for i, tx := range txs {
+		transactions[i] = map[string]any{"id": tx}
+	}
+	return map[string]any{
+		"direction": "forward",
+		"block": map[string]any{
+			"id":           point.Hash,
+			"slot":         point.Slot,
+			"height":       height,
+			"transactions": transactions,
+		},
+	}
+}
+
+func backwardMsg(point connector.ChainPoint) any {
+	return map[string]any{
+		"direction": "backward",
+		"point":     map[string]any{"slot": point.Slot, "id": point.Hash},
+	}
+}
+
+// serveChainSync answers findIntersection with success and nextBlock with
+// msgs in turn, across connections, then with an error.
+func serveChainSync(ogmios *mockOgmios, msgs ...any) {
+	var next atomic.Int64
+	ogmios.handle("findIntersection", func(json.RawMessage) any {
+		return map[string]any{
+			"intersection": map[string]any{"slot": streamStart.Slot, "id": streamStart.Hash},
+		}
+	})
+	ogmios.handle("nextBlock", func(json.RawMessage) any {
+		i := next.Add(1) - 1
+		if i >= int64(len(msgs)) {
+			return mockRPCError{Code: -32000, Message: "connection lost"}
+		}
+		return msgs[i]
+	})
+}
+
+func receiveEvents(t *testing.T, events <-chan connector.BlockEvent, n int) []connector.BlockEvent {
+	t.Helper()
+	got := make([]connector.BlockEvent, 0, n)
+	for len(got) < n {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatalf("stream closed after %d events", len(got))
+			}
+			got = append(got, event)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out after %d events", len(got))
+		}
+	}
+	return got
+}
+
+func assertClosed(t *testing.T, events <-chan connector.BlockEvent) {
+	t.Helper()
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok, "expected the stream to be closed")
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream was not closed")
+	}
+}
+
+func TestStreamBlocksTranslatesRollbacks(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	serveChainSync(ogmios,
+		backwardMsg(streamStart),
+		forwardMsg(streamA, 1, "aa"),
+		forwardMsg(streamB, 2, "bb", "cc"),
+		backwardMsg(streamA),
+		forwardMsg(streamB2, 2),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := kp.StreamBlocks(ctx, streamStart)
+	assert.NoError(t, err)
+
+	got := receiveEvents(t, events, 4)
+	assert.Equal(t, []connector.BlockEvent{
+		{Type: connector.BlockEventRollForward, Point: streamA, Height: 1, TxHashes: []string{"aa"}},
+		{Type: connector.BlockEventRollForward, Point: streamB, Height: 2, TxHashes: []string{"bb", "cc"}},
+		{Type: connector.BlockEventRollBackward, Point: streamA},
+		{Type: connector.BlockEventRollForward, Point: streamB2, Height: 2},
+	}, got)
+
+	cancel()
+	assertClosed(t, events)
+}
+
+func TestStreamBlocksResumesAfterReconnect(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	var connections atomic.Int64
+	var next atomic.Int64
+	msgs := [][]any{
+		{backwardMsg(streamStart), forwardMsg(streamA, 1)},
+		{backwardMsg(streamA), forwardMsg(streamB, 2)},
+	}
+	ogmios.handle("findIntersection", func(json.RawMessage) any {
+		connections.Add(1)
+		next.Store(0)
+		return map[string]any{}
+	})
+	ogmios.handle("nextBlock", func(json.RawMessage) any {
+		conn := connections.Load() - 1
+		i := next.Add(1) - 1
+		if conn >= int64(len(msgs)) || i >= int64(len(msgs[conn])) {
+			return mockRPCError{Code: -32000, Message: "connection lost"}
+		}
+		return msgs[conn][i]
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := kp.StreamBlocks(ctx, streamStart)
+	assert.NoError(t, err)
+
+	got := receiveEvents(t, events, 2)
+	assert.Equal(t, streamA, got[0].Point)
+	assert.Equal(t, connector.BlockEventRollForward, got[1].Type)
+	assert.Equal(t, streamB, got[1].Point)
+
+	calls := ogmios.Calls("findIntersection")
+	assert.GreaterOrEqual(t, len(calls), 2)
+	var params struct {
+		Points []struct {
+			Slot uint64 `json:"slot"`
+			ID   string `json:"id"`
+		} `json:"points"`
+	}
+	assert.NoError(t, json.Unmarshal(calls[1], &params))
+	assert.Len(t, params.Points, 2)
+	assert.Equal(t, streamA.Hash, params.Points[0].ID)
+	assert.Equal(t, streamStart.Hash, params.Points[1].ID)
+}
+
+func TestStreamBlocksEndsWhenResumePointsAreGone(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	var connections atomic.Int64
+	ogmios.handle("findIntersection", func(json.RawMessage) any {
+		if connections.Add(1) > 1 {
+			return mockRPCError{Code: 1000, Message: "No intersection found."}
+		}
+		return map[string]any{}
+	})
+	var next atomic.Int64
+	msgs := []any{backwardMsg(streamStart), forwardMsg(streamA, 1)}
+	ogmios.handle("nextBlock", func(json.RawMessage) any {
+		i := next.Add(1) - 1
+		if i >= int64(len(msgs)) {
+			return mockRPCError{Code: -32000, Message: "connection lost"}
+		}
+		return msgs[i]
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := kp.StreamBlocks(ctx, streamStart)
+	assert.NoError(t, err)
+
+	got := receiveEvents(t, events, 2)
+	assert.Equal(t, streamA, got[0].Point)
+	assert.Empty(t, got[1].Type)
+	assert.True(t, errors.Is(got[1].Err, connector.ErrNotFound), "got %v", got[1].Err)
+	assertClosed(t, events)
+	assert.Equal(t, int64(2), connections.Load())
+}
+
+func TestStreamBlocksUnknownStartPoint(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	ogmios.handle("findIntersection", func(json.RawMessage) any {
+		return mockRPCError{Code: 1000, Message: "No intersection found."}
+	})
+
+	_, err := kp.StreamBlocks(context.Background(), streamStart)
+	assert.True(t, errors.Is(err, connector.ErrNotFound), "got %v", err)
+}
+
+func TestStreamBlocksStopsOnClose(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	serveChainSync(ogmios, backwardMsg(streamStart), forwardMsg(streamA, 1))
+
+	events, err := kp.StreamBlocks(context.Background(), streamStart)
+	assert.NoError(t, err)
+	receiveEvents(t, events, 1)
+
+	assert.NoError(t, kp.Close())
+	assertClosed(t, events)
+
+	_, err = kp.StreamBlocks(context.Background(), streamStart)
+	assert.Error(t, err)
+}
+
+func TestBlockEventRollbackToOrigin(t *testing.T) {
+	var response ogmiosNextBlock
+	assert.NoError(t, json.Unmarshal(
+		[]byte(`{"jsonrpc":"2.0","method":"nextBlock","result":{"direction":"backward","point":"origin","tip":"origin"}}`),
+		&response,
+	))
+	event, err := blockEvent(response)
+	assert.NoError(t, err)
+	assert.Equal(t, connector.BlockEvent{Type: connector.BlockEventRollBackward}, event)
+
+	resume := connector.NewResumePoints(streamStart)
+	resume.Track(connector.BlockEvent{Type: connector.BlockEventRollForward, Point: streamA})
+	resume.Track(event)
+	assert.Empty(t, resume.Points())
+}
+
+func TestResumePointsRollback(t *testing.T) {
+	resume := connector.NewResumePoints(streamStart)
+	for _, point := range []connector.ChainPoint{streamA, streamB} {
+		resume.Track(connector.BlockEvent{Type: connector.BlockEventRollForward, Point: point})
+	}
+	rollback := connector.BlockEvent{Type: connector.BlockEventRollBackward, Point: streamA}
+	resume.Track(rollback)
+	assert.Equal(t, []connector.ChainPoint{streamStart, streamA}, resume.Points())
+	assert.True(t, resume.IsResumeEcho(rollback))
+
+	resume.Track(connector.BlockEvent{
+		Type:  connector.BlockEventRollForward,
+		Point: streamB2,
+	})
+	assert.Equal(t, []connector.ChainPoint{streamStart, streamA, streamB2}, resume.Points())
+	assert.False(t, resume.IsResumeEcho(rollback))
+}
+
+func TestResumePointsBounded(t *testing.T) {
+	resume := connector.NewResumePoints(streamStart)
+	for slot := range uint64(2 * connector.MaxResumePoints) {
+		resume.Track(connector.BlockEvent{
+			Type:  connector.BlockEventRollForward,
+			Point: connector.ChainPoint{Slot: streamStart.Slot + 1 + slot},
+		})
+	}
+	points := resume.Points()
+	assert.Len(t, points, connector.MaxResumePoints)
+	assert.Equal(t, streamStart.Slot+2*connector.MaxResumePoints, points[len(points)-1].Slot)
+}
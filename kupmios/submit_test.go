@@ -0,0 +1,151 @@
+package kupmios
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+func TestSubmitTxMapsOgmiosErrors(t *testing.T) {
+	cases := []struct {
+		code   int
+		reason string
+		want   error
+	}{
+		{3010, "ScriptExecutionFailure", connector.ErrEvaluationFailed},
+		{3117, "UnknownOutputReferences", connector.ErrBadInputs},
+		{3118, "OutsideOfValidityInterval", connector.ErrOutsideValidityInterval},
+		{3119, "TransactionTooLarge", connector.ErrTxTooLarge},
+		{3121, "EmptyInputSet", connector.ErrBadInputs},
+		{3122, "TransactionFeeTooSmall", connector.ErrFeeTooSmall},
+		{3123, "ValueNotConserved", connector.ErrValueNotConserved},
+	}
+	for _, tc := range cases {
+		t.Run(tc.reason, func(t *testing.T) {
+			kp, ogmios, _ := newMockKupmios(t, Config{})
+			ogmios.handle("submitTransaction", func(json.RawMessage) any {
+				return mockRPCError{
+					Code:    tc.code,
+					Message: "rejected: " + tc.reason,
+					Data:    map[string]any{"detail": tc.reason},
+				}
+			})
+
+			_, err := kp.SubmitTx(context.Background(), []byte{0x84})
+			assert.True(t, errors.Is(err, connector.ErrTxSubmissionFailed), "got %v", err)
+			assert.True(t, errors.Is(err, tc.want), "got %v", err)
+			var subErr *connector.SubmissionError
+			assert.True(t, errors.As(err, &subErr))
+			assert.Equal(t, []string{tc.reason}, subErr.Reasons)
+			assert.Contains(t, subErr.Message, "rejected: "+tc.reason)
+		})
+	}
+}
+
+// Ogmios v6 submitTransaction rejections, as returned by a preprod node.
+const (
+	ogmiosUnknownOutputReferences = `{
+		"code": 3117,
+		"message": "The transaction contains unknown UTxO references as inputs. This can happen if the inputs you're trying to spend have already been spent, or if you've simply referred to non-existing UTxO altogether. The field 'data.unknownOutputReferences' indicates all unknown inputs.",
+		"data": {"unknownOutputReferences": [
+			{"transaction": {"id": "0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a"}, "index": 0},
+			{"transaction": {"id": "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b"}, "index": 3}
+		]}
+	}`
+	ogmiosValueNotConserved = `{
+		"code": 3123,
+		"message": "In and out value not conserved. The transaction must balance exactly; the field 'data.valueConsumed' and 'data.valueProduced' indicate the total value consumed and produced.",
+		"data": {
+			"valueConsumed": {"ada": {"lovelace": 10000000}},
+			"valueProduced": {"ada": {"lovelace": 9000000}}
+		}
+	}`
+	ogmiosFeeTooSmall = `{
+		"code": 3122,
+		"message": "Insufficient fee! The transaction does not contain enough fee. The field 'data.minimumRequiredFee' indicates the minimum required fee.",
+		"data": {
+			"minimumRequiredFee": {"ada": {"lovelace": 171573}},
+			"providedFee": {"ada": {"lovelace": 100000}}
+		}
+	}`
+)
+
+func TestSubmitTxCapturedRejections(t *testing.T) {
+	cases := []struct {
+		name      string
+		payload   string
+		want      error
+		badInputs []connector.OutRef
+	}{
+		{
+			name:    "unknown output references",
+			payload: ogmiosUnknownOutputReferences,
+			want:    connector.ErrBadInputs,
+			badInputs: []connector.OutRef{
+				{TxHash: strings.Repeat("0a", 32), Index: 0},
+				{TxHash: strings.Repeat("0b", 32), Index: 3},
+			},
+		},
+		{name: "value not conserved", payload: ogmiosValueNotConserved, want: connector.ErrValueNotConserved},
+		{name: "fee too small", payload: ogmiosFeeTooSmall, want: connector.ErrFeeTooSmall},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var rpcErr mockRPCError
+			assert.NoError(t, json.Unmarshal([]byte(tc.payload), &rpcErr))
+			kp, ogmios, _ := newMockKupmios(t, Config{})
+			ogmios.handle("submitTransaction", func(json.RawMessage) any {
+				return rpcErr
+			})
+
+			_, err := kp.SubmitTx(context.Background(), []byte{0x84})
+			assert.True(t, errors.Is(err, tc.want), "got %v", err)
+			var subErr *connector.SubmissionError
+			assert.True(t, errors.As(err, &subErr))
+			assert.Equal(t, tc.badInputs, subErr.BadInputs)
+			assert.Contains(t, subErr.Message, rpcErr.Message)
+		})
+	}
+}
+
+func TestSubmitTxUnclassifiedRejection(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	ogmios.handle("submitTransaction", func(json.RawMessage) any {
+		return mockRPCError{Code: 3100, Message: "invalid signatories"}
+	})
+
+	_, err := kp.SubmitTx(context.Background(), []byte{0x84})
+	assert.True(t, errors.Is(err, connector.ErrTxSubmissionFailed), "got %v", err)
+	var subErr *connector.SubmissionError
+	assert.True(t, errors.As(err, &subErr))
+	assert.Nil(t, subErr.Kind)
+	assert.Contains(t, err.Error(), "3100")
+	assert.Contains(t, err.Error(), "invalid signatories")
+}
+
+func TestSubmitTxTransportError(t *testing.T) {
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	ogmios.Close()
+
+	_, err := kp.SubmitTx(context.Background(), []byte{0x84})
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, connector.ErrTxSubmissionFailed), "got %v", err)
+	assert.Contains(t, err.Error(), "failed to connect to ogmios")
+}
+
+func TestSubmitTxSuccess(t *testing.T) {
+	txHash := strings.Repeat("0f", 32)
+	kp, ogmios, _ := newMockKupmios(t, Config{})
+	ogmios.handle("submitTransaction", func(json.RawMessage) any {
+		return map[string]any{"transaction": map[string]any{"id": txHash}}
+	})
+
+	got, err := kp.SubmitTx(context.Background(), []byte{0x84})
+	assert.NoError(t, err)
+	assert.Equal(t, txHash, got)
+}
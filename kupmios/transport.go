@@ -0,0 +1,79 @@
+package kupmios
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ogmiosHandshakeTimeout matches the handshake timeout of
+// websocket.DefaultDialer.
+const ogmiosHandshakeTimeout = 45 * time.Second
+
+// newOgmiosDialer returns the dialer for the Ogmios websocket connections the
+// provider opens itself.
+func newOgmiosDialer(tlsConfig *tls.Config) *websocket.Dialer {
+	return &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: ogmiosHandshakeTimeout,
+		TLSClientConfig:  tlsConfig,
+	}
+}
+
+// newKupoClient returns the client for the Kupo requests the provider issues
+// itself when Config.HTTPClient is nil.
+func newKupoClient(tlsConfig *tls.Config) *http.Client {
+	client := &http.Client{Timeout: defaultKupoTimeout}
+	if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		client.Transport = transport
+	}
+	return client
+}
+
+// httpHeaders converts the header map of Config into an http.Header.
+func httpHeaders(headers map[string]string) http.Header {
+	if len(headers) == 0 {
+		return nil
+	}
+	h := make(http.Header, len(headers))
+	for name, value := range headers {
+		h.Set(name, value)
+	}
+	return h
+}
+
+// setHeaders adds headers to req, keeping the headers req already has.
+func setHeaders(req *http.Request, headers http.Header) {
+	for name, values := range headers {
+		if req.Header.Get(name) == "" {
+			req.Header[name] = values
+		}
+	}
+}
+
+// dialOgmios opens a websocket connection to Ogmios with the configured
+// headers and TLS settings.
+func (kp *KupmiosProvider) dialOgmios(ctx context.Context) (*websocket.Conn, error) {
+	conn, _, err := kp.ogmiosDialer.DialContext(
+		ctx,
+		kp.ogmiosEndpoint,
+		kp.ogmiosHeaders,
+	)
+	return conn, err
+}
+
+// redactEndpoint returns endpoint with any password in it masked, for error
+// messages and logs. Header values are never included in either.
+func redactEndpoint(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	return u.Redacted()
+}
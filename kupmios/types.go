@@ -1,23 +1,190 @@
 package kupmios
 
 import (
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
 	"github.com/SundaeSwap-finance/kugo"
 	"github.com/SundaeSwap-finance/ogmigo/v6"
+	"github.com/gorilla/websocket"
+	connector "github.com/zenGate-Global/cardano-connector-go"
 )
 
 type KupmiosProvider struct {
-	ogmigoClient   *ogmigo.Client
-	kugoClient     *kugo.Client
-	ogmiosEndpoint string
-	networkId      int
+	ogmigoClient          *ogmigo.Client
+	kugoClient            *kugo.Client
+	httpClient            *http.Client
+	ogmiosDialer          *websocket.Dialer
+	ogmiosHeaders         http.Header
+	kupoHeaders           http.Header
+	fetcher               chainFetcher
+	kupo                  *kupoFetcher
+	ogmiosEndpoint        string
+	kupoEndpoint          string
+	networkId             int
+	logger                *slog.Logger
+	strictScripts         bool
+	strictDatums          bool
+	lenientOutRefs        bool
+	lenientQuantities     bool
+	maxConcurrentRequests int
+	confirmationSlots     uint64
+	awaitTxOgmiosFallback bool
+	awaitTxMempool        bool
+	awaitTxRecheck        bool
+	maxKupoLag            uint64
+	kupoSyncGuardSlots    uint64
+	skipNetworkCheck      bool
+	kupoChunkSlots        uint64
+	retry                 connector.RetryPolicy
+	instrumentation       Instrumentation
+
+	kupoSyncMu sync.Mutex
+	kupoSync   KupoHealth
+	kupoSyncAt time.Time
+
+	systemStartMu sync.Mutex
+	systemStart   time.Time
+
+	// done is closed by Close to stop the block streams.
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
 type Config struct {
 	OgmigoEndpoint string
 	KupoEndpoint   string
-	NetworkId      int
+	// NetworkId is the network id of Cardano address headers: 1 for
+	// mainnet and 0 for the test networks, preprod and preview among them.
+	// Unlike the Blockfrost and UTxO RPC providers, which take an apollo
+	// constants.Network, Kupmios reads reward addresses and checks the
+	// network Ogmios follows against it, and Network returns it as is.
+	NetworkId int
+	// Logger receives the provider's diagnostics. Defaults to a logger that
+	// discards everything.
+	Logger *slog.Logger
+	// StrictScriptRefs fails UTxO reads whose reference script cannot be
+	// resolved or parsed. By default such UTxOs are returned with a nil
+	// reference script and a warning is logged.
+	StrictScriptRefs bool
+	// StrictDatums makes GetDatums fail with connector.ErrNotFound when any
+	// of the requested datums is unknown to Kupo. By default unknown datums
+	// are left out of the result.
+	StrictDatums bool
+	// LenientOutRefs makes GetUtxosByOutRef log and skip out-refs whose Kupo
+	// lookup fails, or whose Ogmios UTxO cannot be decoded, instead of
+	// returning the failures as errors.
+	LenientOutRefs bool
+	// LenientQuantities clamps lovelace quantities beyond the int64 range to
+	// math.MaxInt64 and logs a warning. By default such a quantity fails the
+	// read with connector.ErrProviderInternal.
+	LenientQuantities bool
+	// MaxConcurrentRequests bounds how many Kupo requests a single call
+	// issues in parallel, including how many matches of an address scan
+	// are converted, with their datum and script lookups, at once. Zero
+	// selects a default of 8; negative values are rejected by New.
+	MaxConcurrentRequests int
+	// ConfirmationSlots is how many slots the tip must be past the slot that
+	// includes a transaction before AwaitTx reports it as confirmed. Zero
+	// reports a transaction as soon as it is on chain.
+	ConfirmationSlots int
+	// AwaitTxOgmiosFallback makes AwaitTx look a transaction up in the Ogmios
+	// ledger state when Kupo has no match for it. Costs one extra Ogmios
+	// query per poll while the transaction is not indexed by Kupo.
+	AwaitTxOgmiosFallback bool
+	// AwaitTxMempool makes AwaitTx ask the Ogmios mempool whether a
+	// transaction is still pending before looking for it on chain. Leave it
+	// off for Ogmios deployments that disable mempool monitoring; failed
+	// mempool lookups fall back to the chain lookups.
+	AwaitTxMempool bool
+	// AwaitTxRecheck makes AwaitTx look a transaction up once more on the
+	// poll after it first reaches ConfirmationSlots, and report it only if
+	// it is still on chain in the same slot. A transaction that was rolled
+	// back in between is waited for again. Costs one more poll per call.
+	AwaitTxRecheck bool
+	// ChainCacheSize bounds how many datums and how many scripts fetched from
+	// Kupo by hash are kept in memory. Zero selects a default of 1024.
+	ChainCacheSize int
+	// DisableChainCache fetches every datum and script from Kupo, without
+	// caching or sharing concurrent lookups.
+	DisableChainCache bool
+	// MaxKupoLag is how many slots Kupo's most recent checkpoint may trail
+	// the node tip before HealthCheck reports the provider unhealthy. The
+	// node tip is the furthest one Ogmios or Kupo has seen, as in
+	// Health.SlotLag, so a Kupo whose own node is stuck is caught too. Zero
+	// selects a default of 120; negative values are rejected by New.
+	MaxKupoLag int
+	// KupoSyncGuardSlots, when positive, makes the methods that read UTxOs
+	// from Kupo fail with ErrProviderSyncing while Kupo's most recent
+	// checkpoint trails Kupo's own view of the node tip, KupoHealth.Lag, by
+	// more than this many slots, rather than return a possibly incomplete
+	// set. Unlike MaxKupoLag it only asks Kupo, so that guarded reads cost
+	// no Ogmios request. Zero disables the guard; negative values are
+	// rejected by New.
+	KupoSyncGuardSlots int
+	// KupoChunkSlots splits the Kupo queries of GetUtxosByAddress and
+	// GetUtxosByPolicy into windows of this many slots by creation slot,
+	// requested one after the other, so that addresses and policies with
+	// very many outputs are not fetched in one response. Results come
+	// grouped by window, oldest first. Costs one request per window up to
+	// Kupo's most recent checkpoint. Zero fetches everything at once;
+	// negative values are rejected by New.
+	KupoChunkSlots int
+	// ValidateOnNew makes New check that Ogmios and Kupo answer their health
+	// endpoints, that the network Ogmios follows matches NetworkId and that
+	// Kupo indexes the chain Ogmios follows, failing with ErrUnhealthy or
+	// ErrNetworkMismatch otherwise.
+	ValidateOnNew bool
+	// SkipNetworkCheck limits ValidateOnNew to the health endpoints, for
+	// setups such as local devnets whose network or chain cannot be
+	// compared.
+	SkipNetworkCheck bool
+	// OgmigoOptions are applied to the Ogmios client after the endpoint
+	// option, e.g. ogmigo.WithLogger or ogmigo.WithPipeline. An
+	// ogmigo.WithEndpoint here overrides OgmigoEndpoint for the client, but
+	// the queries the provider sends over its own websocket connections
+	// still go to OgmigoEndpoint.
+	OgmigoOptions []ogmigo.Option
+	// KugoOptions are applied to the Kupo client after the endpoint option,
+	// e.g. kugo.WithTimeout or kugo.WithLogger. That client serves the match
+	// queries; datums, scripts and HealthCheck are fetched from KupoEndpoint
+	// with HTTPClient.
+	KugoOptions []kugo.Option
+	// HTTPClient makes the Kupo requests the provider issues itself. Defaults
+	// to a client with kugo's five-minute timeout.
+	HTTPClient *http.Client
+	// OgmiosHeaders and KupoHeaders are sent with the requests the provider
+	// issues itself: the Ogmios websocket connections for chain-sync,
+	// mempool and ledger queries, the Kupo datum, script and checkpoint
+	// lookups and chunked match queries, and the health checks. Use them
+	// for the API key headers of hosted services. The ogmigo and kugo
+	// clients offer no way to add headers and do not send them. Header
+	// values are never logged.
+	OgmiosHeaders map[string]string
+	KupoHeaders   map[string]string
+	// TLSConfig secures the wss and https connections the provider opens
+	// itself, e.g. to trust a private CA or present a client certificate.
+	// With HTTPClient set, its transport's TLS settings apply to Kupo
+	// instead.
+	TLSConfig *tls.Config
+	// Retry retries reads that fail transiently: dropped or refused Ogmios
+	// and Kupo connections, and Kupo answering 500, 502, 503 or 504. It is
+	// disabled unless Retry.MaxAttempts is above one. SubmitTx and
+	// EvaluateTx are never retried.
+	Retry connector.RetryPolicy
+	// Instrumentation, when set, is told about every request the provider
+	// sends to Ogmios and Kupo, with its duration and outcome.
+	Instrumentation Instrumentation
 }
 
+// RetryPolicy is the type of Config.Retry. It is connector.RetryPolicy, shared
+// with the other providers; the name is kept so that code written against
+// kupmios.RetryPolicy still compiles.
+type RetryPolicy = connector.RetryPolicy
+
 // ogmiosProtocolParams mirrors the subset of the Ogmios
 // queryLedgerState/protocolParameters response that we map onto
 // backend.ProtocolParameters.
@@ -40,7 +207,6 @@ type ogmiosProtocolParams struct {
 	MaxTxExUnits       ogmiosExUnits          `json:"maxExecutionUnitsPerTransaction"`
 	MaxBlockExUnits    ogmiosExUnits          `json:"maxExecutionUnitsPerBlock"`
 	MinUtxoDeposit     int64                  `json:"minUtxoDepositCoefficient"`
-	MinUtxoConstant    ogmiosLovelace         `json:"minUtxoDepositConstant"`
 	MaxRefScriptsSize  ogmiosBytes            `json:"maxReferenceScriptsSize"`
 	MinFeeRefScripts   ogmiosMinFeeRefScripts `json:"minFeeReferenceScripts"`
 	Version            ogmiosVersion          `json:"version"`
@@ -83,21 +249,3 @@ type ogmiosVersion struct {
 	Major int `json:"major"`
 	Minor int `json:"minor"`
 }
-
-// ogmiosGenesisConfig mirrors the subset of the Ogmios shelley genesis
-// configuration that we map onto backend.GenesisParameters.
-type ogmiosGenesisConfig struct {
-	StartTime         string `json:"startTime"`
-	NetworkMagic      int    `json:"networkMagic"`
-	EpochLength       int    `json:"epochLength"`
-	SlotsPerKesPeriod int    `json:"slotsPerKesPeriod"`
-	MaxKesEvolutions  int    `json:"maxKesEvolutions"`
-	SecurityParam     int    `json:"securityParameter"`
-	UpdateQuorum      int    `json:"updateQuorum"`
-	// ActiveSlotsCoefficient is a fraction like "1/20".
-	ActiveSlots       string `json:"activeSlotsCoefficient"`
-	MaxLovelaceSupply int64  `json:"maxLovelaceSupply"`
-	SlotLength        struct {
-		Milliseconds int `json:"milliseconds"`
-	} `json:"slotLength"`
-}
@@ -0,0 +1,137 @@
+package kupmios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/tj/assert"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// ftName is the hex asset name of a fungible token under outRefPolicy.
+const ftName = "465400"
+
+// serveFTHoldings serves one Kupo match per quantity, each holding that much
+// of the fungible token.
+func serveFTHoldings(kupo *mockKupo, quantities ...uint64) {
+	matches := make([]string, len(quantities))
+	for i, qty := range quantities {
+		matches[i] = fmt.Sprintf(`{
+			"transaction_id": "%064x",
+			"output_index": 0,
+			"address": %q,
+			"value": {"coins": 2000000, "assets": {"%s.%s": %d}},
+			"created_at": {"slot_no": 10, "header_hash": %q}
+		}`, i+1, adapterTestAddr, outRefPolicy, ftName, qty, strings.Repeat("01", 32))
+	}
+	kupo.route(
+		"/v1/matches/"+outRefPolicy+"."+ftName,
+		"["+strings.Join(matches, ",")+"]",
+	)
+}
+
+func TestGetUtxoByUnitFungibleInOneOutput(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{})
+	serveFTHoldings(kupo, 1000)
+
+	utxo, err := kp.GetUtxoByUnit(context.Background(), outRefPolicy+ftName)
+	assert.NoError(t, err)
+	assert.NotNil(t, utxo)
+
+	unit, err := newUnitMatcher(outRefPolicy + ftName)
+	assert.NoError(t, err)
+	qty := utxo.Output.Assets().Asset(unit.policyId, unit.assetName)
+	assert.NotNil(t, qty)
+	assert.Equal(t, 0, qty.Cmp(big.NewInt(1000)), "quantity %v", qty)
+}
+
+func TestGetUtxoByUnitSplitSupply(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{})
+	serveFTHoldings(kupo, 600, 400)
+
+	_, err := kp.GetUtxoByUnit(context.Background(), outRefPolicy+ftName)
+	assert.True(t, errors.Is(err, connector.ErrMultipleUTXOs), "got %v", err)
+}
+
+func TestNewUnitMatcher(t *testing.T) {
+	longName := strings.Repeat("ab", 32)
+	cases := []struct {
+		unit    string
+		assetID string // "" when the unit is invalid
+	}{
+		{unit: outRefPolicy + ftName, assetID: outRefPolicy + "." + ftName},
+		{unit: outRefPolicy + "." + ftName, assetID: outRefPolicy + "." + ftName},
+		{unit: strings.ToUpper(outRefPolicy + ftName), assetID: outRefPolicy + "." + ftName},
+		{unit: outRefPolicy, assetID: outRefPolicy},
+		{unit: outRefPolicy + ".", assetID: outRefPolicy},
+		{unit: outRefPolicy + longName, assetID: outRefPolicy + "." + longName},
+		{unit: ""},
+		{unit: "Lovelace"},
+		{unit: outRefPolicy[:54]},
+		{unit: outRefPolicy[:54] + "." + ftName},
+		{unit: outRefPolicy + "a." + ftName},
+		{unit: "zz" + outRefPolicy[2:] + ftName},
+		{unit: outRefPolicy + "zz"},
+		{unit: outRefPolicy + "465"},
+		{unit: outRefPolicy + longName + "00"},
+		{unit: outRefPolicy + "." + ftName + "." + ftName},
+	}
+	lovelace, err := newUnitMatcher("lovelace")
+	assert.NoError(t, err)
+	assert.True(t, lovelace.lovelace)
+	assert.Empty(t, lovelace.kugoAssetID)
+
+	for _, c := range cases {
+		matcher, err := newUnitMatcher(c.unit)
+		if c.assetID == "" {
+			assert.True(t, errors.Is(err, connector.ErrInvalidUnit), "%q: got %v", c.unit, err)
+			continue
+		}
+		assert.NoError(t, err, c.unit)
+		assert.Equal(t, c.assetID, matcher.kugoAssetID, c.unit)
+	}
+}
+
+func TestGetUtxosWithUnitRejectsMalformedUnit(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{})
+
+	_, err := kp.GetUtxosWithUnit(context.Background(), adapterTestAddr, outRefPolicy+"zz")
+	assert.True(t, errors.Is(err, connector.ErrInvalidUnit), "got %v", err)
+	assert.Equal(t, 0, kupo.Requests("/v1/matches/"+adapterTestAddr))
+}
+
+func TestGetUtxosWithUnitLovelace(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{})
+	kupo.route("/v1/matches/"+adapterTestAddr, fmt.Sprintf(`[{
+		"transaction_id": "%064x",
+		"output_index": 0,
+		"address": %q,
+		"value": {"coins": 2000000},
+		"created_at": {"slot_no": 10, "header_hash": %q}
+	}, {
+		"transaction_id": "%064x",
+		"output_index": 1,
+		"address": %q,
+		"value": {"coins": 1500000, "assets": {"%s.%s": 7}},
+		"created_at": {"slot_no": 11, "header_hash": %q}
+	}]`,
+		1, adapterTestAddr, strings.Repeat("01", 32),
+		2, adapterTestAddr, outRefPolicy, ftName, strings.Repeat("02", 32),
+	))
+
+	utxos, err := kp.GetUtxosWithUnit(context.Background(), adapterTestAddr, "lovelace")
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 2)
+}
+
+func TestGetUtxoByUnitRejectsLovelace(t *testing.T) {
+	kp, _, kupo := newMockKupmios(t, Config{})
+
+	_, err := kp.GetUtxoByUnit(context.Background(), "lovelace")
+	assert.True(t, errors.Is(err, connector.ErrInvalidUnit), "got %v", err)
+	assert.Equal(t, 0, kupo.Requests("/v1/matches/lovelace"))
+}
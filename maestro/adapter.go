@@ -140,28 +140,14 @@ func adaptMaestroProtocolParams(
 				}
 				int64Costs = append(int64Costs, int64(f))
 			}
-			pp.CostModels[maestroCostModelKey(key)] = int64Costs
+			pp.CostModels[key] = int64Costs
 		}
+		pp.CostModels = connector.NormalizeCostModels(pp.CostModels)
 	}
 
 	return pp, nil
 }
 
-// maestroCostModelKey translates Maestro cost model keys to the canonical form
-// expected by ComputeScriptDataHash ("PlutusV1", "PlutusV2", "PlutusV3").
-func maestroCostModelKey(key string) string {
-	switch key {
-	case "plutus:v1", "plutus_v1":
-		return "PlutusV1"
-	case "plutus:v2", "plutus_v2":
-		return "PlutusV2"
-	case "plutus:v3", "plutus_v3":
-		return "PlutusV3"
-	default:
-		return key
-	}
-}
-
 // maestroUtxoToCommon converts a Maestro UTxO to a gouroboros common.Utxo.
 func maestroUtxoToCommon(raw models.Utxo, address common.Address) (common.Utxo, error) {
 	hashBytes, err := hex.DecodeString(raw.TxHash)
@@ -13,6 +13,7 @@ import (
 	"github.com/blinklabs-io/gouroboros/ledger/mary"
 	"github.com/blinklabs-io/gouroboros/ledger/shelley"
 	"github.com/maestro-org/go-sdk/models"
+	connector "github.com/zenGate-Global/cardano-connector-go"
 )
 
 const maestroTestAddr = "addr_test1wpgexmeunzsykesf42d4eqet5yvzeap6trjnflxqtkcf66g0kpnxt"
@@ -234,7 +235,7 @@ func TestAdaptMaestroProtocolParamsMapsRefScriptFields(t *testing.T) {
 // Cost-model shape coverage that previously lived in TestNormalizeMaestroCostModels
 // (apollo v1) is reimplemented against the v2 types in
 // TestAdaptMaestroProtocolParamsCostModels* below, which exercise
-// adaptMaestroProtocolParams' inline parsing + maestroCostModelKey directly.
+// adaptMaestroProtocolParams' inline parsing + connector.CostModelKey directly.
 //
 // TODO(apollo-v2): The following tests were removed during the apollo v2 /
 // gouroboros migration because the code they exercised no longer exists:
@@ -248,23 +249,6 @@ func TestAdaptMaestroProtocolParamsMapsRefScriptFields(t *testing.T) {
 //     documented wire format). Re-add coverage if/when the SDK gains object-
 //     shaped additional_utxos support.
 
-func TestMaestroCostModelKey(t *testing.T) {
-	cases := map[string]string{
-		"plutus:v1": "PlutusV1",
-		"plutus:v2": "PlutusV2",
-		"plutus:v3": "PlutusV3",
-		"plutus_v1": "PlutusV1",
-		"plutus_v2": "PlutusV2",
-		"plutus_v3": "PlutusV3",
-		"unknown":   "unknown",
-	}
-	for in, want := range cases {
-		if got := maestroCostModelKey(in); got != want {
-			t.Errorf("maestroCostModelKey(%q) = %q, want %q", in, got, want)
-		}
-	}
-}
-
 func TestMergeMaestroProtocolParamsUsesPresetForMissingFields(t *testing.T) {
 	current := backend.ProtocolParameters{
 		MinFeeConstant:   1,
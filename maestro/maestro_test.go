@@ -57,6 +57,9 @@ func TestGetProtocolParameters(t *testing.T) {
 	if pp.MaxTxSize == 0 {
 		t.Error("Expected non-zero MaxTxSize")
 	}
+	if diff := tests.CostModelKeysDiff(pp.CostModels); diff != "" {
+		t.Error(diff)
+	}
 	if len(pp.CostModels["PlutusV2"]) == 0 {
 		t.Error("Expected non-empty PlutusV2 cost model")
 	}
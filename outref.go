@@ -0,0 +1,24 @@
+package connector
+
+import "github.com/blinklabs-io/gouroboros/ledger/common"
+
+// OrderByOutRefs returns the UTxOs among utxos that refs asks for, in the
+// order of refs. A ref listed more than once is returned once, at its first
+// position; refs without a UTxO are skipped, as are UTxOs no ref asks for.
+// Providers use it so that GetUtxosByOutRef results line up with the request.
+func OrderByOutRefs(utxos []common.Utxo, refs []OutRef) []common.Utxo {
+	byRef := make(map[OutRef]common.Utxo, len(utxos))
+	for _, utxo := range utxos {
+		ref := OutRef{TxHash: utxo.Id.Id().String(), Index: utxo.Id.Index()}
+		byRef[ref] = utxo
+	}
+
+	ordered := make([]common.Utxo, 0, len(refs))
+	for _, ref := range refs {
+		if utxo, ok := byRef[ref]; ok {
+			ordered = append(ordered, utxo)
+			delete(byRef, ref)
+		}
+	}
+	return ordered
+}
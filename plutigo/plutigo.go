@@ -816,27 +816,20 @@ func costModelForVersion(costModels map[string][]int64, version scriptVersion) (
 		return nil, classifiedError(connector.ErrNotImplemented, "protocol parameters are missing cost models", nil)
 	}
 
-	normalized := make(map[string][]int64, len(costModels))
-	for key, model := range costModels {
-		normalized[normalizeCostModelKey(key)] = model
-	}
-
-	var lookupKeys []string
+	var key string
 	switch version {
 	case scriptVersionV1:
-		lookupKeys = []string{"plutusv1", "plutusscriptv1"}
+		key = connector.CostModelPlutusV1
 	case scriptVersionV2:
-		lookupKeys = []string{"plutusv2", "plutusscriptv2"}
+		key = connector.CostModelPlutusV2
 	case scriptVersionV3:
-		lookupKeys = []string{"plutusv3", "plutusscriptv3"}
+		key = connector.CostModelPlutusV3
 	default:
 		return nil, classifiedError(connector.ErrNotImplemented, "unknown Plutus version", nil)
 	}
 
-	for _, key := range lookupKeys {
-		if model, ok := normalized[key]; ok && len(model) > 0 {
-			return model, nil
-		}
+	if model := connector.NormalizeCostModels(costModels)[key]; len(model) > 0 {
+		return model, nil
 	}
 
 	return nil, classifiedError(
@@ -859,21 +852,6 @@ func cekLanguageVersion(version scriptVersion) cek.LanguageVersion {
 	}
 }
 
-func normalizeCostModelKey(key string) string {
-	var b strings.Builder
-	b.Grow(len(key))
-	for _, r := range key {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
-			b.WriteRune(r)
-			continue
-		}
-		if r >= 'A' && r <= 'Z' {
-			b.WriteRune(r + ('a' - 'A'))
-		}
-	}
-	return b.String()
-}
-
 func parseInt64Param(name string, value string) (int64, error) {
 	if strings.TrimSpace(value) == "" {
 		return 0, classifiedError(connector.ErrNotImplemented, fmt.Sprintf("protocol parameter %s is required", name), nil)
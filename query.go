@@ -0,0 +1,101 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+// Order is the direction in which a list query returns its results.
+type Order string
+
+const (
+	OrderAsc  Order = "asc"
+	OrderDesc Order = "desc"
+)
+
+// QueryOptions refines a list query. The zero value keeps the provider's
+// defaults. Providers ignore the options an endpoint cannot express.
+type QueryOptions struct {
+	// Order, when set, overrides the provider's configured order.
+	Order Order
+	// From and To bound the results to an inclusive range of block heights;
+	// zero leaves that side open.
+	From uint64
+	To   uint64
+	// PageSize, when positive, is the number of items requested per page
+	// from endpoints that paginate.
+	PageSize int
+}
+
+// QueryOption sets a field of QueryOptions.
+type QueryOption func(*QueryOptions)
+
+// WithOrder returns results in the given order.
+func WithOrder(order Order) QueryOption {
+	return func(o *QueryOptions) {
+		o.Order = order
+	}
+}
+
+// WithFromTo limits results to blocks from through to, inclusive. Pass zero
+// for an open bound, e.g. WithFromTo(lastSynced+1, 0) to resume a sync.
+func WithFromTo(from, to uint64) QueryOption {
+	return func(o *QueryOptions) {
+		o.From = from
+		o.To = to
+	}
+}
+
+// WithPageSize requests results in pages of n items.
+func WithPageSize(n int) QueryOption {
+	return func(o *QueryOptions) {
+		o.PageSize = n
+	}
+}
+
+// NewQueryOptions applies opts in order and validates the result.
+func NewQueryOptions(opts ...QueryOption) (QueryOptions, error) {
+	var o QueryOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	switch o.Order {
+	case "", OrderAsc, OrderDesc:
+	default:
+		return QueryOptions{}, fmt.Errorf("%w: order must be %q or %q, got %q", ErrInvalidInput, OrderAsc, OrderDesc, o.Order)
+	}
+	if o.From != 0 && o.To != 0 && o.From > o.To {
+		return QueryOptions{}, fmt.Errorf("%w: from %d is past to %d", ErrInvalidInput, o.From, o.To)
+	}
+	if o.PageSize < 0 {
+		return QueryOptions{}, fmt.Errorf("%w: page size must not be negative, got %d", ErrInvalidInput, o.PageSize)
+	}
+	return o, nil
+}
+
+// AddressTransaction is a transaction that spent from or paid to an address.
+type AddressTransaction struct {
+	TxHash string `json:"tx_hash"`
+	// TxIndex is the transaction's position within its block.
+	TxIndex     int    `json:"tx_index"`
+	BlockHeight uint64 `json:"block_height"`
+	// BlockTime is the block's Unix time in seconds.
+	BlockTime int64 `json:"block_time"`
+}
+
+// AddressTransactionsProvider is an optional capability of providers that can
+// list the transactions of an address.
+type AddressTransactionsProvider interface {
+	// GetAddressTransactions returns the transactions involving the Bech32
+	// address. An address never seen on-chain has none.
+	GetAddressTransactions(ctx context.Context, addr string, opts ...QueryOption) ([]AddressTransaction, error)
+}
+
+// UtxoQueryProvider is an optional capability of providers whose address UTxO
+// listing accepts QueryOptions.
+type UtxoQueryProvider interface {
+	// GetUtxosByAddressWith is GetUtxosByAddress refined by opts.
+	GetUtxosByAddressWith(ctx context.Context, addr string, opts ...QueryOption) ([]common.Utxo, error)
+}
@@ -0,0 +1,110 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 250 * time.Millisecond
+	defaultRetryMaxDelay  = 5 * time.Second
+)
+
+// RetryPolicy controls how a provider retries requests that fail
+// transiently, backing off exponentially between attempts. Which failures
+// count as transient, and which requests may be repeated at all, is up to
+// each provider.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts per request, including the
+	// first one. Retries are disabled unless it is above one.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry; it doubles with every
+	// further retry. Defaults to 250ms.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts. Defaults to 5s.
+	MaxDelay time.Duration
+	// Jitter randomly shortens or lengthens each delay by up to this fraction
+	// of it, between 0 and 1, so concurrent clients do not retry in lockstep.
+	Jitter float64
+}
+
+// WithDefaults validates p, failing with ErrInvalidInput, and fills in its
+// zero delays.
+func (p RetryPolicy) WithDefaults() (RetryPolicy, error) {
+	if p.MaxAttempts < 0 || p.BaseDelay < 0 || p.MaxDelay < 0 {
+		return p, fmt.Errorf(
+			"%w: retry policy must not be negative, got %+v",
+			ErrInvalidInput,
+			p,
+		)
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return p, fmt.Errorf(
+			"%w: retry jitter must be between 0 and 1, got %v",
+			ErrInvalidInput,
+			p.Jitter,
+		)
+	}
+	if p.BaseDelay == 0 {
+		p.BaseDelay = defaultRetryBaseDelay
+	}
+	if p.MaxDelay == 0 {
+		p.MaxDelay = defaultRetryMaxDelay
+	}
+	if p.MaxDelay < p.BaseDelay {
+		p.MaxDelay = p.BaseDelay
+	}
+	return p, nil
+}
+
+// Delay returns the backoff before retry number attempt (1-based): BaseDelay
+// doubled per previous retry, capped at MaxDelay, then spread by Jitter.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, p.MaxDelay)
+	if p.Jitter > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(d))
+	}
+	return d
+}
+
+// Wait sleeps before retry number attempt. It returns false, without
+// waiting, when ctx would expire first, and as soon as ctx is done.
+func (p RetryPolicy) Wait(ctx context.Context, attempt int) bool {
+	d := p.Delay(attempt)
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return false
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// Retry calls call until it succeeds, fails with an error retryable rejects,
+// or p runs out of attempts, and returns the last outcome.
+func Retry[T any](
+	ctx context.Context,
+	p RetryPolicy,
+	retryable func(error) bool,
+	call func() (T, error),
+) (T, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := call()
+		if err == nil || attempt >= p.MaxAttempts || !retryable(err) {
+			return result, err
+		}
+		if !p.Wait(ctx, attempt) {
+			return result, err
+		}
+	}
+}
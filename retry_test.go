@@ -0,0 +1,110 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyWithDefaults(t *testing.T) {
+	p, err := RetryPolicy{MaxAttempts: 3}.WithDefaults()
+	assert.NoError(t, err)
+	assert.Equal(t, RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   defaultRetryBaseDelay,
+		MaxDelay:    defaultRetryMaxDelay,
+	}, p)
+
+	// A cap below the base delay is raised to it.
+	p, err = RetryPolicy{BaseDelay: 10 * time.Second}.WithDefaults()
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Second, p.MaxDelay)
+
+	for _, invalid := range []RetryPolicy{
+		{MaxAttempts: -1},
+		{BaseDelay: -time.Second},
+		{MaxDelay: -time.Second},
+		{Jitter: -0.1},
+		{Jitter: 1.5},
+	} {
+		_, err := invalid.WithDefaults()
+		assert.ErrorIs(t, err, ErrInvalidInput, "%+v", invalid)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt, want := range []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	} {
+		assert.Equal(t, want, p.Delay(attempt+1), "attempt %d", attempt+1)
+	}
+
+	p.Jitter = 0.5
+	for range 100 {
+		d := p.Delay(2)
+		assert.GreaterOrEqual(t, d, 100*time.Millisecond)
+		assert.LessOrEqual(t, d, 300*time.Millisecond)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	transient := errors.New("transient")
+	permanent := errors.New("permanent")
+	retryable := func(err error) bool { return errors.Is(err, transient) }
+	p := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	calls := 0
+	got, err := Retry(context.Background(), p, retryable, func() (int, error) {
+		calls++
+		if calls < 3 {
+			return 0, transient
+		}
+		return 42, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 42, got)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	_, err = Retry(context.Background(), p, retryable, func() (int, error) {
+		calls++
+		return 0, transient
+	})
+	assert.ErrorIs(t, err, transient)
+	assert.Equal(t, 3, calls, "gives up after MaxAttempts")
+
+	calls = 0
+	_, err = Retry(context.Background(), p, retryable, func() (int, error) {
+		calls++
+		return 0, permanent
+	})
+	assert.ErrorIs(t, err, permanent)
+	assert.Equal(t, 1, calls, "does not retry a permanent failure")
+
+	calls = 0
+	_, err = Retry(context.Background(), RetryPolicy{}, retryable, func() (int, error) {
+		calls++
+		return 0, transient
+	})
+	assert.ErrorIs(t, err, transient)
+	assert.Equal(t, 1, calls, "the zero policy does not retry")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	_, err = Retry(ctx, p, retryable, func() (int, error) {
+		calls++
+		return 0, transient
+	})
+	assert.ErrorIs(t, err, transient)
+	assert.Equal(t, 1, calls, "does not retry once ctx is done")
+}
@@ -0,0 +1,65 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+// StakeAddressResolver is an optional capability of providers that can look
+// up the stake address owning a payment address, including pointer addresses
+// whose stake part cannot be read from the address itself.
+type StakeAddressResolver interface {
+	// GetStakeAddressOfAddress returns the Bech32 stake address of addr, or
+	// an empty string when addr has no stake part.
+	GetStakeAddressOfAddress(ctx context.Context, addr string) (string, error)
+}
+
+// StakeAddressOf returns the Bech32 stake address that owns addr, e.g. to pass
+// it on to GetDelegation, or an empty string for enterprise and Byron
+// addresses. Providers implementing StakeAddressResolver answer directly;
+// otherwise the stake part is decoded from addr, which fails with
+// ErrNotImplemented for pointer addresses.
+func StakeAddressOf(ctx context.Context, provider Provider, addr string) (string, error) {
+	if resolver, ok := provider.(StakeAddressResolver); ok {
+		return resolver.GetStakeAddressOfAddress(ctx, addr)
+	}
+
+	address, err := common.NewAddress(addr)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %w", ErrInvalidAddress, addr, err)
+	}
+	switch address.Type() {
+	case common.AddressTypeKeyKey, common.AddressTypeScriptKey,
+		common.AddressTypeKeyScript, common.AddressTypeScriptScript:
+		raw, err := address.Bytes()
+		if err != nil {
+			return "", fmt.Errorf("%w: %s: %w", ErrInvalidAddress, addr, err)
+		}
+		// A base address is header || payment hash || stake hash; the stake
+		// address keeps the network nibble and the stake credential kind.
+		header := byte(common.AddressTypeNoneKey) << 4
+		if address.Type()&0b0010 != 0 {
+			header = byte(common.AddressTypeNoneScript) << 4
+		}
+		header |= raw[0] & 0x0f
+		stake, err := common.NewAddressFromBytes(
+			append([]byte{header}, raw[1+common.AddressHashSize:]...),
+		)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s: %w", ErrInvalidAddress, addr, err)
+		}
+		return stake.String(), nil
+	case common.AddressTypeNoneKey, common.AddressTypeNoneScript:
+		return addr, nil
+	case common.AddressTypeKeyPointer, common.AddressTypeScriptPointer:
+		return "", fmt.Errorf(
+			"%w: the stake address of pointer address %s needs a provider lookup",
+			ErrNotImplemented,
+			addr,
+		)
+	default:
+		return "", nil
+	}
+}
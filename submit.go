@@ -0,0 +1,53 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SubmitAndAwait submits tx through p and waits until it is confirmed,
+// returning its hash. Providers implementing TxWatcher are followed through
+// their stage events; others are asked with AwaitTx, polling every
+// checkInterval. Once the transaction was submitted, its hash is returned
+// even when waiting for it fails.
+func SubmitAndAwait(
+	ctx context.Context,
+	p Provider,
+	tx []byte,
+	checkInterval time.Duration,
+) (string, error) {
+	if w, ok := p.(TxWatcher); ok {
+		events, err := w.SubmitAndWatch(ctx, tx)
+		if err != nil {
+			return "", err
+		}
+		var txHash string
+		for event := range events {
+			txHash = event.TxHash
+			if event.Err != nil {
+				return txHash, event.Err
+			}
+			if event.Stage == TxStageConfirmed {
+				return txHash, nil
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return txHash, err
+		}
+		return txHash, fmt.Errorf("transaction %s: watch ended before confirmation", txHash)
+	}
+
+	txHash, err := p.SubmitTx(ctx, tx)
+	if err != nil {
+		return "", err
+	}
+	confirmed, err := p.AwaitTx(ctx, txHash, checkInterval)
+	if err != nil {
+		return txHash, err
+	}
+	if !confirmed {
+		return txHash, fmt.Errorf("transaction %s was not confirmed", txHash)
+	}
+	return txHash, nil
+}
@@ -6,6 +6,7 @@ import (
 	"sort"
 
 	"github.com/blinklabs-io/gouroboros/ledger/common"
+	connector "github.com/zenGate-Global/cardano-connector-go"
 )
 
 // UtxosEqual reports whether two gouroboros UTxOs are SEMANTICALLY equal.
@@ -160,6 +161,15 @@ func scriptRefDiff(ao, bo common.TransactionOutput) string {
 			return fmt.Sprintf("reference script hash: %x != %x",
 				aScript.Hash().Bytes(), bScript.Hash().Bytes())
 		}
+		// Providers must agree on the framing too, not just the hash: fee
+		// calculation depends on the reference script size.
+		if fmt.Sprintf("%T", aScript) != fmt.Sprintf("%T", bScript) {
+			return fmt.Sprintf("reference script type: %T != %T", aScript, bScript)
+		}
+		if !bytes.Equal(aScript.RawScriptBytes(), bScript.RawScriptBytes()) {
+			return fmt.Sprintf("reference script bytes: %d bytes != %d bytes",
+				len(aScript.RawScriptBytes()), len(bScript.RawScriptBytes()))
+		}
 	}
 	return ""
 }
@@ -203,3 +213,22 @@ func withinTolerance(got, want int64, tolerancePct float64) bool {
 	}
 	return diff <= tolerancePct
 }
+
+// CostModelKeysDiff returns a description of the first cost model key that is
+// not one of the canonical connector.CostModelPlutusV* keys, or "" if all are.
+// Every provider's GetProtocolParameters must pass it.
+func CostModelKeysDiff(costModels map[string][]int64) string {
+	keys := make([]string, 0, len(costModels))
+	for key := range costModels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		switch key {
+		case connector.CostModelPlutusV1, connector.CostModelPlutusV2, connector.CostModelPlutusV3:
+		default:
+			return fmt.Sprintf("non-canonical cost model key %q", key)
+		}
+	}
+	return ""
+}
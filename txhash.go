@@ -0,0 +1,23 @@
+package connector
+
+import (
+	"fmt"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+)
+
+// ComputeTxHash returns the hex hash of a serialised transaction, which is
+// the hash of its body. Providers use it when a submission response does not
+// carry the transaction id. Bytes that do not decode as a transaction yield
+// ErrInvalidInput.
+func ComputeTxHash(tx []byte) (string, error) {
+	txType, err := ledger.DetermineTransactionType(tx)
+	if err != nil {
+		return "", fmt.Errorf("%w: unknown transaction type: %w", ErrInvalidInput, err)
+	}
+	decoded, err := ledger.NewTransactionFromCbor(txType, tx)
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to decode transaction: %w", ErrInvalidInput, err)
+	}
+	return decoded.Hash().String(), nil
+}
@@ -0,0 +1,62 @@
+package connector
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+// maxAssetNameSize is the largest asset name the ledger allows, in bytes.
+const maxAssetNameSize = 32
+
+// ParseUnit splits an asset unit into its policy ID and asset name. The unit
+// is the 56-hex policy ID followed by the asset name hex, which may be empty,
+// or the dotted "policy.assetName" form Kupo uses. "lovelace" is not an asset
+// unit. Malformed units yield ErrInvalidUnit.
+func ParseUnit(unit string) (common.Blake2b224, []byte, error) {
+	if unit == "lovelace" {
+		return common.Blake2b224{}, nil, fmt.Errorf("%w: lovelace is not an asset unit", ErrInvalidUnit)
+	}
+
+	policyHex, nameHex, dotted := strings.Cut(unit, ".")
+	if !dotted {
+		if len(unit) < 2*common.Blake2b224Size {
+			return common.Blake2b224{}, nil, fmt.Errorf(
+				"%w: %q: policy id must be %d hex characters",
+				ErrInvalidUnit,
+				unit,
+				2*common.Blake2b224Size,
+			)
+		}
+		policyHex, nameHex = unit[:2*common.Blake2b224Size], unit[2*common.Blake2b224Size:]
+	}
+	if len(policyHex) != 2*common.Blake2b224Size {
+		return common.Blake2b224{}, nil, fmt.Errorf(
+			"%w: %q: policy id must be %d hex characters, got %d",
+			ErrInvalidUnit,
+			unit,
+			2*common.Blake2b224Size,
+			len(policyHex),
+		)
+	}
+	policy, err := hex.DecodeString(policyHex)
+	if err != nil {
+		return common.Blake2b224{}, nil, fmt.Errorf("%w: %q: policy id: %w", ErrInvalidUnit, unit, err)
+	}
+	name, err := hex.DecodeString(nameHex)
+	if err != nil {
+		return common.Blake2b224{}, nil, fmt.Errorf("%w: %q: asset name: %w", ErrInvalidUnit, unit, err)
+	}
+	if len(name) > maxAssetNameSize {
+		return common.Blake2b224{}, nil, fmt.Errorf(
+			"%w: %q: asset name is %d bytes, at most %d allowed",
+			ErrInvalidUnit,
+			unit,
+			len(name),
+			maxAssetNameSize,
+		)
+	}
+	return common.NewBlake2b224(policy), name, nil
+}
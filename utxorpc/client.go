@@ -0,0 +1,165 @@
+package utxorpc
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1alpha/query/queryconnect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1alpha/submit/submitconnect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1alpha/sync/syncconnect"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// apiKeyHeader carries Config.ApiKey, as Demeter's hosted endpoints expect.
+const apiKeyHeader = "dmtr-api-key"
+
+// rpcClient holds the clients of the UTxO RPC services the provider calls.
+// They share the HTTP client built by newHTTPClient, so the configured
+// headers and TLS settings apply to every service alike.
+type rpcClient struct {
+	query  queryconnect.QueryServiceClient
+	submit submitconnect.SubmitServiceClient
+	sync   syncconnect.SyncServiceClient
+}
+
+// newRPCClient returns the service clients for config, which New has
+// validated. The retry interceptor comes first, so that interceptors from
+// Config.ClientOptions see every attempt.
+func newRPCClient(config Config, headers http.Header, retry connector.RetryPolicy) *rpcClient {
+	httpClient := newHTTPClient(config, headers)
+	opts := append(
+		[]connect.ClientOption{
+			connect.WithGRPC(),
+			connect.WithInterceptors(retryInterceptor(retry)),
+		},
+		config.ClientOptions...,
+	)
+	return &rpcClient{
+		query:  queryconnect.NewQueryServiceClient(httpClient, config.BaseUrl, opts...),
+		submit: submitconnect.NewSubmitServiceClient(httpClient, config.BaseUrl, opts...),
+		sync:   syncconnect.NewSyncServiceClient(httpClient, config.BaseUrl, opts...),
+	}
+}
+
+// newHTTPClient returns Config.HTTPClient, or a client speaking HTTP/2 over
+// TLS for https and over cleartext (h2c) for http, as gRPC requires, wrapped
+// so that every request carries headers.
+func newHTTPClient(config Config, headers http.Header) connect.HTTPClient {
+	var client connect.HTTPClient = config.HTTPClient
+	if config.HTTPClient == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if config.TLSConfig != nil {
+			// The transport adds its ALPN protocols to the config it holds;
+			// clone it so that the caller's config is left alone.
+			transport.TLSClientConfig = config.TLSConfig.Clone()
+		}
+		var protocols http.Protocols
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+		transport.Protocols = &protocols
+		// Streams such as FollowTip stay open indefinitely, so the client has
+		// no overall timeout; calls are bounded by their contexts.
+		client = &http.Client{Transport: transport}
+	}
+	if len(headers) == 0 {
+		return client
+	}
+	return &headerClient{client: client, headers: headers}
+}
+
+// headerClient adds headers to every request it sends.
+type headerClient struct {
+	client  connect.HTTPClient
+	headers http.Header
+}
+
+func (c *headerClient) Do(req *http.Request) (*http.Response, error) {
+	// The protocol headers connect sets, such as Content-Type, win over
+	// configured ones.
+	for name, values := range c.headers {
+		if req.Header.Get(name) == "" {
+			req.Header[name] = values
+		}
+	}
+	return c.client.Do(req)
+}
+
+// requestHeaders merges Config.Headers and the ApiKey header. It fails with
+// ErrInvalidInput when Headers sets the API key header to another value than
+// ApiKey.
+func requestHeaders(config Config) (http.Header, error) {
+	headers := make(http.Header, len(config.Headers)+1)
+	for name, value := range config.Headers {
+		if strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf(
+				"%w: Headers must not contain an empty header name",
+				connector.ErrInvalidInput,
+			)
+		}
+		headers.Set(name, value)
+	}
+	if config.ApiKey != "" {
+		if value := headers.Get(apiKeyHeader); value != "" &&
+			value != config.ApiKey {
+			return nil, fmt.Errorf(
+				"%w: ApiKey conflicts with the %s entry of Headers",
+				connector.ErrInvalidInput,
+				apiKeyHeader,
+			)
+		}
+		headers.Set(apiKeyHeader, config.ApiKey)
+	}
+	if len(headers) == 0 {
+		return nil, nil
+	}
+	return headers, nil
+}
+
+// checkTransport fails with ErrInvalidInput unless BaseUrl is an absolute
+// http or https URL and the TLS settings can take effect.
+func checkTransport(config Config) error {
+	u, err := url.Parse(config.BaseUrl)
+	if err != nil {
+		return fmt.Errorf(
+			"%w: BaseUrl %q is not a URL: %w",
+			connector.ErrInvalidInput,
+			config.BaseUrl,
+			err,
+		)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf(
+			"%w: BaseUrl %q must use http or https",
+			connector.ErrInvalidInput,
+			u.Redacted(),
+		)
+	}
+	if u.Host == "" {
+		return fmt.Errorf(
+			"%w: BaseUrl %q has no host",
+			connector.ErrInvalidInput,
+			u.Redacted(),
+		)
+	}
+	if config.TLSConfig == nil {
+		return nil
+	}
+	if config.HTTPClient != nil {
+		return fmt.Errorf(
+			"%w: TLSConfig and HTTPClient are mutually exclusive; configure "+
+				"TLS on the HTTPClient's transport instead",
+			connector.ErrInvalidInput,
+		)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf(
+			"%w: TLSConfig requires an https BaseUrl, got %q",
+			connector.ErrInvalidInput,
+			u.Redacted(),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package utxorpc
+
+import (
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// rpcError wraps the error of the UTxO RPC call op, translating the status
+// codes that have a connector sentinel: Unimplemented, for RPCs the endpoint
+// does not serve, NotFound and InvalidArgument.
+func rpcError(op string, err error) error {
+	var sentinel error
+	switch connect.CodeOf(err) {
+	case connect.CodeUnimplemented:
+		sentinel = connector.ErrNotImplemented
+	case connect.CodeNotFound:
+		sentinel = connector.ErrNotFound
+	case connect.CodeInvalidArgument:
+		sentinel = connector.ErrInvalidInput
+	default:
+		return fmt.Errorf("utxorpc: %s failed: %w", op, err)
+	}
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return fmt.Errorf("utxorpc: %w: %s: %s", sentinel, op, connectErr.Message())
+	}
+	return fmt.Errorf("utxorpc: %w: %s: %w", sentinel, op, err)
+}
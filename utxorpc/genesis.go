@@ -0,0 +1,113 @@
+package utxorpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/Salvionied/apollo/v2/backend"
+	"github.com/Salvionied/apollo/v2/constants"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// networkGenesis holds the Shelley genesis parameters of the public networks,
+// keyed by apollo constants.Network. UTxO RPC's query service carries
+// protocol parameters but none of these, which are fixed for the life of a
+// network. SystemStart is the start of the chain, which on mainnet is the
+// Byron genesis rather than the Shelley one.
+var networkGenesis = map[int]backend.GenesisParameters{
+	int(constants.MAINNET): {
+		ActiveSlotsCoefficient: 0.05,
+		UpdateQuorum:           5,
+		MaxLovelaceSupply:      "45000000000000000",
+		NetworkMagic:           764824073,
+		EpochLength:            432000,
+		SystemStart:            1506203091,
+		SlotsPerKesPeriod:      129600,
+		SlotLength:             1,
+		MaxKesEvolutions:       62,
+		SecurityParam:          2160,
+	},
+	int(constants.PREPROD): {
+		ActiveSlotsCoefficient: 0.05,
+		UpdateQuorum:           5,
+		MaxLovelaceSupply:      "45000000000000000",
+		NetworkMagic:           1,
+		EpochLength:            432000,
+		SystemStart:            1654041600,
+		SlotsPerKesPeriod:      129600,
+		SlotLength:             1,
+		MaxKesEvolutions:       62,
+		SecurityParam:          2160,
+	},
+	int(constants.PREVIEW): {
+		ActiveSlotsCoefficient: 0.05,
+		UpdateQuorum:           5,
+		MaxLovelaceSupply:      "45000000000000000",
+		NetworkMagic:           2,
+		EpochLength:            86400,
+		SystemStart:            1666656000,
+		SlotsPerKesPeriod:      129600,
+		SlotLength:             1,
+		MaxKesEvolutions:       62,
+		SecurityParam:          432,
+	},
+}
+
+// genesisParams returns the genesis parameters of the configured network.
+func (u *UtxorpcProvider) genesisParams() (backend.GenesisParameters, error) {
+	params, ok := networkGenesis[u.networkId]
+	if !ok {
+		return backend.GenesisParameters{}, fmt.Errorf(
+			"%w: no genesis parameters known for network id %d",
+			connector.ErrNotImplemented,
+			u.networkId,
+		)
+	}
+	return params, nil
+}
+
+// byronEpochLength is the number of slots in a Byron epoch.
+const byronEpochLength = 21600
+
+// shelleyStart is the first Shelley slot and epoch of the public networks,
+// keyed by apollo constants.Network. Slots before it are Byron slots, which
+// are grouped into epochs of byronEpochLength.
+var shelleyStart = map[int]struct {
+	slot  uint64
+	epoch uint64
+}{
+	int(constants.MAINNET): {slot: 4492800, epoch: 208},
+	int(constants.PREPROD): {slot: 86400, epoch: 4},
+	int(constants.PREVIEW): {slot: 0, epoch: 0},
+}
+
+// epochAtSlot returns the epoch that slot falls in on the configured network.
+func (u *UtxorpcProvider) epochAtSlot(slot uint64) (uint64, error) {
+	params, err := u.genesisParams()
+	if err != nil {
+		return 0, err
+	}
+	start := shelleyStart[u.networkId]
+	if slot < start.slot {
+		return slot / byronEpochLength, nil
+	}
+	return start.epoch + (slot-start.slot)/uint64(params.EpochLength), nil
+}
+
+// Epoch returns the epoch of the current tip, computed from its slot and the
+// genesis parameters of the configured network.
+func (u *UtxorpcProvider) Epoch(ctx context.Context) (int, error) {
+	tip, err := u.readTip(ctx)
+	if err != nil {
+		return 0, err
+	}
+	epoch, err := u.epochAtSlot(tip.GetSlot())
+	if err != nil {
+		return 0, err
+	}
+	if epoch > math.MaxInt {
+		return 0, fmt.Errorf("utxorpc: epoch %d exceeds int range", epoch)
+	}
+	return int(epoch), nil
+}
@@ -38,6 +38,7 @@ var protocolParamsPreset = backend.ProtocolParameters{
 	MinFeeReferenceScriptsRange:      0,
 	MinFeeReferenceScriptsBase:       0,
 	MinFeeReferenceScriptsMultiplier: 15,
+	MinFeeRefScriptCostPerByte:       15,
 }
 
 func mergeProtocolParamsWithPreset(
@@ -133,5 +134,8 @@ func mergeProtocolParamsWithPreset(
 	if current.MinFeeReferenceScriptsMultiplier == 0 {
 		current.MinFeeReferenceScriptsMultiplier = preset.MinFeeReferenceScriptsMultiplier
 	}
+	if current.MinFeeRefScriptCostPerByte == 0 {
+		current.MinFeeRefScriptCostPerByte = preset.MinFeeRefScriptCostPerByte
+	}
 	return current
 }
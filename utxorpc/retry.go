@@ -0,0 +1,78 @@
+package utxorpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1alpha/query/queryconnect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1alpha/sync/syncconnect"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// idempotentProcedures are the unary calls the provider may repeat. Submit
+// and evaluation calls are deliberately absent.
+var idempotentProcedures = map[string]bool{
+	queryconnect.QueryServiceReadParamsProcedure:  true,
+	queryconnect.QueryServiceReadUtxosProcedure:   true,
+	queryconnect.QueryServiceSearchUtxosProcedure: true,
+	queryconnect.QueryServiceReadDataProcedure:    true,
+	syncconnect.SyncServiceReadTipProcedure:       true,
+	syncconnect.SyncServiceFetchBlockProcedure:    true,
+}
+
+// retryable reports whether a call that failed with err may be attempted
+// again: the endpoint must have answered Unavailable or ResourceExhausted.
+// Context errors, and so a caller's deadline, are final.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	switch connect.CodeOf(err) {
+	case connect.CodeUnavailable, connect.CodeResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryInterceptor repeats the idempotent unary calls that fail retryably
+// until they succeed or p runs out of attempts. A call that ends with
+// ResourceExhausted, retried or not, fails with an error that also wraps
+// connector.ErrRateLimited. Streams are passed through untouched.
+func retryInterceptor(p connector.RetryPolicy) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(
+			ctx context.Context,
+			req connect.AnyRequest,
+		) (connect.AnyResponse, error) {
+			policy := p
+			if !idempotentProcedures[req.Spec().Procedure] {
+				policy.MaxAttempts = 1
+			}
+			resp, err := connector.Retry(ctx, policy, retryable, func() (connect.AnyResponse, error) {
+				return next(ctx, req)
+			})
+			return resp, rateLimitError(err)
+		}
+	}
+}
+
+// rateLimitError returns err, which keeps its code, wrapping
+// connector.ErrRateLimited as well when it is a ResourceExhausted error.
+func rateLimitError(err error) error {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) ||
+		connectErr.Code() != connect.CodeResourceExhausted {
+		return err
+	}
+	limited := connect.NewError(
+		connect.CodeResourceExhausted,
+		fmt.Errorf("%w: %s", connector.ErrRateLimited, connectErr.Message()),
+	)
+	for name, values := range connectErr.Meta() {
+		limited.Meta()[name] = values
+	}
+	return limited
+}
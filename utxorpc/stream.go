@@ -0,0 +1,338 @@
+package utxorpc
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1alpha/cardano"
+	syncpb "github.com/utxorpc/go-codegen/utxorpc/v1alpha/sync"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+var _ connector.BlockStreamer = (*UtxorpcProvider)(nil)
+
+// A lost FollowTip stream is reopened after streamRetryDelay, doubling with
+// every failed attempt up to maxStreamRetryDelay.
+const (
+	streamRetryDelay    = time.Second
+	maxStreamRetryDelay = 30 * time.Second
+)
+
+// tipStream is the part of a FollowTip stream a block stream reads.
+type tipStream interface {
+	Receive() bool
+	Msg() *syncpb.FollowTipResponse
+	Err() error
+	Close() error
+}
+
+// openTipFunc opens a FollowTip stream at the most recent of intersect that
+// is on the chain.
+type openTipFunc func(ctx context.Context, intersect []*syncpb.BlockRef) (tipStream, error)
+
+// StreamBlocks implements connector.BlockStreamer on the sync service's
+// FollowTip stream, intersecting at from. When the stream breaks it is
+// reopened at the blocks delivered last, so a consumer sees any rollback that
+// happened in between as a BlockEventRollBackward rather than a gap. Ending
+// ctx or calling Close closes the channel, as does a final event whose Err
+// wraps ErrNotFound once none of those blocks is on the chain any more.
+func (u *UtxorpcProvider) StreamBlocks(
+	ctx context.Context,
+	from connector.ChainPoint,
+) (<-chan connector.BlockEvent, error) {
+	select {
+	case <-u.done:
+		return nil, errors.New("utxorpc: provider is closed")
+	default:
+	}
+
+	start := from
+	if start == (connector.ChainPoint{}) {
+		tip, err := u.readTip(ctx)
+		if err != nil {
+			return nil, err
+		}
+		start = connector.ChainPoint{
+			Slot: tip.GetSlot(),
+			Hash: hex.EncodeToString(tip.GetHash()),
+		}
+	} else if err := u.checkPoint(ctx, start); err != nil {
+		return nil, err
+	}
+
+	events := make(chan connector.BlockEvent)
+	go followTip(ctx, u.done, u.openTip, start, events)
+	return events, nil
+}
+
+// Close ends the provider's block streams and transaction watches;
+// StreamBlocks and SubmitAndWatch fail afterwards. Queries and submissions
+// keep working.
+func (u *UtxorpcProvider) Close() error {
+	if u.done != nil {
+		u.closeOnce.Do(func() { close(u.done) })
+	}
+	return nil
+}
+
+// checkPoint returns an error wrapping connector.ErrNotFound unless point
+// names a block the endpoint knows.
+func (u *UtxorpcProvider) checkPoint(ctx context.Context, point connector.ChainPoint) error {
+	ref, err := blockRef(point)
+	if err != nil {
+		return err
+	}
+	req := connect.NewRequest(&syncpb.FetchBlockRequest{
+		Ref: []*syncpb.BlockRef{ref},
+	})
+	resp, err := u.client.sync.FetchBlock(ctx, req)
+	if err != nil {
+		return rpcError("FetchBlock", err)
+	}
+	if resp.Msg == nil || len(resp.Msg.GetBlock()) == 0 {
+		return fmt.Errorf(
+			"utxorpc: %w: no block at slot %d with hash %s",
+			connector.ErrNotFound,
+			point.Slot,
+			point.Hash,
+		)
+	}
+	return nil
+}
+
+// openTip is the openTipFunc backed by the provider's client.
+func (u *UtxorpcProvider) openTip(
+	ctx context.Context,
+	intersect []*syncpb.BlockRef,
+) (tipStream, error) {
+	req := connect.NewRequest(&syncpb.FollowTipRequest{Intersect: intersect})
+	stream, err := u.client.sync.FollowTip(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// blockRef converts point into a sync BlockRef.
+func blockRef(point connector.ChainPoint) (*syncpb.BlockRef, error) {
+	hash, err := hex.DecodeString(point.Hash)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: invalid block hash %q: %w",
+			connector.ErrInvalidInput,
+			point.Hash,
+			err,
+		)
+	}
+	return &syncpb.BlockRef{Slot: point.Slot, Hash: hash}, nil
+}
+
+// followTip delivers the events of FollowTip streams opened with open,
+// reopening them whenever one fails, until ctx ends, done is closed or the
+// endpoint finds no resume point on the chain.
+func followTip(
+	ctx context.Context,
+	done <-chan struct{},
+	open openTipFunc,
+	start connector.ChainPoint,
+	events chan<- connector.BlockEvent,
+) {
+	defer close(events)
+
+	resume := connector.NewResumePoints(start)
+	delay := streamRetryDelay
+	for {
+		delivered, err := followTipOnce(ctx, done, open, resume, events)
+		if streamStopped(ctx, done) {
+			return
+		}
+		if errors.Is(err, connector.ErrNotFound) {
+			// The chain rolled back past every point the stream could resume
+			// from; reopening cannot help.
+			select {
+			case events <- connector.BlockEvent{Err: err}:
+			case <-ctx.Done():
+			case <-done:
+			}
+			return
+		}
+		if delivered {
+			delay = streamRetryDelay
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-done:
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxStreamRetryDelay)
+	}
+}
+
+// streamStopped reports whether a block stream should end.
+func streamStopped(ctx context.Context, done <-chan struct{}) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
+
+// followTipOnce opens one FollowTip stream at the recently delivered points
+// and delivers its events until it fails or the stream is stopped. It reports
+// whether any event was delivered and why the stream ended.
+func followTipOnce(
+	ctx context.Context,
+	done <-chan struct{},
+	open openTipFunc,
+	resume *connector.ResumePoints,
+	events chan<- connector.BlockEvent,
+) (bool, error) {
+	// Cancelling the stream's context unblocks a pending receive once the
+	// stream stops.
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-streamCtx.Done():
+		}
+	}()
+
+	// FollowTip intersects at the first of the refs that is on the chain.
+	recent := resume.Points()
+	intersect := make([]*syncpb.BlockRef, 0, len(recent))
+	for _, point := range slices.Backward(recent) {
+		ref, err := blockRef(point)
+		if err != nil {
+			return false, err
+		}
+		intersect = append(intersect, ref)
+	}
+	stream, err := open(streamCtx, intersect)
+	if err != nil {
+		return false, rpcError("FollowTip", err)
+	}
+	defer stream.Close()
+
+	delivered := false
+	first := true
+	for stream.Receive() {
+		event, ok, err := tipEvent(stream.Msg(), resume.Points())
+		if err != nil {
+			return delivered, err
+		}
+		if !ok {
+			continue
+		}
+
+		// A reset to the intersection may open the stream; it was delivered
+		// before.
+		if first {
+			first = false
+			if resume.IsResumeEcho(event) {
+				continue
+			}
+		}
+		resume.Track(event)
+
+		select {
+		case events <- event:
+			delivered = true
+		case <-ctx.Done():
+			return delivered, ctx.Err()
+		case <-done:
+			return delivered, nil
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return delivered, rpcError("FollowTip", err)
+	}
+	return delivered, errors.New("utxorpc: FollowTip stream ended")
+}
+
+// tipEvent translates a FollowTip message into a BlockEvent. recent, the
+// delivered points oldest first, locates the block an undone block rolls
+// back to. Messages without an action are skipped.
+func tipEvent(
+	msg *syncpb.FollowTipResponse,
+	recent []connector.ChainPoint,
+) (connector.BlockEvent, bool, error) {
+	switch {
+	case msg.GetApply() != nil:
+		header, body, err := cardanoBlock(msg.GetApply())
+		if err != nil {
+			return connector.BlockEvent{}, false, err
+		}
+		event := connector.BlockEvent{
+			Type: connector.BlockEventRollForward,
+			Point: connector.ChainPoint{
+				Slot: header.GetSlot(),
+				Hash: hex.EncodeToString(header.GetHash()),
+			},
+			Height: header.GetHeight(),
+		}
+		for _, tx := range body.GetTx() {
+			event.TxHashes = append(event.TxHashes, hex.EncodeToString(tx.GetHash()))
+		}
+		return event, true, nil
+	case msg.GetUndo() != nil:
+		header, _, err := cardanoBlock(msg.GetUndo())
+		if err != nil {
+			return connector.BlockEvent{}, false, err
+		}
+		undone := connector.ChainPoint{
+			Slot: header.GetSlot(),
+			Hash: hex.EncodeToString(header.GetHash()),
+		}
+		for i := len(recent) - 1; i > 0; i-- {
+			if recent[i] == undone {
+				return connector.BlockEvent{
+					Type:  connector.BlockEventRollBackward,
+					Point: recent[i-1],
+				}, true, nil
+			}
+		}
+		// Reconnecting lets the server name the common ancestor.
+		return connector.BlockEvent{}, false, fmt.Errorf(
+			"%w: FollowTip undid block %s, which precedes the resume points",
+			connector.ErrProviderInternal,
+			undone.Hash,
+		)
+	case msg.GetReset_() != nil:
+		ref := msg.GetReset_()
+		return connector.BlockEvent{
+			Type: connector.BlockEventRollBackward,
+			Point: connector.ChainPoint{
+				Slot: ref.GetSlot(),
+				Hash: hex.EncodeToString(ref.GetHash()),
+			},
+		}, true, nil
+	default:
+		return connector.BlockEvent{}, false, nil
+	}
+}
+
+// cardanoBlock returns the header and body of a Cardano block, which must
+// carry a header.
+func cardanoBlock(block *syncpb.AnyChainBlock) (*cardano.BlockHeader, *cardano.BlockBody, error) {
+	cb := block.GetCardano()
+	if cb == nil || cb.GetHeader() == nil {
+		return nil, nil, fmt.Errorf(
+			"%w: FollowTip block without a Cardano header",
+			connector.ErrProviderInternal,
+		)
+	}
+	return cb.GetHeader(), cb.GetBody(), nil
+}
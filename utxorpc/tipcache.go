@@ -0,0 +1,80 @@
+package utxorpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+// tipCache keeps the last fetched tip for a TTL and collapses concurrent
+// fetches into a single request. A nil cache, or one with a zero TTL, always
+// fetches.
+type tipCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	tip      connector.Tip
+	expires  time.Time
+	inflight *tipCall
+}
+
+type tipCall struct {
+	done chan struct{}
+	tip  connector.Tip
+	err  error
+}
+
+func newTipCache(ttl time.Duration) *tipCache {
+	return &tipCache{ttl: ttl}
+}
+
+// get returns the cached tip or joins/starts a fetch. The fetch runs detached
+// from ctx so that one caller giving up does not fail the others waiting on
+// it; each caller still returns as soon as its own ctx is done.
+func (c *tipCache) get(
+	ctx context.Context,
+	fetch func(context.Context) (connector.Tip, error),
+) (connector.Tip, error) {
+	if c == nil || c.ttl <= 0 {
+		return fetch(ctx)
+	}
+
+	c.mu.Lock()
+	if time.Now().Before(c.expires) {
+		tip := c.tip
+		c.mu.Unlock()
+		return tip, nil
+	}
+	call := c.inflight
+	if call == nil {
+		call = &tipCall{done: make(chan struct{})}
+		c.inflight = call
+		go c.run(context.WithoutCancel(ctx), call, fetch)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return connector.Tip{}, ctx.Err()
+	case <-call.done:
+		return call.tip, call.err
+	}
+}
+
+func (c *tipCache) run(
+	ctx context.Context,
+	call *tipCall,
+	fetch func(context.Context) (connector.Tip, error),
+) {
+	call.tip, call.err = fetch(ctx)
+
+	c.mu.Lock()
+	if call.err == nil {
+		c.tip = call.tip
+		c.expires = time.Now().Add(c.ttl)
+	}
+	c.inflight = nil
+	c.mu.Unlock()
+	close(call.done)
+}
@@ -1,13 +1,18 @@
 package utxorpc
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"math"
 	"math/big"
+	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"connectrpc.com/connect"
@@ -19,37 +24,108 @@ import (
 	"github.com/utxorpc/go-codegen/utxorpc/v1alpha/query"
 	"github.com/utxorpc/go-codegen/utxorpc/v1alpha/submit"
 	syncpb "github.com/utxorpc/go-codegen/utxorpc/v1alpha/sync"
-	sdk "github.com/utxorpc/go-sdk"
 	connector "github.com/zenGate-Global/cardano-connector-go"
 )
 
+// defaultSearchPageSize is the number of UTxOs requested per SearchUtxos
+// page unless a query asks for another page size.
+const defaultSearchPageSize = 100
+
 type UtxorpcProvider struct {
-	client    *sdk.UtxorpcClient
-	networkId int
+	client        *rpcClient
+	networkId     int
+	maxResults    int
+	strictOutRefs bool
+	tipCache      *tipCache
+	// done is closed by Close to stop the block streams and watches.
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
 type Config struct {
 	BaseUrl   string
 	ApiKey    string
 	NetworkId int
+	// MaxResults bounds the UTxOs an address or asset query collects across
+	// pages. A query with more matches returns the first MaxResults together
+	// with an error wrapping connector.ErrTruncated. Zero means no bound.
+	MaxResults int
+	// TipCacheTTL is how long GetTip reuses a fetched tip, so that bursts of
+	// callers share one request. Zero fetches the tip on every call.
+	TipCacheTTL time.Duration
+	// StrictOutRefs makes GetUtxosByOutRef fail with a
+	// *connector.MissingOutRefsError, alongside the UTxOs it did resolve, when
+	// any requested ref does not exist instead of silently omitting it.
+	StrictOutRefs bool
+	// Headers are sent with every request to every service, e.g. for
+	// authentication schemes other than ApiKey or metadata a proxy expects.
+	// ApiKey is sent as the dmtr-api-key header; setting that header here to
+	// another value is rejected by New.
+	Headers map[string]string
+	// TLSConfig secures the connection to an https BaseUrl, e.g. to trust a
+	// private CA, present a client certificate for mutual TLS or, for
+	// development only, skip verification with InsecureSkipVerify. New
+	// rejects it together with an http BaseUrl or an HTTPClient.
+	TLSConfig *tls.Config
+	// HTTPClient sends the requests instead of the provider's own client. It
+	// must speak HTTP/2, which gRPC requires; Headers are still added.
+	HTTPClient *http.Client
+	// ClientOptions are applied to the connect clients of all services after
+	// the provider's own options, e.g. connect.WithInterceptors or
+	// connect.WithReadMaxBytes.
+	ClientOptions []connect.ClientOption
+	// Retry repeats reads answered with Unavailable, as during endpoint
+	// deploys, or ResourceExhausted, when the request quota is used up: the
+	// ReadParams, ReadTip, FetchBlock, ReadData and UTxO query calls. It is
+	// disabled unless Retry.MaxAttempts is above one. SubmitTx and
+	// EvaluateTx are never retried.
+	Retry connector.RetryPolicy
 }
 
-var _ connector.Provider = (*UtxorpcProvider)(nil)
+// RetryPolicy is the type of Config.Retry. It is connector.RetryPolicy, shared
+// with the other providers; the name is kept so that code written against
+// utxorpc.RetryPolicy still compiles.
+type RetryPolicy = connector.RetryPolicy
+
+var (
+	_ connector.Provider          = (*UtxorpcProvider)(nil)
+	_ connector.UtxoQueryProvider = (*UtxorpcProvider)(nil)
+)
 
 func New(config Config) (*UtxorpcProvider, error) {
-	opts := []sdk.ClientOption{
-		sdk.WithBaseUrl(config.BaseUrl),
+	if config.MaxResults < 0 {
+		return nil, fmt.Errorf(
+			"%w: max results must not be negative, got %d",
+			connector.ErrInvalidInput,
+			config.MaxResults,
+		)
+	}
+	if config.TipCacheTTL < 0 {
+		return nil, fmt.Errorf(
+			"%w: tip cache TTL must not be negative, got %s",
+			connector.ErrInvalidInput,
+			config.TipCacheTTL,
+		)
 	}
-	if config.ApiKey != "" {
-		opts = append(opts, sdk.WithHeaders(map[string]string{
-			"dmtr-api-key": config.ApiKey,
-		}))
+	if err := checkTransport(config); err != nil {
+		return nil, err
+	}
+	headers, err := requestHeaders(config)
+	if err != nil {
+		return nil, err
+	}
+	retry, err := config.Retry.WithDefaults()
+	if err != nil {
+		return nil, err
 	}
-	client := sdk.NewClient(opts...)
 
 	provider := &UtxorpcProvider{
-		client:    client,
-		networkId: config.NetworkId,
+		client:        newRPCClient(config, headers, retry),
+		networkId:     config.NetworkId,
+		maxResults:    config.MaxResults,
+		strictOutRefs: config.StrictOutRefs,
+		tipCache:      newTipCache(config.TipCacheTTL),
+		done:          make(chan struct{}),
 	}
 
 	return provider, nil
@@ -59,7 +135,7 @@ func (u *UtxorpcProvider) GetProtocolParameters(
 	ctx context.Context,
 ) (backend.ProtocolParameters, error) {
 	req := connect.NewRequest(&query.ReadParamsRequest{})
-	resp, err := u.client.ReadParamsWithContext(ctx, req)
+	resp, err := u.client.query.ReadParams(ctx, req)
 	if err != nil {
 		return backend.ProtocolParameters{}, fmt.Errorf(
 			"utxorpc: ReadParams failed: %w",
@@ -96,6 +172,11 @@ func (u *UtxorpcProvider) GetProtocolParameters(
 		return backend.ProtocolParameters{}, err
 	}
 
+	// Since Babbage the minimum UTxO deposit is priced per byte; Blockfrost,
+	// Maestro and Kupmios report that price for MinUtxo and CoinsPerUtxoWord
+	// as well.
+	coinsPerUtxoByte := bigIntToString(params.GetCoinsPerUtxoByte())
+
 	pp := backend.ProtocolParameters{
 		MinFeeConstant:     bigIntToInt64(params.GetMinFeeConstant()),
 		MinFeeCoefficient:  bigIntToInt64(params.GetMinFeeCoefficient()),
@@ -117,7 +198,15 @@ func (u *UtxorpcProvider) GetProtocolParameters(
 		MaxValSize:          strconv.FormatUint(params.GetMaxValueSize(), 10),
 		CollateralPercent:   collateralPercent,
 		MaxCollateralInputs: maxCollateralInputs,
-		CoinsPerUtxoByte:    bigIntToString(params.GetCoinsPerUtxoByte()),
+		MinUtxo:             coinsPerUtxoByte,
+		CoinsPerUtxoWord:    coinsPerUtxoByte,
+		CoinsPerUtxoByte:    coinsPerUtxoByte,
+		// UTxO RPC carries only the flat reference-script price per byte. The
+		// size limit and the range and multiplier of the tiered fee are ledger
+		// constants it does not expose, so those fields keep their presets.
+		MinFeeRefScriptCostPerByte: rationalToFloat64(
+			params.GetMinFeeScriptRefCostPerByte(),
+		),
 	}
 
 	if prices := params.GetPrices(); prices != nil {
@@ -134,53 +223,72 @@ func (u *UtxorpcProvider) GetProtocolParameters(
 	}
 
 	// Parse cost models from UTxO RPC protobuf response.
-	// Keys match ComputeScriptDataHash expectations: "PlutusV1", "PlutusV2", "PlutusV3".
 	if cm := params.GetCostModels(); cm != nil {
 		pp.CostModels = make(map[string][]int64)
 		if v1 := cm.GetPlutusV1(); v1 != nil {
-			pp.CostModels["PlutusV1"] = append([]int64(nil), v1.GetValues()...)
+			pp.CostModels[connector.CostModelPlutusV1] = append([]int64(nil), v1.GetValues()...)
 		}
 		if v2 := cm.GetPlutusV2(); v2 != nil {
-			pp.CostModels["PlutusV2"] = append([]int64(nil), v2.GetValues()...)
+			pp.CostModels[connector.CostModelPlutusV2] = append([]int64(nil), v2.GetValues()...)
 		}
 		if v3 := cm.GetPlutusV3(); v3 != nil {
-			pp.CostModels["PlutusV3"] = append([]int64(nil), v3.GetValues()...)
+			pp.CostModels[connector.CostModelPlutusV3] = append([]int64(nil), v3.GetValues()...)
 		}
 	}
 
 	return mergeProtocolParamsWithPreset(pp, protocolParamsPreset), nil
 }
 
+// GetGenesisParams returns the genesis parameters of the configured network.
+// UTxO RPC does not serve them, so they are known only for mainnet, preprod
+// and preview; other networks yield ErrNotImplemented.
 func (u *UtxorpcProvider) GetGenesisParams(
 	ctx context.Context,
 ) (backend.GenesisParameters, error) {
-	return backend.GenesisParameters{}, connector.ErrNotImplemented
+	return u.genesisParams()
 }
 
 func (u *UtxorpcProvider) Network() int {
 	return u.networkId
 }
 
-func (u *UtxorpcProvider) Epoch(ctx context.Context) (int, error) {
-	return 0, connector.ErrNotImplemented
-}
-
-func (u *UtxorpcProvider) GetTip(ctx context.Context) (connector.Tip, error) {
+// readTip returns the reference of the chain tip.
+func (u *UtxorpcProvider) readTip(ctx context.Context) (*syncpb.BlockRef, error) {
 	tipReq := connect.NewRequest(&syncpb.ReadTipRequest{})
-	tipResp, err := u.client.ReadTipWithContext(ctx, tipReq)
+	tipResp, err := u.client.sync.ReadTip(ctx, tipReq)
 	if err != nil {
-		return connector.Tip{}, fmt.Errorf(
+		return nil, fmt.Errorf(
 			"utxorpc: failed to get tip: %w",
 			err,
 		)
 	}
 
 	if tipResp.Msg == nil || tipResp.Msg.GetTip() == nil {
-		return connector.Tip{}, errors.New(
+		return nil, errors.New(
 			"received nil tip from ReadTipResponse",
 		)
 	}
-	blockRef := tipResp.Msg.GetTip()
+	return tipResp.Msg.GetTip(), nil
+}
+
+// GetTip returns the chain tip. Its height comes from the tip reference when
+// the gateway fills it in; only otherwise is the tip block fetched for its
+// header. With Config.TipCacheTTL set, a tip is reused for that long.
+func (u *UtxorpcProvider) GetTip(ctx context.Context) (connector.Tip, error) {
+	return u.tipCache.get(ctx, u.fetchTip)
+}
+
+// fetchTip reads the chain tip from the gateway.
+func (u *UtxorpcProvider) fetchTip(ctx context.Context) (connector.Tip, error) {
+	blockRef, err := u.readTip(ctx)
+	if err != nil {
+		return connector.Tip{}, err
+	}
+	if len(blockRef.GetHash()) == 0 {
+		return connector.Tip{}, errors.New(
+			"utxorpc: ReadTip returned a tip without a block hash",
+		)
+	}
 
 	height := blockRef.GetHeight()
 	if height == 0 {
@@ -189,20 +297,17 @@ func (u *UtxorpcProvider) GetTip(ctx context.Context) (connector.Tip, error) {
 		blockReq := connect.NewRequest(&syncpb.FetchBlockRequest{
 			Ref: []*syncpb.BlockRef{blockRef},
 		})
-		blockResp, blockErr := u.client.FetchBlockWithContext(ctx, blockReq)
-		if blockErr != nil {
+		blockResp, err := u.client.sync.FetchBlock(ctx, blockReq)
+		if err != nil {
 			return connector.Tip{}, fmt.Errorf(
 				"utxorpc: failed to get block: %w",
-				blockErr,
+				err,
 			)
 		}
-		if blockResp.Msg == nil || len(blockResp.Msg.GetBlock()) == 0 ||
-			blockResp.Msg.GetBlock()[0] == nil {
-			return connector.Tip{}, errors.New(
-				"received nil or empty block data from FetchBlockResponse for tip",
-			)
+		height, err = blockHeight(blockResp.Msg)
+		if err != nil {
+			return connector.Tip{}, err
 		}
-		height = blockResp.Msg.GetBlock()[0].GetCardano().GetHeader().GetHeight()
 	}
 
 	return connector.Tip{
@@ -212,10 +317,48 @@ func (u *UtxorpcProvider) GetTip(ctx context.Context) (connector.Tip, error) {
 	}, nil
 }
 
+// blockHeight returns the height in the header of the single block of a
+// FetchBlock response.
+func blockHeight(msg *syncpb.FetchBlockResponse) (uint64, error) {
+	if msg == nil || len(msg.GetBlock()) == 0 || msg.GetBlock()[0] == nil {
+		return 0, errors.New(
+			"utxorpc: FetchBlock returned no block for the tip",
+		)
+	}
+	block := msg.GetBlock()[0].GetCardano()
+	if block == nil {
+		return 0, errors.New(
+			"utxorpc: FetchBlock returned a tip block without Cardano data",
+		)
+	}
+	header := block.GetHeader()
+	if header == nil {
+		return 0, errors.New(
+			"utxorpc: FetchBlock returned a tip block without a header",
+		)
+	}
+	return header.GetHeight(), nil
+}
+
 func (u *UtxorpcProvider) GetUtxosByAddress(
 	ctx context.Context,
 	addr string,
 ) ([]common.Utxo, error) {
+	return u.GetUtxosByAddressWith(ctx, addr)
+}
+
+// GetUtxosByAddressWith is GetUtxosByAddress honouring connector.WithPageSize.
+// UTxO RPC cannot order or range UTxO searches, so the other options are
+// ignored.
+func (u *UtxorpcProvider) GetUtxosByAddressWith(
+	ctx context.Context,
+	addr string,
+	opts ...connector.QueryOption,
+) ([]common.Utxo, error) {
+	q, err := connector.NewQueryOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
 	addrObj, err := common.NewAddress(addr)
 	if err != nil {
 		return nil, fmt.Errorf(
@@ -233,7 +376,7 @@ func (u *UtxorpcProvider) GetUtxosByAddress(
 		Address: &cardano.AddressPattern{
 			ExactAddress: addrBytes,
 		},
-	})
+	}, q.PageSize)
 }
 
 func (u *UtxorpcProvider) GetUtxosWithUnit(
@@ -254,13 +397,6 @@ func (u *UtxorpcProvider) GetUtxosWithUnit(
 		return nil, fmt.Errorf("utxorpc: failed to get address bytes: %w", err)
 	}
 
-	if unit == "lovelace" {
-		return nil, fmt.Errorf(
-			"%w: lovelace is not a valid unit",
-			connector.ErrInvalidUnit,
-		)
-	}
-
 	assetPattern, err := unitToAssetPattern(unit)
 	if err != nil {
 		return nil, err
@@ -271,20 +407,13 @@ func (u *UtxorpcProvider) GetUtxosWithUnit(
 			ExactAddress: addrBytes,
 		},
 		Asset: assetPattern,
-	})
+	}, defaultSearchPageSize)
 }
 
 func (u *UtxorpcProvider) GetUtxoByUnit(
 	ctx context.Context,
 	unit string,
 ) (*common.Utxo, error) {
-	if unit == "lovelace" {
-		return nil, fmt.Errorf(
-			"%w: lovelace is not a valid unit for GetUtxoByUnit",
-			connector.ErrInvalidInput,
-		)
-	}
-
 	assetPattern, err := unitToAssetPattern(unit)
 	if err != nil {
 		return nil, err
@@ -292,7 +421,7 @@ func (u *UtxorpcProvider) GetUtxoByUnit(
 
 	utxos, err := u.searchUtxos(ctx, &cardano.TxOutputPattern{
 		Asset: assetPattern,
-	})
+	}, defaultSearchPageSize)
 	if err != nil {
 		return nil, err
 	}
@@ -316,40 +445,93 @@ func (u *UtxorpcProvider) GetUtxoByUnit(
 	return &utxos[0], nil
 }
 
+// GetUtxosByOutRef returns the UTxOs at outRefs, in the order of outRefs; a
+// ref listed more than once is returned once. Refs without a UTxO are
+// skipped, or with Config.StrictOutRefs reported as a
+// *connector.MissingOutRefsError alongside the UTxOs that were found.
 func (u *UtxorpcProvider) GetUtxosByOutRef(
 	ctx context.Context,
 	outRefs []connector.OutRef,
 ) ([]common.Utxo, error) {
+	return u.utxosByOutRef(ctx, outRefs, u.strictOutRefs)
+}
+
+// utxosByOutRef is GetUtxosByOutRef with the strictness given by strict.
+func (u *UtxorpcProvider) utxosByOutRef(
+	ctx context.Context,
+	outRefs []connector.OutRef,
+	strict bool,
+) ([]common.Utxo, error) {
+	refs := make([]connector.OutRef, len(outRefs))
 	keys := make([]*query.TxoRef, len(outRefs))
 	for i, ref := range outRefs {
 		hash, err := hex.DecodeString(ref.TxHash)
-		if err != nil {
-			return nil, err
+		if err != nil || len(hash) != common.Blake2b256Size {
+			return nil, fmt.Errorf(
+				"%w: invalid tx hash %q in output reference",
+				connector.ErrInvalidInput,
+				ref.TxHash,
+			)
 		}
+		// UTxOs report their hash in lower case.
+		refs[i] = connector.OutRef{TxHash: hex.EncodeToString(hash), Index: ref.Index}
 		keys[i] = &query.TxoRef{
 			Hash:  hash,
 			Index: ref.Index,
 		}
 	}
+	if len(keys) == 0 {
+		return []common.Utxo{}, nil
+	}
 
 	req := connect.NewRequest(&query.ReadUtxosRequest{Keys: keys})
-	resp, err := u.client.ReadUtxosWithContext(ctx, req)
+	resp, err := u.client.query.ReadUtxos(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, rpcError("ReadUtxos", err)
 	}
-
-	ret := []common.Utxo{}
-	if resp.Msg == nil {
-		return ret, nil
+	var items []*query.AnyUtxoData
+	if resp.Msg != nil {
+		items = resp.Msg.GetItems()
 	}
-	for _, item := range resp.Msg.GetItems() {
+	return orderOutRefs(refs, items, strict)
+}
+
+// orderOutRefs parses the ReadUtxos items into UTxOs ordered like refs, whose
+// hashes must be lower case. With strict set, refs without an item are
+// reported as a *connector.MissingOutRefsError alongside the UTxOs.
+func orderOutRefs(
+	refs []connector.OutRef,
+	items []*query.AnyUtxoData,
+	strict bool,
+) ([]common.Utxo, error) {
+	utxos := make([]common.Utxo, 0, len(items))
+	for _, item := range items {
 		utxo, err := utxoFromRpc(item)
 		if err != nil {
-			return ret, err
+			return nil, fmt.Errorf("utxorpc: failed to parse UTxO from RPC: %w", err)
+		}
+		utxos = append(utxos, utxo)
+	}
+	ordered := connector.OrderByOutRefs(utxos, refs)
+	if !strict || len(ordered) == len(refs) {
+		return ordered, nil
+	}
+
+	found := make(map[connector.OutRef]bool, len(ordered))
+	for _, utxo := range ordered {
+		found[connector.OutRef{TxHash: utxo.Id.Id().String(), Index: utxo.Id.Index()}] = true
+	}
+	var missing []connector.OutRef
+	for _, ref := range refs {
+		if !found[ref] {
+			found[ref] = true
+			missing = append(missing, ref)
 		}
-		ret = append(ret, utxo)
 	}
-	return ret, nil
+	if len(missing) == 0 {
+		return ordered, nil
+	}
+	return ordered, &connector.MissingOutRefsError{Refs: missing}
 }
 
 func (u *UtxorpcProvider) GetDelegation(
@@ -359,13 +541,62 @@ func (u *UtxorpcProvider) GetDelegation(
 	return connector.Delegation{}, connector.ErrNotImplemented
 }
 
+// GetDatum reads the datum stored under datumHash with the query service's
+// ReadData. Unknown hashes yield ErrNotFound, and endpoints that do not serve
+// ReadData yield ErrNotImplemented.
 func (u *UtxorpcProvider) GetDatum(
 	ctx context.Context,
 	datumHash string,
 ) (common.Datum, error) {
-	return common.Datum{}, connector.ErrNotImplemented
+	hash, err := hex.DecodeString(datumHash)
+	if err != nil || len(hash) != common.Blake2b256Size {
+		return common.Datum{}, fmt.Errorf(
+			"%w: invalid datum hash %q",
+			connector.ErrInvalidInput,
+			datumHash,
+		)
+	}
+
+	req := connect.NewRequest(&query.ReadDataRequest{Keys: [][]byte{hash}})
+	resp, err := u.client.query.ReadData(ctx, req)
+	if err != nil {
+		return common.Datum{}, rpcError("ReadData", err)
+	}
+
+	var datumBytes []byte
+	if resp.Msg != nil {
+		for _, value := range resp.Msg.GetValues() {
+			if bytes.Equal(value.GetKey(), hash) {
+				datumBytes = value.GetNativeBytes()
+				break
+			}
+		}
+	}
+	if len(datumBytes) == 0 {
+		return common.Datum{}, fmt.Errorf(
+			"utxorpc: %w: datum hash %s",
+			connector.ErrNotFound,
+			datumHash,
+		)
+	}
+
+	var datum common.Datum
+	if err := datum.UnmarshalCBOR(datumBytes); err != nil {
+		return common.Datum{}, fmt.Errorf(
+			"utxorpc: %w: failed to decode datum CBOR for %s: %w",
+			connector.ErrProviderInternal,
+			datumHash,
+			err,
+		)
+	}
+	return datum, nil
 }
 
+// AwaitTx waits until the transaction is confirmed. A transaction whose first
+// output is already on chain is reported at once; otherwise the submit
+// service's WaitForTx stream is followed until it reports the transaction
+// confirmed. When the endpoint does not serve WaitForTx, or the stream ends
+// without a confirmation, the chain is polled every checkInterval instead.
 func (u *UtxorpcProvider) AwaitTx(
 	ctx context.Context,
 	txHash string,
@@ -379,33 +610,86 @@ func (u *UtxorpcProvider) AwaitTx(
 			err,
 		)
 	}
+	if checkInterval <= 0 {
+		checkInterval = 5 * time.Second
+	}
 
-	req := connect.NewRequest(&submit.WaitForTxRequest{
-		Ref: [][]byte{hashBytes},
-	})
-	stream, err := u.client.WaitForTxWithContext(ctx, req)
-	if err != nil {
-		return false, fmt.Errorf(
-			"utxorpc: WaitForTx failed: %w",
-			err,
-		)
+	onChain, err := u.txOnChain(ctx, hashBytes)
+	if err != nil || onChain {
+		return onChain, err
 	}
-	defer stream.Close()
 
+	confirmed, err := u.watchTx(ctx, hashBytes)
+	if confirmed || (err != nil && !errors.Is(err, connector.ErrNotImplemented)) {
+		return confirmed, err
+	}
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return false, ctx.Err()
-		default:
-			if stream.Receive() {
-				return true, nil
-			}
-			if err := stream.Err(); err != nil {
-				return false, fmt.Errorf("utxorpc: WaitForTx stream failed: %w", err)
-			}
-			time.Sleep(100 * time.Millisecond)
+		case <-ticker.C:
+		}
+		onChain, err := u.txOnChain(ctx, hashBytes)
+		if err != nil || onChain {
+			return onChain, err
+		}
+	}
+}
+
+// txOnChain reports whether the first output of the transaction is an
+// unspent output on chain. A transaction whose outputs have all been spent
+// again is not recognised.
+func (u *UtxorpcProvider) txOnChain(ctx context.Context, txHash []byte) (bool, error) {
+	req := connect.NewRequest(&query.ReadUtxosRequest{
+		Keys: []*query.TxoRef{{Hash: txHash, Index: 0}},
+	})
+	resp, err := u.client.query.ReadUtxos(ctx, req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return false, ctxErr
 		}
+		return false, rpcError("ReadUtxos", err)
+	}
+	return resp.Msg != nil && len(resp.Msg.GetItems()) > 0, nil
+}
+
+// watchTx follows the WaitForTx stream of the transaction until it reports
+// the transaction confirmed, which yields true. It yields false with a nil
+// error when the server ends the stream first, and the stream's error, which
+// wraps ErrNotImplemented for endpoints without WaitForTx, when it fails.
+func (u *UtxorpcProvider) watchTx(ctx context.Context, txHash []byte) (bool, error) {
+	req := connect.NewRequest(&submit.WaitForTxRequest{
+		Ref: [][]byte{txHash},
+	})
+	stream, err := u.client.submit.WaitForTx(ctx, req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return false, ctxErr
+		}
+		return false, rpcError("WaitForTx", err)
+	}
+	defer stream.Close()
+
+	// Receive blocks until the next message and returns false once the
+	// stream ends, fails or ctx is done. Endpoints may leave Ref empty, as
+	// the stream only ever reports the one transaction requested.
+	for stream.Receive() {
+		msg := stream.Msg()
+		if msg.GetStage() == submit.Stage_STAGE_CONFIRMED &&
+			(len(msg.GetRef()) == 0 || bytes.Equal(msg.GetRef(), txHash)) {
+			return true, nil
+		}
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return false, ctxErr
+	}
+	if err := stream.Err(); err != nil {
+		return false, rpcError("WaitForTx", err)
 	}
+	return false, nil
 }
 
 func (u *UtxorpcProvider) SubmitTx(
@@ -417,98 +701,292 @@ func (u *UtxorpcProvider) SubmitTx(
 			Type: &submit.AnyChainTx_Raw{Raw: tx},
 		},
 	})
-	resp, err := u.client.SubmitTxWithContext(ctx, req)
+	resp, err := u.client.submit.SubmitTx(ctx, req)
 	if err != nil {
-		return "", fmt.Errorf("utxorpc: SubmitTx failed: %w", err)
+		return "", submitTxError(err)
 	}
+	return submitTxHash(resp.Msg, tx)
+}
+
+// submitTxHash returns the hex id of the submitted transaction tx: the ref of
+// the submit response or, when the endpoint answers without one, the hash
+// computed from tx.
+func submitTxHash(msg *submit.SubmitTxResponse, tx []byte) (string, error) {
+	if ref := msg.GetRef(); len(ref) > 0 {
+		return hex.EncodeToString(ref), nil
+	}
+	txHash, err := connector.ComputeTxHash(tx)
+	if err != nil {
+		return "", fmt.Errorf(
+			"utxorpc: no tx ref in submit response and the tx hash cannot be computed: %w",
+			err,
+		)
+	}
+	return txHash, nil
+}
 
-	ref := resp.Msg.GetRef()
-	if len(ref) == 0 {
-		return "", errors.New("utxorpc: no tx ref in submit response")
+// submitTxError translates a failed SubmitTx call. Rejections of the
+// transaction become a *connector.SubmissionError; transport failures, after
+// which the transaction may or may not have reached the node, do not.
+func submitTxError(err error) error {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		switch connectErr.Code() {
+		case connect.CodeInvalidArgument,
+			connect.CodeFailedPrecondition,
+			connect.CodeAborted:
+			return fmt.Errorf("utxorpc: %w", &connector.SubmissionError{
+				Message: connectErr.Message(),
+			})
+		}
 	}
-	return hex.EncodeToString(ref), nil
+	return fmt.Errorf("utxorpc: SubmitTx failed: %w", err)
 }
 
-// EvaluateTx evaluates the scripts in a transaction. The additionalUTxOs
-// argument is IGNORED: the utxorpc EvalTx schema (submit.EvalTxRequest) carries
-// only the raw transaction CBOR and has no field for additional/resolved
-// UTxOs. This backend can therefore only evaluate transactions whose inputs
-// are already visible on-chain to the provider and does NOT support evaluating
-// off-chain or chained inputs.
+// EvaluateTx evaluates the scripts in a transaction. The utxorpc EvalTx
+// schema (submit.EvalTxRequest) carries only the raw transaction CBOR and has
+// no field for additional/resolved UTxOs, so the node can only resolve inputs
+// that are already on chain. additionalUTxOs that the provider sees on chain
+// are therefore accepted as is; if any of them is not, evaluation is
+// unsupported here and EvaluateTx returns an error wrapping
+// connector.ErrNotImplemented, so that failover wrappers can route the call to
+// a backend that accepts additional UTxOs.
 func (u *UtxorpcProvider) EvaluateTx(
 	ctx context.Context,
 	tx []byte,
 	additionalUTxOs []common.Utxo,
 ) (map[common.RedeemerKey]common.ExUnits, error) {
-	// Per the Provider contract, additionalUTxOs are ignored (not an error) by
-	// backends that cannot forward them. The utxorpc EvalTx schema carries only
-	// the raw tx CBOR and has no field for resolved UTxOs, so off-chain/chained
-	// inputs cannot be supplied here.
-	_ = additionalUTxOs
+	if len(additionalUTxOs) > 0 {
+		refs := make([]connector.OutRef, len(additionalUTxOs))
+		for i, utxo := range additionalUTxOs {
+			refs[i] = connector.OutRef{
+				TxHash: utxo.Id.Id().String(),
+				Index:  utxo.Id.Index(),
+			}
+		}
+		onChain, err := u.utxosByOutRef(ctx, refs, false)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"utxorpc: failed to look up additional UTxOs: %w",
+				err,
+			)
+		}
+		if err := offChainUtxosError(refs, onChain); err != nil {
+			return nil, err
+		}
+	}
 
 	req := connect.NewRequest(&submit.EvalTxRequest{
 		Tx: &submit.AnyChainTx{
 			Type: &submit.AnyChainTx_Raw{Raw: tx},
 		},
 	})
-	resp, err := u.client.EvalTxWithContext(ctx, req)
+	resp, err := u.client.submit.EvalTx(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 	return evalTxResponseToExUnits(resp.Msg)
 }
 
+// GetScriptCborByScriptHash returns the hex of the script with the given hash,
+// in the framing Blockfrost and Kupo serve. UTxO RPC cannot look scripts up
+// by hash, so the script is taken from a UTxO that carries it as reference
+// script among those locked at the script's own addresses, which is where
+// validators are commonly deployed. A script found at none of them yields
+// ErrNotFound; endpoints that cannot search by payment credential yield
+// ErrNotImplemented.
 func (u *UtxorpcProvider) GetScriptCborByScriptHash(
 	ctx context.Context,
 	scriptHash string,
 ) (string, error) {
-	return "", connector.ErrNotImplemented
+	hash, err := hex.DecodeString(scriptHash)
+	if err != nil || len(hash) != common.Blake2b224Size {
+		return "", fmt.Errorf(
+			"%w: invalid script hash %q",
+			connector.ErrInvalidInput,
+			scriptHash,
+		)
+	}
+	return findReferenceScript(ctx, u.searchPage, hash, u.maxResults)
 }
 
-// searchUtxos runs a SearchUtxos query for the given Cardano output pattern and
-// parses the matched items into gouroboros common.Utxo values.
+// findReferenceScript pages through the UTxOs locked by the payment
+// credential hash and returns the hex of the first reference script with
+// that hash, without fetching the pages after it.
+func findReferenceScript(
+	ctx context.Context,
+	fetch searchPageFunc,
+	hash []byte,
+	maxResults int,
+) (string, error) {
+	var found string
+	err := walkUtxoSearch(ctx, fetch, &cardano.TxOutputPattern{
+		Address: &cardano.AddressPattern{PaymentPart: hash},
+	}, defaultSearchPageSize, maxResults, func(utxo common.Utxo) bool {
+		script := utxo.Output.ScriptRef()
+		if script != nil && bytes.Equal(script.Hash().Bytes(), hash) {
+			found = hex.EncodeToString(script.RawScriptBytes())
+			return false
+		}
+		return true
+	})
+	if found != "" {
+		return found, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf(
+		"utxorpc: %w: no UTxO at the addresses of script %x carries it as "+
+			"reference script",
+		connector.ErrNotFound,
+		hash,
+	)
+}
+
+// searchPageFunc fetches one page of a SearchUtxos query.
+type searchPageFunc func(
+	ctx context.Context,
+	req *query.SearchUtxosRequest,
+) (*query.SearchUtxosResponse, error)
+
+// searchUtxos runs a SearchUtxos query for the given Cardano output pattern,
+// following the server's pages of pageSize items, and parses the matched
+// items into gouroboros common.Utxo values. A non-positive pageSize uses
+// defaultSearchPageSize.
 func (u *UtxorpcProvider) searchUtxos(
 	ctx context.Context,
 	pattern *cardano.TxOutputPattern,
+	pageSize int,
 ) ([]common.Utxo, error) {
-	req := connect.NewRequest(&query.SearchUtxosRequest{
-		Predicate: &query.UtxoPredicate{
-			Match: &query.AnyUtxoPattern{
-				UtxoPattern: &query.AnyUtxoPattern_Cardano{
-					Cardano: pattern,
-				},
-			},
-		},
-	})
-	resp, err := u.client.SearchUtxosWithContext(ctx, req)
+	return searchAllUtxos(ctx, u.searchPage, pattern, pageSize, u.maxResults)
+}
+
+// searchPage is the searchPageFunc backed by the provider's client.
+func (u *UtxorpcProvider) searchPage(
+	ctx context.Context,
+	req *query.SearchUtxosRequest,
+) (*query.SearchUtxosResponse, error) {
+	resp, err := u.client.query.SearchUtxos(ctx, connect.NewRequest(req))
 	if err != nil {
-		return nil, fmt.Errorf("utxorpc: SearchUtxos failed: %w", err)
+		return nil, rpcError("SearchUtxos", err)
 	}
+	return resp.Msg, nil
+}
 
+// searchAllUtxos collects the UTxOs matching pattern from every page fetch
+// returns, passing each page's next token on to the following request, until
+// a page comes without one. Once maxResults items are collected with more
+// pending, they are returned with an error wrapping connector.ErrTruncated;
+// zero means no bound.
+func searchAllUtxos(
+	ctx context.Context,
+	fetch searchPageFunc,
+	pattern *cardano.TxOutputPattern,
+	pageSize int,
+	maxResults int,
+) ([]common.Utxo, error) {
 	ret := []common.Utxo{}
-	if resp.Msg == nil {
-		return ret, nil
+	err := walkUtxoSearch(ctx, fetch, pattern, pageSize, maxResults,
+		func(utxo common.Utxo) bool {
+			ret = append(ret, utxo)
+			return true
+		})
+	return ret, err
+}
+
+// walkUtxoSearch passes the UTxOs matching pattern to visit one page at a
+// time, fetching the next page only once visit has seen every item of the
+// current one. It stops without error as soon as visit returns false, and
+// with an error wrapping connector.ErrTruncated once maxResults items have
+// been visited with more pending; zero means no bound. A non-positive
+// pageSize uses defaultSearchPageSize.
+func walkUtxoSearch(
+	ctx context.Context,
+	fetch searchPageFunc,
+	pattern *cardano.TxOutputPattern,
+	pageSize int,
+	maxResults int,
+	visit func(common.Utxo) bool,
+) error {
+	if pageSize <= 0 {
+		pageSize = defaultSearchPageSize
 	}
-	for _, item := range resp.Msg.GetItems() {
-		utxo, err := utxoFromRpc(item)
+	pageSize = min(pageSize, math.MaxInt32)
+
+	visited := 0
+	token := ""
+	for {
+		msg, err := fetch(ctx, &query.SearchUtxosRequest{
+			Predicate: &query.UtxoPredicate{
+				Match: &query.AnyUtxoPattern{
+					UtxoPattern: &query.AnyUtxoPattern_Cardano{
+						Cardano: pattern,
+					},
+				},
+			},
+			MaxItems:   int32(pageSize),
+			StartToken: token,
+		})
 		if err != nil {
-			return ret, fmt.Errorf("utxorpc: failed to parse UTxO from RPC: %w", err)
+			return err
+		}
+		if msg == nil {
+			return nil
 		}
-		ret = append(ret, utxo)
+
+		for _, item := range msg.GetItems() {
+			if maxResults > 0 && visited == maxResults {
+				return truncatedError(maxResults)
+			}
+			utxo, err := utxoFromRpc(item)
+			if err != nil {
+				return fmt.Errorf("utxorpc: failed to parse UTxO from RPC: %w", err)
+			}
+			visited++
+			if !visit(utxo) {
+				return nil
+			}
+		}
+
+		next := msg.GetNextToken()
+		if next == "" {
+			return nil
+		}
+		if next == token {
+			return fmt.Errorf(
+				"utxorpc: SearchUtxos returned next token %q again",
+				next,
+			)
+		}
+		if maxResults > 0 && visited == maxResults {
+			return truncatedError(maxResults)
+		}
+		token = next
 	}
-	return ret, nil
 }
 
-// unitToAssetPattern converts an asset unit (policyId hex + asset name hex) into
-// a UTxO RPC AssetPattern.
+// truncatedError reports a search cut off after maxResults UTxOs.
+func truncatedError(maxResults int) error {
+	return fmt.Errorf(
+		"%w: more than %d UTxOs match",
+		connector.ErrTruncated,
+		maxResults,
+	)
+}
+
+// unitToAssetPattern converts an asset unit, concatenated or in the dotted
+// "policy.assetName" form, into a UTxO RPC AssetPattern. A unit without an
+// asset name leaves the name unconstrained. Malformed units, and "lovelace",
+// yield connector.ErrInvalidUnit.
 func unitToAssetPattern(unit string) (*cardano.AssetPattern, error) {
-	policyId, assetName, err := backend.ParseAssetUnit(unit)
+	policyId, assetName, err := connector.ParseUnit(unit)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", connector.ErrInvalidUnit, err)
+		return nil, err
 	}
 	return &cardano.AssetPattern{
 		PolicyId:  policyId.Bytes(),
-		AssetName: assetName.Bytes(),
+		AssetName: assetName,
 	}, nil
 }
 
@@ -547,10 +1025,40 @@ func utxoFromRpc(item *query.AnyUtxoData) (common.Utxo, error) {
 	}, nil
 }
 
+// offChainUtxosError returns an error wrapping connector.ErrNotImplemented
+// naming the refs that have no UTxO in onChain, or nil when there are none.
+func offChainUtxosError(refs []connector.OutRef, onChain []common.Utxo) error {
+	found := make(map[connector.OutRef]bool, len(onChain))
+	for _, utxo := range onChain {
+		found[connector.OutRef{
+			TxHash: utxo.Id.Id().String(),
+			Index:  utxo.Id.Index(),
+		}] = true
+	}
+
+	var missing []string
+	for _, ref := range refs {
+		if !found[ref] {
+			missing = append(missing, fmt.Sprintf("%s#%d", ref.TxHash, ref.Index))
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf(
+		"%w: utxorpc EvalTx cannot take additional UTxOs, and these are not on chain: %s",
+		connector.ErrNotImplemented,
+		strings.Join(missing, ", "),
+	)
+}
+
 // evalTxResponseToExUnits converts an EvalTxResponse into a redeemer ExUnits
 // map. A missing report, missing cardano report, or zero evaluation results
 // is an error: returning an empty map with a nil error would let callers
-// silently keep zero execution budgets for their redeemers.
+// silently keep zero execution budgets for their redeemers. Errors in the
+// report are returned as a *connector.EvaluationError. Redeemers with a
+// purpose this package does not know are collected into one error, returned
+// together with the budgets of the other redeemers.
 func evalTxResponseToExUnits(msg *submit.EvalTxResponse) (map[common.RedeemerKey]common.ExUnits, error) {
 	if msg == nil {
 		return nil, errors.New("utxorpc: empty evaluate response")
@@ -563,11 +1071,23 @@ func evalTxResponseToExUnits(msg *submit.EvalTxResponse) (map[common.RedeemerKey
 	if cardanoReport == nil {
 		return nil, errors.New("utxorpc: no cardano evaluation report in response")
 	}
+	if evalErrors := cardanoReport.GetErrors(); len(evalErrors) > 0 {
+		messages := make([]string, 0, len(evalErrors))
+		for _, evalErr := range evalErrors {
+			if m := evalErr.GetMsg(); m != "" {
+				messages = append(messages, m)
+			}
+		}
+		return nil, &connector.EvaluationError{Message: strings.Join(messages, "; ")}
+	}
+
 	result := make(map[common.RedeemerKey]common.ExUnits)
+	var unknown []string
 	for _, redeemer := range cardanoReport.GetRedeemers() {
 		tag, err := utxorpcPurposeToRedeemerTag(redeemer.GetPurpose())
 		if err != nil {
-			return nil, fmt.Errorf("utxorpc: failed to map redeemer purpose: %w", err)
+			unknown = append(unknown, fmt.Sprintf("%s:%d", redeemer.GetPurpose(), redeemer.GetIndex()))
+			continue
 		}
 		key := common.RedeemerKey{
 			Tag:   tag,
@@ -587,6 +1107,13 @@ func evalTxResponseToExUnits(msg *submit.EvalTxResponse) (map[common.RedeemerKey
 			Steps:  int64(steps),
 		}
 	}
+	if len(unknown) > 0 {
+		return result, fmt.Errorf(
+			"%w: unsupported redeemer purposes in evaluation report: %s",
+			connector.ErrProviderInternal,
+			strings.Join(unknown, ", "),
+		)
+	}
 	if len(result) == 0 {
 		return nil, errors.New("utxorpc: script evaluation returned no results")
 	}
@@ -605,6 +1132,10 @@ func utxorpcPurposeToRedeemerTag(purpose cardano.RedeemerPurpose) (common.Redeem
 		return common.RedeemerTagCert, nil
 	case cardano.RedeemerPurpose_REDEEMER_PURPOSE_REWARD:
 		return common.RedeemerTagReward, nil
+	case cardano.RedeemerPurpose_REDEEMER_PURPOSE_VOTE:
+		return common.RedeemerTagVoting, nil
+	case cardano.RedeemerPurpose_REDEEMER_PURPOSE_PROPOSE:
+		return common.RedeemerTagProposing, nil
 	default:
 		return 0, fmt.Errorf("unsupported redeemer purpose: %d", purpose)
 	}
@@ -1,17 +1,38 @@
 package utxorpc
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"connectrpc.com/connect"
 	"github.com/Salvionied/apollo/v2/constants"
+	"github.com/blinklabs-io/gouroboros/cbor"
 	"github.com/blinklabs-io/gouroboros/ledger/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/utxorpc/go-codegen/utxorpc/v1alpha/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1alpha/query"
+	"github.com/utxorpc/go-codegen/utxorpc/v1alpha/query/queryconnect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1alpha/submit"
+	"github.com/utxorpc/go-codegen/utxorpc/v1alpha/submit/submitconnect"
+	syncpb "github.com/utxorpc/go-codegen/utxorpc/v1alpha/sync"
+	"github.com/utxorpc/go-codegen/utxorpc/v1alpha/sync/syncconnect"
 	connector "github.com/zenGate-Global/cardano-connector-go"
+	"github.com/zenGate-Global/cardano-connector-go/blockfrost"
 	"github.com/zenGate-Global/cardano-connector-go/tests"
 )
 
@@ -61,10 +82,122 @@ func TestGetProtocolParameters(t *testing.T) {
 	if pp.MaxTxSize == 0 {
 		t.Error("Expected non-zero MaxTxSize")
 	}
+	if diff := tests.CostModelKeysDiff(pp.CostModels); diff != "" {
+		t.Error(diff)
+	}
+
+	// Ratios below one must survive the conversion instead of truncating
+	// to zero.
+	assert.InDelta(t, 0.0577, pp.PriceMem, 0.001, "PriceMem")
+	assert.InDelta(t, 0.0000721, pp.PriceStep, 0.000001, "PriceStep")
+	assert.InDelta(t, 0.3, pp.PoolInfluence, 0.01, "PoolInfluence")
+	assert.InDelta(t, 0.003, pp.MonetaryExpansion, 0.0001, "MonetaryExpansion")
+	assert.InDelta(t, 0.2, pp.TreasuryExpansion, 0.01, "TreasuryExpansion")
+}
+
+func TestGetProtocolParametersMatchesBlockfrost(t *testing.T) {
+	projectID := os.Getenv("BLOCKFROST_KEY")
+	if projectID == "" {
+		t.Skip("BLOCKFROST_KEY environment variable not set")
+	}
+	bf, err := blockfrost.New(blockfrost.Config{
+		ProjectID:   projectID,
+		NetworkName: "preprod",
+		NetworkId:   int(constants.PREPROD),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create Blockfrost provider: %v", err)
+	}
+	utxorpc := setupUtxorpc(t)
+	ctx := context.Background()
+
+	want, err := bf.GetProtocolParameters(ctx)
+	if err != nil {
+		t.Fatalf("Blockfrost GetProtocolParameters failed: %v", err)
+	}
+	got, err := utxorpc.GetProtocolParameters(ctx)
+	if err != nil {
+		t.Fatalf("GetProtocolParameters failed: %v", err)
+	}
+
+	assert.Equal(t, want.MinUtxo, got.MinUtxo, "MinUtxo")
+	assert.Equal(t, want.CoinsPerUtxoByte, got.CoinsPerUtxoByte, "CoinsPerUtxoByte")
+	assert.Equal(t, want.CoinsPerUtxoWord, got.CoinsPerUtxoWord, "CoinsPerUtxoWord")
+	assert.Equal(
+		t,
+		want.MinFeeRefScriptCostPerByte,
+		got.MinFeeRefScriptCostPerByte,
+		"MinFeeRefScriptCostPerByte",
+	)
+}
+
+func TestRationalToFloat64(t *testing.T) {
+	assert.InDelta(
+		t,
+		0.0577,
+		rationalToFloat64(&cardano.RationalNumber{Numerator: 577, Denominator: 10000}),
+		1e-12,
+	)
+	assert.InDelta(
+		t,
+		0.0000721,
+		rationalToFloat64(&cardano.RationalNumber{Numerator: 721, Denominator: 10000000}),
+		1e-15,
+	)
+	assert.Equal(t, 1.5, rationalToFloat64(&cardano.RationalNumber{Numerator: 3, Denominator: 2}))
+	assert.Zero(t, rationalToFloat64(&cardano.RationalNumber{Numerator: 3}))
+	assert.Zero(t, rationalToFloat64(nil))
 }
 
 func TestGetGenesisParams(t *testing.T) {
-	t.Skip("Skipping genesis params test - Utxorpc does not support it")
+	utxorpc := setupUtxorpc(t)
+	ctx := context.Background()
+
+	gp, err := utxorpc.GetGenesisParams(ctx)
+	if err != nil {
+		t.Fatalf("GetGenesisParams failed: %v", err)
+	}
+
+	assert.Equal(
+		t,
+		0.05,
+		gp.ActiveSlotsCoefficient,
+		"ActiveSlotsCoefficient should be 0.05",
+	)
+	assert.Equal(t, 5, gp.UpdateQuorum, "UpdateQuorum should be 5")
+	assert.Equal(
+		t,
+		"45000000000000000",
+		gp.MaxLovelaceSupply,
+		"MaxLovelaceSupply should be 45000000000000000",
+	)
+	assert.Equal(t, 1, gp.NetworkMagic, "NetworkMagic should be 1")
+	assert.Equal(t, 432000, gp.EpochLength, "EpochLength should be 432000")
+	assert.Equal(
+		t,
+		int64(1654041600),
+		gp.SystemStart,
+		"SystemStart should be 1654041600",
+	)
+	assert.Equal(
+		t,
+		129600,
+		gp.SlotsPerKesPeriod,
+		"SlotsPerKesPeriod should be 129600",
+	)
+	assert.Equal(t, 1, gp.SlotLength, "SlotLength should be 1")
+	assert.Equal(t, 62, gp.MaxKesEvolutions, "MaxKesEvolutions should be 62")
+	assert.Equal(t, 2160, gp.SecurityParam, "SecurityParam should be 2160")
+}
+
+func TestGetGenesisParamsUnknownNetwork(t *testing.T) {
+	provider, err := New(Config{BaseUrl: "http://127.0.0.1:50051", NetworkId: 42})
+	if err != nil {
+		t.Fatalf("Failed to create UTXORPC provider: %v", err)
+	}
+
+	_, err = provider.GetGenesisParams(context.Background())
+	assert.ErrorIs(t, err, connector.ErrNotImplemented)
 }
 
 func TestNetwork(t *testing.T) {
@@ -78,7 +211,55 @@ func TestNetwork(t *testing.T) {
 }
 
 func TestEpoch(t *testing.T) {
-	t.Skip("Skipping epoch test - Utxorpc does not support it")
+	utxorpc := setupUtxorpc(t)
+	ctx := context.Background()
+
+	epoch, err := utxorpc.Epoch(ctx)
+	if err != nil {
+		t.Fatalf("Epoch failed: %v", err)
+	}
+
+	tip, err := utxorpc.GetTip(ctx)
+	if err != nil {
+		t.Fatalf("GetTip failed: %v", err)
+	}
+	// Preprod epochs are 432000 slots long from epoch 4 at slot 86400 on.
+	want := 4 + int((tip.Slot-86400)/432000)
+	assert.InDelta(t, want, epoch, 1, "Epoch should match the tip slot")
+	assert.True(t, epoch > 200, "Epoch should be greater than 200")
+}
+
+func TestEpochAtSlot(t *testing.T) {
+	cases := []struct {
+		network constants.Network
+		slot    uint64
+		epoch   uint64
+	}{
+		{constants.MAINNET, 0, 0},
+		{constants.MAINNET, 21599, 0},
+		{constants.MAINNET, 21600, 1},
+		{constants.MAINNET, 4492799, 207},
+		{constants.MAINNET, 4492800, 208},
+		{constants.MAINNET, 4924799, 208},
+		{constants.MAINNET, 4924800, 209},
+		{constants.MAINNET, 130636800, 500},
+		{constants.PREPROD, 86399, 3},
+		{constants.PREPROD, 86400, 4},
+		{constants.PREPROD, 518400, 5},
+		{constants.PREPROD, 41558400, 100},
+		{constants.PREVIEW, 0, 0},
+		{constants.PREVIEW, 86400, 1},
+		{constants.PREVIEW, 8640000, 100},
+	}
+	for _, tc := range cases {
+		provider := &UtxorpcProvider{networkId: int(tc.network)}
+		epoch, err := provider.epochAtSlot(tc.slot)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.epoch, epoch, "network %d slot %d", tc.network, tc.slot)
+	}
+
+	_, err := (&UtxorpcProvider{networkId: 42}).epochAtSlot(0)
+	assert.ErrorIs(t, err, connector.ErrNotImplemented)
 }
 
 func TestGetTip(t *testing.T) {
@@ -101,6 +282,76 @@ func TestGetTip(t *testing.T) {
 	assert.True(t, len(tip.Hash) == 64, "Hash should be 64 characters long")
 }
 
+func TestBlockHeight(t *testing.T) {
+	height, err := blockHeight(&syncpb.FetchBlockResponse{
+		Block: []*syncpb.AnyChainBlock{{
+			Chain: &syncpb.AnyChainBlock_Cardano{
+				Cardano: &cardano.Block{Header: &cardano.BlockHeader{Height: 4242}},
+			},
+		}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4242), height)
+}
+
+func TestBlockHeightMissingData(t *testing.T) {
+	for name, msg := range map[string]*syncpb.FetchBlockResponse{
+		"nil response": nil,
+		"no blocks":    {},
+		"nil block":    {Block: []*syncpb.AnyChainBlock{nil}},
+		"no cardano":   {Block: []*syncpb.AnyChainBlock{{}}},
+		"no header": {Block: []*syncpb.AnyChainBlock{{
+			Chain: &syncpb.AnyChainBlock_Cardano{Cardano: &cardano.Block{}},
+		}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := blockHeight(msg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTipCache(t *testing.T) {
+	var fetches atomic.Int64
+	fetch := func(context.Context) (connector.Tip, error) {
+		n := fetches.Add(1)
+		return connector.Tip{Slot: uint64(n), Height: uint64(n)}, nil
+	}
+	ctx := context.Background()
+
+	cache := newTipCache(time.Hour)
+	first, err := cache.get(ctx, fetch)
+	assert.NoError(t, err)
+	second, err := cache.get(ctx, fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, int64(1), fetches.Load())
+
+	uncached := newTipCache(0)
+	_, err = uncached.get(ctx, fetch)
+	assert.NoError(t, err)
+	_, err = uncached.get(ctx, fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), fetches.Load())
+}
+
+func TestTipCacheDoesNotKeepErrors(t *testing.T) {
+	var fetches atomic.Int64
+	fetch := func(context.Context) (connector.Tip, error) {
+		if fetches.Add(1) == 1 {
+			return connector.Tip{}, errors.New("unavailable")
+		}
+		return connector.Tip{Slot: 7}, nil
+	}
+	cache := newTipCache(time.Hour)
+
+	_, err := cache.get(context.Background(), fetch)
+	assert.Error(t, err)
+	tip, err := cache.get(context.Background(), fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), tip.Slot)
+}
+
 func TestGetUtxos(t *testing.T) {
 	utxorpc := setupUtxorpc(t)
 	ctx := context.Background()
@@ -116,6 +367,128 @@ func TestGetUtxos(t *testing.T) {
 	t.Logf("Found %d UTxOs", len(utxos))
 }
 
+// searchItem is a UTxO RPC search result paying lovelace to the discovery
+// address at output index of a fixed transaction.
+func searchItem(t *testing.T, index uint32, lovelace uint64) *query.AnyUtxoData {
+	t.Helper()
+	addr, err := common.NewAddress(
+		"addr_test1wpgexmeunzsykesf42d4eqet5yvzeap6trjnflxqtkcf66g0kpnxt",
+	)
+	if err != nil {
+		t.Fatalf("NewAddress failed: %v", err)
+	}
+	addrBytes, err := addr.Bytes()
+	if err != nil {
+		t.Fatalf("Address.Bytes failed: %v", err)
+	}
+	output, err := cbor.Encode([]any{addrBytes, lovelace})
+	if err != nil {
+		t.Fatalf("cbor.Encode failed: %v", err)
+	}
+	return &query.AnyUtxoData{
+		NativeBytes: output,
+		TxoRef:      &query.TxoRef{Hash: bytes.Repeat([]byte{0xab}, 32), Index: index},
+	}
+}
+
+// pagedSearch serves pages in turn, each linked to the next by its token, and
+// records the requests it receives.
+func pagedSearch(
+	pages [][]*query.AnyUtxoData,
+	requests *[]*query.SearchUtxosRequest,
+) searchPageFunc {
+	return func(
+		ctx context.Context,
+		req *query.SearchUtxosRequest,
+	) (*query.SearchUtxosResponse, error) {
+		*requests = append(*requests, req)
+		page := 0
+		if req.GetStartToken() != "" {
+			var err error
+			page, err = strconv.Atoi(strings.TrimPrefix(req.GetStartToken(), "page-"))
+			if err != nil {
+				return nil, err
+			}
+		}
+		resp := &query.SearchUtxosResponse{Items: pages[page]}
+		if page+1 < len(pages) {
+			resp.NextToken = fmt.Sprintf("page-%d", page+1)
+		}
+		return resp, nil
+	}
+}
+
+func TestSearchAllUtxosFollowsPages(t *testing.T) {
+	pages := [][]*query.AnyUtxoData{
+		{searchItem(t, 0, 1000000), searchItem(t, 1, 2000000)},
+		{searchItem(t, 2, 3000000), searchItem(t, 3, 4000000)},
+		{searchItem(t, 4, 5000000)},
+	}
+	var requests []*query.SearchUtxosRequest
+
+	utxos, err := searchAllUtxos(
+		context.Background(),
+		pagedSearch(pages, &requests),
+		&cardano.TxOutputPattern{},
+		2,
+		0,
+	)
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 5)
+	for i, utxo := range utxos {
+		assert.Equal(t, uint32(i), utxo.Id.Index())
+	}
+
+	assert.Len(t, requests, 3)
+	for i, req := range requests {
+		assert.Equal(t, int32(2), req.GetMaxItems())
+		if i == 0 {
+			assert.Empty(t, req.GetStartToken())
+		} else {
+			assert.Equal(t, fmt.Sprintf("page-%d", i), req.GetStartToken())
+		}
+	}
+}
+
+func TestSearchAllUtxosMaxResults(t *testing.T) {
+	pages := [][]*query.AnyUtxoData{
+		{searchItem(t, 0, 1000000), searchItem(t, 1, 2000000)},
+		{searchItem(t, 2, 3000000), searchItem(t, 3, 4000000)},
+		{searchItem(t, 4, 5000000)},
+	}
+	var requests []*query.SearchUtxosRequest
+
+	utxos, err := searchAllUtxos(
+		context.Background(),
+		pagedSearch(pages, &requests),
+		&cardano.TxOutputPattern{},
+		2,
+		3,
+	)
+	assert.ErrorIs(t, err, connector.ErrTruncated)
+	assert.Len(t, utxos, 3)
+	assert.Len(t, requests, 2)
+}
+
+func TestGetUtxosByAddressWithNegativePageSize(t *testing.T) {
+	provider, err := New(Config{BaseUrl: "http://127.0.0.1:1", NetworkId: int(constants.PREPROD)})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	_, err = provider.GetUtxosByAddressWith(
+		context.Background(),
+		tests.AddressToQuery,
+		connector.WithPageSize(-1),
+	)
+	assert.ErrorIs(t, err, connector.ErrInvalidInput)
+}
+
+func TestNewRejectsNegativeMaxResults(t *testing.T) {
+	_, err := New(Config{BaseUrl: "http://127.0.0.1:1", MaxResults: -1})
+	assert.ErrorIs(t, err, connector.ErrInvalidInput)
+}
+
 func TestGetUtxosWithUnit(t *testing.T) {
 	utxorpc := setupUtxorpc(t)
 	ctx := context.Background()
@@ -140,6 +513,48 @@ func TestGetUtxosWithUnit(t *testing.T) {
 	}
 }
 
+func TestUnitToAssetPattern(t *testing.T) {
+	policy := strings.Repeat("ab", 28)
+	policyBytes, _ := hex.DecodeString(policy)
+	name := "4d794e4654"
+	nameBytes, _ := hex.DecodeString(name)
+
+	cases := map[string]struct {
+		unit string
+		name []byte
+	}{
+		"concatenated": {policy + name, nameBytes},
+		"dotted":       {policy + "." + name, nameBytes},
+		"policy only":  {policy, []byte{}},
+		"dotted empty": {policy + ".", []byte{}},
+	}
+	for label, tc := range cases {
+		t.Run(label, func(t *testing.T) {
+			pattern, err := unitToAssetPattern(tc.unit)
+			assert.NoError(t, err)
+			assert.Equal(t, policyBytes, pattern.GetPolicyId())
+			assert.Equal(t, tc.name, pattern.GetAssetName())
+		})
+	}
+}
+
+func TestUnitToAssetPatternInvalid(t *testing.T) {
+	policy := strings.Repeat("ab", 28)
+	for _, unit := range []string{
+		"",
+		"lovelace",
+		policy[:20],
+		strings.Repeat("zz", 28),
+		policy + "4d7",
+		policy + "xyz0",
+		policy[:54] + ".4d79",
+		policy + strings.Repeat("00", 33),
+	} {
+		_, err := unitToAssetPattern(unit)
+		assert.ErrorIs(t, err, connector.ErrInvalidUnit, "unit %q", unit)
+	}
+}
+
 func TestGetUtxoByUnit(t *testing.T) {
 	utxorpc := setupUtxorpc(t)
 	ctx := context.Background()
@@ -186,12 +601,129 @@ func TestGetUtxosByOutRef(t *testing.T) {
 	}
 }
 
+func TestOrderOutRefsFollowsRequest(t *testing.T) {
+	txHash := strings.Repeat("ab", 32)
+	refs := []connector.OutRef{
+		{TxHash: txHash, Index: 2},
+		{TxHash: txHash, Index: 0},
+		{TxHash: txHash, Index: 1},
+		{TxHash: txHash, Index: 2},
+	}
+	// The server answers in its own order.
+	items := []*query.AnyUtxoData{
+		searchItem(t, 1, 2000000),
+		searchItem(t, 0, 1000000),
+		searchItem(t, 2, 3000000),
+	}
+
+	utxos, err := orderOutRefs(refs, items, false)
+	assert.NoError(t, err)
+	var indexes []uint32
+	for _, utxo := range utxos {
+		indexes = append(indexes, utxo.Id.Index())
+	}
+	assert.Equal(t, []uint32{2, 0, 1}, indexes)
+	assert.Equal(t, big.NewInt(3000000), utxos[0].Output.Amount())
+	assert.Equal(t, big.NewInt(1000000), utxos[1].Output.Amount())
+}
+
+func TestOrderOutRefsStrict(t *testing.T) {
+	txHash := strings.Repeat("ab", 32)
+	refs := []connector.OutRef{
+		{TxHash: txHash, Index: 0},
+		{TxHash: txHash, Index: 5},
+		{TxHash: txHash, Index: 5},
+	}
+	items := []*query.AnyUtxoData{searchItem(t, 0, 1000000)}
+
+	utxos, err := orderOutRefs(refs, items, false)
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 1)
+
+	utxos, err = orderOutRefs(refs, items, true)
+	assert.Len(t, utxos, 1)
+	var missingErr *connector.MissingOutRefsError
+	if assert.ErrorAs(t, err, &missingErr) {
+		assert.Equal(t, []connector.OutRef{{TxHash: txHash, Index: 5}}, missingErr.Refs)
+	}
+	assert.ErrorIs(t, err, connector.ErrNotFound)
+}
+
+func TestGetUtxosByOutRefInvalidHash(t *testing.T) {
+	provider, err := New(Config{BaseUrl: "http://127.0.0.1:1", NetworkId: int(constants.PREPROD)})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	_, err = provider.GetUtxosByOutRef(context.Background(), []connector.OutRef{
+		{TxHash: "not-a-hash", Index: 0},
+	})
+	assert.ErrorIs(t, err, connector.ErrInvalidInput)
+}
+
 func TestGetDelegation(t *testing.T) {
 	t.Skip("Skipping delegation test - Utxorpc does not support it")
 }
 
 func TestGetDatum(t *testing.T) {
-	t.Skip("Skipping datum test - Utxorpc does not support it")
+	utxorpc := setupUtxorpc(t)
+	ctx := context.Background()
+
+	datum, err := utxorpc.GetDatum(
+		ctx,
+		"9781f0bc32835479f5051e367556df615a9040714fe7df167782df8e3e5b76df",
+	)
+	if err != nil {
+		if errors.Is(err, connector.ErrNotImplemented) {
+			t.Skipf("Endpoint does not serve ReadData: %v", err)
+		}
+		t.Fatalf("GetDatum failed: %v", err)
+	}
+
+	datumBytes, err := datum.MarshalCBOR()
+	if err != nil {
+		t.Fatalf("Failed to marshal datum: %v", err)
+	}
+
+	actualDatumHex := hex.EncodeToString(datumBytes)
+
+	if actualDatumHex != tests.ExpectedDatum {
+		t.Errorf(
+			"Expected datum %s, got %s",
+			tests.ExpectedDatum,
+			actualDatumHex,
+		)
+	}
+}
+
+func TestGetDatumInvalidHash(t *testing.T) {
+	provider, err := New(Config{BaseUrl: "http://127.0.0.1:1", NetworkId: int(constants.PREPROD)})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	_, err = provider.GetDatum(context.Background(), "not-a-hash")
+	assert.ErrorIs(t, err, connector.ErrInvalidInput)
+}
+
+func TestRPCError(t *testing.T) {
+	cases := []struct {
+		code connect.Code
+		want error
+	}{
+		{connect.CodeUnimplemented, connector.ErrNotImplemented},
+		{connect.CodeNotFound, connector.ErrNotFound},
+		{connect.CodeInvalidArgument, connector.ErrInvalidInput},
+	}
+	for _, tc := range cases {
+		err := rpcError("ReadData", connect.NewError(tc.code, errors.New("boom")))
+		assert.ErrorIs(t, err, tc.want)
+		assert.Contains(t, err.Error(), "ReadData")
+	}
+
+	err := rpcError("ReadData", connect.NewError(connect.CodeUnavailable, errors.New("down")))
+	assert.NotErrorIs(t, err, connector.ErrNotImplemented)
+	assert.Equal(t, connect.CodeUnavailable, connect.CodeOf(err))
 }
 
 func TestAwaitTx(t *testing.T) {
@@ -213,135 +745,1353 @@ func TestAwaitTx(t *testing.T) {
 	}
 }
 
-func TestSubmitTxBadRequest(t *testing.T) {
-	utxorpc := setupUtxorpc(t)
-	ctx := context.Background()
+func TestAwaitTxInvalidHash(t *testing.T) {
+	provider, err := New(Config{BaseUrl: "http://127.0.0.1:1", NetworkId: int(constants.PREPROD)})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
 
-	// Test with invalid transaction data
-	invalidTxBytes := []byte{0x80} // Invalid CBOR
+	_, err = provider.AwaitTx(context.Background(), "not-a-hash", time.Second)
+	assert.ErrorIs(t, err, connector.ErrInvalidInput)
+}
 
-	_, err := utxorpc.SubmitTx(ctx, invalidTxBytes)
-	if err == nil {
-		t.Error("Expected SubmitTx to fail with invalid transaction data")
+func TestAwaitTxCanceled(t *testing.T) {
+	provider, err := New(Config{BaseUrl: "http://127.0.0.1:1", NetworkId: int(constants.PREPROD)})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
 	}
-}
 
-func TestEvaluateTxSample1(t *testing.T) {
-	t.Skip("Skipping sample 1 - Utxorpc does not support it")
-	utxorpc := setupUtxorpc(t)
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	confirmed, err := provider.AwaitTx(
+		ctx,
+		"2a1f95a9d85bf556a3dc889831593ee963ba491ca7164d930b3af0802a9796d0",
+		time.Second,
+	)
+	assert.False(t, confirmed)
+	assert.ErrorIs(t, err, context.Canceled)
+}
 
-	tx1Bytes, _ := hex.DecodeString(tests.ApolloEvalSample1Transaction)
+// waitStub answers WaitForTx with msgs.
+type waitStub struct {
+	submitconnect.UnimplementedSubmitServiceHandler
+	msgs []*submit.WaitForTxResponse
+}
 
-	redeemers, err := utxorpc.EvaluateTx(ctx, tx1Bytes, tests.ApolloEvalSample1UTxOs)
-	if err != nil {
-		t.Fatalf("EvaluateTx failed: %v", err)
+func (s *waitStub) WaitForTx(
+	_ context.Context,
+	_ *connect.Request[submit.WaitForTxRequest],
+	stream *connect.ServerStream[submit.WaitForTxResponse],
+) error {
+	for _, msg := range s.msgs {
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	if ok, diff := tests.RedeemersApproxEqual(redeemers, tests.ApolloEvalSample1RedeemersExUnits, 0.02); !ok {
-		t.Errorf("redeemers mismatch (>2%% drift): %s", diff)
+func TestWatchTxConfirmation(t *testing.T) {
+	hash := bytes.Repeat([]byte{0xcd}, 32)
+	other := bytes.Repeat([]byte{0xef}, 32)
+	cases := []struct {
+		name      string
+		msgs      []*submit.WaitForTxResponse
+		confirmed bool
+	}{
+		{"matching ref", []*submit.WaitForTxResponse{stageMsg(hash, submit.Stage_STAGE_CONFIRMED)}, true},
+		{"empty ref", []*submit.WaitForTxResponse{stageMsg(nil, submit.Stage_STAGE_CONFIRMED)}, true},
+		{"other ref", []*submit.WaitForTxResponse{stageMsg(other, submit.Stage_STAGE_CONFIRMED)}, false},
+		{"not confirmed", []*submit.WaitForTxResponse{stageMsg(hash, submit.Stage_STAGE_MEMPOOL)}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.Handle(submitconnect.NewSubmitServiceHandler(&waitStub{msgs: c.msgs}))
+			srv := httptest.NewUnstartedServer(mux)
+			srv.EnableHTTP2 = true
+			srv.StartTLS()
+			t.Cleanup(srv.Close)
+			provider, err := New(Config{
+				BaseUrl:   srv.URL,
+				NetworkId: int(constants.PREPROD),
+				TLSConfig: &tls.Config{InsecureSkipVerify: true},
+			})
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			confirmed, err := provider.watchTx(context.Background(), hash)
+			assert.NoError(t, err)
+			assert.Equal(t, c.confirmed, confirmed)
+		})
 	}
 }
 
-// Invalid request: failed to decode payload from base64 or base16.
-func TestEvaluateTxSample2(t *testing.T) {
-	t.Skip("Skipping sample 2 - Utxorpc does not support it")
-	utxorpc := setupUtxorpc(t)
-	ctx := context.Background()
+// stubStageStream replays msgs, then ends with err.
+type stubStageStream struct {
+	msgs []*submit.WaitForTxResponse
+	err  error
+	next *submit.WaitForTxResponse
+}
 
-	tx2Bytes, _ := hex.DecodeString(tests.ApolloEvalSample2Transaction)
+func (s *stubStageStream) Receive() bool {
+	if len(s.msgs) == 0 {
+		return false
+	}
+	s.next, s.msgs = s.msgs[0], s.msgs[1:]
+	return true
+}
 
-	redeemers, err := utxorpc.EvaluateTx(ctx, tx2Bytes, tests.ApolloEvalSample2UTxOs)
-	if err != nil {
-		t.Fatalf("EvaluateTx failed: %v", err)
+func (s *stubStageStream) Msg() *submit.WaitForTxResponse { return s.next }
+func (s *stubStageStream) Err() error                     { return s.err }
+func (s *stubStageStream) Close() error                   { return nil }
+
+// stubStages opens the given streams in turn.
+func stubStages(streams ...*stubStageStream) openStagesFunc {
+	var opened atomic.Int64
+	return func(context.Context, []byte) (stageStream, error) {
+		i := opened.Add(1) - 1
+		if int(i) >= len(streams) {
+			return nil, connect.NewError(connect.CodeUnavailable, errors.New("no more streams"))
+		}
+		return streams[i], nil
 	}
+}
 
-	if ok, diff := tests.RedeemersApproxEqual(redeemers, tests.ApolloEvalSample2RedeemersExUnits, 0.02); !ok {
-		t.Errorf("redeemers mismatch (>2%% drift): %s", diff)
+func stageMsg(hash []byte, stage submit.Stage) *submit.WaitForTxResponse {
+	return &submit.WaitForTxResponse{Ref: hash, Stage: stage}
+}
+
+func collectStages(t *testing.T, events <-chan connector.TxStageEvent) []connector.TxStageEvent {
+	t.Helper()
+	var got []connector.TxStageEvent
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return got
+			}
+			got = append(got, event)
+		case <-timeout:
+			t.Fatalf("watch not closed after %d events", len(got))
+		}
 	}
 }
 
-// NOTE: The following transaction doesn't work with Blockfrost's TX evaluation.
-func TestEvaluateTxSample3(t *testing.T) {
-	t.Skip("Skipping sample 3 - Utxorpc does not support it")
-	utxorpc := setupUtxorpc(t)
-	ctx := context.Background()
+func TestWatchStagesReconnects(t *testing.T) {
+	txHash := strings.Repeat("cd", 32)
+	hash, _ := hex.DecodeString(txHash)
+	other := bytes.Repeat([]byte{0xef}, 32)
+	open := stubStages(
+		&stubStageStream{
+			msgs: []*submit.WaitForTxResponse{
+				stageMsg(hash, submit.Stage_STAGE_MEMPOOL),
+				stageMsg(other, submit.Stage_STAGE_CONFIRMED),
+			},
+			err: connect.NewError(connect.CodeUnavailable, errors.New("connection reset")),
+		},
+		&stubStageStream{
+			msgs: []*submit.WaitForTxResponse{
+				stageMsg(hash, submit.Stage_STAGE_MEMPOOL),
+				stageMsg(hash, submit.Stage_STAGE_NETWORK),
+				stageMsg(hash, submit.Stage_STAGE_CONFIRMED),
+			},
+		},
+	)
 
-	tx3Bytes, _ := hex.DecodeString(tests.ApolloEvalSample3Transaction)
+	events := make(chan connector.TxStageEvent)
+	go watchStages(context.Background(), nil, open, time.Millisecond, txHash, hash, events)
 
-	redeemers, err := utxorpc.EvaluateTx(ctx, tx3Bytes, tests.ApolloEvalSample3UTxOs)
-	if err != nil {
-		t.Fatalf("EvaluateTx failed: %v", err)
-	}
+	assert.Equal(t, []connector.TxStageEvent{
+		{TxHash: txHash, Stage: connector.TxStageAcknowledged},
+		{TxHash: txHash, Stage: connector.TxStageMempool},
+		{TxHash: txHash, Stage: connector.TxStageNetwork},
+		{TxHash: txHash, Stage: connector.TxStageConfirmed},
+	}, collectStages(t, events))
+}
 
-	if ok, diff := tests.RedeemersApproxEqual(redeemers, tests.ApolloEvalSample3RedeemersExUnits, 0.02); !ok {
-		t.Errorf("redeemers mismatch (>2%% drift): %s", diff)
+func TestWatchStagesStreamError(t *testing.T) {
+	txHash := strings.Repeat("cd", 32)
+	hash, _ := hex.DecodeString(txHash)
+	open := stubStages(&stubStageStream{
+		err: connect.NewError(connect.CodeInvalidArgument, errors.New("bad ref")),
+	})
+
+	events := make(chan connector.TxStageEvent)
+	go watchStages(context.Background(), nil, open, time.Millisecond, txHash, hash, events)
+
+	got := collectStages(t, events)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, connector.TxStageAcknowledged, got[0].Stage)
+		assert.Empty(t, got[1].Stage)
+		assert.ErrorIs(t, got[1].Err, connector.ErrInvalidInput)
 	}
 }
 
-func TestEvaluateTxSample4(t *testing.T) {
-	t.Skip(
-		"Skipping sample 4 - unimplemented - want to test eval with no additional utxos",
-	)
-	utxorpc := setupUtxorpc(t)
-	ctx := context.Background()
+func TestWatchStagesStopsOnCancel(t *testing.T) {
+	txHash := strings.Repeat("cd", 32)
+	hash, _ := hex.DecodeString(txHash)
+	ctx, cancel := context.WithCancel(context.Background())
 
-	tx3Bytes, _ := hex.DecodeString("")
+	events := make(chan connector.TxStageEvent)
+	go watchStages(ctx, nil, stubStages(), watchRetryDelay, txHash, hash, events)
 
-	redeemers, err := utxorpc.EvaluateTx(ctx, tx3Bytes, []common.Utxo{})
-	if err != nil {
-		t.Fatalf("EvaluateTx failed: %v", err)
+	event := <-events
+	assert.Equal(t, connector.TxStageAcknowledged, event.Stage)
+	cancel()
+	collectStages(t, events)
+}
+
+func TestWatchStagesStopsOnClose(t *testing.T) {
+	txHash := strings.Repeat("cd", 32)
+	hash, _ := hex.DecodeString(txHash)
+	done := make(chan struct{})
+
+	events := make(chan connector.TxStageEvent)
+	go watchStages(context.Background(), done, stubStages(), watchRetryDelay, txHash, hash, events)
+
+	event := <-events
+	assert.Equal(t, connector.TxStageAcknowledged, event.Stage)
+	close(done)
+	assert.Empty(t, collectStages(t, events))
+}
+
+func TestWatchStagesGivesUp(t *testing.T) {
+	txHash := strings.Repeat("cd", 32)
+	hash, _ := hex.DecodeString(txHash)
+	var opened atomic.Int64
+	open := func(context.Context, []byte) (stageStream, error) {
+		opened.Add(1)
+		return &stubStageStream{}, nil
 	}
 
-	if ok, diff := tests.RedeemersApproxEqual(redeemers, tests.ApolloEvalSample3RedeemersExUnits, 0.02); !ok {
-		t.Errorf("redeemers mismatch (>2%% drift): %s", diff)
+	events := make(chan connector.TxStageEvent)
+	go watchStages(context.Background(), nil, open, time.Millisecond, txHash, hash, events)
+
+	got := collectStages(t, events)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, connector.TxStageAcknowledged, got[0].Stage)
+		assert.ErrorIs(t, got[1].Err, errStreamEnded)
 	}
+	assert.Equal(t, int64(maxWatchReopens+1), opened.Load())
 }
 
-// TestEvaluateTxIgnoresAdditionalUTxOs asserts the Provider contract: passing a
-// non-empty additionalUTxOs set must NOT be rejected up front. The utxorpc
-// backend cannot forward those UTxOs, but it must ignore them rather than
-// erroring. We point at an unroutable endpoint with an already-cancelled
-// context so the call fails fast; the assertion is that whatever error comes
-// back is a transport/RPC failure, not an "additional UTxOs not supported"
-// rejection.
-func TestEvaluateTxIgnoresAdditionalUTxOs(t *testing.T) {
-	provider, err := New(Config{
-		BaseUrl:   "http://127.0.0.1:1",
-		NetworkId: int(constants.PREPROD),
-	})
+func TestSubmitAndWatchAfterClose(t *testing.T) {
+	provider, err := New(Config{BaseUrl: "http://127.0.0.1:1", NetworkId: int(constants.PREPROD)})
 	if err != nil {
 		t.Fatalf("New failed: %v", err)
 	}
+	assert.NoError(t, provider.Close())
+
+	_, err = provider.SubmitAndWatch(context.Background(), []byte{0x80})
+	assert.Error(t, err)
+}
+
+var (
+	tipStart = connector.ChainPoint{Slot: 10, Hash: strings.Repeat("10", 32)}
+	tipA     = connector.ChainPoint{Slot: 11, Hash: strings.Repeat("11", 32)}
+	tipB     = connector.ChainPoint{Slot: 12, Hash: strings.Repeat("12", 32)}
+	tipB2    = connector.ChainPoint{Slot: 13, Hash: strings.Repeat("13", 32)}
+	tipC     = connector.ChainPoint{Slot: 14, Hash: strings.Repeat("14", 32)}
+)
+
+func tipBlock(point connector.ChainPoint, height uint64, txs ...string) *syncpb.AnyChainBlock {
+	hash, _ := hex.DecodeString(point.Hash)
+	body := &cardano.BlockBody{}
+	for _, tx := range txs {
+		txHash, _ := hex.DecodeString(tx)
+		body.Tx = append(body.Tx, &cardano.Tx{Hash: txHash})
+	}
+	return &syncpb.AnyChainBlock{
+		Chain: &syncpb.AnyChainBlock_Cardano{Cardano: &cardano.Block{
+			Header: &cardano.BlockHeader{Slot: point.Slot, Hash: hash, Height: height},
+			Body:   body,
+		}},
+	}
+}
+
+func applyMsg(point connector.ChainPoint, height uint64, txs ...string) *syncpb.FollowTipResponse {
+	return &syncpb.FollowTipResponse{
+		Action: &syncpb.FollowTipResponse_Apply{Apply: tipBlock(point, height, txs...)},
+	}
+}
+
+func undoMsg(point connector.ChainPoint) *syncpb.FollowTipResponse {
+	return &syncpb.FollowTipResponse{
+		Action: &syncpb.FollowTipResponse_Undo{Undo: tipBlock(point, 0)},
+	}
+}
+
+func resetMsg(point connector.ChainPoint) *syncpb.FollowTipResponse {
+	hash, _ := hex.DecodeString(point.Hash)
+	return &syncpb.FollowTipResponse{
+		Action: &syncpb.FollowTipResponse_Reset_{
+			Reset_: &syncpb.BlockRef{Slot: point.Slot, Hash: hash},
+		},
+	}
+}
+
+// stubTipStream replays msgs, then ends with err.
+type stubTipStream struct {
+	msgs []*syncpb.FollowTipResponse
+	err  error
+	next *syncpb.FollowTipResponse
+}
+
+func (s *stubTipStream) Receive() bool {
+	if len(s.msgs) == 0 {
+		return false
+	}
+	s.next, s.msgs = s.msgs[0], s.msgs[1:]
+	return true
+}
+
+func (s *stubTipStream) Msg() *syncpb.FollowTipResponse { return s.next }
+func (s *stubTipStream) Err() error                     { return s.err }
+func (s *stubTipStream) Close() error                   { return nil }
+
+func TestTipEventRollbacks(t *testing.T) {
+	recent := []connector.ChainPoint{tipStart, tipA, tipB}
+
+	event, ok, err := tipEvent(undoMsg(tipB), recent)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, connector.BlockEvent{Type: connector.BlockEventRollBackward, Point: tipA}, event)
+
+	event, ok, err = tipEvent(resetMsg(tipStart), recent)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, connector.BlockEvent{Type: connector.BlockEventRollBackward, Point: tipStart}, event)
+
+	_, _, err = tipEvent(undoMsg(tipC), recent)
+	assert.ErrorIs(t, err, connector.ErrProviderInternal)
+
+	_, ok, err = tipEvent(&syncpb.FollowTipResponse{}, recent)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestTipEventApply(t *testing.T) {
+	event, ok, err := tipEvent(applyMsg(tipA, 7, strings.Repeat("aa", 32), strings.Repeat("bb", 32)), nil)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, connector.BlockEvent{
+		Type:     connector.BlockEventRollForward,
+		Point:    tipA,
+		Height:   7,
+		TxHashes: []string{strings.Repeat("aa", 32), strings.Repeat("bb", 32)},
+	}, event)
+
+	_, _, err = tipEvent(&syncpb.FollowTipResponse{
+		Action: &syncpb.FollowTipResponse_Apply{Apply: &syncpb.AnyChainBlock{}},
+	}, nil)
+	assert.ErrorIs(t, err, connector.ErrProviderInternal)
+}
+
+func TestFollowTipResumesAfterReconnect(t *testing.T) {
+	streams := []*stubTipStream{
+		{
+			msgs: []*syncpb.FollowTipResponse{
+				resetMsg(tipStart),
+				applyMsg(tipA, 1),
+				applyMsg(tipB, 2),
+				undoMsg(tipB),
+				applyMsg(tipB2, 2),
+			},
+			err: connect.NewError(connect.CodeUnavailable, errors.New("connection reset")),
+		},
+		{
+			msgs: []*syncpb.FollowTipResponse{
+				resetMsg(tipB2),
+				applyMsg(tipC, 3),
+			},
+		},
+	}
+	var intersects [][]*syncpb.BlockRef
+	open := func(_ context.Context, intersect []*syncpb.BlockRef) (tipStream, error) {
+		if len(intersects) >= len(streams) {
+			return nil, connect.NewError(connect.CodeUnavailable, errors.New("down"))
+		}
+		intersects = append(intersects, intersect)
+		return streams[len(intersects)-1], nil
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
-	cancel() // fail fast without reaching a live server
+	events := make(chan connector.BlockEvent)
+	go followTip(ctx, nil, open, tipStart, events)
+
+	var got []connector.BlockEvent
+	for len(got) < 5 {
+		select {
+		case event := <-events:
+			got = append(got, event)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out after %d events", len(got))
+		}
+	}
+	assert.Equal(t, []connector.BlockEvent{
+		{Type: connector.BlockEventRollForward, Point: tipA, Height: 1},
+		{Type: connector.BlockEventRollForward, Point: tipB, Height: 2},
+		{Type: connector.BlockEventRollBackward, Point: tipA},
+		{Type: connector.BlockEventRollForward, Point: tipB2, Height: 2},
+		{Type: connector.BlockEventRollForward, Point: tipC, Height: 3},
+	}, got)
+
+	if assert.Len(t, intersects, 2) {
+		assert.Len(t, intersects[1], 3)
+		assert.Equal(t, tipB2.Slot, intersects[1][0].GetSlot())
+		assert.Equal(t, tipStart.Slot, intersects[1][2].GetSlot())
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok, "expected the stream to be closed")
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream was not closed")
+	}
+}
+
+func TestFollowTipEndsWhenResumePointsAreGone(t *testing.T) {
+	opened := 0
+	open := func(context.Context, []*syncpb.BlockRef) (tipStream, error) {
+		opened++
+		if opened == 1 {
+			return &stubTipStream{
+				msgs: []*syncpb.FollowTipResponse{resetMsg(tipStart), applyMsg(tipA, 1)},
+				err:  connect.NewError(connect.CodeUnavailable, errors.New("connection reset")),
+			}, nil
+		}
+		return &stubTipStream{
+			err: connect.NewError(connect.CodeNotFound, errors.New("no intersection")),
+		}, nil
+	}
 
-	_, err = provider.EvaluateTx(ctx, []byte{0x80}, tests.ApolloEvalSample1UTxOs)
+	events := make(chan connector.BlockEvent)
+	go followTip(context.Background(), nil, open, tipStart, events)
+
+	var got []connector.BlockEvent
+	for event := range events {
+		got = append(got, event)
+	}
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, tipA, got[0].Point)
+		assert.Empty(t, got[1].Type)
+		assert.ErrorIs(t, got[1].Err, connector.ErrNotFound)
+	}
+	assert.Equal(t, 2, opened)
+}
+
+func TestStreamBlocksStopsOnClose(t *testing.T) {
+	provider, err := New(Config{BaseUrl: "http://127.0.0.1:1", NetworkId: int(constants.PREPROD)})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	assert.NoError(t, provider.Close())
+	assert.NoError(t, provider.Close())
+
+	_, err = provider.StreamBlocks(context.Background(), tipStart)
+	assert.Error(t, err)
+}
+
+func TestStreamBlocks(t *testing.T) {
+	utxorpc := setupUtxorpc(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	events, err := utxorpc.StreamBlocks(ctx, connector.ChainPoint{})
+	if errors.Is(err, connector.ErrNotImplemented) {
+		t.Skipf("endpoint does not serve FollowTip: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("StreamBlocks failed: %v", err)
+	}
+
+	var forward []connector.BlockEvent
+	for len(forward) < 2 {
+		event, ok := <-events
+		if !ok {
+			t.Fatalf("stream closed after %d blocks", len(forward))
+		}
+		if event.Type == connector.BlockEventRollForward {
+			forward = append(forward, event)
+		}
+	}
+	assert.Greater(t, forward[1].Point.Slot, forward[0].Point.Slot)
+	assert.Equal(t, forward[0].Height+1, forward[1].Height)
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestSubmitTxBadRequest(t *testing.T) {
+	utxorpc := setupUtxorpc(t)
+	ctx := context.Background()
+
+	// Test with invalid transaction data
+	invalidTxBytes := []byte{0x80} // Invalid CBOR
+
+	_, err := utxorpc.SubmitTx(ctx, invalidTxBytes)
 	if err == nil {
-		// A nil error here would be surprising (no server), but it still
-		// satisfies the contract: additional UTxOs were not rejected.
-		return
+		t.Error("Expected SubmitTx to fail with invalid transaction data")
+	}
+}
+
+func TestSubmitTxHash(t *testing.T) {
+	txBytes, err := hex.DecodeString(tests.ApolloEvalSample1Transaction)
+	if err != nil {
+		t.Fatalf("Failed to decode transaction: %v", err)
 	}
-	if strings.Contains(err.Error(), "does not support additional UTxOs") {
-		t.Fatalf("EvaluateTx must ignore additional UTxOs, not reject them: %v", err)
+	ref, err := hex.DecodeString(strings.Repeat("0f", 32))
+	if err != nil {
+		t.Fatalf("Failed to decode ref: %v", err)
 	}
+
+	txHash, err := submitTxHash(&submit.SubmitTxResponse{Ref: ref}, txBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat("0f", 32), txHash)
+
+	// Without a ref the hash is computed from the transaction body.
+	txHash, err = submitTxHash(&submit.SubmitTxResponse{}, txBytes)
+	assert.NoError(t, err)
+	assert.Len(t, txHash, 64)
+	assert.Equal(
+		t,
+		"b6bf562621e006f7995fb8bfb057275be02cc8b909db2d7706359d79d3e37185",
+		txHash,
+	)
+
+	_, err = submitTxHash(nil, []byte{0x80})
+	assert.ErrorIs(t, err, connector.ErrInvalidInput)
 }
 
-func TestGetScriptCborByScriptHash(t *testing.T) {
+func TestSubmitTxError(t *testing.T) {
+	err := submitTxError(connect.NewError(
+		connect.CodeInvalidArgument,
+		errors.New("BadInputsUTxO"),
+	))
+	assert.ErrorIs(t, err, connector.ErrTxSubmissionFailed)
+	var subErr *connector.SubmissionError
+	assert.ErrorAs(t, err, &subErr)
+	assert.Contains(t, subErr.Message, "BadInputsUTxO")
+
+	err = submitTxError(connect.NewError(connect.CodeUnavailable, errors.New("down")))
+	assert.NotErrorIs(t, err, connector.ErrTxSubmissionFailed)
+}
+
+func TestEvaluateTxSample1(t *testing.T) {
+	utxorpc := setupUtxorpc(t)
+	ctx := context.Background()
+
+	tx1Bytes, _ := hex.DecodeString(tests.ApolloEvalSample1Transaction)
+
+	redeemers, err := utxorpc.EvaluateTx(ctx, tx1Bytes, tests.ApolloEvalSample1UTxOs)
+	if errors.Is(err, connector.ErrNotImplemented) {
+		t.Skipf("additional UTxOs are not on chain: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("EvaluateTx failed: %v", err)
+	}
+
+	if ok, diff := tests.RedeemersApproxEqual(redeemers, tests.ApolloEvalSample1RedeemersExUnits, 0.02); !ok {
+		t.Errorf("redeemers mismatch (>2%% drift): %s", diff)
+	}
+}
+
+// Invalid request: failed to decode payload from base64 or base16.
+func TestEvaluateTxSample2(t *testing.T) {
+	t.Skip("Skipping sample 2 - Utxorpc does not support it")
+	utxorpc := setupUtxorpc(t)
+	ctx := context.Background()
+
+	tx2Bytes, _ := hex.DecodeString(tests.ApolloEvalSample2Transaction)
+
+	redeemers, err := utxorpc.EvaluateTx(ctx, tx2Bytes, tests.ApolloEvalSample2UTxOs)
+	if err != nil {
+		t.Fatalf("EvaluateTx failed: %v", err)
+	}
+
+	if ok, diff := tests.RedeemersApproxEqual(redeemers, tests.ApolloEvalSample2RedeemersExUnits, 0.02); !ok {
+		t.Errorf("redeemers mismatch (>2%% drift): %s", diff)
+	}
+}
+
+// NOTE: The following transaction doesn't work with Blockfrost's TX evaluation.
+func TestEvaluateTxSample3(t *testing.T) {
+	utxorpc := setupUtxorpc(t)
+	ctx := context.Background()
+
+	tx3Bytes, _ := hex.DecodeString(tests.ApolloEvalSample3Transaction)
+
+	redeemers, err := utxorpc.EvaluateTx(ctx, tx3Bytes, tests.ApolloEvalSample3UTxOs)
+	if errors.Is(err, connector.ErrNotImplemented) {
+		t.Skipf("additional UTxOs are not on chain: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("EvaluateTx failed: %v", err)
+	}
+
+	if ok, diff := tests.RedeemersApproxEqual(redeemers, tests.ApolloEvalSample3RedeemersExUnits, 0.02); !ok {
+		t.Errorf("redeemers mismatch (>2%% drift): %s", diff)
+	}
+}
+
+func TestEvaluateTxSample4(t *testing.T) {
 	t.Skip(
-		"Skipping script cbor by script hash test - Utxorpc does not support it",
+		"Skipping sample 4 - unimplemented - want to test eval with no additional utxos",
 	)
 	utxorpc := setupUtxorpc(t)
 	ctx := context.Background()
 
+	tx3Bytes, _ := hex.DecodeString("")
+
+	redeemers, err := utxorpc.EvaluateTx(ctx, tx3Bytes, []common.Utxo{})
+	if err != nil {
+		t.Fatalf("EvaluateTx failed: %v", err)
+	}
+
+	if ok, diff := tests.RedeemersApproxEqual(redeemers, tests.ApolloEvalSample3RedeemersExUnits, 0.02); !ok {
+		t.Errorf("redeemers mismatch (>2%% drift): %s", diff)
+	}
+}
+
+// evalStub serves ReadUtxos from onChain and counts EvalTx calls, which it
+// answers with a gRPC FailedPrecondition status.
+type evalStub struct {
+	queryconnect.UnimplementedQueryServiceHandler
+	submitconnect.UnimplementedSubmitServiceHandler
+	onChain   []*query.AnyUtxoData
+	evalCalls atomic.Int64
+}
+
+func (s *evalStub) ReadUtxos(
+	_ context.Context,
+	req *connect.Request[query.ReadUtxosRequest],
+) (*connect.Response[query.ReadUtxosResponse], error) {
+	resp := &query.ReadUtxosResponse{}
+	for _, key := range req.Msg.GetKeys() {
+		for _, item := range s.onChain {
+			if bytes.Equal(item.GetTxoRef().GetHash(), key.GetHash()) &&
+				item.GetTxoRef().GetIndex() == key.GetIndex() {
+				resp.Items = append(resp.Items, item)
+			}
+		}
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *evalStub) EvalTx(
+	context.Context,
+	*connect.Request[submit.EvalTxRequest],
+) (*connect.Response[submit.EvalTxResponse], error) {
+	s.evalCalls.Add(1)
+	return nil, connect.NewError(connect.CodeFailedPrecondition, errors.New("stub"))
+}
+
+// newEvalStubProvider returns a provider talking to an HTTP/2 TLS server
+// backed by stub.
+func newEvalStubProvider(t *testing.T, stub *evalStub) *UtxorpcProvider {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.Handle(queryconnect.NewQueryServiceHandler(stub))
+	mux.Handle(submitconnect.NewSubmitServiceHandler(stub))
+	srv := httptest.NewUnstartedServer(mux)
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	provider, err := New(Config{
+		BaseUrl:   srv.URL,
+		NetworkId: int(constants.PREPROD),
+		TLSConfig: &tls.Config{InsecureSkipVerify: true},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return provider
+}
+
+// stubUtxos parses items into the UTxOs they describe.
+func stubUtxos(t *testing.T, items ...*query.AnyUtxoData) []common.Utxo {
+	t.Helper()
+	utxos := make([]common.Utxo, len(items))
+	for i, item := range items {
+		utxo, err := utxoFromRpc(item)
+		if err != nil {
+			t.Fatalf("utxoFromRpc failed: %v", err)
+		}
+		utxos[i] = utxo
+	}
+	return utxos
+}
+
+func TestEvaluateTxAdditionalUTxOsOffChain(t *testing.T) {
+	items := []*query.AnyUtxoData{searchItem(t, 0, 1000000), searchItem(t, 1, 2000000)}
+	stub := &evalStub{onChain: items[:1]}
+	provider := newEvalStubProvider(t, stub)
+
+	_, err := provider.EvaluateTx(context.Background(), []byte{0x80}, stubUtxos(t, items...))
+	assert.ErrorIs(t, err, connector.ErrNotImplemented)
+	assert.Contains(t, err.Error(), fmt.Sprintf("%x#1", items[1].GetTxoRef().GetHash()))
+	assert.Equal(t, int64(0), stub.evalCalls.Load())
+}
+
+func TestEvaluateTxAdditionalUTxOsOnChain(t *testing.T) {
+	items := []*query.AnyUtxoData{searchItem(t, 0, 1000000), searchItem(t, 1, 2000000)}
+	stub := &evalStub{onChain: items}
+	provider := newEvalStubProvider(t, stub)
+
+	_, err := provider.EvaluateTx(context.Background(), []byte{0x80}, stubUtxos(t, items...))
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, connector.ErrNotImplemented)
+	assert.Equal(t, int64(1), stub.evalCalls.Load())
+}
+
+func TestOffChainUtxosError(t *testing.T) {
+	utxos := tests.ApolloEvalSample1UTxOs
+	refs := make([]connector.OutRef, len(utxos))
+	for i, utxo := range utxos {
+		refs[i] = connector.OutRef{TxHash: utxo.Id.Id().String(), Index: utxo.Id.Index()}
+	}
+
+	assert.NoError(t, offChainUtxosError(refs, utxos))
+
+	err := offChainUtxosError(refs, utxos[1:])
+	assert.ErrorIs(t, err, connector.ErrNotImplemented)
+	assert.Contains(t, err.Error(), fmt.Sprintf("%s#%d", refs[0].TxHash, refs[0].Index))
+}
+
+// evalResponse wraps a Cardano evaluation report in an EvalTxResponse.
+func evalResponse(report *cardano.TxEval) *submit.EvalTxResponse {
+	return &submit.EvalTxResponse{
+		Report: &submit.AnyChainEval{
+			Chain: &submit.AnyChainEval_Cardano{Cardano: report},
+		},
+	}
+}
+
+func evalRedeemer(purpose cardano.RedeemerPurpose, index uint32) *cardano.Redeemer {
+	return &cardano.Redeemer{
+		Purpose: purpose,
+		Index:   index,
+		ExUnits: &cardano.ExUnits{Memory: 1000, Steps: 2000},
+	}
+}
+
+func TestEvalTxResponseToExUnitsEmptyReport(t *testing.T) {
+	for name, msg := range map[string]*submit.EvalTxResponse{
+		"nil response":   nil,
+		"no report":      {},
+		"no cardano":     {Report: &submit.AnyChainEval{}},
+		"no redeemers":   evalResponse(&cardano.TxEval{}),
+		"empty redeemer": evalResponse(&cardano.TxEval{Redeemers: []*cardano.Redeemer{{}}}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := evalTxResponseToExUnits(msg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEvalTxResponseToExUnitsErrorReport(t *testing.T) {
+	_, err := evalTxResponseToExUnits(evalResponse(&cardano.TxEval{
+		Errors: []*cardano.EvalError{
+			{Msg: "the validator crashed / exited prematurely"},
+			{Msg: "missing required signer"},
+		},
+	}))
+
+	assert.ErrorIs(t, err, connector.ErrEvaluationFailed)
+	var evalErr *connector.EvaluationError
+	if assert.ErrorAs(t, err, &evalErr) {
+		assert.Equal(
+			t,
+			"the validator crashed / exited prematurely; missing required signer",
+			evalErr.Message,
+		)
+	}
+}
+
+func TestEvalTxResponseToExUnitsPurposes(t *testing.T) {
+	result, err := evalTxResponseToExUnits(evalResponse(&cardano.TxEval{
+		Redeemers: []*cardano.Redeemer{
+			evalRedeemer(cardano.RedeemerPurpose_REDEEMER_PURPOSE_SPEND, 0),
+			evalRedeemer(cardano.RedeemerPurpose_REDEEMER_PURPOSE_VOTE, 1),
+			evalRedeemer(cardano.RedeemerPurpose_REDEEMER_PURPOSE_PROPOSE, 2),
+		},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, map[common.RedeemerKey]common.ExUnits{
+		{Tag: common.RedeemerTagSpend, Index: 0}:     {Memory: 1000, Steps: 2000},
+		{Tag: common.RedeemerTagVoting, Index: 1}:    {Memory: 1000, Steps: 2000},
+		{Tag: common.RedeemerTagProposing, Index: 2}: {Memory: 1000, Steps: 2000},
+	}, result)
+}
+
+func TestEvalTxResponseToExUnitsUnknownPurposes(t *testing.T) {
+	result, err := evalTxResponseToExUnits(evalResponse(&cardano.TxEval{
+		Redeemers: []*cardano.Redeemer{
+			evalRedeemer(cardano.RedeemerPurpose_REDEEMER_PURPOSE_MINT, 0),
+			evalRedeemer(cardano.RedeemerPurpose(98), 1),
+			evalRedeemer(cardano.RedeemerPurpose(99), 2),
+		},
+	}))
+	assert.ErrorIs(t, err, connector.ErrProviderInternal)
+	assert.Contains(t, err.Error(), "98:1")
+	assert.Contains(t, err.Error(), "99:2")
+	assert.Len(t, result, 1)
+}
+
+func TestGetScriptCborByScriptHash(t *testing.T) {
+	utxorpc := setupUtxorpc(t)
+	ctx := context.Background()
+
 	scriptCbor, err := utxorpc.GetScriptCborByScriptHash(
 		ctx,
 		tests.ScriptHashToQuery,
 	)
 	if err != nil {
+		if errors.Is(err, connector.ErrNotImplemented) {
+			t.Skipf("Endpoint cannot search by payment credential: %v", err)
+		}
 		t.Fatalf("GetScriptCborByScriptHash failed: %v", err)
 	}
 
 	assert.Equal(t, scriptCbor, tests.ExpectedScriptCbor)
 }
+
+func TestGetScriptCborByScriptHashInvalidHash(t *testing.T) {
+	provider, err := New(Config{BaseUrl: "http://127.0.0.1:1", NetworkId: int(constants.PREPROD)})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	_, err = provider.GetScriptCborByScriptHash(context.Background(), tests.ScriptHashToQuery+"00")
+	assert.ErrorIs(t, err, connector.ErrInvalidInput)
+}
+
+// scriptRefItem is a UTxO RPC search result at output index of a fixed
+// transaction whose Babbage output carries script as reference script.
+func scriptRefItem(t *testing.T, index uint32, script common.PlutusV2Script) *query.AnyUtxoData {
+	t.Helper()
+	addr, err := common.NewAddress(
+		"addr_test1wpgexmeunzsykesf42d4eqet5yvzeap6trjnflxqtkcf66g0kpnxt",
+	)
+	if err != nil {
+		t.Fatalf("NewAddress failed: %v", err)
+	}
+	addrBytes, err := addr.Bytes()
+	if err != nil {
+		t.Fatalf("Address.Bytes failed: %v", err)
+	}
+	scriptRef, err := cbor.Encode([]any{common.ScriptRefTypePlutusV2, []byte(script)})
+	if err != nil {
+		t.Fatalf("cbor.Encode failed: %v", err)
+	}
+	output, err := cbor.Encode(map[uint]any{
+		0: addrBytes,
+		1: uint64(2000000),
+		3: cbor.Tag{Number: 24, Content: scriptRef},
+	})
+	if err != nil {
+		t.Fatalf("cbor.Encode failed: %v", err)
+	}
+	return &query.AnyUtxoData{
+		NativeBytes: output,
+		TxoRef:      &query.TxoRef{Hash: bytes.Repeat([]byte{0xab}, 32), Index: index},
+	}
+}
+
+func TestFindReferenceScriptNotFound(t *testing.T) {
+	pages := [][]*query.AnyUtxoData{
+		{searchItem(t, 0, 1000000), searchItem(t, 1, 2000000)},
+		{searchItem(t, 2, 3000000)},
+	}
+	var requests []*query.SearchUtxosRequest
+	hash, err := hex.DecodeString(tests.ScriptHashToQuery)
+	if err != nil {
+		t.Fatalf("DecodeString failed: %v", err)
+	}
+
+	_, err = findReferenceScript(context.Background(), pagedSearch(pages, &requests), hash, 0)
+	assert.ErrorIs(t, err, connector.ErrNotFound)
+	assert.Len(t, requests, 2)
+	assert.Equal(t, "page-1", requests[1].GetStartToken())
+	assert.Equal(t, hash, requests[0].GetPredicate().GetMatch().GetCardano().GetAddress().GetPaymentPart())
+}
+
+func TestFindReferenceScriptStopsAtMatch(t *testing.T) {
+	script := common.PlutusV2Script([]byte{0x46, 0x01, 0x00, 0x00, 0x22, 0x00, 0x11})
+	other := common.PlutusV2Script([]byte{0x46, 0x01, 0x00, 0x00, 0x22, 0x00, 0x12})
+	pages := [][]*query.AnyUtxoData{
+		{searchItem(t, 0, 1000000), scriptRefItem(t, 1, other)},
+		{searchItem(t, 2, 3000000), scriptRefItem(t, 3, script)},
+		{searchItem(t, 4, 5000000)},
+	}
+	var requests []*query.SearchUtxosRequest
+	hash := script.Hash()
+
+	cborHex, err := findReferenceScript(
+		context.Background(),
+		pagedSearch(pages, &requests),
+		hash.Bytes(),
+		0,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(script.RawScriptBytes()), cborHex)
+	// The page after the one carrying the script is never fetched.
+	assert.Len(t, requests, 2)
+}
+
+// captureServer records the requests it receives, keyed by procedure, and
+// answers every call with a gRPC Unimplemented status.
+type captureServer struct {
+	mu       sync.Mutex
+	requests map[string]*http.Request
+}
+
+func (c *captureServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	if c.requests == nil {
+		c.requests = make(map[string]*http.Request)
+	}
+	c.requests[r.URL.Path] = r.Clone(context.Background())
+	c.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Grpc-Status", "12")
+	w.Header().Set("Grpc-Message", "not implemented")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *captureServer) request(procedure string) *http.Request {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.requests[procedure]
+}
+
+// newTLSCaptureServer starts an HTTP/2 TLS server whose TLS settings are
+// adjusted by configure before it starts.
+func newTLSCaptureServer(t *testing.T, configure func(*tls.Config)) (*httptest.Server, *captureServer) {
+	t.Helper()
+	capture := &captureServer{}
+	srv := httptest.NewUnstartedServer(capture)
+	srv.EnableHTTP2 = true
+	srv.TLS = &tls.Config{}
+	if configure != nil {
+		configure(srv.TLS)
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv, capture
+}
+
+// trustServer returns a TLS config trusting srv's certificate.
+func trustServer(srv *httptest.Server) *tls.Config {
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	return &tls.Config{RootCAs: pool}
+}
+
+func TestHeadersReachEveryService(t *testing.T) {
+	srv, capture := newTLSCaptureServer(t, nil)
+	provider, err := New(Config{
+		BaseUrl:   srv.URL,
+		ApiKey:    "dmtr-key",
+		NetworkId: int(constants.PREPROD),
+		Headers:   map[string]string{"X-Api-Token": "secret"},
+		TLSConfig: trustServer(srv),
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ctx := context.Background()
+
+	_, err = provider.GetTip(ctx)
+	assert.Error(t, err)
+	_, err = provider.GetUtxosByOutRef(ctx, []connector.OutRef{
+		{TxHash: strings.Repeat("ab", 32), Index: 0},
+	})
+	assert.ErrorIs(t, err, connector.ErrNotImplemented)
+	_, err = provider.SubmitTx(ctx, []byte{0x84})
+	assert.Error(t, err)
+
+	for _, procedure := range []string{
+		syncconnect.SyncServiceReadTipProcedure,
+		queryconnect.QueryServiceReadUtxosProcedure,
+		submitconnect.SubmitServiceSubmitTxProcedure,
+	} {
+		req := capture.request(procedure)
+		if !assert.NotNil(t, req, "no request for %s", procedure) {
+			continue
+		}
+		assert.Equal(t, 2, req.ProtoMajor, procedure)
+		assert.NotNil(t, req.TLS, procedure)
+		assert.Equal(t, "secret", req.Header.Get("X-Api-Token"), procedure)
+		assert.Equal(t, "dmtr-key", req.Header.Get("dmtr-api-key"), procedure)
+		assert.Equal(t, "application/grpc", req.Header.Get("Content-Type"), procedure)
+	}
+}
+
+func TestTLSConfigTakesEffect(t *testing.T) {
+	srv, capture := newTLSCaptureServer(t, nil)
+
+	// Without the server's certificate in the trusted roots the handshake
+	// fails before any request is sent.
+	untrusted, err := New(Config{
+		BaseUrl:   srv.URL,
+		TLSConfig: &tls.Config{RootCAs: x509.NewCertPool()},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	_, err = untrusted.GetTip(context.Background())
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, connector.ErrNotImplemented)
+	assert.Nil(t, capture.request(syncconnect.SyncServiceReadTipProcedure))
+
+	insecure, err := New(Config{
+		BaseUrl:   srv.URL,
+		TLSConfig: &tls.Config{InsecureSkipVerify: true},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	_, err = insecure.GetUtxosByOutRef(context.Background(), []connector.OutRef{
+		{TxHash: strings.Repeat("ab", 32), Index: 0},
+	})
+	assert.ErrorIs(t, err, connector.ErrNotImplemented)
+	assert.NotNil(t, capture.request(queryconnect.QueryServiceReadUtxosProcedure))
+}
+
+func TestTLSConfigClientCertificate(t *testing.T) {
+	srv, capture := newTLSCaptureServer(t, func(config *tls.Config) {
+		config.ClientAuth = tls.RequireAnyClientCert
+	})
+	tlsConfig := trustServer(srv)
+	// The server's own certificate doubles as the client certificate; the
+	// server only requires that one is presented.
+	tlsConfig.Certificates = srv.TLS.Certificates
+
+	provider, err := New(Config{BaseUrl: srv.URL, TLSConfig: tlsConfig})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	_, err = provider.GetUtxosByOutRef(context.Background(), []connector.OutRef{
+		{TxHash: strings.Repeat("ab", 32), Index: 0},
+	})
+	assert.ErrorIs(t, err, connector.ErrNotImplemented)
+
+	req := capture.request(queryconnect.QueryServiceReadUtxosProcedure)
+	if assert.NotNil(t, req) {
+		assert.NotEmpty(t, req.TLS.PeerCertificates)
+	}
+	assert.Empty(t, tlsConfig.NextProtos, "the caller's TLS config must not be modified")
+}
+
+// newCleartextServer starts a server for handler that accepts HTTP/2 without
+// TLS (h2c), as gRPC over an http BaseUrl needs.
+func newCleartextServer(t *testing.T, handler http.Handler) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(handler)
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+	srv.Config.Protocols = &protocols
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestHeadersOverCleartextHTTP2(t *testing.T) {
+	capture := &captureServer{}
+	srv := newCleartextServer(t, capture)
+
+	provider, err := New(Config{
+		BaseUrl: srv.URL,
+		Headers: map[string]string{"X-Api-Token": "secret"},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	_, err = provider.GetTip(context.Background())
+	assert.Error(t, err)
+
+	req := capture.request(syncconnect.SyncServiceReadTipProcedure)
+	if assert.NotNil(t, req) {
+		assert.Equal(t, 2, req.ProtoMajor)
+		assert.Equal(t, "secret", req.Header.Get("X-Api-Token"))
+	}
+}
+
+func TestHTTPClientAndClientOptions(t *testing.T) {
+	srv, capture := newTLSCaptureServer(t, nil)
+	var intercepted atomic.Int64
+	interceptor := connect.UnaryInterceptorFunc(
+		func(next connect.UnaryFunc) connect.UnaryFunc {
+			return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+				intercepted.Add(1)
+				return next(ctx, req)
+			}
+		},
+	)
+
+	provider, err := New(Config{
+		BaseUrl:       srv.URL,
+		HTTPClient:    srv.Client(),
+		Headers:       map[string]string{"X-Api-Token": "secret"},
+		ClientOptions: []connect.ClientOption{connect.WithInterceptors(interceptor)},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	_, err = provider.GetTip(context.Background())
+	assert.Error(t, err)
+
+	assert.Equal(t, int64(1), intercepted.Load())
+	req := capture.request(syncconnect.SyncServiceReadTipProcedure)
+	if assert.NotNil(t, req) {
+		assert.Equal(t, "secret", req.Header.Get("X-Api-Token"))
+	}
+}
+
+func TestNewRejectsConflictingTransportOptions(t *testing.T) {
+	cases := []struct {
+		name   string
+		config Config
+	}{
+		{"empty base url", Config{}},
+		{"unsupported scheme", Config{BaseUrl: "ftp://127.0.0.1:1"}},
+		{"no host", Config{BaseUrl: "https:///path"}},
+		{"tls over http", Config{
+			BaseUrl:   "http://127.0.0.1:1",
+			TLSConfig: &tls.Config{},
+		}},
+		{"tls with http client", Config{
+			BaseUrl:    "https://127.0.0.1:1",
+			TLSConfig:  &tls.Config{},
+			HTTPClient: &http.Client{},
+		}},
+		{"api key conflicts with header", Config{
+			BaseUrl: "https://127.0.0.1:1",
+			ApiKey:  "key",
+			Headers: map[string]string{"DMTR-API-KEY": "other"},
+		}},
+		{"empty header name", Config{
+			BaseUrl: "https://127.0.0.1:1",
+			Headers: map[string]string{" ": "value"},
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(tc.config)
+			assert.ErrorIs(t, err, connector.ErrInvalidInput)
+		})
+	}
+
+	_, err := New(Config{
+		BaseUrl: "https://127.0.0.1:1",
+		ApiKey:  "key",
+		Headers: map[string]string{"dmtr-api-key": "key"},
+	})
+	assert.NoError(t, err)
+}
+
+// flakyServer fails the calls of each procedure with the gRPC codes queued
+// for it, one per attempt, and answers with an empty message once they are
+// used up. It counts the attempts per procedure.
+type flakyServer struct {
+	mu       sync.Mutex
+	failures map[string][]connect.Code
+	attempts map[string]int
+}
+
+func newFlakyServer(failures map[string][]connect.Code) *flakyServer {
+	return &flakyServer{failures: failures, attempts: make(map[string]int)}
+}
+
+func (f *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	procedure := r.URL.Path
+	f.attempts[procedure]++
+	var code connect.Code
+	if queued := f.failures[procedure]; len(queued) > 0 {
+		code, f.failures[procedure] = queued[0], queued[1:]
+	}
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/grpc")
+	if code != 0 {
+		w.Header().Set("Grpc-Status", strconv.Itoa(int(code)))
+		w.Header().Set("Grpc-Message", code.String())
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.Header().Set(http.TrailerPrefix+"Grpc-Status", "0")
+	w.WriteHeader(http.StatusOK)
+	// An uncompressed, empty message.
+	_, _ = w.Write([]byte{0, 0, 0, 0, 0})
+}
+
+func (f *flakyServer) Attempts(procedure string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.attempts[procedure]
+}
+
+// fastRetry retries quickly enough for tests.
+var fastRetry = connector.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+func newFlakyProvider(t *testing.T, retry connector.RetryPolicy, failures map[string][]connect.Code) (*UtxorpcProvider, *flakyServer) {
+	t.Helper()
+	flaky := newFlakyServer(failures)
+	srv := newCleartextServer(t, flaky)
+	provider, err := New(Config{
+		BaseUrl:   srv.URL,
+		NetworkId: int(constants.PREPROD),
+		Retry:     retry,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return provider, flaky
+}
+
+var retryOutRefs = []connector.OutRef{{TxHash: strings.Repeat("ab", 32), Index: 0}}
+
+func TestRetryTransientFailures(t *testing.T) {
+	procedure := queryconnect.QueryServiceReadUtxosProcedure
+	provider, flaky := newFlakyProvider(t, fastRetry, map[string][]connect.Code{
+		procedure: {connect.CodeUnavailable, connect.CodeResourceExhausted},
+	})
+
+	utxos, err := provider.GetUtxosByOutRef(context.Background(), retryOutRefs)
+	assert.NoError(t, err)
+	assert.Empty(t, utxos)
+	assert.Equal(t, 3, flaky.Attempts(procedure))
+}
+
+func TestRetryRateLimitedGivesUp(t *testing.T) {
+	procedure := syncconnect.SyncServiceReadTipProcedure
+	provider, flaky := newFlakyProvider(t, fastRetry, map[string][]connect.Code{
+		procedure: {
+			connect.CodeResourceExhausted,
+			connect.CodeResourceExhausted,
+			connect.CodeResourceExhausted,
+		},
+	})
+
+	_, err := provider.GetTip(context.Background())
+	assert.ErrorIs(t, err, connector.ErrRateLimited)
+	assert.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err))
+	assert.Equal(t, 3, flaky.Attempts(procedure))
+}
+
+func TestRetryUnavailableGivesUp(t *testing.T) {
+	procedure := queryconnect.QueryServiceReadParamsProcedure
+	provider, flaky := newFlakyProvider(t, fastRetry, map[string][]connect.Code{
+		procedure: {
+			connect.CodeUnavailable,
+			connect.CodeUnavailable,
+			connect.CodeUnavailable,
+		},
+	})
+
+	_, err := provider.GetProtocolParameters(context.Background())
+	assert.Equal(t, connect.CodeUnavailable, connect.CodeOf(err))
+	assert.NotErrorIs(t, err, connector.ErrRateLimited)
+	assert.Equal(t, 3, flaky.Attempts(procedure))
+}
+
+func TestRetryFinalCodes(t *testing.T) {
+	for _, code := range []connect.Code{
+		connect.CodeNotFound,
+		connect.CodeInvalidArgument,
+		connect.CodeUnimplemented,
+		connect.CodeInternal,
+	} {
+		t.Run(code.String(), func(t *testing.T) {
+			procedure := queryconnect.QueryServiceReadUtxosProcedure
+			provider, flaky := newFlakyProvider(t, fastRetry, map[string][]connect.Code{
+				procedure: {code},
+			})
+
+			_, err := provider.GetUtxosByOutRef(context.Background(), retryOutRefs)
+			assert.Error(t, err)
+			assert.Equal(t, 1, flaky.Attempts(procedure))
+		})
+	}
+}
+
+func TestRetryNeverResubmits(t *testing.T) {
+	procedure := submitconnect.SubmitServiceSubmitTxProcedure
+	provider, flaky := newFlakyProvider(t, fastRetry, map[string][]connect.Code{
+		procedure: {connect.CodeUnavailable, connect.CodeUnavailable},
+	})
+
+	_, err := provider.SubmitTx(context.Background(), []byte{0x84})
+	assert.Error(t, err)
+	assert.Equal(t, 1, flaky.Attempts(procedure))
+}
+
+func TestRetryDisabledByDefault(t *testing.T) {
+	procedure := queryconnect.QueryServiceReadUtxosProcedure
+	provider, flaky := newFlakyProvider(t, connector.RetryPolicy{}, map[string][]connect.Code{
+		procedure: {connect.CodeResourceExhausted},
+	})
+
+	_, err := provider.GetUtxosByOutRef(context.Background(), retryOutRefs)
+	assert.ErrorIs(t, err, connector.ErrRateLimited)
+	assert.Equal(t, 1, flaky.Attempts(procedure))
+}
+
+func TestRetryStopsBeforeDeadline(t *testing.T) {
+	procedure := queryconnect.QueryServiceReadUtxosProcedure
+	provider, flaky := newFlakyProvider(t,
+		connector.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour},
+		map[string][]connect.Code{procedure: {connect.CodeUnavailable}},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := provider.GetUtxosByOutRef(ctx, retryOutRefs)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, 1, flaky.Attempts(procedure))
+}
+
+func TestRetryable(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"unavailable", connect.NewError(connect.CodeUnavailable, errors.New("deploying")), true},
+		{"resource exhausted", connect.NewError(connect.CodeResourceExhausted, errors.New("quota")), true},
+		{"wrapped", fmt.Errorf("utxorpc: %w", connect.NewError(connect.CodeUnavailable, errors.New("x"))), true},
+		{"not found", connect.NewError(connect.CodeNotFound, errors.New("x")), false},
+		{"internal", connect.NewError(connect.CodeInternal, errors.New("x")), false},
+		{"canceled", connect.NewError(connect.CodeUnavailable, context.Canceled), false},
+		{"deadline", context.DeadlineExceeded, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, retryable(tc.err))
+		})
+	}
+}
+
+func TestNewRejectsInvalidRetry(t *testing.T) {
+	for _, retry := range []connector.RetryPolicy{
+		{MaxAttempts: -1},
+		{BaseDelay: -time.Second},
+		{Jitter: 1.5},
+	} {
+		_, err := New(Config{BaseUrl: "http://127.0.0.1:1", Retry: retry})
+		assert.ErrorIs(t, err, connector.ErrInvalidInput)
+	}
+}
@@ -0,0 +1,215 @@
+package utxorpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1alpha/submit"
+	connector "github.com/zenGate-Global/cardano-connector-go"
+)
+
+var _ connector.TxWatcher = (*UtxorpcProvider)(nil)
+
+const (
+	// watchRetryDelay and maxWatchRetryDelay bound the wait between attempts
+	// to reopen a WaitForTx stream.
+	watchRetryDelay    = time.Second
+	maxWatchRetryDelay = 30 * time.Second
+	// maxWatchReopens is how many times in a row a WaitForTx stream is
+	// reopened without the transaction advancing a stage before the watch
+	// gives up.
+	maxWatchReopens = 8
+)
+
+// errStreamEnded reports a WaitForTx stream the server closed before the
+// transaction was confirmed.
+var errStreamEnded = errors.New("utxorpc: WaitForTx stream ended")
+
+// stageStream is the part of a WaitForTx stream the watch reads.
+type stageStream interface {
+	Receive() bool
+	Msg() *submit.WaitForTxResponse
+	Err() error
+	Close() error
+}
+
+// openStagesFunc opens a WaitForTx stream for the transaction hash.
+type openStagesFunc func(ctx context.Context, hash []byte) (stageStream, error)
+
+// txStages maps the submit service's stages to connector stages.
+var txStages = map[submit.Stage]string{
+	submit.Stage_STAGE_ACKNOWLEDGED: connector.TxStageAcknowledged,
+	submit.Stage_STAGE_MEMPOOL:      connector.TxStageMempool,
+	submit.Stage_STAGE_NETWORK:      connector.TxStageNetwork,
+	submit.Stage_STAGE_CONFIRMED:    connector.TxStageConfirmed,
+}
+
+// SubmitAndWatch submits tx and follows its stages over the submit service's
+// WaitForTx stream until it is confirmed. A stream that the server ends, or
+// that fails as Unavailable, is reopened with growing delays, up to
+// maxWatchReopens times in a row without progress; other stream errors, and
+// the last one once the watch gives up, are delivered as the last event.
+// Stages are only ever reported forward, each once. Ending ctx or calling
+// Close closes the channel.
+func (u *UtxorpcProvider) SubmitAndWatch(
+	ctx context.Context,
+	tx []byte,
+) (<-chan connector.TxStageEvent, error) {
+	select {
+	case <-u.done:
+		return nil, errors.New("utxorpc: provider is closed")
+	default:
+	}
+
+	txHash, err := u.SubmitTx(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := hex.DecodeString(txHash)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: invalid tx hash %q from SubmitTx",
+			connector.ErrProviderInternal,
+			txHash,
+		)
+	}
+
+	events := make(chan connector.TxStageEvent)
+	go watchStages(ctx, u.done, u.openStages, watchRetryDelay, txHash, hash, events)
+	return events, nil
+}
+
+// openStages is the openStagesFunc backed by the provider's client.
+func (u *UtxorpcProvider) openStages(ctx context.Context, hash []byte) (stageStream, error) {
+	req := connect.NewRequest(&submit.WaitForTxRequest{
+		Ref: [][]byte{hash},
+	})
+	stream, err := u.client.submit.WaitForTx(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// watchStages delivers the stages of the transaction to events, which it
+// closes when the watch ends, ctx ends or done is closed. Failed streams are
+// reopened after retryDelay, doubling up to maxWatchRetryDelay.
+func watchStages(
+	ctx context.Context,
+	done <-chan struct{},
+	open openStagesFunc,
+	retryDelay time.Duration,
+	txHash string,
+	hash []byte,
+	events chan<- connector.TxStageEvent,
+) {
+	defer close(events)
+
+	// Cancelling ctx once done is closed unblocks a pending receive.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	send := func(event connector.TxStageEvent) bool {
+		select {
+		case events <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	// SubmitTx returning means the service acknowledged the transaction.
+	if !send(connector.TxStageEvent{TxHash: txHash, Stage: connector.TxStageAcknowledged}) {
+		return
+	}
+	reached := submit.Stage_STAGE_ACKNOWLEDGED
+	delay := retryDelay
+	reopens := 0
+	for {
+		before := reached
+		err := followStages(ctx, open, txHash, hash, &reached, send)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		if !errors.Is(err, errStreamEnded) &&
+			connect.CodeOf(err) != connect.CodeUnavailable {
+			send(connector.TxStageEvent{TxHash: txHash, Err: err})
+			return
+		}
+		if reached > before {
+			reopens = 0
+			delay = retryDelay
+		}
+		if reopens == maxWatchReopens {
+			send(connector.TxStageEvent{
+				TxHash: txHash,
+				Err: fmt.Errorf(
+					"utxorpc: gave up on WaitForTx after %d failed attempts in a row: %w",
+					reopens+1,
+					err,
+				),
+			})
+			return
+		}
+		reopens++
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxWatchRetryDelay)
+	}
+}
+
+// followStages reads one WaitForTx stream, sending the stages past reached.
+// It returns nil once the transaction is confirmed or events can no longer
+// be sent, and otherwise the reason the stream ended.
+func followStages(
+	ctx context.Context,
+	open openStagesFunc,
+	txHash string,
+	hash []byte,
+	reached *submit.Stage,
+	send func(connector.TxStageEvent) bool,
+) error {
+	stream, err := open(ctx, hash)
+	if err != nil {
+		return rpcError("WaitForTx", err)
+	}
+	defer stream.Close()
+
+	for stream.Receive() {
+		msg := stream.Msg()
+		stage, known := txStages[msg.GetStage()]
+		if !known || msg.GetStage() <= *reached {
+			continue
+		}
+		if len(msg.GetRef()) > 0 && !bytes.Equal(msg.GetRef(), hash) {
+			continue
+		}
+		*reached = msg.GetStage()
+		if !send(connector.TxStageEvent{TxHash: txHash, Stage: stage}) {
+			return nil
+		}
+		if stage == connector.TxStageConfirmed {
+			return nil
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return rpcError("WaitForTx", err)
+	}
+	return errStreamEnded
+}